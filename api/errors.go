@@ -0,0 +1,43 @@
+package api
+
+import (
+	"github.com/OpenBazaar/multiwallet/api/pb"
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError converts an error returned by a wallet into a gRPC status. The status code
+// reflects the kind of failure and an ErrorDetail carries the kind and any reason given by
+// the wallet server so clients need not parse the message.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var (
+		code = codes.Unknown
+		kind = pb.ErrorKind_UNKNOWN_ERROR
+	)
+	switch clientErr.KindOf(err) {
+	case clientErr.KindInsufficientFunds:
+		code, kind = codes.FailedPrecondition, pb.ErrorKind_INSUFFICIENT_FUNDS
+	case clientErr.KindDust:
+		code, kind = codes.InvalidArgument, pb.ErrorKind_DUST_AMOUNT
+	case clientErr.KindBackendUnavailable:
+		code, kind = codes.Unavailable, pb.ErrorKind_BACKEND_UNAVAILABLE
+	case clientErr.KindInvalidAddress:
+		code, kind = codes.InvalidArgument, pb.ErrorKind_INVALID_ADDRESS
+	case clientErr.KindFeeTooHigh:
+		code, kind = codes.FailedPrecondition, pb.ErrorKind_FEE_TOO_HIGH
+	case clientErr.KindRejected:
+		code, kind = codes.Aborted, pb.ErrorKind_REJECTED_BY_MEMPOOL
+	}
+	st := status.New(code, err.Error())
+	if detailed, dErr := st.WithDetails(&pb.ErrorDetail{Kind: kind, Reason: clientErr.ReasonOf(err)}); dErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
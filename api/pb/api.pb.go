@@ -24,6 +24,9 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// Coins of the coins registry are named here after their coin name. A registered coin which is
+// not named is selected by the slip44 field of a request, its SLIP-0044 coin type, which
+// overrides coin when it is not zero.
 type CoinType int32
 
 const (
@@ -32,6 +35,8 @@ const (
 	CoinType_ZCASH        CoinType = 2
 	CoinType_LITECOIN     CoinType = 3
 	CoinType_ETHEREUM     CoinType = 4
+	CoinType_DOGECOIN     CoinType = 5
+	CoinType_DASH         CoinType = 6
 )
 
 var CoinType_name = map[int32]string{
@@ -40,6 +45,8 @@ var CoinType_name = map[int32]string{
 	2: "ZCASH",
 	3: "LITECOIN",
 	4: "ETHEREUM",
+	5: "DOGECOIN",
+	6: "DASH",
 }
 var CoinType_value = map[string]int32{
 	"BITCOIN":      0,
@@ -47,13 +54,15 @@ var CoinType_value = map[string]int32{
 	"ZCASH":        2,
 	"LITECOIN":     3,
 	"ETHEREUM":     4,
+	"DOGECOIN":     5,
+	"DASH":         6,
 }
 
 func (x CoinType) String() string {
 	return proto.EnumName(CoinType_name, int32(x))
 }
 func (CoinType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{0}
+	return fileDescriptor_api_4f28567b98176b45, []int{0}
 }
 
 type KeyPurpose int32
@@ -76,7 +85,7 @@ func (x KeyPurpose) String() string {
 	return proto.EnumName(KeyPurpose_name, int32(x))
 }
 func (KeyPurpose) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{1}
+	return fileDescriptor_api_4f28567b98176b45, []int{1}
 }
 
 type FeeLevel int32
@@ -102,7 +111,45 @@ func (x FeeLevel) String() string {
 	return proto.EnumName(FeeLevel_name, int32(x))
 }
 func (FeeLevel) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{2}
+	return fileDescriptor_api_4f28567b98176b45, []int{2}
+}
+
+type ErrorKind int32
+
+const (
+	ErrorKind_UNKNOWN_ERROR       ErrorKind = 0
+	ErrorKind_INSUFFICIENT_FUNDS  ErrorKind = 1
+	ErrorKind_DUST_AMOUNT         ErrorKind = 2
+	ErrorKind_BACKEND_UNAVAILABLE ErrorKind = 3
+	ErrorKind_INVALID_ADDRESS     ErrorKind = 4
+	ErrorKind_FEE_TOO_HIGH        ErrorKind = 5
+	ErrorKind_REJECTED_BY_MEMPOOL ErrorKind = 6
+)
+
+var ErrorKind_name = map[int32]string{
+	0: "UNKNOWN_ERROR",
+	1: "INSUFFICIENT_FUNDS",
+	2: "DUST_AMOUNT",
+	3: "BACKEND_UNAVAILABLE",
+	4: "INVALID_ADDRESS",
+	5: "FEE_TOO_HIGH",
+	6: "REJECTED_BY_MEMPOOL",
+}
+var ErrorKind_value = map[string]int32{
+	"UNKNOWN_ERROR":       0,
+	"INSUFFICIENT_FUNDS":  1,
+	"DUST_AMOUNT":         2,
+	"BACKEND_UNAVAILABLE": 3,
+	"INVALID_ADDRESS":     4,
+	"FEE_TOO_HIGH":        5,
+	"REJECTED_BY_MEMPOOL": 6,
+}
+
+func (x ErrorKind) String() string {
+	return proto.EnumName(ErrorKind_name, int32(x))
+}
+func (ErrorKind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{3}
 }
 
 type Empty struct {
@@ -115,7 +162,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{0}
+	return fileDescriptor_api_4f28567b98176b45, []int{0}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Empty.Unmarshal(m, b)
@@ -137,6 +184,7 @@ var xxx_messageInfo_Empty proto.InternalMessageInfo
 
 type CoinSelection struct {
 	Coin                 CoinType `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
+	Slip44               uint32   `protobuf:"varint,2,opt,name=slip44,proto3" json:"slip44,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -146,7 +194,7 @@ func (m *CoinSelection) Reset()         { *m = CoinSelection{} }
 func (m *CoinSelection) String() string { return proto.CompactTextString(m) }
 func (*CoinSelection) ProtoMessage()    {}
 func (*CoinSelection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{1}
+	return fileDescriptor_api_4f28567b98176b45, []int{1}
 }
 func (m *CoinSelection) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CoinSelection.Unmarshal(m, b)
@@ -173,6 +221,13 @@ func (m *CoinSelection) GetCoin() CoinType {
 	return CoinType_BITCOIN
 }
 
+func (m *CoinSelection) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
 type Row struct {
 	Data                 string   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -184,7 +239,7 @@ func (m *Row) Reset()         { *m = Row{} }
 func (m *Row) String() string { return proto.CompactTextString(m) }
 func (*Row) ProtoMessage()    {}
 func (*Row) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{2}
+	return fileDescriptor_api_4f28567b98176b45, []int{2}
 }
 func (m *Row) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Row.Unmarshal(m, b)
@@ -214,6 +269,7 @@ func (m *Row) GetData() string {
 type KeySelection struct {
 	Coin                 CoinType   `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
 	Purpose              KeyPurpose `protobuf:"varint,2,opt,name=purpose,proto3,enum=pb.KeyPurpose" json:"purpose,omitempty"`
+	Slip44               uint32     `protobuf:"varint,3,opt,name=slip44,proto3" json:"slip44,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
 	XXX_unrecognized     []byte     `json:"-"`
 	XXX_sizecache        int32      `json:"-"`
@@ -223,7 +279,7 @@ func (m *KeySelection) Reset()         { *m = KeySelection{} }
 func (m *KeySelection) String() string { return proto.CompactTextString(m) }
 func (*KeySelection) ProtoMessage()    {}
 func (*KeySelection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{3}
+	return fileDescriptor_api_4f28567b98176b45, []int{3}
 }
 func (m *KeySelection) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KeySelection.Unmarshal(m, b)
@@ -257,9 +313,17 @@ func (m *KeySelection) GetPurpose() KeyPurpose {
 	return KeyPurpose_INTERNAL
 }
 
+func (m *KeySelection) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
 type Address struct {
 	Coin                 CoinType `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
 	Addr                 string   `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Slip44               uint32   `protobuf:"varint,3,opt,name=slip44,proto3" json:"slip44,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -269,7 +333,7 @@ func (m *Address) Reset()         { *m = Address{} }
 func (m *Address) String() string { return proto.CompactTextString(m) }
 func (*Address) ProtoMessage()    {}
 func (*Address) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{4}
+	return fileDescriptor_api_4f28567b98176b45, []int{4}
 }
 func (m *Address) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Address.Unmarshal(m, b)
@@ -303,6 +367,13 @@ func (m *Address) GetAddr() string {
 	return ""
 }
 
+func (m *Address) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
 type Height struct {
 	Height               uint32   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -314,7 +385,7 @@ func (m *Height) Reset()         { *m = Height{} }
 func (m *Height) String() string { return proto.CompactTextString(m) }
 func (*Height) ProtoMessage()    {}
 func (*Height) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{5}
+	return fileDescriptor_api_4f28567b98176b45, []int{5}
 }
 func (m *Height) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Height.Unmarshal(m, b)
@@ -353,7 +424,7 @@ func (m *Balances) Reset()         { *m = Balances{} }
 func (m *Balances) String() string { return proto.CompactTextString(m) }
 func (*Balances) ProtoMessage()    {}
 func (*Balances) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{6}
+	return fileDescriptor_api_4f28567b98176b45, []int{6}
 }
 func (m *Balances) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Balances.Unmarshal(m, b)
@@ -398,7 +469,7 @@ func (m *Key) Reset()         { *m = Key{} }
 func (m *Key) String() string { return proto.CompactTextString(m) }
 func (*Key) ProtoMessage()    {}
 func (*Key) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{7}
+	return fileDescriptor_api_4f28567b98176b45, []int{7}
 }
 func (m *Key) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Key.Unmarshal(m, b)
@@ -436,7 +507,7 @@ func (m *Keys) Reset()         { *m = Keys{} }
 func (m *Keys) String() string { return proto.CompactTextString(m) }
 func (*Keys) ProtoMessage()    {}
 func (*Keys) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{8}
+	return fileDescriptor_api_4f28567b98176b45, []int{8}
 }
 func (m *Keys) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Keys.Unmarshal(m, b)
@@ -474,7 +545,7 @@ func (m *Addresses) Reset()         { *m = Addresses{} }
 func (m *Addresses) String() string { return proto.CompactTextString(m) }
 func (*Addresses) ProtoMessage()    {}
 func (*Addresses) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{9}
+	return fileDescriptor_api_4f28567b98176b45, []int{9}
 }
 func (m *Addresses) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Addresses.Unmarshal(m, b)
@@ -512,7 +583,7 @@ func (m *BoolResponse) Reset()         { *m = BoolResponse{} }
 func (m *BoolResponse) String() string { return proto.CompactTextString(m) }
 func (*BoolResponse) ProtoMessage()    {}
 func (*BoolResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{10}
+	return fileDescriptor_api_4f28567b98176b45, []int{10}
 }
 func (m *BoolResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BoolResponse.Unmarshal(m, b)
@@ -550,7 +621,7 @@ func (m *NetParams) Reset()         { *m = NetParams{} }
 func (m *NetParams) String() string { return proto.CompactTextString(m) }
 func (*NetParams) ProtoMessage()    {}
 func (*NetParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{11}
+	return fileDescriptor_api_4f28567b98176b45, []int{11}
 }
 func (m *NetParams) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NetParams.Unmarshal(m, b)
@@ -588,7 +659,7 @@ func (m *TransactionList) Reset()         { *m = TransactionList{} }
 func (m *TransactionList) String() string { return proto.CompactTextString(m) }
 func (*TransactionList) ProtoMessage()    {}
 func (*TransactionList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{12}
+	return fileDescriptor_api_4f28567b98176b45, []int{12}
 }
 func (m *TransactionList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TransactionList.Unmarshal(m, b)
@@ -616,22 +687,25 @@ func (m *TransactionList) GetTransactions() []*Tx {
 }
 
 type Tx struct {
-	Txid                 string               `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
-	Value                int64                `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
-	Height               int32                `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
-	Timestamp            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	WatchOnly            bool                 `protobuf:"varint,5,opt,name=watchOnly,proto3" json:"watchOnly,omitempty"`
-	Raw                  []byte               `protobuf:"bytes,6,opt,name=raw,proto3" json:"raw,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Txid      string               `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Value     int64                `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	Height    int32                `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	Timestamp *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	WatchOnly bool                 `protobuf:"varint,5,opt,name=watchOnly,proto3" json:"watchOnly,omitempty"`
+	Raw       []byte               `protobuf:"bytes,6,opt,name=raw,proto3" json:"raw,omitempty"`
+	// Value of the transaction in each currency at the exchange rates
+	// recorded when the wallet first saw it
+	FiatValues           map[string]float64 `protobuf:"bytes,7,rep,name=fiatValues,proto3" json:"fiatValues,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *Tx) Reset()         { *m = Tx{} }
 func (m *Tx) String() string { return proto.CompactTextString(m) }
 func (*Tx) ProtoMessage()    {}
 func (*Tx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{13}
+	return fileDescriptor_api_4f28567b98176b45, []int{13}
 }
 func (m *Tx) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Tx.Unmarshal(m, b)
@@ -693,9 +767,17 @@ func (m *Tx) GetRaw() []byte {
 	return nil
 }
 
+func (m *Tx) GetFiatValues() map[string]float64 {
+	if m != nil {
+		return m.FiatValues
+	}
+	return nil
+}
+
 type Txid struct {
 	Coin                 CoinType `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
 	Hash                 string   `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Slip44               uint32   `protobuf:"varint,3,opt,name=slip44,proto3" json:"slip44,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -705,7 +787,7 @@ func (m *Txid) Reset()         { *m = Txid{} }
 func (m *Txid) String() string { return proto.CompactTextString(m) }
 func (*Txid) ProtoMessage()    {}
 func (*Txid) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{14}
+	return fileDescriptor_api_4f28567b98176b45, []int{14}
 }
 func (m *Txid) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Txid.Unmarshal(m, b)
@@ -739,9 +821,17 @@ func (m *Txid) GetHash() string {
 	return ""
 }
 
+func (m *Txid) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
 type FeeLevelSelection struct {
 	Coin                 CoinType `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
 	FeeLevel             FeeLevel `protobuf:"varint,2,opt,name=feeLevel,proto3,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+	Slip44               uint32   `protobuf:"varint,3,opt,name=slip44,proto3" json:"slip44,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -751,7 +841,7 @@ func (m *FeeLevelSelection) Reset()         { *m = FeeLevelSelection{} }
 func (m *FeeLevelSelection) String() string { return proto.CompactTextString(m) }
 func (*FeeLevelSelection) ProtoMessage()    {}
 func (*FeeLevelSelection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{15}
+	return fileDescriptor_api_4f28567b98176b45, []int{15}
 }
 func (m *FeeLevelSelection) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FeeLevelSelection.Unmarshal(m, b)
@@ -785,6 +875,13 @@ func (m *FeeLevelSelection) GetFeeLevel() FeeLevel {
 	return FeeLevel_ECONOMIC
 }
 
+func (m *FeeLevelSelection) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
 type FeePerByte struct {
 	Fee                  uint64   `protobuf:"varint,1,opt,name=fee,proto3" json:"fee,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -796,7 +893,7 @@ func (m *FeePerByte) Reset()         { *m = FeePerByte{} }
 func (m *FeePerByte) String() string { return proto.CompactTextString(m) }
 func (*FeePerByte) ProtoMessage()    {}
 func (*FeePerByte) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{16}
+	return fileDescriptor_api_4f28567b98176b45, []int{16}
 }
 func (m *FeePerByte) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FeePerByte.Unmarshal(m, b)
@@ -834,7 +931,7 @@ func (m *Fee) Reset()         { *m = Fee{} }
 func (m *Fee) String() string { return proto.CompactTextString(m) }
 func (*Fee) ProtoMessage()    {}
 func (*Fee) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{17}
+	return fileDescriptor_api_4f28567b98176b45, []int{17}
 }
 func (m *Fee) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Fee.Unmarshal(m, b)
@@ -867,6 +964,8 @@ type SpendInfo struct {
 	Amount               uint64   `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
 	FeeLevel             FeeLevel `protobuf:"varint,4,opt,name=feeLevel,proto3,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
 	Memo                 string   `protobuf:"bytes,5,opt,name=memo,proto3" json:"memo,omitempty"`
+	CoinSelection        string   `protobuf:"bytes,6,opt,name=coinSelection,proto3" json:"coinSelection,omitempty"`
+	Slip44               uint32   `protobuf:"varint,7,opt,name=slip44,proto3" json:"slip44,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -876,7 +975,7 @@ func (m *SpendInfo) Reset()         { *m = SpendInfo{} }
 func (m *SpendInfo) String() string { return proto.CompactTextString(m) }
 func (*SpendInfo) ProtoMessage()    {}
 func (*SpendInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{18}
+	return fileDescriptor_api_4f28567b98176b45, []int{18}
 }
 func (m *SpendInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SpendInfo.Unmarshal(m, b)
@@ -931,6 +1030,144 @@ func (m *SpendInfo) GetMemo() string {
 	return ""
 }
 
+func (m *SpendInfo) GetCoinSelection() string {
+	if m != nil {
+		return m.CoinSelection
+	}
+	return ""
+}
+
+func (m *SpendInfo) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
+type Recipient struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Amount               uint64   `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Recipient) Reset()         { *m = Recipient{} }
+func (m *Recipient) String() string { return proto.CompactTextString(m) }
+func (*Recipient) ProtoMessage()    {}
+func (*Recipient) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{19}
+}
+func (m *Recipient) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Recipient.Unmarshal(m, b)
+}
+func (m *Recipient) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Recipient.Marshal(b, m, deterministic)
+}
+func (dst *Recipient) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Recipient.Merge(dst, src)
+}
+func (m *Recipient) XXX_Size() int {
+	return xxx_messageInfo_Recipient.Size(m)
+}
+func (m *Recipient) XXX_DiscardUnknown() {
+	xxx_messageInfo_Recipient.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Recipient proto.InternalMessageInfo
+
+func (m *Recipient) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Recipient) GetAmount() uint64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+type SpendManyInfo struct {
+	Coin                 CoinType     `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
+	Recipients           []*Recipient `protobuf:"bytes,2,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	FeeLevel             FeeLevel     `protobuf:"varint,3,opt,name=feeLevel,proto3,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+	Memo                 string       `protobuf:"bytes,4,opt,name=memo,proto3" json:"memo,omitempty"`
+	CoinSelection        string       `protobuf:"bytes,5,opt,name=coinSelection,proto3" json:"coinSelection,omitempty"`
+	Slip44               uint32       `protobuf:"varint,6,opt,name=slip44,proto3" json:"slip44,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SpendManyInfo) Reset()         { *m = SpendManyInfo{} }
+func (m *SpendManyInfo) String() string { return proto.CompactTextString(m) }
+func (*SpendManyInfo) ProtoMessage()    {}
+func (*SpendManyInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{20}
+}
+func (m *SpendManyInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SpendManyInfo.Unmarshal(m, b)
+}
+func (m *SpendManyInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SpendManyInfo.Marshal(b, m, deterministic)
+}
+func (dst *SpendManyInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpendManyInfo.Merge(dst, src)
+}
+func (m *SpendManyInfo) XXX_Size() int {
+	return xxx_messageInfo_SpendManyInfo.Size(m)
+}
+func (m *SpendManyInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpendManyInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpendManyInfo proto.InternalMessageInfo
+
+func (m *SpendManyInfo) GetCoin() CoinType {
+	if m != nil {
+		return m.Coin
+	}
+	return CoinType_BITCOIN
+}
+
+func (m *SpendManyInfo) GetRecipients() []*Recipient {
+	if m != nil {
+		return m.Recipients
+	}
+	return nil
+}
+
+func (m *SpendManyInfo) GetFeeLevel() FeeLevel {
+	if m != nil {
+		return m.FeeLevel
+	}
+	return FeeLevel_ECONOMIC
+}
+
+func (m *SpendManyInfo) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *SpendManyInfo) GetCoinSelection() string {
+	if m != nil {
+		return m.CoinSelection
+	}
+	return ""
+}
+
+func (m *SpendManyInfo) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
 type Confirmations struct {
 	Confirmations        uint32   `protobuf:"varint,1,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -942,7 +1179,7 @@ func (m *Confirmations) Reset()         { *m = Confirmations{} }
 func (m *Confirmations) String() string { return proto.CompactTextString(m) }
 func (*Confirmations) ProtoMessage()    {}
 func (*Confirmations) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{19}
+	return fileDescriptor_api_4f28567b98176b45, []int{21}
 }
 func (m *Confirmations) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Confirmations.Unmarshal(m, b)
@@ -982,7 +1219,7 @@ func (m *Utxo) Reset()         { *m = Utxo{} }
 func (m *Utxo) String() string { return proto.CompactTextString(m) }
 func (*Utxo) ProtoMessage()    {}
 func (*Utxo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{20}
+	return fileDescriptor_api_4f28567b98176b45, []int{22}
 }
 func (m *Utxo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Utxo.Unmarshal(m, b)
@@ -1023,145 +1260,744 @@ func (m *Utxo) GetValue() uint64 {
 	return 0
 }
 
-type SweepInfo struct {
-	Coin                 CoinType `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
-	Utxos                []*Utxo  `protobuf:"bytes,2,rep,name=utxos,proto3" json:"utxos,omitempty"`
-	Address              string   `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
-	Key                  string   `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
-	RedeemScript         []byte   `protobuf:"bytes,5,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
-	FeeLevel             FeeLevel `protobuf:"varint,6,opt,name=feeLevel,proto3,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+type Outpoint struct {
+	Txid                 string   `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Index                uint32   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SweepInfo) Reset()         { *m = SweepInfo{} }
-func (m *SweepInfo) String() string { return proto.CompactTextString(m) }
-func (*SweepInfo) ProtoMessage()    {}
-func (*SweepInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{21}
+func (m *Outpoint) Reset()         { *m = Outpoint{} }
+func (m *Outpoint) String() string { return proto.CompactTextString(m) }
+func (*Outpoint) ProtoMessage()    {}
+func (*Outpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{23}
 }
-func (m *SweepInfo) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SweepInfo.Unmarshal(m, b)
+func (m *Outpoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Outpoint.Unmarshal(m, b)
 }
-func (m *SweepInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SweepInfo.Marshal(b, m, deterministic)
+func (m *Outpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Outpoint.Marshal(b, m, deterministic)
 }
-func (dst *SweepInfo) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SweepInfo.Merge(dst, src)
+func (dst *Outpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Outpoint.Merge(dst, src)
 }
-func (m *SweepInfo) XXX_Size() int {
-	return xxx_messageInfo_SweepInfo.Size(m)
+func (m *Outpoint) XXX_Size() int {
+	return xxx_messageInfo_Outpoint.Size(m)
 }
-func (m *SweepInfo) XXX_DiscardUnknown() {
-	xxx_messageInfo_SweepInfo.DiscardUnknown(m)
+func (m *Outpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_Outpoint.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SweepInfo proto.InternalMessageInfo
+var xxx_messageInfo_Outpoint proto.InternalMessageInfo
 
-func (m *SweepInfo) GetCoin() CoinType {
+func (m *Outpoint) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *Outpoint) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type OutpointSelection struct {
+	Coin                 CoinType  `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
+	Outpoint             *Outpoint `protobuf:"bytes,2,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	Slip44               uint32    `protobuf:"varint,3,opt,name=slip44,proto3" json:"slip44,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *OutpointSelection) Reset()         { *m = OutpointSelection{} }
+func (m *OutpointSelection) String() string { return proto.CompactTextString(m) }
+func (*OutpointSelection) ProtoMessage()    {}
+func (*OutpointSelection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{24}
+}
+func (m *OutpointSelection) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OutpointSelection.Unmarshal(m, b)
+}
+func (m *OutpointSelection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OutpointSelection.Marshal(b, m, deterministic)
+}
+func (dst *OutpointSelection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutpointSelection.Merge(dst, src)
+}
+func (m *OutpointSelection) XXX_Size() int {
+	return xxx_messageInfo_OutpointSelection.Size(m)
+}
+func (m *OutpointSelection) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutpointSelection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OutpointSelection proto.InternalMessageInfo
+
+func (m *OutpointSelection) GetCoin() CoinType {
 	if m != nil {
 		return m.Coin
 	}
 	return CoinType_BITCOIN
 }
 
-func (m *SweepInfo) GetUtxos() []*Utxo {
+func (m *OutpointSelection) GetOutpoint() *Outpoint {
 	if m != nil {
-		return m.Utxos
+		return m.Outpoint
 	}
 	return nil
 }
 
-func (m *SweepInfo) GetAddress() string {
+func (m *OutpointSelection) GetSlip44() uint32 {
 	if m != nil {
-		return m.Address
+		return m.Slip44
 	}
-	return ""
+	return 0
 }
 
-func (m *SweepInfo) GetKey() string {
+type SpendOutpointsInfo struct {
+	Coin                 CoinType     `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
+	Outpoints            []*Outpoint  `protobuf:"bytes,2,rep,name=outpoints,proto3" json:"outpoints,omitempty"`
+	Recipients           []*Recipient `protobuf:"bytes,3,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	FeeLevel             FeeLevel     `protobuf:"varint,4,opt,name=feeLevel,proto3,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+	Memo                 string       `protobuf:"bytes,5,opt,name=memo,proto3" json:"memo,omitempty"`
+	Slip44               uint32       `protobuf:"varint,6,opt,name=slip44,proto3" json:"slip44,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SpendOutpointsInfo) Reset()         { *m = SpendOutpointsInfo{} }
+func (m *SpendOutpointsInfo) String() string { return proto.CompactTextString(m) }
+func (*SpendOutpointsInfo) ProtoMessage()    {}
+func (*SpendOutpointsInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{25}
+}
+func (m *SpendOutpointsInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SpendOutpointsInfo.Unmarshal(m, b)
+}
+func (m *SpendOutpointsInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SpendOutpointsInfo.Marshal(b, m, deterministic)
+}
+func (dst *SpendOutpointsInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpendOutpointsInfo.Merge(dst, src)
+}
+func (m *SpendOutpointsInfo) XXX_Size() int {
+	return xxx_messageInfo_SpendOutpointsInfo.Size(m)
+}
+func (m *SpendOutpointsInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpendOutpointsInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpendOutpointsInfo proto.InternalMessageInfo
+
+func (m *SpendOutpointsInfo) GetCoin() CoinType {
 	if m != nil {
-		return m.Key
+		return m.Coin
 	}
-	return ""
+	return CoinType_BITCOIN
 }
 
-func (m *SweepInfo) GetRedeemScript() []byte {
+func (m *SpendOutpointsInfo) GetOutpoints() []*Outpoint {
 	if m != nil {
-		return m.RedeemScript
+		return m.Outpoints
 	}
 	return nil
 }
 
-func (m *SweepInfo) GetFeeLevel() FeeLevel {
+func (m *SpendOutpointsInfo) GetRecipients() []*Recipient {
+	if m != nil {
+		return m.Recipients
+	}
+	return nil
+}
+
+func (m *SpendOutpointsInfo) GetFeeLevel() FeeLevel {
 	if m != nil {
 		return m.FeeLevel
 	}
 	return FeeLevel_ECONOMIC
 }
 
-type Input struct {
-	Txid                 string   `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
-	Index                uint32   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+func (m *SpendOutpointsInfo) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *SpendOutpointsInfo) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
+type UtxoStatus struct {
+	Txid      string `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Index     uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Value     uint64 `protobuf:"varint,3,opt,name=value,proto3" json:"value,omitempty"`
+	Address   string `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	Height    uint32 `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	WatchOnly bool   `protobuf:"varint,6,opt,name=watchOnly,proto3" json:"watchOnly,omitempty"`
+	Frozen    bool   `protobuf:"varint,7,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	// Set if the output carries a CashToken
+	TokenCategory        string   `protobuf:"bytes,8,opt,name=tokenCategory,proto3" json:"tokenCategory,omitempty"`
+	TokenAmount          uint64   `protobuf:"varint,9,opt,name=tokenAmount,proto3" json:"tokenAmount,omitempty"`
+	TokenNFT             bool     `protobuf:"varint,10,opt,name=tokenNFT,proto3" json:"tokenNFT,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Input) Reset()         { *m = Input{} }
-func (m *Input) String() string { return proto.CompactTextString(m) }
-func (*Input) ProtoMessage()    {}
-func (*Input) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{22}
+func (m *UtxoStatus) Reset()         { *m = UtxoStatus{} }
+func (m *UtxoStatus) String() string { return proto.CompactTextString(m) }
+func (*UtxoStatus) ProtoMessage()    {}
+func (*UtxoStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{26}
 }
-func (m *Input) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Input.Unmarshal(m, b)
+func (m *UtxoStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UtxoStatus.Unmarshal(m, b)
 }
-func (m *Input) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Input.Marshal(b, m, deterministic)
+func (m *UtxoStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UtxoStatus.Marshal(b, m, deterministic)
 }
-func (dst *Input) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Input.Merge(dst, src)
+func (dst *UtxoStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UtxoStatus.Merge(dst, src)
 }
-func (m *Input) XXX_Size() int {
-	return xxx_messageInfo_Input.Size(m)
+func (m *UtxoStatus) XXX_Size() int {
+	return xxx_messageInfo_UtxoStatus.Size(m)
 }
-func (m *Input) XXX_DiscardUnknown() {
-	xxx_messageInfo_Input.DiscardUnknown(m)
+func (m *UtxoStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_UtxoStatus.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Input proto.InternalMessageInfo
+var xxx_messageInfo_UtxoStatus proto.InternalMessageInfo
 
-func (m *Input) GetTxid() string {
+func (m *UtxoStatus) GetTxid() string {
 	if m != nil {
 		return m.Txid
 	}
 	return ""
 }
 
-func (m *Input) GetIndex() uint32 {
+func (m *UtxoStatus) GetIndex() uint32 {
 	if m != nil {
 		return m.Index
 	}
 	return 0
 }
 
-type Output struct {
-	ScriptPubKey         []byte   `protobuf:"bytes,1,opt,name=scriptPubKey,proto3" json:"scriptPubKey,omitempty"`
-	Value                uint64   `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *UtxoStatus) GetValue() uint64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
 }
 
-func (m *Output) Reset()         { *m = Output{} }
-func (m *Output) String() string { return proto.CompactTextString(m) }
-func (*Output) ProtoMessage()    {}
-func (*Output) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{23}
-}
-func (m *Output) XXX_Unmarshal(b []byte) error {
+func (m *UtxoStatus) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *UtxoStatus) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *UtxoStatus) GetWatchOnly() bool {
+	if m != nil {
+		return m.WatchOnly
+	}
+	return false
+}
+
+func (m *UtxoStatus) GetFrozen() bool {
+	if m != nil {
+		return m.Frozen
+	}
+	return false
+}
+
+func (m *UtxoStatus) GetTokenCategory() string {
+	if m != nil {
+		return m.TokenCategory
+	}
+	return ""
+}
+
+func (m *UtxoStatus) GetTokenAmount() uint64 {
+	if m != nil {
+		return m.TokenAmount
+	}
+	return 0
+}
+
+func (m *UtxoStatus) GetTokenNFT() bool {
+	if m != nil {
+		return m.TokenNFT
+	}
+	return false
+}
+
+type UtxoList struct {
+	Utxos                []*UtxoStatus `protobuf:"bytes,1,rep,name=utxos,proto3" json:"utxos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *UtxoList) Reset()         { *m = UtxoList{} }
+func (m *UtxoList) String() string { return proto.CompactTextString(m) }
+func (*UtxoList) ProtoMessage()    {}
+func (*UtxoList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{27}
+}
+func (m *UtxoList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UtxoList.Unmarshal(m, b)
+}
+func (m *UtxoList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UtxoList.Marshal(b, m, deterministic)
+}
+func (dst *UtxoList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UtxoList.Merge(dst, src)
+}
+func (m *UtxoList) XXX_Size() int {
+	return xxx_messageInfo_UtxoList.Size(m)
+}
+func (m *UtxoList) XXX_DiscardUnknown() {
+	xxx_messageInfo_UtxoList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UtxoList proto.InternalMessageInfo
+
+func (m *UtxoList) GetUtxos() []*UtxoStatus {
+	if m != nil {
+		return m.Utxos
+	}
+	return nil
+}
+
+type ConsolidateInfo struct {
+	Coin                 CoinType `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	Slip44               uint32   `protobuf:"varint,3,opt,name=slip44,proto3" json:"slip44,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConsolidateInfo) Reset()         { *m = ConsolidateInfo{} }
+func (m *ConsolidateInfo) String() string { return proto.CompactTextString(m) }
+func (*ConsolidateInfo) ProtoMessage()    {}
+func (*ConsolidateInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{28}
+}
+func (m *ConsolidateInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConsolidateInfo.Unmarshal(m, b)
+}
+func (m *ConsolidateInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConsolidateInfo.Marshal(b, m, deterministic)
+}
+func (dst *ConsolidateInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConsolidateInfo.Merge(dst, src)
+}
+func (m *ConsolidateInfo) XXX_Size() int {
+	return xxx_messageInfo_ConsolidateInfo.Size(m)
+}
+func (m *ConsolidateInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConsolidateInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConsolidateInfo proto.InternalMessageInfo
+
+func (m *ConsolidateInfo) GetCoin() CoinType {
+	if m != nil {
+		return m.Coin
+	}
+	return CoinType_BITCOIN
+}
+
+func (m *ConsolidateInfo) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *ConsolidateInfo) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
+type ConsolidationPlan struct {
+	Coin                 CoinType `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
+	Utxos                uint32   `protobuf:"varint,2,opt,name=utxos,proto3" json:"utxos,omitempty"`
+	Value                uint64   `protobuf:"varint,3,opt,name=value,proto3" json:"value,omitempty"`
+	Size                 uint32   `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	FeePerByte           uint64   `protobuf:"varint,5,opt,name=feePerByte,proto3" json:"feePerByte,omitempty"`
+	Fee                  uint64   `protobuf:"varint,6,opt,name=fee,proto3" json:"fee,omitempty"`
+	FutureFeePerByte     uint64   `protobuf:"varint,7,opt,name=futureFeePerByte,proto3" json:"futureFeePerByte,omitempty"`
+	Savings              int64    `protobuf:"varint,8,opt,name=savings,proto3" json:"savings,omitempty"`
+	WithinFeeLimit       bool     `protobuf:"varint,9,opt,name=withinFeeLimit,proto3" json:"withinFeeLimit,omitempty"`
+	Txid                 string   `protobuf:"bytes,10,opt,name=txid,proto3" json:"txid,omitempty"`
+	Slip44               uint32   `protobuf:"varint,11,opt,name=slip44,proto3" json:"slip44,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConsolidationPlan) Reset()         { *m = ConsolidationPlan{} }
+func (m *ConsolidationPlan) String() string { return proto.CompactTextString(m) }
+func (*ConsolidationPlan) ProtoMessage()    {}
+func (*ConsolidationPlan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{29}
+}
+func (m *ConsolidationPlan) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConsolidationPlan.Unmarshal(m, b)
+}
+func (m *ConsolidationPlan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConsolidationPlan.Marshal(b, m, deterministic)
+}
+func (dst *ConsolidationPlan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConsolidationPlan.Merge(dst, src)
+}
+func (m *ConsolidationPlan) XXX_Size() int {
+	return xxx_messageInfo_ConsolidationPlan.Size(m)
+}
+func (m *ConsolidationPlan) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConsolidationPlan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConsolidationPlan proto.InternalMessageInfo
+
+func (m *ConsolidationPlan) GetCoin() CoinType {
+	if m != nil {
+		return m.Coin
+	}
+	return CoinType_BITCOIN
+}
+
+func (m *ConsolidationPlan) GetUtxos() uint32 {
+	if m != nil {
+		return m.Utxos
+	}
+	return 0
+}
+
+func (m *ConsolidationPlan) GetValue() uint64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *ConsolidationPlan) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *ConsolidationPlan) GetFeePerByte() uint64 {
+	if m != nil {
+		return m.FeePerByte
+	}
+	return 0
+}
+
+func (m *ConsolidationPlan) GetFee() uint64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+func (m *ConsolidationPlan) GetFutureFeePerByte() uint64 {
+	if m != nil {
+		return m.FutureFeePerByte
+	}
+	return 0
+}
+
+func (m *ConsolidationPlan) GetSavings() int64 {
+	if m != nil {
+		return m.Savings
+	}
+	return 0
+}
+
+func (m *ConsolidationPlan) GetWithinFeeLimit() bool {
+	if m != nil {
+		return m.WithinFeeLimit
+	}
+	return false
+}
+
+func (m *ConsolidationPlan) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *ConsolidationPlan) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
+type TokenBalance struct {
+	Category             string   `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Amount               uint64   `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Nfts                 uint32   `protobuf:"varint,3,opt,name=nfts,proto3" json:"nfts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TokenBalance) Reset()         { *m = TokenBalance{} }
+func (m *TokenBalance) String() string { return proto.CompactTextString(m) }
+func (*TokenBalance) ProtoMessage()    {}
+func (*TokenBalance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{30}
+}
+func (m *TokenBalance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenBalance.Unmarshal(m, b)
+}
+func (m *TokenBalance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenBalance.Marshal(b, m, deterministic)
+}
+func (dst *TokenBalance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenBalance.Merge(dst, src)
+}
+func (m *TokenBalance) XXX_Size() int {
+	return xxx_messageInfo_TokenBalance.Size(m)
+}
+func (m *TokenBalance) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenBalance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenBalance proto.InternalMessageInfo
+
+func (m *TokenBalance) GetCategory() string {
+	if m != nil {
+		return m.Category
+	}
+	return ""
+}
+
+func (m *TokenBalance) GetAmount() uint64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *TokenBalance) GetNfts() uint32 {
+	if m != nil {
+		return m.Nfts
+	}
+	return 0
+}
+
+type TokenBalanceList struct {
+	Balances             []*TokenBalance `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *TokenBalanceList) Reset()         { *m = TokenBalanceList{} }
+func (m *TokenBalanceList) String() string { return proto.CompactTextString(m) }
+func (*TokenBalanceList) ProtoMessage()    {}
+func (*TokenBalanceList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{31}
+}
+func (m *TokenBalanceList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenBalanceList.Unmarshal(m, b)
+}
+func (m *TokenBalanceList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenBalanceList.Marshal(b, m, deterministic)
+}
+func (dst *TokenBalanceList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenBalanceList.Merge(dst, src)
+}
+func (m *TokenBalanceList) XXX_Size() int {
+	return xxx_messageInfo_TokenBalanceList.Size(m)
+}
+func (m *TokenBalanceList) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenBalanceList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenBalanceList proto.InternalMessageInfo
+
+func (m *TokenBalanceList) GetBalances() []*TokenBalance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+type SweepInfo struct {
+	Coin                 CoinType `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
+	Utxos                []*Utxo  `protobuf:"bytes,2,rep,name=utxos,proto3" json:"utxos,omitempty"`
+	Address              string   `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Key                  string   `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	RedeemScript         []byte   `protobuf:"bytes,5,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
+	FeeLevel             FeeLevel `protobuf:"varint,6,opt,name=feeLevel,proto3,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+	Slip44               uint32   `protobuf:"varint,7,opt,name=slip44,proto3" json:"slip44,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SweepInfo) Reset()         { *m = SweepInfo{} }
+func (m *SweepInfo) String() string { return proto.CompactTextString(m) }
+func (*SweepInfo) ProtoMessage()    {}
+func (*SweepInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{32}
+}
+func (m *SweepInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SweepInfo.Unmarshal(m, b)
+}
+func (m *SweepInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SweepInfo.Marshal(b, m, deterministic)
+}
+func (dst *SweepInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SweepInfo.Merge(dst, src)
+}
+func (m *SweepInfo) XXX_Size() int {
+	return xxx_messageInfo_SweepInfo.Size(m)
+}
+func (m *SweepInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SweepInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SweepInfo proto.InternalMessageInfo
+
+func (m *SweepInfo) GetCoin() CoinType {
+	if m != nil {
+		return m.Coin
+	}
+	return CoinType_BITCOIN
+}
+
+func (m *SweepInfo) GetUtxos() []*Utxo {
+	if m != nil {
+		return m.Utxos
+	}
+	return nil
+}
+
+func (m *SweepInfo) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *SweepInfo) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *SweepInfo) GetRedeemScript() []byte {
+	if m != nil {
+		return m.RedeemScript
+	}
+	return nil
+}
+
+func (m *SweepInfo) GetFeeLevel() FeeLevel {
+	if m != nil {
+		return m.FeeLevel
+	}
+	return FeeLevel_ECONOMIC
+}
+
+func (m *SweepInfo) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
+type Input struct {
+	Txid                 string   `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Index                uint32   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Input) Reset()         { *m = Input{} }
+func (m *Input) String() string { return proto.CompactTextString(m) }
+func (*Input) ProtoMessage()    {}
+func (*Input) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{33}
+}
+func (m *Input) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Input.Unmarshal(m, b)
+}
+func (m *Input) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Input.Marshal(b, m, deterministic)
+}
+func (dst *Input) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Input.Merge(dst, src)
+}
+func (m *Input) XXX_Size() int {
+	return xxx_messageInfo_Input.Size(m)
+}
+func (m *Input) XXX_DiscardUnknown() {
+	xxx_messageInfo_Input.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Input proto.InternalMessageInfo
+
+func (m *Input) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *Input) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type Output struct {
+	ScriptPubKey         []byte   `protobuf:"bytes,1,opt,name=scriptPubKey,proto3" json:"scriptPubKey,omitempty"`
+	Value                uint64   `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Output) Reset()         { *m = Output{} }
+func (m *Output) String() string { return proto.CompactTextString(m) }
+func (*Output) ProtoMessage()    {}
+func (*Output) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{34}
+}
+func (m *Output) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Output.Unmarshal(m, b)
 }
 func (m *Output) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
@@ -1205,7 +2041,7 @@ func (m *Signature) Reset()         { *m = Signature{} }
 func (m *Signature) String() string { return proto.CompactTextString(m) }
 func (*Signature) ProtoMessage()    {}
 func (*Signature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{24}
+	return fileDescriptor_api_4f28567b98176b45, []int{35}
 }
 func (m *Signature) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Signature.Unmarshal(m, b)
@@ -1246,6 +2082,7 @@ type CreateMultisigInfo struct {
 	Key                  string    `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
 	RedeemScript         []byte    `protobuf:"bytes,5,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
 	FeePerByte           uint64    `protobuf:"varint,6,opt,name=feePerByte,proto3" json:"feePerByte,omitempty"`
+	Slip44               uint32    `protobuf:"varint,7,opt,name=slip44,proto3" json:"slip44,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
 	XXX_unrecognized     []byte    `json:"-"`
 	XXX_sizecache        int32     `json:"-"`
@@ -1255,7 +2092,7 @@ func (m *CreateMultisigInfo) Reset()         { *m = CreateMultisigInfo{} }
 func (m *CreateMultisigInfo) String() string { return proto.CompactTextString(m) }
 func (*CreateMultisigInfo) ProtoMessage()    {}
 func (*CreateMultisigInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{25}
+	return fileDescriptor_api_4f28567b98176b45, []int{36}
 }
 func (m *CreateMultisigInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateMultisigInfo.Unmarshal(m, b)
@@ -1317,6 +2154,13 @@ func (m *CreateMultisigInfo) GetFeePerByte() uint64 {
 	return 0
 }
 
+func (m *CreateMultisigInfo) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
 type SignatureList struct {
 	Sigs                 []*Signature `protobuf:"bytes,1,rep,name=sigs,proto3" json:"sigs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
@@ -1328,7 +2172,7 @@ func (m *SignatureList) Reset()         { *m = SignatureList{} }
 func (m *SignatureList) String() string { return proto.CompactTextString(m) }
 func (*SignatureList) ProtoMessage()    {}
 func (*SignatureList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{26}
+	return fileDescriptor_api_4f28567b98176b45, []int{37}
 }
 func (m *SignatureList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignatureList.Unmarshal(m, b)
@@ -1364,6 +2208,7 @@ type MultisignInfo struct {
 	RedeemScript         []byte       `protobuf:"bytes,6,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
 	FeePerByte           uint64       `protobuf:"varint,7,opt,name=feePerByte,proto3" json:"feePerByte,omitempty"`
 	Broadcast            bool         `protobuf:"varint,8,opt,name=broadcast,proto3" json:"broadcast,omitempty"`
+	Slip44               uint32       `protobuf:"varint,9,opt,name=slip44,proto3" json:"slip44,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -1373,7 +2218,7 @@ func (m *MultisignInfo) Reset()         { *m = MultisignInfo{} }
 func (m *MultisignInfo) String() string { return proto.CompactTextString(m) }
 func (*MultisignInfo) ProtoMessage()    {}
 func (*MultisignInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{27}
+	return fileDescriptor_api_4f28567b98176b45, []int{38}
 }
 func (m *MultisignInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MultisignInfo.Unmarshal(m, b)
@@ -1449,6 +2294,13 @@ func (m *MultisignInfo) GetBroadcast() bool {
 	return false
 }
 
+func (m *MultisignInfo) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
 type RawTx struct {
 	Tx                   []byte   `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1456,97 +2308,316 @@ type RawTx struct {
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RawTx) Reset()         { *m = RawTx{} }
-func (m *RawTx) String() string { return proto.CompactTextString(m) }
-func (*RawTx) ProtoMessage()    {}
-func (*RawTx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{28}
+func (m *RawTx) Reset()         { *m = RawTx{} }
+func (m *RawTx) String() string { return proto.CompactTextString(m) }
+func (*RawTx) ProtoMessage()    {}
+func (*RawTx) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{39}
+}
+func (m *RawTx) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RawTx.Unmarshal(m, b)
+}
+func (m *RawTx) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RawTx.Marshal(b, m, deterministic)
+}
+func (dst *RawTx) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RawTx.Merge(dst, src)
+}
+func (m *RawTx) XXX_Size() int {
+	return xxx_messageInfo_RawTx.Size(m)
+}
+func (m *RawTx) XXX_DiscardUnknown() {
+	xxx_messageInfo_RawTx.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RawTx proto.InternalMessageInfo
+
+func (m *RawTx) GetTx() []byte {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+type EstimateFeeData struct {
+	Coin                 CoinType  `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
+	Inputs               []*Input  `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs              []*Output `protobuf:"bytes,3,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	FeePerByte           uint64    `protobuf:"varint,4,opt,name=feePerByte,proto3" json:"feePerByte,omitempty"`
+	Slip44               uint32    `protobuf:"varint,5,opt,name=slip44,proto3" json:"slip44,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *EstimateFeeData) Reset()         { *m = EstimateFeeData{} }
+func (m *EstimateFeeData) String() string { return proto.CompactTextString(m) }
+func (*EstimateFeeData) ProtoMessage()    {}
+func (*EstimateFeeData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{40}
+}
+func (m *EstimateFeeData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EstimateFeeData.Unmarshal(m, b)
+}
+func (m *EstimateFeeData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EstimateFeeData.Marshal(b, m, deterministic)
+}
+func (dst *EstimateFeeData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EstimateFeeData.Merge(dst, src)
+}
+func (m *EstimateFeeData) XXX_Size() int {
+	return xxx_messageInfo_EstimateFeeData.Size(m)
+}
+func (m *EstimateFeeData) XXX_DiscardUnknown() {
+	xxx_messageInfo_EstimateFeeData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EstimateFeeData proto.InternalMessageInfo
+
+func (m *EstimateFeeData) GetCoin() CoinType {
+	if m != nil {
+		return m.Coin
+	}
+	return CoinType_BITCOIN
+}
+
+func (m *EstimateFeeData) GetInputs() []*Input {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *EstimateFeeData) GetOutputs() []*Output {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *EstimateFeeData) GetFeePerByte() uint64 {
+	if m != nil {
+		return m.FeePerByte
+	}
+	return 0
+}
+
+func (m *EstimateFeeData) GetSlip44() uint32 {
+	if m != nil {
+		return m.Slip44
+	}
+	return 0
+}
+
+type EndpointHealth struct {
+	Url                  string               `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Current              bool                 `protobuf:"varint,2,opt,name=current,proto3" json:"current,omitempty"`
+	Healthy              bool                 `protobuf:"varint,3,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Score                float64              `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	LatencyMillis        uint64               `protobuf:"varint,5,opt,name=latencyMillis,proto3" json:"latencyMillis,omitempty"`
+	ErrorRate            float64              `protobuf:"fixed64,6,opt,name=errorRate,proto3" json:"errorRate,omitempty"`
+	TipHeight            uint32               `protobuf:"varint,7,opt,name=tipHeight,proto3" json:"tipHeight,omitempty"`
+	TipLag               uint32               `protobuf:"varint,8,opt,name=tipLag,proto3" json:"tipLag,omitempty"`
+	LastError            string               `protobuf:"bytes,9,opt,name=lastError,proto3" json:"lastError,omitempty"`
+	LastFailedAt         *timestamp.Timestamp `protobuf:"bytes,10,opt,name=lastFailedAt,proto3" json:"lastFailedAt,omitempty"`
+	NextAvailableAt      *timestamp.Timestamp `protobuf:"bytes,11,opt,name=nextAvailableAt,proto3" json:"nextAvailableAt,omitempty"`
+	LastSeenAt           *timestamp.Timestamp `protobuf:"bytes,12,opt,name=lastSeenAt,proto3" json:"lastSeenAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *EndpointHealth) Reset()         { *m = EndpointHealth{} }
+func (m *EndpointHealth) String() string { return proto.CompactTextString(m) }
+func (*EndpointHealth) ProtoMessage()    {}
+func (*EndpointHealth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{41}
+}
+func (m *EndpointHealth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EndpointHealth.Unmarshal(m, b)
+}
+func (m *EndpointHealth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EndpointHealth.Marshal(b, m, deterministic)
+}
+func (dst *EndpointHealth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndpointHealth.Merge(dst, src)
+}
+func (m *EndpointHealth) XXX_Size() int {
+	return xxx_messageInfo_EndpointHealth.Size(m)
+}
+func (m *EndpointHealth) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndpointHealth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndpointHealth proto.InternalMessageInfo
+
+func (m *EndpointHealth) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *EndpointHealth) GetCurrent() bool {
+	if m != nil {
+		return m.Current
+	}
+	return false
+}
+
+func (m *EndpointHealth) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *EndpointHealth) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *EndpointHealth) GetLatencyMillis() uint64 {
+	if m != nil {
+		return m.LatencyMillis
+	}
+	return 0
+}
+
+func (m *EndpointHealth) GetErrorRate() float64 {
+	if m != nil {
+		return m.ErrorRate
+	}
+	return 0
+}
+
+func (m *EndpointHealth) GetTipHeight() uint32 {
+	if m != nil {
+		return m.TipHeight
+	}
+	return 0
+}
+
+func (m *EndpointHealth) GetTipLag() uint32 {
+	if m != nil {
+		return m.TipLag
+	}
+	return 0
+}
+
+func (m *EndpointHealth) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *EndpointHealth) GetLastFailedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastFailedAt
+	}
+	return nil
+}
+
+func (m *EndpointHealth) GetNextAvailableAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.NextAvailableAt
+	}
+	return nil
+}
+
+func (m *EndpointHealth) GetLastSeenAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastSeenAt
+	}
+	return nil
+}
+
+type EndpointHealthList struct {
+	Endpoints            []*EndpointHealth `protobuf:"bytes,1,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *EndpointHealthList) Reset()         { *m = EndpointHealthList{} }
+func (m *EndpointHealthList) String() string { return proto.CompactTextString(m) }
+func (*EndpointHealthList) ProtoMessage()    {}
+func (*EndpointHealthList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{42}
 }
-func (m *RawTx) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RawTx.Unmarshal(m, b)
+func (m *EndpointHealthList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EndpointHealthList.Unmarshal(m, b)
 }
-func (m *RawTx) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RawTx.Marshal(b, m, deterministic)
+func (m *EndpointHealthList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EndpointHealthList.Marshal(b, m, deterministic)
 }
-func (dst *RawTx) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RawTx.Merge(dst, src)
+func (dst *EndpointHealthList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndpointHealthList.Merge(dst, src)
 }
-func (m *RawTx) XXX_Size() int {
-	return xxx_messageInfo_RawTx.Size(m)
+func (m *EndpointHealthList) XXX_Size() int {
+	return xxx_messageInfo_EndpointHealthList.Size(m)
 }
-func (m *RawTx) XXX_DiscardUnknown() {
-	xxx_messageInfo_RawTx.DiscardUnknown(m)
+func (m *EndpointHealthList) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndpointHealthList.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RawTx proto.InternalMessageInfo
+var xxx_messageInfo_EndpointHealthList proto.InternalMessageInfo
 
-func (m *RawTx) GetTx() []byte {
+func (m *EndpointHealthList) GetEndpoints() []*EndpointHealth {
 	if m != nil {
-		return m.Tx
+		return m.Endpoints
 	}
 	return nil
 }
 
-type EstimateFeeData struct {
-	Coin                 CoinType  `protobuf:"varint,1,opt,name=coin,proto3,enum=pb.CoinType" json:"coin,omitempty"`
-	Inputs               []*Input  `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty"`
-	Outputs              []*Output `protobuf:"bytes,3,rep,name=outputs,proto3" json:"outputs,omitempty"`
-	FeePerByte           uint64    `protobuf:"varint,4,opt,name=feePerByte,proto3" json:"feePerByte,omitempty"`
+// ErrorDetail is attached to the status of a failed call to classify the failure
+type ErrorDetail struct {
+	Kind                 ErrorKind `protobuf:"varint,1,opt,name=kind,proto3,enum=pb.ErrorKind" json:"kind,omitempty"`
+	Reason               string    `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
 	XXX_unrecognized     []byte    `json:"-"`
 	XXX_sizecache        int32     `json:"-"`
 }
 
-func (m *EstimateFeeData) Reset()         { *m = EstimateFeeData{} }
-func (m *EstimateFeeData) String() string { return proto.CompactTextString(m) }
-func (*EstimateFeeData) ProtoMessage()    {}
-func (*EstimateFeeData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_api_2ff753dddd9b028a, []int{29}
-}
-func (m *EstimateFeeData) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_EstimateFeeData.Unmarshal(m, b)
+func (m *ErrorDetail) Reset()         { *m = ErrorDetail{} }
+func (m *ErrorDetail) String() string { return proto.CompactTextString(m) }
+func (*ErrorDetail) ProtoMessage()    {}
+func (*ErrorDetail) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_4f28567b98176b45, []int{43}
 }
-func (m *EstimateFeeData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_EstimateFeeData.Marshal(b, m, deterministic)
+func (m *ErrorDetail) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ErrorDetail.Unmarshal(m, b)
 }
-func (dst *EstimateFeeData) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_EstimateFeeData.Merge(dst, src)
+func (m *ErrorDetail) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ErrorDetail.Marshal(b, m, deterministic)
 }
-func (m *EstimateFeeData) XXX_Size() int {
-	return xxx_messageInfo_EstimateFeeData.Size(m)
+func (dst *ErrorDetail) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorDetail.Merge(dst, src)
 }
-func (m *EstimateFeeData) XXX_DiscardUnknown() {
-	xxx_messageInfo_EstimateFeeData.DiscardUnknown(m)
+func (m *ErrorDetail) XXX_Size() int {
+	return xxx_messageInfo_ErrorDetail.Size(m)
 }
-
-var xxx_messageInfo_EstimateFeeData proto.InternalMessageInfo
-
-func (m *EstimateFeeData) GetCoin() CoinType {
-	if m != nil {
-		return m.Coin
-	}
-	return CoinType_BITCOIN
+func (m *ErrorDetail) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorDetail.DiscardUnknown(m)
 }
 
-func (m *EstimateFeeData) GetInputs() []*Input {
-	if m != nil {
-		return m.Inputs
-	}
-	return nil
-}
+var xxx_messageInfo_ErrorDetail proto.InternalMessageInfo
 
-func (m *EstimateFeeData) GetOutputs() []*Output {
+func (m *ErrorDetail) GetKind() ErrorKind {
 	if m != nil {
-		return m.Outputs
+		return m.Kind
 	}
-	return nil
+	return ErrorKind_UNKNOWN_ERROR
 }
 
-func (m *EstimateFeeData) GetFeePerByte() uint64 {
+func (m *ErrorDetail) GetReason() string {
 	if m != nil {
-		return m.FeePerByte
+		return m.Reason
 	}
-	return 0
+	return ""
 }
 
 func init() {
@@ -1564,13 +2635,25 @@ func init() {
 	proto.RegisterType((*NetParams)(nil), "pb.NetParams")
 	proto.RegisterType((*TransactionList)(nil), "pb.TransactionList")
 	proto.RegisterType((*Tx)(nil), "pb.Tx")
+	proto.RegisterMapType((map[string]float64)(nil), "pb.Tx.FiatValuesEntry")
 	proto.RegisterType((*Txid)(nil), "pb.Txid")
 	proto.RegisterType((*FeeLevelSelection)(nil), "pb.FeeLevelSelection")
 	proto.RegisterType((*FeePerByte)(nil), "pb.FeePerByte")
 	proto.RegisterType((*Fee)(nil), "pb.Fee")
 	proto.RegisterType((*SpendInfo)(nil), "pb.SpendInfo")
+	proto.RegisterType((*Recipient)(nil), "pb.Recipient")
+	proto.RegisterType((*SpendManyInfo)(nil), "pb.SpendManyInfo")
 	proto.RegisterType((*Confirmations)(nil), "pb.Confirmations")
 	proto.RegisterType((*Utxo)(nil), "pb.Utxo")
+	proto.RegisterType((*Outpoint)(nil), "pb.Outpoint")
+	proto.RegisterType((*OutpointSelection)(nil), "pb.OutpointSelection")
+	proto.RegisterType((*SpendOutpointsInfo)(nil), "pb.SpendOutpointsInfo")
+	proto.RegisterType((*UtxoStatus)(nil), "pb.UtxoStatus")
+	proto.RegisterType((*UtxoList)(nil), "pb.UtxoList")
+	proto.RegisterType((*ConsolidateInfo)(nil), "pb.ConsolidateInfo")
+	proto.RegisterType((*ConsolidationPlan)(nil), "pb.ConsolidationPlan")
+	proto.RegisterType((*TokenBalance)(nil), "pb.TokenBalance")
+	proto.RegisterType((*TokenBalanceList)(nil), "pb.TokenBalanceList")
 	proto.RegisterType((*SweepInfo)(nil), "pb.SweepInfo")
 	proto.RegisterType((*Input)(nil), "pb.Input")
 	proto.RegisterType((*Output)(nil), "pb.Output")
@@ -1580,9 +2663,13 @@ func init() {
 	proto.RegisterType((*MultisignInfo)(nil), "pb.MultisignInfo")
 	proto.RegisterType((*RawTx)(nil), "pb.RawTx")
 	proto.RegisterType((*EstimateFeeData)(nil), "pb.EstimateFeeData")
+	proto.RegisterType((*EndpointHealth)(nil), "pb.EndpointHealth")
+	proto.RegisterType((*EndpointHealthList)(nil), "pb.EndpointHealthList")
+	proto.RegisterType((*ErrorDetail)(nil), "pb.ErrorDetail")
 	proto.RegisterEnum("pb.CoinType", CoinType_name, CoinType_value)
 	proto.RegisterEnum("pb.KeyPurpose", KeyPurpose_name, KeyPurpose_value)
 	proto.RegisterEnum("pb.FeeLevel", FeeLevel_name, FeeLevel_value)
+	proto.RegisterEnum("pb.ErrorKind", ErrorKind_name, ErrorKind_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1610,7 +2697,10 @@ type APIClient interface {
 	GetTransaction(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Tx, error)
 	GetFeePerByte(ctx context.Context, in *FeeLevelSelection, opts ...grpc.CallOption) (*FeePerByte, error)
 	Spend(ctx context.Context, in *SpendInfo, opts ...grpc.CallOption) (*Txid, error)
+	SpendMany(ctx context.Context, in *SpendManyInfo, opts ...grpc.CallOption) (*Txid, error)
+	SpendOutpoints(ctx context.Context, in *SpendOutpointsInfo, opts ...grpc.CallOption) (*Txid, error)
 	BumpFee(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Txid, error)
+	AbandonTransaction(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Empty, error)
 	AddWatchedScript(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Empty, error)
 	GetConfirmations(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Confirmations, error)
 	SweepAddress(ctx context.Context, in *SweepInfo, opts ...grpc.CallOption) (*Txid, error)
@@ -1622,6 +2712,12 @@ type APIClient interface {
 	ListAddresses(ctx context.Context, in *CoinSelection, opts ...grpc.CallOption) (*Addresses, error)
 	WalletNotify(ctx context.Context, in *CoinSelection, opts ...grpc.CallOption) (API_WalletNotifyClient, error)
 	DumpTables(ctx context.Context, in *CoinSelection, opts ...grpc.CallOption) (API_DumpTablesClient, error)
+	EndpointHealth(ctx context.Context, in *CoinSelection, opts ...grpc.CallOption) (*EndpointHealthList, error)
+	ListUtxos(ctx context.Context, in *CoinSelection, opts ...grpc.CallOption) (*UtxoList, error)
+	FreezeUtxo(ctx context.Context, in *OutpointSelection, opts ...grpc.CallOption) (*Empty, error)
+	UnfreezeUtxo(ctx context.Context, in *OutpointSelection, opts ...grpc.CallOption) (*Empty, error)
+	Consolidate(ctx context.Context, in *ConsolidateInfo, opts ...grpc.CallOption) (*ConsolidationPlan, error)
+	TokenBalances(ctx context.Context, in *CoinSelection, opts ...grpc.CallOption) (*TokenBalanceList, error)
 }
 
 type aPIClient struct {
@@ -1749,6 +2845,24 @@ func (c *aPIClient) Spend(ctx context.Context, in *SpendInfo, opts ...grpc.CallO
 	return out, nil
 }
 
+func (c *aPIClient) SpendMany(ctx context.Context, in *SpendManyInfo, opts ...grpc.CallOption) (*Txid, error) {
+	out := new(Txid)
+	err := c.cc.Invoke(ctx, "/pb.API/SpendMany", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SpendOutpoints(ctx context.Context, in *SpendOutpointsInfo, opts ...grpc.CallOption) (*Txid, error) {
+	out := new(Txid)
+	err := c.cc.Invoke(ctx, "/pb.API/SpendOutpoints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIClient) BumpFee(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Txid, error) {
 	out := new(Txid)
 	err := c.cc.Invoke(ctx, "/pb.API/BumpFee", in, out, opts...)
@@ -1758,6 +2872,15 @@ func (c *aPIClient) BumpFee(ctx context.Context, in *Txid, opts ...grpc.CallOpti
 	return out, nil
 }
 
+func (c *aPIClient) AbandonTransaction(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.API/AbandonTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIClient) AddWatchedScript(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
 	err := c.cc.Invoke(ctx, "/pb.API/AddWatchedScript", in, out, opts...)
@@ -1903,6 +3026,60 @@ func (x *aPIDumpTablesClient) Recv() (*Row, error) {
 	return m, nil
 }
 
+func (c *aPIClient) EndpointHealth(ctx context.Context, in *CoinSelection, opts ...grpc.CallOption) (*EndpointHealthList, error) {
+	out := new(EndpointHealthList)
+	err := c.cc.Invoke(ctx, "/pb.API/EndpointHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListUtxos(ctx context.Context, in *CoinSelection, opts ...grpc.CallOption) (*UtxoList, error) {
+	out := new(UtxoList)
+	err := c.cc.Invoke(ctx, "/pb.API/ListUtxos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) FreezeUtxo(ctx context.Context, in *OutpointSelection, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.API/FreezeUtxo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) UnfreezeUtxo(ctx context.Context, in *OutpointSelection, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.API/UnfreezeUtxo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Consolidate(ctx context.Context, in *ConsolidateInfo, opts ...grpc.CallOption) (*ConsolidationPlan, error) {
+	out := new(ConsolidationPlan)
+	err := c.cc.Invoke(ctx, "/pb.API/Consolidate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) TokenBalances(ctx context.Context, in *CoinSelection, opts ...grpc.CallOption) (*TokenBalanceList, error) {
+	out := new(TokenBalanceList)
+	err := c.cc.Invoke(ctx, "/pb.API/TokenBalances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // APIServer is the server API for API service.
 type APIServer interface {
 	Stop(context.Context, *Empty) (*Empty, error)
@@ -1918,7 +3095,10 @@ type APIServer interface {
 	GetTransaction(context.Context, *Txid) (*Tx, error)
 	GetFeePerByte(context.Context, *FeeLevelSelection) (*FeePerByte, error)
 	Spend(context.Context, *SpendInfo) (*Txid, error)
+	SpendMany(context.Context, *SpendManyInfo) (*Txid, error)
+	SpendOutpoints(context.Context, *SpendOutpointsInfo) (*Txid, error)
 	BumpFee(context.Context, *Txid) (*Txid, error)
+	AbandonTransaction(context.Context, *Txid) (*Empty, error)
 	AddWatchedScript(context.Context, *Address) (*Empty, error)
 	GetConfirmations(context.Context, *Txid) (*Confirmations, error)
 	SweepAddress(context.Context, *SweepInfo) (*Txid, error)
@@ -1930,6 +3110,12 @@ type APIServer interface {
 	ListAddresses(context.Context, *CoinSelection) (*Addresses, error)
 	WalletNotify(*CoinSelection, API_WalletNotifyServer) error
 	DumpTables(*CoinSelection, API_DumpTablesServer) error
+	EndpointHealth(context.Context, *CoinSelection) (*EndpointHealthList, error)
+	ListUtxos(context.Context, *CoinSelection) (*UtxoList, error)
+	FreezeUtxo(context.Context, *OutpointSelection) (*Empty, error)
+	UnfreezeUtxo(context.Context, *OutpointSelection) (*Empty, error)
+	Consolidate(context.Context, *ConsolidateInfo) (*ConsolidationPlan, error)
+	TokenBalances(context.Context, *CoinSelection) (*TokenBalanceList, error)
 }
 
 func RegisterAPIServer(s *grpc.Server, srv APIServer) {
@@ -2170,6 +3356,42 @@ func _API_Spend_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_SpendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpendManyInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).SpendMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/SpendMany",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).SpendMany(ctx, req.(*SpendManyInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_SpendOutpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpendOutpointsInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).SpendOutpoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/SpendOutpoints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).SpendOutpoints(ctx, req.(*SpendOutpointsInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Txid)
 	if err := dec(in); err != nil {
@@ -2188,6 +3410,24 @@ func _API_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_AbandonTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Txid)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).AbandonTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/AbandonTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).AbandonTransaction(ctx, req.(*Txid))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_AddWatchedScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Address)
 	if err := dec(in); err != nil {
@@ -2392,6 +3632,114 @@ func (x *aPIDumpTablesServer) Send(m *Row) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _API_EndpointHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoinSelection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).EndpointHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/EndpointHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).EndpointHealth(ctx, req.(*CoinSelection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_ListUtxos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoinSelection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ListUtxos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/ListUtxos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ListUtxos(ctx, req.(*CoinSelection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_FreezeUtxo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutpointSelection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).FreezeUtxo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/FreezeUtxo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).FreezeUtxo(ctx, req.(*OutpointSelection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_UnfreezeUtxo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutpointSelection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).UnfreezeUtxo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/UnfreezeUtxo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).UnfreezeUtxo(ctx, req.(*OutpointSelection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_Consolidate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsolidateInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).Consolidate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/Consolidate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Consolidate(ctx, req.(*ConsolidateInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_TokenBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoinSelection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).TokenBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/TokenBalances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).TokenBalances(ctx, req.(*CoinSelection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _API_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.API",
 	HandlerType: (*APIServer)(nil),
@@ -2448,10 +3796,22 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Spend",
 			Handler:    _API_Spend_Handler,
 		},
+		{
+			MethodName: "SpendMany",
+			Handler:    _API_SpendMany_Handler,
+		},
+		{
+			MethodName: "SpendOutpoints",
+			Handler:    _API_SpendOutpoints_Handler,
+		},
 		{
 			MethodName: "BumpFee",
 			Handler:    _API_BumpFee_Handler,
 		},
+		{
+			MethodName: "AbandonTransaction",
+			Handler:    _API_AbandonTransaction_Handler,
+		},
 		{
 			MethodName: "AddWatchedScript",
 			Handler:    _API_AddWatchedScript_Handler,
@@ -2488,6 +3848,30 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListAddresses",
 			Handler:    _API_ListAddresses_Handler,
 		},
+		{
+			MethodName: "EndpointHealth",
+			Handler:    _API_EndpointHealth_Handler,
+		},
+		{
+			MethodName: "ListUtxos",
+			Handler:    _API_ListUtxos_Handler,
+		},
+		{
+			MethodName: "FreezeUtxo",
+			Handler:    _API_FreezeUtxo_Handler,
+		},
+		{
+			MethodName: "UnfreezeUtxo",
+			Handler:    _API_UnfreezeUtxo_Handler,
+		},
+		{
+			MethodName: "Consolidate",
+			Handler:    _API_Consolidate_Handler,
+		},
+		{
+			MethodName: "TokenBalances",
+			Handler:    _API_TokenBalances_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2504,99 +3888,162 @@ var _API_serviceDesc = grpc.ServiceDesc{
 	Metadata: "api.proto",
 }
 
-func init() { proto.RegisterFile("api.proto", fileDescriptor_api_2ff753dddd9b028a) }
-
-var fileDescriptor_api_2ff753dddd9b028a = []byte{
-	// 1449 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0xcd, 0x72, 0xdb, 0x46,
-	0x12, 0xe6, 0x0f, 0xf8, 0x83, 0x16, 0x28, 0xd3, 0xb3, 0xbb, 0x36, 0x57, 0xeb, 0x92, 0xe9, 0x59,
-	0x1f, 0x64, 0xad, 0x57, 0xb6, 0xe8, 0x4a, 0xca, 0x87, 0xa4, 0x5c, 0x12, 0xad, 0x1f, 0x46, 0x12,
-	0xc5, 0x1a, 0xd1, 0xe5, 0xc4, 0x17, 0xd7, 0x90, 0x68, 0x49, 0x28, 0x93, 0x00, 0x0a, 0x18, 0x58,
-	0xe4, 0x3d, 0x8f, 0x91, 0x5c, 0xf2, 0x04, 0x79, 0x8b, 0x3c, 0x41, 0xde, 0x27, 0x35, 0x83, 0x01,
-	0x01, 0xc8, 0xb4, 0x2d, 0xe7, 0xe0, 0x5b, 0x4f, 0xf7, 0x87, 0x99, 0xee, 0xaf, 0x7b, 0x7a, 0x1a,
-	0x60, 0x72, 0xdf, 0xd9, 0xf2, 0x03, 0x4f, 0x78, 0xa4, 0xe4, 0x8f, 0xd6, 0xee, 0x5f, 0x78, 0xde,
-	0xc5, 0x04, 0x9f, 0x28, 0xcd, 0x28, 0x3a, 0x7f, 0x22, 0x9c, 0x29, 0x86, 0x82, 0x4f, 0xfd, 0x18,
-	0x44, 0x6b, 0x50, 0xd9, 0x9b, 0xfa, 0x62, 0x4e, 0xb7, 0xa1, 0xd1, 0xf5, 0x1c, 0xf7, 0x0c, 0x27,
-	0x38, 0x16, 0x8e, 0xe7, 0x92, 0x36, 0x18, 0x63, 0xcf, 0x71, 0x5b, 0xc5, 0x76, 0x71, 0x63, 0xb5,
-	0x63, 0x6d, 0xf9, 0xa3, 0x2d, 0x09, 0x18, 0xce, 0x7d, 0x64, 0xca, 0x42, 0xff, 0x0d, 0x65, 0xe6,
-	0x5d, 0x11, 0x02, 0x86, 0xcd, 0x05, 0x57, 0x40, 0x93, 0x29, 0x99, 0xbe, 0x01, 0xeb, 0x08, 0xe7,
-	0x5f, 0xb0, 0x19, 0xd9, 0x80, 0x9a, 0x1f, 0x05, 0xbe, 0x17, 0x62, 0xab, 0xa4, 0x40, 0xab, 0x12,
-	0x74, 0x84, 0xf3, 0x41, 0xac, 0x65, 0x89, 0x99, 0xbe, 0x80, 0xda, 0x8e, 0x6d, 0x07, 0x18, 0x86,
-	0x37, 0xd8, 0x96, 0x80, 0xc1, 0x6d, 0x3b, 0x50, 0x7b, 0x9a, 0x4c, 0xc9, 0xb4, 0x0d, 0xd5, 0x43,
-	0x74, 0x2e, 0x2e, 0x05, 0xb9, 0x03, 0xd5, 0x4b, 0x25, 0xa9, 0x1d, 0x1a, 0x4c, 0xaf, 0xe8, 0x0f,
-	0x50, 0xdf, 0xe5, 0x13, 0xee, 0x8e, 0x31, 0x24, 0xf7, 0xc0, 0x1c, 0x7b, 0xee, 0xb9, 0x13, 0x4c,
-	0xd1, 0x56, 0x30, 0x83, 0xa5, 0x0a, 0xd2, 0x86, 0x95, 0xc8, 0x4d, 0xed, 0x25, 0x65, 0xcf, 0xaa,
-	0xe8, 0x5d, 0x28, 0x1f, 0xe1, 0x9c, 0x34, 0xa1, 0xfc, 0x0e, 0xe7, 0x9a, 0x24, 0x29, 0xd2, 0xff,
-	0x82, 0x71, 0x84, 0xf3, 0x90, 0xfc, 0x07, 0x8c, 0x77, 0x38, 0x0f, 0x5b, 0xc5, 0x76, 0x79, 0x63,
-	0xa5, 0x53, 0xd3, 0x61, 0x33, 0xa5, 0xa4, 0xdf, 0x82, 0xa9, 0x83, 0xc5, 0x90, 0x3c, 0x02, 0x93,
-	0x27, 0x0b, 0x0d, 0x5f, 0x91, 0x70, 0x8d, 0x60, 0xa9, 0x95, 0x52, 0xb0, 0x76, 0x3d, 0x6f, 0xc2,
-	0x30, 0xf4, 0x3d, 0x37, 0x44, 0xc9, 0xc3, 0xc8, 0xf3, 0x26, 0xea, 0xfc, 0x3a, 0x53, 0x32, 0xbd,
-	0x0f, 0x66, 0x1f, 0xc5, 0x80, 0x07, 0x7c, 0x1a, 0x4a, 0x80, 0xcb, 0xa7, 0x98, 0x64, 0x51, 0xca,
-	0xf4, 0x7b, 0xb8, 0x35, 0x0c, 0xb8, 0x1b, 0x72, 0x95, 0xc4, 0x63, 0x27, 0x14, 0x64, 0x13, 0x2c,
-	0x91, 0xaa, 0x12, 0x2f, 0xaa, 0xd2, 0x8b, 0xe1, 0x8c, 0xe5, 0x6c, 0xf4, 0xf7, 0x22, 0x94, 0x86,
-	0x33, 0xb9, 0xb3, 0x98, 0x39, 0x76, 0xb2, 0xb3, 0x94, 0xc9, 0x3f, 0xa1, 0xf2, 0x9e, 0x4f, 0xa2,
-	0x38, 0xd7, 0x65, 0x16, 0x2f, 0x32, 0xe9, 0x28, 0xb7, 0x8b, 0x1b, 0x95, 0x24, 0x1d, 0xe4, 0x39,
-	0x98, 0x8b, 0xba, 0x6d, 0x19, 0xed, 0xe2, 0xc6, 0x4a, 0x67, 0x6d, 0x2b, 0xae, 0xec, 0xad, 0xa4,
-	0xb2, 0xb7, 0x86, 0x09, 0x82, 0xa5, 0x60, 0x99, 0xbc, 0x2b, 0x2e, 0xc6, 0x97, 0xa7, 0xee, 0x64,
-	0xde, 0xaa, 0xa8, 0xd8, 0x53, 0x85, 0xcc, 0x49, 0xc0, 0xaf, 0x5a, 0xd5, 0x76, 0x71, 0xc3, 0x62,
-	0x52, 0xa4, 0xdf, 0x81, 0x31, 0x94, 0xfe, 0xdd, 0xa8, 0xb0, 0x2e, 0x79, 0x78, 0x99, 0x14, 0x96,
-	0x94, 0xe9, 0x5b, 0xb8, 0xbd, 0x8f, 0x78, 0x8c, 0xef, 0x71, 0xf2, 0x65, 0xa5, 0x5f, 0x3f, 0xd7,
-	0x9f, 0xe9, 0xda, 0x57, 0xa8, 0x64, 0x2b, 0xb6, 0xb0, 0xd2, 0x75, 0x80, 0x7d, 0xc4, 0x01, 0x06,
-	0xbb, 0x73, 0x81, 0xd2, 0xfd, 0x73, 0x44, 0x5d, 0x93, 0x52, 0x94, 0xb5, 0xb6, 0x8f, 0xcb, 0x0c,
-	0xbf, 0x16, 0xc1, 0x3c, 0xf3, 0xd1, 0xb5, 0x7b, 0xee, 0xb9, 0x77, 0x03, 0x97, 0x5a, 0x50, 0xd3,
-	0xb5, 0xa4, 0x03, 0x4c, 0x96, 0x32, 0x47, 0x7c, 0xea, 0x45, 0x6e, 0x9c, 0x23, 0x83, 0xe9, 0x55,
-	0x2e, 0x08, 0xe3, 0x53, 0x41, 0x48, 0xe6, 0xa6, 0x38, 0xf5, 0x54, 0x3a, 0x4c, 0xa6, 0x64, 0xfa,
-	0x8d, 0xec, 0x3e, 0xea, 0xc6, 0x70, 0x55, 0x3b, 0xe4, 0x21, 0x34, 0xc6, 0x59, 0x85, 0xbe, 0xa0,
-	0x79, 0x25, 0xdd, 0x07, 0xe3, 0x95, 0x98, 0x79, 0x1f, 0x2b, 0x31, 0xc7, 0xb5, 0x71, 0xa6, 0x02,
-	0x68, 0xb0, 0x78, 0x91, 0x16, 0x5e, 0xec, 0x7d, 0xbc, 0xa0, 0x7f, 0x48, 0x7a, 0xae, 0x10, 0xfd,
-	0x1b, 0xd2, 0xb3, 0x0e, 0x95, 0x48, 0xcc, 0x3c, 0x49, 0x8e, 0x2c, 0xff, 0xba, 0x84, 0x48, 0x47,
-	0x58, 0xac, 0xce, 0xd2, 0x57, 0xce, 0xd3, 0xa7, 0xdb, 0x80, 0xb1, 0x68, 0x03, 0x84, 0x82, 0x15,
-	0xa0, 0x8d, 0x38, 0x3d, 0x1b, 0x07, 0x8e, 0x2f, 0x14, 0x2d, 0x16, 0xcb, 0xe9, 0x72, 0xe4, 0x56,
-	0x3f, 0x59, 0x21, 0xdb, 0x50, 0xe9, 0xb9, 0x7e, 0x24, 0x6e, 0x4e, 0x09, 0xdd, 0x85, 0xea, 0x69,
-	0x24, 0xe4, 0x37, 0x14, 0xac, 0x50, 0x1d, 0x38, 0x88, 0x46, 0x47, 0xba, 0x59, 0x59, 0x2c, 0xa7,
-	0xcb, 0xdf, 0xdc, 0x05, 0x81, 0x2f, 0xc0, 0x3c, 0x73, 0x2e, 0x5c, 0x2e, 0xa2, 0x00, 0xd3, 0x63,
-	0x8a, 0x59, 0xe6, 0xef, 0x81, 0x19, 0x26, 0x10, 0xf5, 0xb1, 0xc5, 0x52, 0x05, 0xfd, 0xb3, 0x08,
-	0xa4, 0x1b, 0x20, 0x17, 0x78, 0x12, 0x4d, 0x84, 0x13, 0x3a, 0x17, 0x37, 0x4c, 0xc5, 0x03, 0xa8,
-	0x3a, 0x32, 0xe0, 0x24, 0x17, 0xa6, 0xc4, 0x28, 0x0a, 0x98, 0x36, 0x90, 0x87, 0x50, 0xf3, 0x54,
-	0x80, 0x32, 0x1b, 0x12, 0x03, 0x12, 0x13, 0xc7, 0xcc, 0x12, 0xd3, 0xdf, 0xcc, 0xcc, 0x3a, 0xc0,
-	0xf9, 0xe2, 0x46, 0xaa, 0xdc, 0x18, 0x2c, 0xa3, 0xa1, 0x1d, 0x68, 0x2c, 0x88, 0x51, 0x0d, 0xf4,
-	0x01, 0x18, 0xa1, 0x73, 0x91, 0x34, 0xce, 0x86, 0xf4, 0x64, 0x01, 0x60, 0xca, 0x44, 0x7f, 0x2b,
-	0x41, 0x23, 0x61, 0xc1, 0xfd, 0xda, 0x34, 0xc4, 0xfe, 0x6d, 0xb7, 0x8c, 0x8f, 0xf9, 0xb7, 0xad,
-	0x21, 0x9d, 0x56, 0xe5, 0x63, 0x90, 0xce, 0x07, 0xd4, 0x55, 0x3f, 0x4b, 0x5d, 0xed, 0x3a, 0x75,
-	0xb2, 0x60, 0x46, 0x81, 0xc7, 0xed, 0x31, 0x0f, 0x45, 0xab, 0x1e, 0xf7, 0xee, 0x85, 0x82, 0xde,
-	0x85, 0x0a, 0xe3, 0x57, 0xc3, 0x19, 0x59, 0x85, 0x92, 0x98, 0xe9, 0x52, 0x2d, 0x89, 0x19, 0xfd,
-	0xa5, 0x08, 0xb7, 0xf6, 0x42, 0xe1, 0x4c, 0xb9, 0xc0, 0x7d, 0xc4, 0x97, 0x5c, 0xf0, 0xaf, 0xc9,
-	0x5f, 0x3e, 0x2a, 0xe3, 0x7a, 0x54, 0x9b, 0x03, 0xa8, 0x27, 0x47, 0x93, 0x15, 0xa8, 0xed, 0xf6,
-	0x86, 0xdd, 0xd3, 0x5e, 0xbf, 0x59, 0x20, 0x4d, 0xb0, 0xf4, 0xe2, 0x6d, 0x77, 0xe7, 0xec, 0xb0,
-	0x59, 0x24, 0x26, 0x54, 0xde, 0x28, 0xb1, 0x44, 0x2c, 0xa8, 0x1f, 0xf7, 0x86, 0x7b, 0x0a, 0x5a,
-	0x96, 0xab, 0xbd, 0xe1, 0xe1, 0x1e, 0xdb, 0x7b, 0x75, 0xd2, 0x34, 0x36, 0x37, 0x00, 0xd2, 0x31,
-	0x49, 0xda, 0x7a, 0xfd, 0xe1, 0x1e, 0xeb, 0xef, 0x1c, 0x37, 0x0b, 0x0a, 0xf9, 0xa3, 0x5e, 0x15,
-	0x37, 0x3b, 0x50, 0x4f, 0x5a, 0x86, 0xb2, 0x74, 0x4f, 0xfb, 0xa7, 0x27, 0xbd, 0x6e, 0xb3, 0x40,
-	0x00, 0xaa, 0xfd, 0x53, 0x76, 0x22, 0x51, 0xd2, 0x32, 0x60, 0xbd, 0x53, 0xd6, 0x1b, 0xfe, 0xd4,
-	0x2c, 0x75, 0x7e, 0x36, 0xa1, 0xbc, 0x33, 0xe8, 0x91, 0x75, 0x30, 0xce, 0x84, 0xe7, 0x13, 0x45,
-	0x8c, 0x1a, 0x19, 0xd7, 0x52, 0x91, 0x16, 0xc8, 0x36, 0xac, 0x76, 0xa3, 0x20, 0x40, 0x57, 0x24,
-	0xc3, 0x59, 0x53, 0x4f, 0x32, 0x8b, 0xa7, 0x70, 0x2d, 0x3b, 0xac, 0xd0, 0x02, 0xf9, 0x3f, 0x40,
-	0x1f, 0xaf, 0x6e, 0x0c, 0xff, 0x1f, 0xd4, 0xbb, 0x97, 0xdc, 0x71, 0x87, 0x8e, 0x4f, 0x6e, 0x27,
-	0x29, 0x4c, 0xd1, 0x2a, 0x1b, 0xf1, 0x5c, 0x47, 0x0b, 0xe4, 0x31, 0xd4, 0xf4, 0x04, 0xb7, 0x0c,
-	0xab, 0x2a, 0x20, 0x99, 0xf0, 0x68, 0x81, 0x3c, 0x85, 0xe6, 0x09, 0x0f, 0x05, 0x06, 0x83, 0xc0,
-	0x79, 0xcf, 0x05, 0xca, 0x46, 0xb7, 0xe4, 0xb3, 0x64, 0x36, 0xa3, 0x05, 0xf2, 0x04, 0x6e, 0xe9,
-	0x2f, 0xa2, 0xd1, 0xc4, 0x19, 0x7f, 0xfe, 0x83, 0x47, 0x50, 0x3d, 0xe4, 0xa1, 0xc4, 0x65, 0xc3,
-	0x5a, 0x53, 0x51, 0x67, 0x27, 0x35, 0x5a, 0x20, 0x0f, 0xa1, 0xaa, 0x87, 0xb2, 0x0c, 0xd9, 0xea,
-	0x9a, 0x2d, 0xc6, 0x35, 0x5a, 0x20, 0xcf, 0xc1, 0xca, 0x0c, 0x67, 0xe1, 0xb2, 0xe3, 0xff, 0xa1,
-	0xc6, 0xb2, 0xfc, 0x04, 0xa7, 0xf6, 0x5f, 0x3d, 0x40, 0x91, 0xd1, 0x93, 0x7a, 0x3c, 0xbf, 0x39,
-	0xf6, 0x9a, 0x9e, 0xe4, 0xd4, 0xfe, 0x8d, 0x03, 0x14, 0x99, 0x71, 0xe3, 0x5f, 0xd9, 0x27, 0x27,
-	0x3d, 0x64, 0x55, 0xab, 0x93, 0x8e, 0x57, 0x20, 0x14, 0x2a, 0x6a, 0xd6, 0x20, 0x71, 0x6b, 0x48,
-	0xc6, 0x8e, 0xb5, 0xc5, 0x29, 0xb4, 0x40, 0xee, 0x43, 0x6d, 0x37, 0x9a, 0xfa, 0x72, 0x5a, 0x49,
-	0x0f, 0xcf, 0x02, 0x1e, 0x43, 0x73, 0xc7, 0xb6, 0x5f, 0xcb, 0x59, 0x0d, 0x6d, 0xdd, 0x31, 0x72,
-	0xcc, 0x5d, 0xab, 0xbe, 0xe6, 0x01, 0x8a, 0xfc, 0x08, 0x91, 0xee, 0xab, 0xa9, 0xc9, 0x4e, 0x0e,
-	0x32, 0x21, 0x96, 0x7a, 0xf2, 0x93, 0xfa, 0x8b, 0x9d, 0x4d, 0x86, 0x80, 0x9c, 0x2f, 0xfb, 0x70,
-	0x37, 0xff, 0x36, 0xa5, 0x6f, 0xdd, 0x1d, 0xb5, 0xf5, 0x07, 0x0f, 0x57, 0x7c, 0x64, 0xae, 0xf3,
-	0xab, 0x0a, 0x36, 0x17, 0x7d, 0x3d, 0xce, 0x57, 0xae, 0xcd, 0xc7, 0x21, 0xa9, 0xae, 0xa6, 0x6e,
-	0xc7, 0x4a, 0xa6, 0x8d, 0x11, 0x95, 0xcb, 0x6b, 0x7d, 0x2d, 0xae, 0xaf, 0x7d, 0x94, 0xa4, 0xb7,
-	0xa1, 0x7a, 0x80, 0xe2, 0x83, 0xfa, 0xca, 0x55, 0x60, 0x5d, 0xfa, 0xa1, 0xfe, 0x39, 0x96, 0x14,
-	0x4b, 0x5d, 0x23, 0x25, 0x37, 0xcf, 0xa0, 0x21, 0xa1, 0xe9, 0x9f, 0xc7, 0x12, 0x7c, 0x23, 0x73,
-	0x0c, 0xc6, 0xd7, 0xd9, 0x7a, 0xcd, 0x27, 0x13, 0x14, 0x7d, 0x4f, 0x38, 0xe7, 0x4b, 0xef, 0xc3,
-	0xa2, 0xba, 0x9e, 0x16, 0xc9, 0x63, 0x80, 0x97, 0xd1, 0xd4, 0x1f, 0xf2, 0xd1, 0x64, 0xf9, 0x01,
-	0xca, 0x75, 0xe6, 0x5d, 0x49, 0xf4, 0xa8, 0xaa, 0xe6, 0xfc, 0x67, 0x7f, 0x05, 0x00, 0x00, 0xff,
-	0xff, 0x87, 0x69, 0x86, 0xca, 0xe0, 0x0e, 0x00, 0x00,
+func init() { proto.RegisterFile("api.proto", fileDescriptor_api_4f28567b98176b45) }
+
+var fileDescriptor_api_4f28567b98176b45 = []byte{
+	// 2458 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0x4b, 0x73, 0xdb, 0xc8,
+	0x11, 0xe6, 0x03, 0x7c, 0xa0, 0x49, 0x4a, 0xf4, 0xac, 0x57, 0x66, 0x14, 0x97, 0xad, 0x45, 0x5c,
+	0x29, 0xad, 0xd6, 0x91, 0x6d, 0xad, 0xb3, 0xe5, 0x72, 0xca, 0x49, 0x28, 0x3e, 0x24, 0xae, 0x24,
+	0x52, 0x35, 0x84, 0xec, 0x6c, 0x2e, 0xaa, 0x11, 0x39, 0x94, 0x50, 0x06, 0x01, 0x14, 0x30, 0xb0,
+	0x44, 0xff, 0x92, 0x1c, 0x73, 0xcb, 0x1f, 0xc8, 0x29, 0xb7, 0x5c, 0xf2, 0x27, 0x92, 0xaa, 0x54,
+	0x4e, 0x39, 0xe5, 0x3f, 0xa4, 0x66, 0x30, 0x43, 0x00, 0x92, 0x68, 0x53, 0x49, 0xd5, 0xde, 0xa6,
+	0x1f, 0xf3, 0xea, 0xfe, 0x7a, 0xba, 0xa7, 0x41, 0x27, 0x9e, 0xb5, 0xed, 0xf9, 0x2e, 0x73, 0x51,
+	0xce, 0x3b, 0x5b, 0x7f, 0x7c, 0xee, 0xba, 0xe7, 0x36, 0x7d, 0x26, 0x38, 0x67, 0xe1, 0xe4, 0x19,
+	0xb3, 0xa6, 0x34, 0x60, 0x64, 0xea, 0x45, 0x4a, 0x46, 0x09, 0x0a, 0x9d, 0xa9, 0xc7, 0x66, 0x46,
+	0x0f, 0x6a, 0x2d, 0xd7, 0x72, 0x86, 0xd4, 0xa6, 0x23, 0x66, 0xb9, 0x0e, 0xda, 0x00, 0x6d, 0xe4,
+	0x5a, 0x4e, 0x23, 0xbb, 0x91, 0xdd, 0x5c, 0xd9, 0xa9, 0x6e, 0x7b, 0x67, 0xdb, 0x5c, 0xc1, 0x9c,
+	0x79, 0x14, 0x0b, 0x09, 0x5a, 0x83, 0x62, 0x60, 0x5b, 0xde, 0xcb, 0x97, 0x8d, 0xdc, 0x46, 0x76,
+	0xb3, 0x86, 0x25, 0x65, 0xfc, 0x04, 0xf2, 0xd8, 0xbd, 0x44, 0x08, 0xb4, 0x31, 0x61, 0x44, 0x2c,
+	0xa0, 0x63, 0x31, 0x36, 0x7c, 0xa8, 0x1e, 0xd0, 0xd9, 0x5d, 0x36, 0xd9, 0x84, 0x92, 0x17, 0xfa,
+	0x9e, 0x1b, 0x50, 0xb1, 0xcb, 0xca, 0xce, 0x0a, 0x57, 0x3a, 0xa0, 0xb3, 0xe3, 0x88, 0x8b, 0x95,
+	0x38, 0x71, 0x9c, 0x7c, 0xea, 0x38, 0xef, 0xa0, 0xd4, 0x1c, 0x8f, 0x7d, 0x1a, 0x04, 0x4b, 0x6c,
+	0x87, 0x40, 0x23, 0xe3, 0xb1, 0x2f, 0xf6, 0xd2, 0xb1, 0x18, 0x2f, 0x5c, 0x78, 0x03, 0x8a, 0xfb,
+	0xd4, 0x3a, 0xbf, 0x60, 0x5c, 0xe3, 0x42, 0x8c, 0xc4, 0xca, 0x35, 0x2c, 0x29, 0xe3, 0x7b, 0x28,
+	0xef, 0x12, 0x9b, 0x38, 0x23, 0x1a, 0xa0, 0x87, 0xa0, 0x8f, 0x5c, 0x67, 0x62, 0xf9, 0x53, 0x3a,
+	0x16, 0x6a, 0x1a, 0x8e, 0x19, 0x68, 0x03, 0x2a, 0xa1, 0x13, 0xcb, 0x73, 0x42, 0x9e, 0x64, 0x19,
+	0x0f, 0x20, 0x7f, 0x40, 0x67, 0xa8, 0x0e, 0xf9, 0xf7, 0x74, 0x26, 0x8d, 0xca, 0x87, 0xc6, 0xcf,
+	0x40, 0x3b, 0xa0, 0xb3, 0x00, 0xfd, 0x14, 0xb4, 0xf7, 0x74, 0x16, 0x34, 0xb2, 0x1b, 0xf9, 0xcd,
+	0xca, 0x4e, 0x49, 0x9a, 0x09, 0x0b, 0xa6, 0xf1, 0x1d, 0xe8, 0xd2, 0x08, 0x34, 0x40, 0x5f, 0x83,
+	0x4e, 0x14, 0x21, 0xd5, 0x2b, 0x5c, 0x5d, 0x6a, 0xe0, 0x58, 0x6a, 0x18, 0x50, 0xdd, 0x75, 0x5d,
+	0x1b, 0xd3, 0xc0, 0x73, 0x9d, 0x80, 0x72, 0xfb, 0x9c, 0xb9, 0xae, 0x2d, 0xf6, 0x2f, 0x63, 0x31,
+	0x36, 0x1e, 0x83, 0xde, 0xa7, 0xec, 0x98, 0xf8, 0x64, 0x1a, 0x70, 0x05, 0x87, 0x4c, 0xa9, 0xf2,
+	0x3a, 0x1f, 0x1b, 0x6f, 0x60, 0xd5, 0xf4, 0x89, 0x13, 0x10, 0xe1, 0xf4, 0x43, 0x2b, 0x60, 0x68,
+	0x0b, 0xaa, 0x2c, 0x66, 0xa9, 0x53, 0x14, 0xf9, 0x29, 0xcc, 0x2b, 0x9c, 0x92, 0x19, 0x7f, 0xca,
+	0x41, 0xce, 0xbc, 0xe2, 0x2b, 0xb3, 0x2b, 0x6b, 0xac, 0x56, 0xe6, 0x63, 0x74, 0x1f, 0x0a, 0x1f,
+	0x88, 0x1d, 0x46, 0xd8, 0xc8, 0xe3, 0x88, 0x48, 0xb8, 0x83, 0x3b, 0xac, 0xa0, 0xdc, 0x81, 0x5e,
+	0x81, 0x3e, 0xc7, 0x7f, 0x43, 0xdb, 0xc8, 0x6e, 0x56, 0x76, 0xd6, 0xb7, 0xa3, 0x08, 0xd9, 0x56,
+	0x11, 0xb2, 0x6d, 0x2a, 0x0d, 0x1c, 0x2b, 0x73, 0xe7, 0x5d, 0x12, 0x36, 0xba, 0x18, 0x38, 0xf6,
+	0xac, 0x51, 0x10, 0x77, 0x8f, 0x19, 0xdc, 0x27, 0x3e, 0xb9, 0x6c, 0x14, 0x37, 0xb2, 0x9b, 0x55,
+	0xcc, 0x87, 0xe8, 0x3b, 0x80, 0x89, 0x45, 0xd8, 0x5b, 0x7e, 0x9c, 0xa0, 0x51, 0x12, 0x97, 0x5b,
+	0x8b, 0x2e, 0xb7, 0xdd, 0x9d, 0x0b, 0x3a, 0x0e, 0xf3, 0x67, 0x38, 0xa1, 0xb9, 0xfe, 0x06, 0x56,
+	0xaf, 0x89, 0x6f, 0x3a, 0x3c, 0x7d, 0xe9, 0xac, 0xbc, 0xf4, 0xeb, 0xdc, 0xab, 0xac, 0x61, 0x82,
+	0x66, 0x72, 0xb3, 0x2c, 0x85, 0xf3, 0x0b, 0x12, 0x5c, 0x28, 0x9c, 0xf3, 0xf1, 0x42, 0x9c, 0x5f,
+	0xc2, 0xbd, 0x2e, 0xa5, 0x87, 0xf4, 0x03, 0xb5, 0xef, 0x16, 0xb9, 0xe5, 0x89, 0x9c, 0xd6, 0xc8,
+	0xc5, 0x5a, 0x6a, 0x29, 0x3c, 0x97, 0x2e, 0xdc, 0xf8, 0x11, 0x40, 0x97, 0xd2, 0x63, 0xea, 0xef,
+	0xce, 0x18, 0xe5, 0x86, 0x98, 0x50, 0x2a, 0x43, 0x87, 0x0f, 0x79, 0x48, 0x74, 0xe9, 0x6d, 0x82,
+	0xbf, 0x67, 0x41, 0x1f, 0x7a, 0xd4, 0x19, 0xf7, 0x9c, 0x89, 0xbb, 0xc4, 0x51, 0x1b, 0x50, 0x92,
+	0x90, 0x97, 0x06, 0x51, 0x24, 0x3f, 0x1a, 0x99, 0xba, 0xa1, 0x13, 0x41, 0x49, 0xc3, 0x92, 0x4a,
+	0x5d, 0x4e, 0xfb, 0xe4, 0xe5, 0x10, 0x68, 0x53, 0x3a, 0x75, 0x05, 0x6a, 0x74, 0x2c, 0xc6, 0xe8,
+	0x09, 0xd4, 0x46, 0xc9, 0xc7, 0x56, 0x40, 0x47, 0xc7, 0x69, 0x66, 0xc2, 0x2c, 0xa5, 0x94, 0x59,
+	0xde, 0x80, 0x8e, 0xe9, 0xc8, 0xf2, 0x2c, 0xea, 0xb0, 0xe4, 0xd1, 0xb3, 0x8b, 0x8e, 0x9e, 0x4b,
+	0x1e, 0xdd, 0xf8, 0x47, 0x16, 0x6a, 0xc2, 0x38, 0x47, 0xc4, 0x99, 0x2d, 0x69, 0xa0, 0x5f, 0x00,
+	0xf8, 0x6a, 0x4b, 0x6e, 0x23, 0x8e, 0xe7, 0x1a, 0xd7, 0x9b, 0x1f, 0x04, 0x27, 0x14, 0x52, 0xd6,
+	0xc9, 0x2f, 0x65, 0x1d, 0xed, 0x53, 0xd6, 0x29, 0x7c, 0xda, 0x3a, 0xc5, 0x94, 0x75, 0x7e, 0xc9,
+	0x13, 0x99, 0x78, 0x34, 0x09, 0xd7, 0x0b, 0xa2, 0xe5, 0x12, 0x0c, 0xf9, 0x46, 0xa7, 0x99, 0x46,
+	0x17, 0xb4, 0x13, 0x76, 0xe5, 0x2e, 0x7a, 0x65, 0x2c, 0x67, 0x4c, 0xaf, 0x64, 0x9e, 0x8b, 0x88,
+	0x38, 0x0c, 0x23, 0x64, 0x44, 0x84, 0xf1, 0x12, 0xca, 0x83, 0x90, 0x79, 0xae, 0xe5, 0xb0, 0xe5,
+	0xd7, 0xe2, 0x21, 0xa6, 0x66, 0xdd, 0x31, 0xc4, 0x5c, 0x39, 0x4d, 0xac, 0x57, 0x89, 0xb4, 0xd4,
+	0x52, 0x78, 0x2e, 0x5d, 0x18, 0x62, 0xff, 0xce, 0x02, 0x12, 0x60, 0x50, 0x73, 0x82, 0x25, 0x11,
+	0xb1, 0x05, 0xba, 0x5a, 0x5c, 0x01, 0x22, 0xbd, 0x77, 0x2c, 0xbe, 0x86, 0x9e, 0xfc, 0x5d, 0xd0,
+	0x73, 0xf7, 0xd8, 0x5a, 0x84, 0x8b, 0x3f, 0xe4, 0x00, 0xb8, 0x87, 0x87, 0x8c, 0xb0, 0x30, 0xf8,
+	0x7f, 0xfd, 0x9c, 0x8c, 0x3b, 0xed, 0x46, 0xdc, 0xc9, 0xec, 0x53, 0x48, 0x16, 0x03, 0xe9, 0x1c,
+	0x52, 0xbc, 0x9e, 0x43, 0xd6, 0xa0, 0x38, 0xf1, 0xdd, 0x8f, 0xd4, 0x11, 0xc1, 0x5e, 0xc6, 0x92,
+	0xe2, 0xe8, 0x65, 0xee, 0x7b, 0xea, 0xb4, 0x08, 0xa3, 0xe7, 0xae, 0x3f, 0x6b, 0x94, 0xa3, 0x60,
+	0x48, 0x31, 0x79, 0xf9, 0x20, 0x18, 0xcd, 0x28, 0xe0, 0xf5, 0xa8, 0x7c, 0x48, 0xb0, 0xd0, 0x3a,
+	0x94, 0x05, 0xd9, 0xef, 0x9a, 0x0d, 0x10, 0x3b, 0xcc, 0x69, 0xe3, 0x39, 0x94, 0xb9, 0x65, 0x44,
+	0x62, 0x7e, 0x02, 0x85, 0x90, 0x5d, 0xb9, 0x2a, 0x23, 0x8b, 0x6a, 0x2b, 0x36, 0x1b, 0x8e, 0x84,
+	0xc6, 0x08, 0x56, 0x5b, 0xae, 0x13, 0xb8, 0xb6, 0x35, 0x26, 0x8c, 0x2e, 0x09, 0x99, 0x35, 0x28,
+	0x8e, 0xfd, 0x19, 0x0e, 0x1d, 0x61, 0xdf, 0x32, 0x96, 0xd4, 0x42, 0x6c, 0xfe, 0x35, 0x07, 0xf7,
+	0xe2, 0x5d, 0x2c, 0xd7, 0x39, 0xb6, 0xc9, 0x32, 0x51, 0x71, 0x5f, 0x5d, 0x41, 0xba, 0x51, 0x10,
+	0x0b, 0xdc, 0x88, 0x40, 0x0b, 0xac, 0x8f, 0x54, 0xf8, 0xb0, 0x86, 0xc5, 0x18, 0x3d, 0x02, 0x98,
+	0xcc, 0xd3, 0x8e, 0x70, 0xa2, 0x86, 0x13, 0x1c, 0x95, 0x6f, 0x8a, 0xf3, 0x7c, 0x83, 0xb6, 0xa0,
+	0x3e, 0x09, 0x59, 0xe8, 0xd3, 0x38, 0x5d, 0x09, 0x37, 0x6a, 0xf8, 0x06, 0x9f, 0x03, 0x27, 0x20,
+	0x1f, 0x2c, 0xe7, 0x3c, 0x10, 0xae, 0xcc, 0x63, 0x45, 0xa2, 0x9f, 0xc3, 0xca, 0xa5, 0xc5, 0x2e,
+	0x2c, 0x87, 0x23, 0xdd, 0x9a, 0x5a, 0x91, 0x1f, 0xcb, 0xf8, 0x1a, 0x77, 0x0e, 0x5d, 0x48, 0x40,
+	0x37, 0xb6, 0x61, 0x25, 0x65, 0xc3, 0xb7, 0x50, 0x35, 0xb9, 0x9b, 0x65, 0x19, 0xca, 0x61, 0x30,
+	0x52, 0x48, 0x8a, 0xa0, 0x3f, 0xa7, 0x17, 0x25, 0x0c, 0xbe, 0x9f, 0x33, 0x11, 0x81, 0x2b, 0x6c,
+	0xc4, 0xc7, 0xc6, 0x6f, 0xa1, 0x9e, 0x5c, 0x57, 0x40, 0xe7, 0x29, 0x94, 0xcf, 0x22, 0x52, 0xa1,
+	0xa7, 0x2e, 0x4a, 0x9e, 0x84, 0x1e, 0x9e, 0x6b, 0x18, 0xff, 0xe4, 0x39, 0xfa, 0x92, 0x52, 0x6f,
+	0x49, 0xf4, 0x3c, 0x8a, 0xbd, 0xca, 0x97, 0x2e, 0x2b, 0x60, 0x2a, 0xff, 0x26, 0x02, 0x32, 0x9f,
+	0x0e, 0x48, 0x59, 0x41, 0x69, 0x71, 0x05, 0x65, 0x40, 0xd5, 0xa7, 0x63, 0x4a, 0xa7, 0xc3, 0x91,
+	0x6f, 0x79, 0x51, 0xa0, 0x56, 0x71, 0x8a, 0x97, 0x7a, 0x85, 0x8a, 0x4b, 0x96, 0x2f, 0xe9, 0x3c,
+	0xfd, 0x02, 0x0a, 0x3d, 0xc7, 0x0b, 0xef, 0x92, 0x07, 0x76, 0xa1, 0xc8, 0x1f, 0xd0, 0x90, 0xf1,
+	0x23, 0x06, 0xe2, 0x20, 0xc7, 0xe1, 0xd9, 0x81, 0xac, 0xff, 0xaa, 0x38, 0xc5, 0x4b, 0x17, 0x82,
+	0xf3, 0x0c, 0xf4, 0x1b, 0xd0, 0x87, 0xd6, 0xb9, 0x43, 0x38, 0xee, 0xe2, 0x6d, 0xb2, 0xc9, 0x27,
+	0xed, 0x21, 0xe8, 0x81, 0x52, 0x11, 0x93, 0xab, 0x38, 0x66, 0x18, 0xff, 0xc9, 0x02, 0x6a, 0xf9,
+	0x94, 0x30, 0x7a, 0x14, 0xda, 0xcc, 0x0a, 0xac, 0xf3, 0x25, 0x5d, 0xf4, 0x15, 0x14, 0x2d, 0x7e,
+	0x61, 0xe5, 0x23, 0x9d, 0xeb, 0x08, 0x13, 0x60, 0x29, 0x40, 0x4f, 0xa0, 0xe4, 0x8a, 0x0b, 0xaa,
+	0x3c, 0x00, 0x2a, 0x69, 0x84, 0x0c, 0x2b, 0xd1, 0xff, 0xe8, 0xb1, 0x74, 0xdc, 0x16, 0x6f, 0xc4,
+	0xed, 0x22, 0x3f, 0xed, 0x40, 0x6d, 0x6e, 0x30, 0x01, 0xe4, 0xaf, 0xf8, 0xa3, 0x70, 0xae, 0x40,
+	0x2c, 0x32, 0xd5, 0x5c, 0x01, 0x0b, 0x91, 0xf1, 0x97, 0x1c, 0xd4, 0x94, 0x75, 0x9c, 0x1f, 0xdb,
+	0x3c, 0xd1, 0xf9, 0x5e, 0x34, 0xb4, 0x45, 0xe7, 0x7b, 0x21, 0x55, 0x76, 0x1a, 0x85, 0x45, 0x2a,
+	0x3b, 0x37, 0x4c, 0x5a, 0xfc, 0xac, 0x49, 0x4b, 0x37, 0x4c, 0xfa, 0x10, 0xf4, 0x33, 0xdf, 0x25,
+	0xe3, 0x11, 0x09, 0x98, 0x78, 0xce, 0xca, 0x38, 0x66, 0x24, 0x0c, 0xae, 0xa7, 0x0c, 0xfe, 0x00,
+	0x0a, 0x98, 0x5c, 0x9a, 0x57, 0x68, 0x05, 0x72, 0xec, 0x4a, 0x42, 0x3b, 0xc7, 0xae, 0x8c, 0x3f,
+	0x67, 0x61, 0xb5, 0x13, 0x30, 0x6b, 0x4a, 0x18, 0x7f, 0x32, 0xdb, 0x84, 0x91, 0x1f, 0xd3, 0xae,
+	0xe9, 0xdb, 0x6a, 0x9f, 0x00, 0x50, 0x21, 0x75, 0x9f, 0xbf, 0xe5, 0x61, 0xa5, 0xe3, 0x8c, 0x45,
+	0xb5, 0xb3, 0x4f, 0x89, 0xcd, 0x2e, 0x38, 0x82, 0x43, 0xdf, 0x56, 0xbf, 0xb6, 0xd0, 0xb7, 0xf9,
+	0xfb, 0x34, 0x0a, 0x7d, 0x9f, 0xca, 0xe7, 0xb5, 0x8c, 0x15, 0xc9, 0x25, 0x17, 0x62, 0xd6, 0x4c,
+	0xbc, 0x5c, 0x65, 0xac, 0x48, 0x1e, 0xbd, 0xc1, 0xc8, 0xf5, 0xa3, 0xb3, 0x64, 0x71, 0x44, 0xf0,
+	0x92, 0xc0, 0x26, 0x8c, 0x3a, 0xa3, 0xd9, 0x91, 0x65, 0xdb, 0x56, 0x20, 0x53, 0x54, 0x9a, 0xc9,
+	0x5d, 0x43, 0x7d, 0xdf, 0xf5, 0x31, 0x91, 0xc1, 0x90, 0xc5, 0x31, 0x83, 0x4b, 0x99, 0xe5, 0x45,
+	0xed, 0x0b, 0x19, 0x0e, 0x31, 0x83, 0x5f, 0x94, 0x59, 0xde, 0x21, 0x39, 0x17, 0x3e, 0xad, 0x61,
+	0x49, 0xf1, 0x59, 0x36, 0x09, 0x58, 0x87, 0x2f, 0x23, 0x7c, 0xaa, 0xe3, 0x98, 0x81, 0x7e, 0x0d,
+	0x55, 0x4e, 0x74, 0x89, 0x65, 0xd3, 0x71, 0x93, 0x35, 0xe0, 0xb3, 0x3f, 0xec, 0x94, 0x3e, 0x6a,
+	0xc3, 0xaa, 0x43, 0xaf, 0x58, 0xf3, 0x03, 0xb1, 0x6c, 0x72, 0x66, 0xd3, 0x26, 0x6b, 0x54, 0x3e,
+	0xbb, 0xc4, 0xf5, 0x29, 0xe8, 0x35, 0x00, 0x5f, 0x75, 0x48, 0xa9, 0xd3, 0x64, 0x8d, 0xea, 0x67,
+	0x17, 0x48, 0x68, 0x1b, 0x5d, 0x40, 0x69, 0x3f, 0x8a, 0xe7, 0xe0, 0x39, 0xe8, 0x54, 0x72, 0xd5,
+	0x9b, 0x80, 0x38, 0x7c, 0xd2, 0xaa, 0x38, 0x56, 0x32, 0xf6, 0xa1, 0x22, 0x4c, 0xd2, 0xa6, 0x8c,
+	0x58, 0x36, 0x0f, 0xc6, 0xf7, 0x96, 0x33, 0x96, 0x10, 0x16, 0xc1, 0x28, 0xc4, 0x07, 0x96, 0x33,
+	0xc6, 0x42, 0xc4, 0x2d, 0xee, 0x53, 0x12, 0xb8, 0x8e, 0xfc, 0x80, 0x4a, 0x6a, 0xeb, 0x02, 0xca,
+	0x0a, 0xed, 0xa8, 0x02, 0xa5, 0xdd, 0x9e, 0xd9, 0x1a, 0xf4, 0xfa, 0xf5, 0x0c, 0xaa, 0x43, 0x55,
+	0x12, 0xa7, 0xad, 0xe6, 0x70, 0xbf, 0x9e, 0x45, 0x3a, 0x14, 0x7e, 0x2f, 0x86, 0x39, 0x54, 0x85,
+	0xf2, 0x61, 0xcf, 0xec, 0x08, 0xd5, 0x3c, 0xa7, 0x3a, 0xe6, 0x7e, 0x07, 0x77, 0x4e, 0x8e, 0xea,
+	0x1a, 0xa7, 0xda, 0x83, 0xbd, 0x48, 0x56, 0x40, 0x65, 0xd0, 0xda, 0x7c, 0x4e, 0x71, 0x6b, 0x13,
+	0x20, 0xee, 0xaa, 0x71, 0xad, 0x5e, 0xdf, 0xec, 0xe0, 0x7e, 0xf3, 0xb0, 0x9e, 0x11, 0x2b, 0xfc,
+	0x4e, 0x52, 0xd9, 0xad, 0x1d, 0x28, 0xab, 0x2c, 0x28, 0x24, 0xad, 0x41, 0x7f, 0x70, 0xd4, 0x6b,
+	0xd5, 0x33, 0x08, 0xa0, 0xd8, 0x1f, 0xe0, 0x23, 0xae, 0xc5, 0x25, 0xc7, 0xb8, 0x37, 0xc0, 0x3d,
+	0xf3, 0x87, 0x7a, 0x6e, 0xeb, 0x8f, 0x59, 0xd0, 0xe7, 0x77, 0x46, 0xf7, 0xa0, 0x76, 0xd2, 0x3f,
+	0xe8, 0x0f, 0xde, 0xf5, 0x4f, 0x3b, 0x18, 0x0f, 0x70, 0x3d, 0x83, 0xd6, 0x00, 0xf5, 0xfa, 0xc3,
+	0x93, 0x6e, 0xb7, 0xd7, 0xea, 0x75, 0xfa, 0xe6, 0x69, 0xf7, 0xa4, 0xdf, 0x1e, 0xd6, 0xb3, 0x68,
+	0x15, 0x2a, 0xed, 0x93, 0xa1, 0x79, 0xda, 0x3c, 0x1a, 0x9c, 0xf4, 0xcd, 0x7a, 0x0e, 0x3d, 0x80,
+	0x2f, 0x76, 0x9b, 0xad, 0x83, 0x4e, 0xbf, 0x7d, 0x7a, 0xd2, 0x6f, 0xbe, 0x6d, 0xf6, 0x0e, 0x9b,
+	0xbb, 0x87, 0x9d, 0x7a, 0x1e, 0x7d, 0x01, 0xab, 0xbd, 0xfe, 0xdb, 0xe6, 0x61, 0xaf, 0x7d, 0xda,
+	0x6c, 0xb7, 0x71, 0x67, 0x38, 0xac, 0x6b, 0xdc, 0x4c, 0xdd, 0x4e, 0xe7, 0xd4, 0x1c, 0x0c, 0x4e,
+	0xf7, 0x7b, 0x7b, 0xfb, 0xf5, 0x02, 0x9f, 0x8f, 0x3b, 0xdf, 0x77, 0x5a, 0x66, 0xa7, 0x7d, 0xba,
+	0xfb, 0xc3, 0xe9, 0x51, 0xe7, 0xe8, 0x78, 0x30, 0x38, 0xac, 0x17, 0x77, 0xfe, 0x55, 0x85, 0x7c,
+	0xf3, 0xb8, 0x87, 0x1e, 0x81, 0x36, 0x64, 0xae, 0x87, 0xc4, 0x33, 0x22, 0x9a, 0xa3, 0xeb, 0xf1,
+	0xd0, 0xc8, 0xa0, 0x17, 0xb0, 0xd2, 0x8a, 0x22, 0x57, 0xb5, 0x15, 0xeb, 0xb2, 0xd7, 0x36, 0xff,
+	0xba, 0xad, 0x27, 0xdb, 0x69, 0x46, 0x86, 0x7f, 0x80, 0xfa, 0xf4, 0x72, 0x69, 0xf5, 0x6f, 0xa0,
+	0xdc, 0xba, 0x20, 0x96, 0x63, 0x5a, 0x1e, 0xba, 0xa7, 0x1e, 0xbc, 0x58, 0x5b, 0xbc, 0x5d, 0x51,
+	0xa4, 0x1a, 0x19, 0xf4, 0x14, 0x4a, 0xaa, 0xb8, 0xbb, 0x45, 0x57, 0xbc, 0x97, 0xbb, 0xaa, 0xe6,
+	0xca, 0xa0, 0xe7, 0x50, 0x3f, 0x22, 0x01, 0xa3, 0xfe, 0xb1, 0x6f, 0x7d, 0x20, 0x8c, 0xf2, 0x32,
+	0xe2, 0x96, 0x69, 0xaa, 0x7b, 0x68, 0x64, 0xd0, 0x33, 0x58, 0x95, 0x33, 0xc2, 0x33, 0xdb, 0x1a,
+	0x7d, 0x7e, 0xc2, 0xd7, 0x50, 0xdc, 0x27, 0x01, 0xd7, 0x4b, 0x5e, 0x6b, 0x5d, 0xdc, 0x3a, 0xd9,
+	0x4b, 0x34, 0x32, 0xe8, 0x09, 0x14, 0x65, 0xdb, 0x30, 0x61, 0x6c, 0x11, 0x1f, 0xf3, 0x86, 0xa2,
+	0x91, 0x41, 0xaf, 0xa0, 0x9a, 0x68, 0x1f, 0x06, 0xb7, 0x6d, 0xff, 0x05, 0x67, 0x5d, 0xeb, 0x31,
+	0x8a, 0xf5, 0x57, 0xf6, 0x28, 0x4b, 0xf0, 0x91, 0x28, 0x1b, 0x79, 0x8f, 0x6c, 0x5d, 0xf6, 0x1a,
+	0xc5, 0xfa, 0xb5, 0x3d, 0xca, 0x12, 0x25, 0xfa, 0x97, 0xc9, 0x42, 0x2f, 0xde, 0x64, 0x45, 0xb2,
+	0xa5, 0x9a, 0x91, 0x41, 0x06, 0x14, 0xc4, 0xe7, 0x19, 0x45, 0x09, 0x56, 0x75, 0x9c, 0xd6, 0xe7,
+	0xbb, 0x18, 0x19, 0xfe, 0x51, 0x9e, 0x77, 0x5b, 0xa2, 0xa3, 0xa7, 0x9a, 0x2f, 0x29, 0xdd, 0x97,
+	0xb0, 0x92, 0xfe, 0x8c, 0xa3, 0xb5, 0xf9, 0x84, 0xd4, 0x07, 0x3d, 0x35, 0xeb, 0x31, 0x94, 0x76,
+	0xc3, 0xa9, 0xc7, 0x5b, 0x61, 0xf1, 0xf5, 0x92, 0x0a, 0xdf, 0x00, 0x6a, 0x9e, 0x11, 0x67, 0xec,
+	0x3a, 0xb7, 0x9b, 0x22, 0x05, 0xef, 0xa7, 0x50, 0x6f, 0x8e, 0xc7, 0xef, 0xf8, 0xc7, 0x94, 0x8e,
+	0x65, 0x19, 0x90, 0x72, 0xe4, 0xb5, 0x60, 0xa8, 0xef, 0x51, 0x96, 0x6e, 0xb8, 0xc4, 0x0b, 0x4b,
+	0x4f, 0x25, 0x84, 0x02, 0x1f, 0x55, 0x51, 0xf7, 0xab, 0x70, 0x88, 0x6c, 0xa7, 0x7e, 0x02, 0xa9,
+	0x83, 0x77, 0xe1, 0x41, 0xba, 0x10, 0x8d, 0x0b, 0x5b, 0x61, 0x98, 0x9b, 0x55, 0x6a, 0xb4, 0x65,
+	0xaa, 0x9c, 0x13, 0x06, 0xd0, 0x95, 0x92, 0x13, 0xf9, 0x20, 0x55, 0xbb, 0x45, 0x57, 0x12, 0x25,
+	0x89, 0x08, 0xd6, 0x4a, 0xa2, 0x06, 0x41, 0x02, 0x5a, 0xd7, 0x8a, 0x92, 0x08, 0xee, 0x5d, 0xca,
+	0x31, 0xb0, 0x01, 0xc5, 0x3d, 0xca, 0x6e, 0xc0, 0x3d, 0x15, 0x10, 0x65, 0x7e, 0x0e, 0xd1, 0xa4,
+	0xbf, 0x05, 0xbb, 0x65, 0xa9, 0xc9, 0x6d, 0xf3, 0x2d, 0xd4, 0xb8, 0x6a, 0xdc, 0xaa, 0xbf, 0x45,
+	0xbf, 0x96, 0xd8, 0x86, 0x46, 0xaf, 0x4b, 0xf5, 0x1d, 0xb1, 0x6d, 0xca, 0xfa, 0x2e, 0xb3, 0x26,
+	0xb7, 0x86, 0xe7, 0x1c, 0xec, 0xcf, 0xb3, 0xe8, 0x29, 0x40, 0x3b, 0x9c, 0x7a, 0x26, 0xcf, 0x97,
+	0xc1, 0xc2, 0x58, 0xc6, 0xee, 0xa5, 0xd0, 0x7e, 0x73, 0xa3, 0xb4, 0xb9, 0x65, 0xc6, 0xda, 0xcd,
+	0x74, 0x28, 0x2d, 0xbf, 0x0d, 0x3a, 0x1f, 0x9d, 0x88, 0x2f, 0xda, 0xa2, 0xf7, 0x49, 0x35, 0x1f,
+	0xc4, 0xfb, 0x04, 0x5d, 0x9f, 0xd2, 0x8f, 0x94, 0xf3, 0xa2, 0x40, 0xbc, 0xd1, 0x18, 0x4b, 0x23,
+	0x70, 0x07, 0xaa, 0x27, 0xce, 0xe4, 0x6e, 0x73, 0x7e, 0x05, 0x95, 0x44, 0xfb, 0x22, 0x72, 0xf1,
+	0xb5, 0x7e, 0xc6, 0xfa, 0x97, 0x69, 0xa6, 0x6c, 0x3f, 0x18, 0x19, 0xf4, 0x1a, 0x6a, 0xc9, 0x2f,
+	0xed, 0xad, 0xd7, 0xba, 0x7f, 0xfd, 0xe3, 0x1b, 0x5d, 0xef, 0xac, 0x28, 0x0a, 0x90, 0x6f, 0xff,
+	0x3b, 0x00, 0x38, 0x22, 0x06, 0x7d, 0xa7, 0x1b, 0x00, 0x00,
 }
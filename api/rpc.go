@@ -2,19 +2,29 @@ package api
 
 import (
 	"errors"
+	"io"
+	"math/big"
 	"net"
+	"strconv"
+	"time"
 
 	"github.com/OpenBazaar/multiwallet"
 	"github.com/OpenBazaar/multiwallet/api/pb"
-	"github.com/OpenBazaar/multiwallet/bitcoin"
-	"github.com/OpenBazaar/multiwallet/bitcoincash"
-	"github.com/OpenBazaar/multiwallet/litecoin"
-	"github.com/OpenBazaar/multiwallet/zcash"
+	"github.com/OpenBazaar/multiwallet/client"
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/multiwallet/service"
+	"github.com/OpenBazaar/multiwallet/util"
 	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 const Addr = "127.0.0.1:8234"
@@ -37,7 +47,15 @@ func ServeAPI(w multiwallet.MultiWallet) error {
 	return nil
 }
 
-func coinType(coinType pb.CoinType) wallet.CoinType {
+// coinType returns the wallet coin type a request selects. A nonzero slip44 selects the registered
+// coin of that SLIP-0044 coin type and an unknown one selects no wallet.
+func coinType(coinType pb.CoinType, slip44 uint32) wallet.CoinType {
+	if slip44 != 0 {
+		if c, ok := coins.Lookup(wallet.CoinType(slip44)); ok {
+			return c.CoinType
+		}
+		return wallet.CoinType(slip44)
+	}
 	switch coinType {
 	case pb.CoinType_BITCOIN:
 		return wallet.Bitcoin
@@ -47,9 +65,12 @@ func coinType(coinType pb.CoinType) wallet.CoinType {
 		return wallet.Zcash
 	case pb.CoinType_LITECOIN:
 		return wallet.Litecoin
-	default:
-		return wallet.Bitcoin
 	}
+	// Registered coins are named in the enum after their coin name
+	if c, ok := coins.LookupName(coinType.String()); ok {
+		return c.CoinType
+	}
+	return wallet.Bitcoin
 }
 
 func (s *server) Stop(ctx context.Context, in *pb.Empty) (*pb.Empty, error) {
@@ -64,15 +85,15 @@ func (s *server) CurrentAddress(ctx context.Context, in *pb.KeySelection) (*pb.A
 	} else if in.Purpose == pb.KeyPurpose_EXTERNAL {
 		purpose = wallet.EXTERNAL
 	} else {
-		return nil, errors.New("Unknown key purpose")
+		return nil, status.Error(codes.InvalidArgument, "Unknown key purpose")
 	}
-	ct := coinType(in.Coin)
-	wal, err := s.w.WalletForCurrencyCode(ct.CurrencyCode())
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	addr := wal.CurrentAddress(purpose)
-	return &pb.Address{Coin: in.Coin, Addr: addr.String()}, nil
+	return &pb.Address{Coin: in.Coin, Slip44: in.Slip44, Addr: addr.String()}, nil
 }
 
 func (s *server) NewAddress(ctx context.Context, in *pb.KeySelection) (*pb.Address, error) {
@@ -82,32 +103,32 @@ func (s *server) NewAddress(ctx context.Context, in *pb.KeySelection) (*pb.Addre
 	} else if in.Purpose == pb.KeyPurpose_EXTERNAL {
 		purpose = wallet.EXTERNAL
 	} else {
-		return nil, errors.New("Unknown key purpose")
+		return nil, status.Error(codes.InvalidArgument, "Unknown key purpose")
 	}
-	ct := coinType(in.Coin)
-	wal, err := s.w.WalletForCurrencyCode(ct.CurrencyCode())
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	addr := wal.NewAddress(purpose)
-	return &pb.Address{Coin: in.Coin, Addr: addr.String()}, nil
+	return &pb.Address{Coin: in.Coin, Slip44: in.Slip44, Addr: addr.String()}, nil
 }
 
 func (s *server) ChainTip(ctx context.Context, in *pb.CoinSelection) (*pb.Height, error) {
-	ct := coinType(in.Coin)
-	wal, err := s.w.WalletForCurrencyCode(ct.CurrencyCode())
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	h, _ := wal.ChainTip()
 	return &pb.Height{Height: h}, nil
 }
 
 func (s *server) Balance(ctx context.Context, in *pb.CoinSelection) (*pb.Balances, error) {
-	ct := coinType(in.Coin)
-	wal, err := s.w.WalletForCurrencyCode(ct.CurrencyCode())
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	c, u := wal.Balance()
 	return &pb.Balances{Confirmed: uint64(c), Unconfirmed: uint64(u)}, nil
@@ -133,16 +154,70 @@ func (s *server) HasKey(ctx context.Context, in *pb.Address) (*pb.BoolResponse,
 	return &pb.BoolResponse{Bool: false}, nil
 }
 
+type receiptValuer interface {
+	ValueAtReceipt(txn wallet.Txn) (map[string]float64, error)
+}
+
 func (s *server) Transactions(ctx context.Context, in *pb.CoinSelection) (*pb.TransactionList, error) {
-	// Stub
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	txns, err := wal.Transactions()
+	if err != nil {
+		return nil, statusError(err)
+	}
 	var list []*pb.Tx
+	for _, txn := range txns {
+		tx, err := txProto(wal, txn)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		list = append(list, tx)
+	}
 	return &pb.TransactionList{Transactions: list}, nil
 }
 
 func (s *server) GetTransaction(ctx context.Context, in *pb.Txid) (*pb.Tx, error) {
-	// Stub
-	respTx := &pb.Tx{}
-	return respTx, nil
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	txid, err := chainhash.NewHashFromStr(in.Hash)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	txn, err := wal.GetTransaction(*txid)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return txProto(wal, txn)
+}
+
+// txProto converts txn, valuing it at the exchange rates recorded when it was first seen if the
+// wallet records them
+func txProto(wal wallet.Wallet, txn wallet.Txn) (*pb.Tx, error) {
+	value, err := strconv.ParseInt(txn.Value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	tx := &pb.Tx{
+		Txid:      txn.Txid,
+		Value:     value,
+		Height:    txn.Height,
+		Timestamp: timestampProto(txn.Timestamp),
+		WatchOnly: txn.WatchOnly,
+		Raw:       txn.Bytes,
+	}
+	if valuer, ok := wal.(receiptValuer); ok {
+		tx.FiatValues, err = valuer.ValueAtReceipt(txn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
 }
 
 func (s *server) GetFeePerByte(ctx context.Context, in *pb.FeeLevelSelection) (*pb.FeePerByte, error) {
@@ -154,37 +229,172 @@ func (s *server) Spend(ctx context.Context, in *pb.SpendInfo) (*pb.Txid, error)
 	var addr btcutil.Address
 	var err error
 
-	ct := coinType(in.Coin)
-	wal, err := s.w.WalletForCurrencyCode(ct.CurrencyCode())
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	addr, err = wal.DecodeAddress(in.Address)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 
-	var feeLevel wallet.FeeLevel
-	switch in.FeeLevel {
+	var txid *chainhash.Hash
+	if in.CoinSelection == "" {
+		txid, err = wal.Spend(int64(in.Amount), addr, feeLevel(in.FeeLevel), "", false)
+	} else {
+		// Only batch spends take a coin selection strategy so pay the single recipient as a batch
+		spender, ok := wal.(batchSpender)
+		if !ok {
+			return nil, status.Error(codes.Unimplemented, "Coin selection is not available for this coin")
+		}
+		strategy, parseErr := util.ParseCoinSelectionStrategy(in.CoinSelection)
+		if parseErr != nil {
+			return nil, status.Error(codes.InvalidArgument, parseErr.Error())
+		}
+		output := wallet.TransactionOutput{Address: addr, Value: *new(big.Int).SetUint64(in.Amount)}
+		txid, err = spender.SpendMany([]wallet.TransactionOutput{output}, feeLevel(in.FeeLevel), in.Memo, strategy)
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Txid{Coin: in.Coin, Slip44: in.Slip44, Hash: txid.String()}, nil
+}
+
+func feeLevel(level pb.FeeLevel) wallet.FeeLevel {
+	switch level {
 	case pb.FeeLevel_PRIORITY:
-		feeLevel = wallet.PRIOIRTY
-	case pb.FeeLevel_NORMAL:
-		feeLevel = wallet.NORMAL
+		return wallet.PRIOIRTY
 	case pb.FeeLevel_ECONOMIC:
-		feeLevel = wallet.ECONOMIC
+		return wallet.ECONOMIC
 	default:
-		feeLevel = wallet.NORMAL
+		return wallet.NORMAL
 	}
-	txid, err := wal.Spend(int64(in.Amount), addr, feeLevel, "", false)
+}
+
+type batchSpender interface {
+	SpendMany(outputs []wallet.TransactionOutput, feeLevel wallet.FeeLevel, referenceID string, strategy util.CoinSelectionStrategy) (*chainhash.Hash, error)
+}
+
+func (s *server) SpendMany(ctx context.Context, in *pb.SpendManyInfo) (*pb.Txid, error) {
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
+	}
+	spender, ok := wal.(batchSpender)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "Batch payments are not available for this coin")
+	}
+	if len(in.Recipients) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "At least one recipient is required")
+	}
+	strategy, err := util.ParseCoinSelectionStrategy(in.CoinSelection)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	outputs := make([]wallet.TransactionOutput, 0, len(in.Recipients))
+	for _, r := range in.Recipients {
+		addr, err := wal.DecodeAddress(r.Address)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		outputs = append(outputs, wallet.TransactionOutput{
+			Address: addr,
+			Value:   *new(big.Int).SetUint64(r.Amount),
+		})
 	}
-	return &pb.Txid{Coin: in.Coin, Hash: txid.String()}, nil
+	txid, err := spender.SpendMany(outputs, feeLevel(in.FeeLevel), in.Memo, strategy)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Txid{Coin: in.Coin, Slip44: in.Slip44, Hash: txid.String()}, nil
+}
+
+type outpointSpender interface {
+	SpendOutpoints(outpoints []wire.OutPoint, outputs []wallet.TransactionOutput, feeLevel wallet.FeeLevel, referenceID string) (*chainhash.Hash, error)
+}
+
+func (s *server) SpendOutpoints(ctx context.Context, in *pb.SpendOutpointsInfo) (*pb.Txid, error) {
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	spender, ok := wal.(outpointSpender)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "Coin control is not available for this coin")
+	}
+	if len(in.Outpoints) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "At least one outpoint is required")
+	}
+	if len(in.Recipients) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "At least one recipient is required")
+	}
+	outpoints := make([]wire.OutPoint, 0, len(in.Outpoints))
+	for _, o := range in.Outpoints {
+		op, err := outpoint(o)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		outpoints = append(outpoints, op)
+	}
+	outputs := make([]wallet.TransactionOutput, 0, len(in.Recipients))
+	for _, r := range in.Recipients {
+		addr, err := wal.DecodeAddress(r.Address)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		outputs = append(outputs, wallet.TransactionOutput{
+			Address: addr,
+			Value:   *new(big.Int).SetUint64(r.Amount),
+		})
+	}
+	txid, err := spender.SpendOutpoints(outpoints, outputs, feeLevel(in.FeeLevel), in.Memo)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Txid{Coin: in.Coin, Slip44: in.Slip44, Hash: txid.String()}, nil
+}
+
+func outpoint(o *pb.Outpoint) (wire.OutPoint, error) {
+	if o == nil {
+		return wire.OutPoint{}, errors.New("missing outpoint")
+	}
+	hash, err := chainhash.NewHashFromStr(o.Txid)
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+	return *wire.NewOutPoint(hash, o.Index), nil
 }
 
 func (s *server) BumpFee(ctx context.Context, in *pb.Txid) (*pb.Txid, error) {
 	// Stub
-	return &pb.Txid{Coin: in.Coin, Hash: ""}, nil
+	return &pb.Txid{Coin: in.Coin, Slip44: in.Slip44, Hash: ""}, nil
+}
+
+type transactionAbandoner interface {
+	AbandonTransaction(txid chainhash.Hash) error
+}
+
+func (s *server) AbandonTransaction(ctx context.Context, in *pb.Txid) (*pb.Empty, error) {
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	abandoner, ok := wal.(transactionAbandoner)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "Abandoning transactions is not available for this coin")
+	}
+	txid, err := chainhash.NewHashFromStr(in.Hash)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := abandoner.AbandonTransaction(*txid); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Empty{}, nil
 }
 
 func (s *server) AddWatchedScript(ctx context.Context, in *pb.Address) (*pb.Empty, error) {
@@ -198,7 +408,7 @@ func (s *server) GetConfirmations(ctx context.Context, in *pb.Txid) (*pb.Confirm
 
 func (s *server) SweepAddress(ctx context.Context, in *pb.SweepInfo) (*pb.Txid, error) {
 	// Stub
-	return &pb.Txid{Coin: in.Coin, Hash: ""}, nil
+	return &pb.Txid{Coin: in.Coin, Slip44: in.Slip44, Hash: ""}, nil
 }
 
 func (s *server) CreateMultisigSignature(ctx context.Context, in *pb.CreateMultisigInfo) (*pb.SignatureList, error) {
@@ -250,32 +460,211 @@ func (h *HeaderWriter) Write(p []byte) (n int, err error) {
 	return 0, nil
 }
 
+type tableDumper interface {
+	DumpTables(wr io.Writer)
+}
+
 func (s *server) DumpTables(in *pb.CoinSelection, stream pb.API_DumpTablesServer) error {
 	writer := HeaderWriter{stream}
-	ct := coinType(in.Coin)
-	wal, err := s.w.WalletForCurrencyCode(ct.CurrencyCode())
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
 	if err != nil {
-		return err
+		return statusError(err)
 	}
-	bitcoinWallet, ok := wal.(*bitcoin.BitcoinWallet)
-	if ok {
-		bitcoinWallet.DumpTables(&writer)
-		return nil
+	dumper, ok := wal.(tableDumper)
+	if !ok {
+		return status.Error(codes.Unimplemented, "Dumping tables is not available for this coin")
 	}
-	bitcoincashWallet, ok := wal.(*bitcoincash.BitcoinCashWallet)
-	if ok {
-		bitcoincashWallet.DumpTables(&writer)
-		return nil
+	dumper.DumpTables(&writer)
+	return nil
+}
+
+type endpointHealthReporter interface {
+	EndpointHealth() []client.EndpointHealth
+}
+
+func (s *server) EndpointHealth(ctx context.Context, in *pb.CoinSelection) (*pb.EndpointHealthList, error) {
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	reporter, ok := wal.(endpointHealthReporter)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "Endpoint health is not available for this coin")
+	}
+	var list []*pb.EndpointHealth
+	for _, h := range reporter.EndpointHealth() {
+		list = append(list, &pb.EndpointHealth{
+			Url:             string(h.Target),
+			Current:         h.Current,
+			Healthy:         h.Healthy,
+			Score:           h.Score,
+			LatencyMillis:   uint64(h.Latency / time.Millisecond),
+			ErrorRate:       h.ErrorRate,
+			TipHeight:       uint32(h.TipHeight),
+			TipLag:          uint32(h.TipLag),
+			LastError:       h.LastError,
+			LastFailedAt:    timestampProto(h.LastFailedAt),
+			NextAvailableAt: timestampProto(h.NextAvailableAt),
+			LastSeenAt:      timestampProto(h.LastSeenAt),
+		})
+	}
+	return &pb.EndpointHealthList{Endpoints: list}, nil
+}
+
+type coinController interface {
+	ListUtxos() ([]service.UtxoStatus, error)
+	FreezeUtxo(op wire.OutPoint) error
+	UnfreezeUtxo(op wire.OutPoint) error
+}
+
+func (s *server) coinController(coin pb.CoinType, slip44 uint32) (wallet.Wallet, coinController, error) {
+	ct := coinType(coin, slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
+	if err != nil {
+		return nil, nil, statusError(err)
+	}
+	controller, ok := wal.(coinController)
+	if !ok {
+		return nil, nil, status.Error(codes.Unimplemented, "Coin control is not available for this coin")
+	}
+	return wal, controller, nil
+}
+
+func (s *server) ListUtxos(ctx context.Context, in *pb.CoinSelection) (*pb.UtxoList, error) {
+	wal, controller, err := s.coinController(in.Coin, in.Slip44)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := controller.ListUtxos()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var list []*pb.UtxoStatus
+	for _, u := range statuses {
+		value, _ := strconv.ParseUint(u.Utxo.Value, 10, 64)
+		var address string
+		if addr, err := wal.ScriptToAddress(u.Utxo.ScriptPubkey); err == nil {
+			address = addr.String()
+		}
+		utxo := &pb.UtxoStatus{
+			Txid:      u.Utxo.Op.Hash.String(),
+			Index:     u.Utxo.Op.Index,
+			Value:     value,
+			Address:   address,
+			Height:    uint32(u.Utxo.AtHeight),
+			WatchOnly: u.Utxo.WatchOnly,
+			Frozen:    u.Frozen,
+		}
+		if u.Token != nil {
+			utxo.TokenCategory = u.Token.Category.String()
+			utxo.TokenAmount = u.Token.Amount
+			utxo.TokenNFT = u.Token.HasNFT
+		}
+		list = append(list, utxo)
+	}
+	return &pb.UtxoList{Utxos: list}, nil
+}
+
+func (s *server) FreezeUtxo(ctx context.Context, in *pb.OutpointSelection) (*pb.Empty, error) {
+	_, controller, err := s.coinController(in.Coin, in.Slip44)
+	if err != nil {
+		return nil, err
 	}
-	litecoinWallet, ok := wal.(*litecoin.LitecoinWallet)
-	if ok {
-		litecoinWallet.DumpTables(&writer)
+	op, err := outpoint(in.Outpoint)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := controller.FreezeUtxo(op); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *server) UnfreezeUtxo(ctx context.Context, in *pb.OutpointSelection) (*pb.Empty, error) {
+	_, controller, err := s.coinController(in.Coin, in.Slip44)
+	if err != nil {
+		return nil, err
+	}
+	op, err := outpoint(in.Outpoint)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := controller.UnfreezeUtxo(op); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+type consolidator interface {
+	Consolidate(dryRun bool) (*util.ConsolidationPlan, error)
+}
+
+func (s *server) Consolidate(ctx context.Context, in *pb.ConsolidateInfo) (*pb.ConsolidationPlan, error) {
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	c, ok := wal.(consolidator)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "Consolidation is not available for this coin")
+	}
+	plan, err := c.Consolidate(in.DryRun)
+	if err == util.ErrNothingToConsolidate {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.ConsolidationPlan{
+		Coin:             in.Coin,
+		Utxos:            uint32(len(plan.Utxos)),
+		Value:            uint64(plan.Value),
+		Size:             uint32(plan.Size),
+		FeePerByte:       uint64(plan.FeePerByte),
+		Fee:              uint64(plan.Fee),
+		FutureFeePerByte: uint64(plan.FutureFeePerByte),
+		Savings:          plan.Savings,
+		WithinFeeLimit:   plan.WithinFeeLimit,
+		Txid:             plan.Txid,
+		Slip44:           in.Slip44,
+	}, nil
+}
+
+type tokenHolder interface {
+	TokenBalances() ([]service.TokenBalance, error)
+}
+
+func (s *server) TokenBalances(ctx context.Context, in *pb.CoinSelection) (*pb.TokenBalanceList, error) {
+	ct := coinType(in.Coin, in.Slip44)
+	wal, err := s.w.WalletForCurrencyCode(util.CurrencyCode(ct))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	h, ok := wal.(tokenHolder)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "Tokens are not available for this coin")
+	}
+	balances, err := h.TokenBalances()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var list []*pb.TokenBalance
+	for _, b := range balances {
+		list = append(list, &pb.TokenBalance{Category: b.Category, Amount: b.Amount, Nfts: uint32(b.NFTs)})
+	}
+	return &pb.TokenBalanceList{Balances: list}, nil
+}
+
+func timestampProto(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
 		return nil
 	}
-	zcashWallet, ok := wal.(*zcash.ZCashWallet)
-	if ok {
-		zcashWallet.DumpTables(&writer)
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
 		return nil
 	}
-	return nil
+	return ts
 }
@@ -3,676 +3,80 @@ package bitcoin
 import (
 	"bytes"
 	"crypto/sha256"
-	"encoding/hex"
 	"errors"
-	"fmt"
-	"math/big"
-	"strconv"
-	"time"
 
-	"github.com/btcsuite/btcd/chaincfg"
-
-	"github.com/OpenBazaar/spvwallet"
 	wi "github.com/OpenBazaar/wallet-interface"
-	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	btc "github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/coinset"
-	hd "github.com/btcsuite/btcutil/hdkeychain"
-	"github.com/btcsuite/btcutil/txsort"
-	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
 
-	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/multiwallet/utxo"
 )
 
-func (w *BitcoinWallet) buildTx(amount int64, addr btc.Address, feeLevel wi.FeeLevel, optionalOutput *wire.TxOut) (*wire.MsgTx, error) {
-	// Check for dust
-	script, _ := txscript.PayToAddrScript(addr)
-	if txrules.IsDustAmount(btc.Amount(amount), len(script), txrules.DefaultRelayFeePerKb) {
-		return nil, wi.ErrorDustAmount
-	}
-
-	var additionalPrevScripts map[wire.OutPoint][]byte
-	var additionalKeysByAddress map[string]*btc.WIF
-
-	// Create input source
-	height, _ := w.ws.ChainTip()
-	utxos, err := w.db.Utxos().GetAll()
-	if err != nil {
-		return nil, err
-	}
-	coinMap := util.GatherCoins(height, utxos, w.ScriptToAddress, w.km.GetKeyForScript)
-
-	coins := make([]coinset.Coin, 0, len(coinMap))
-	for k := range coinMap {
-		coins = append(coins, k)
-	}
-	inputSource := func(target btc.Amount) (total btc.Amount, inputs []*wire.TxIn, inputValues []btc.Amount, scripts [][]byte, err error) {
-		coinSelector := coinset.MaxValueAgeCoinSelector{MaxInputs: 10000, MinChangeAmount: btc.Amount(0)}
-		coins, err := coinSelector.CoinSelect(target, coins)
-		if err != nil {
-			return total, inputs, inputValues, scripts, wi.ErrInsufficientFunds
-		}
-		additionalPrevScripts = make(map[wire.OutPoint][]byte)
-		additionalKeysByAddress = make(map[string]*btc.WIF)
-		for _, c := range coins.Coins() {
-			total += c.Value()
-			outpoint := wire.NewOutPoint(c.Hash(), c.Index())
-			in := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
-			in.Sequence = 0 // Opt-in RBF so we can bump fees
-			inputs = append(inputs, in)
-			additionalPrevScripts[*outpoint] = c.PkScript()
-			key := coinMap[c]
-			addr, err := key.Address(w.params)
-			if err != nil {
-				continue
-			}
-			privKey, err := key.ECPrivKey()
-			if err != nil {
-				continue
-			}
-			wif, _ := btc.NewWIF(privKey, w.params, true)
-			additionalKeysByAddress[addr.EncodeAddress()] = wif
-		}
-		return total, inputs, inputValues, scripts, nil
-	}
-
-	// Get the fee per kilobyte
-	f := w.GetFeePerByte(feeLevel)
-	feePerKB := f.Int64() * 1000
-
-	// outputs
-	out := wire.NewTxOut(amount, script)
-
-	// Create change source
-	changeSource := func() ([]byte, error) {
-		addr := w.CurrentAddress(wi.INTERNAL)
-		script, err := txscript.PayToAddrScript(addr)
-		if err != nil {
-			return []byte{}, err
-		}
-		return script, nil
-	}
-
-	outputs := []*wire.TxOut{out}
-	if optionalOutput != nil {
-		outputs = append(outputs, optionalOutput)
-	}
-	authoredTx, err := newUnsignedTransaction(outputs, btc.Amount(feePerKB), inputSource, changeSource)
-	if err != nil {
-		return nil, err
-	}
-
-	// BIP 69 sorting
-	txsort.InPlaceSort(authoredTx.Tx)
-
-	// Sign tx
-	getKey := txscript.KeyClosure(func(addr btc.Address) (*btcec.PrivateKey, bool, error) {
-		addrStr := addr.EncodeAddress()
-		wif := additionalKeysByAddress[addrStr]
-		return wif.PrivKey, wif.CompressPubKey, nil
-	})
-	getScript := txscript.ScriptClosure(func(
-		addr btc.Address) ([]byte, error) {
-		return []byte{}, nil
-	})
-	for i, txIn := range authoredTx.Tx.TxIn {
-		prevOutScript := additionalPrevScripts[txIn.PreviousOutPoint]
-		script, err := txscript.SignTxOutput(w.params,
-			authoredTx.Tx, i, prevOutScript, txscript.SigHashAll, getKey,
-			getScript, txIn.SignatureScript)
-		if err != nil {
-			return nil, errors.New("Failed to sign transaction")
-		}
-		txIn.SignatureScript = script
-	}
-	return authoredTx.Tx, nil
+// bitcoinCoin signs P2PKH inputs with ECDSA and pays multisig escrows to a P2WSH address spent
+// with witnesses
+type bitcoinCoin struct{}
+
+var bitcoinFeatures = utxo.Features{
+	CoinType:           wi.Bitcoin,
+	Currency:           BitcoinCurrencyDefinition,
+	P2PKHInput:         utxo.P2PKH,
+	RelayFeePerKb:      txrules.DefaultRelayFeePerKb,
+	SafeConfirmations:  6,
+	FeeEstimateAddress: "bc1qxtq7ha2l5qg70atpwp3fus84fx3w0v2w4r2my7gt89ll3w0vnlgspu349h",
+	Segwit:             true,
+	Timelocks:          true,
+	Replacement:        true,
 }
 
-func (w *BitcoinWallet) buildSpendAllTx(addr btc.Address, feeLevel wi.FeeLevel) (*wire.MsgTx, error) {
-	tx := wire.NewMsgTx(1)
-
-	height, _ := w.ws.ChainTip()
-	utxos, err := w.db.Utxos().GetAll()
-	if err != nil {
-		return nil, err
-	}
-	coinMap := util.GatherCoins(height, utxos, w.ScriptToAddress, w.km.GetKeyForScript)
-
-	totalIn, _, additionalPrevScripts, additionalKeysByAddress := util.LoadAllInputs(tx, coinMap, w.params)
-
-	// outputs
-	script, err := txscript.PayToAddrScript(addr)
-	if err != nil {
-		return nil, err
-	}
-
-	// Get the fee
-	fee0 := w.GetFeePerByte(feeLevel)
-	feePerByte := fee0.Int64()
-	estimatedSize := EstimateSerializeSize(1, []*wire.TxOut{wire.NewTxOut(0, script)}, false, P2PKH)
-	fee := int64(estimatedSize) * feePerByte
-
-	// Check for dust output
-	if txrules.IsDustAmount(btc.Amount(totalIn-fee), len(script), txrules.DefaultRelayFeePerKb) {
-		return nil, wi.ErrorDustAmount
-	}
-
-	// Build the output
-	out := wire.NewTxOut(totalIn-fee, script)
-	tx.TxOut = append(tx.TxOut, out)
-
-	// BIP 69 sorting
-	txsort.InPlaceSort(tx)
-
-	// Sign
-	getKey := txscript.KeyClosure(func(addr btc.Address) (*btcec.PrivateKey, bool, error) {
-		addrStr := addr.EncodeAddress()
-		wif, ok := additionalKeysByAddress[addrStr]
-		if !ok {
-			return nil, false, errors.New("key not found")
-		}
-		return wif.PrivKey, wif.CompressPubKey, nil
-	})
-	getScript := txscript.ScriptClosure(func(
-		addr btc.Address) ([]byte, error) {
-		return []byte{}, nil
-	})
-	for i, txIn := range tx.TxIn {
-		prevOutScript := additionalPrevScripts[txIn.PreviousOutPoint]
-		script, err := txscript.SignTxOutput(w.params,
-			tx, i, prevOutScript, txscript.SigHashAll, getKey,
-			getScript, txIn.SignatureScript)
-		if err != nil {
-			return nil, errors.New("failed to sign transaction")
-		}
-		txIn.SignatureScript = script
-	}
-	return tx, nil
+func (bitcoinCoin) Features() utxo.Features {
+	return bitcoinFeatures
 }
 
-func newUnsignedTransaction(outputs []*wire.TxOut, feePerKb btc.Amount, fetchInputs txauthor.InputSource, fetchChange txauthor.ChangeSource) (*txauthor.AuthoredTx, error) {
-
-	var targetAmount btc.Amount
-	for _, txOut := range outputs {
-		targetAmount += btc.Amount(txOut.Value)
-	}
-
-	estimatedSize := EstimateSerializeSize(1, outputs, true, P2PKH)
-	targetFee := txrules.FeeForSerializeSize(feePerKb, estimatedSize)
-
-	for {
-		inputAmount, inputs, _, scripts, err := fetchInputs(targetAmount + targetFee)
-		if err != nil {
-			return nil, err
-		}
-		if inputAmount < targetAmount+targetFee {
-			return nil, errors.New("insufficient funds available to construct transaction")
-		}
-
-		maxSignedSize := EstimateSerializeSize(len(inputs), outputs, true, P2PKH)
-		maxRequiredFee := txrules.FeeForSerializeSize(feePerKb, maxSignedSize)
-		remainingAmount := inputAmount - targetAmount
-		if remainingAmount < maxRequiredFee {
-			targetFee = maxRequiredFee
-			continue
-		}
-
-		unsignedTransaction := &wire.MsgTx{
-			Version:  wire.TxVersion,
-			TxIn:     inputs,
-			TxOut:    outputs,
-			LockTime: 0,
-		}
-		changeIndex := -1
-		changeAmount := inputAmount - targetAmount - maxRequiredFee
-		if changeAmount != 0 && !txrules.IsDustAmount(changeAmount,
-			P2PKHOutputSize, txrules.DefaultRelayFeePerKb) {
-			changeScript, err := fetchChange()
-			if err != nil {
-				return nil, err
-			}
-			if len(changeScript) > P2PKHPkScriptSize {
-				return nil, errors.New("fee estimation requires change " +
-					"scripts no larger than P2PKH output scripts")
-			}
-			change := wire.NewTxOut(int64(changeAmount), changeScript)
-			l := len(outputs)
-			unsignedTransaction.TxOut = append(outputs[:l:l], change)
-			changeIndex = l
-		}
-
-		return &txauthor.AuthoredTx{
-			Tx:          unsignedTransaction,
-			PrevScripts: scripts,
-			TotalInput:  inputAmount,
-			ChangeIndex: changeIndex,
-		}, nil
-	}
+func (bitcoinCoin) DecodeAddress(addr string, params *chaincfg.Params) (btc.Address, error) {
+	return btc.DecodeAddress(addr, params)
 }
 
-func (w *BitcoinWallet) bumpFee(txid chainhash.Hash) (*chainhash.Hash, error) {
-	txn, err := w.db.Txns().Get(txid)
-	if err != nil {
-		return nil, err
-	}
-	if txn.Height > 0 {
-		return nil, spvwallet.BumpFeeAlreadyConfirmedError
-	}
-	if txn.Height < 0 {
-		return nil, spvwallet.BumpFeeTransactionDeadError
-	}
-	// Check utxos for CPFP
-	utxos, _ := w.db.Utxos().GetAll()
-	for _, u := range utxos {
-		if u.Op.Hash.IsEqual(&txid) && u.AtHeight == 0 {
-			addr, err := w.ScriptToAddress(u.ScriptPubkey)
-			if err != nil {
-				return nil, err
-			}
-			key, err := w.km.GetKeyForScript(addr.ScriptAddress())
-			if err != nil {
-				return nil, err
-			}
-			h, err := hex.DecodeString(u.Op.Hash.String())
-			if err != nil {
-				return nil, err
-			}
-			n := new(big.Int)
-			n, _ = n.SetString(u.Value, 10)
-			in := wi.TransactionInput{
-				LinkedAddress: addr,
-				OutpointIndex: u.Op.Index,
-				OutpointHash:  h,
-				Value:         *n,
-			}
-			transactionID, err := w.sweepAddress([]wi.TransactionInput{in}, nil, key, nil, wi.FEE_BUMP)
-			if err != nil {
-				return nil, err
-			}
-			return transactionID, nil
-		}
-	}
-	return nil, spvwallet.BumpFeeNotFoundError
+func (bitcoinCoin) PayToAddrScript(addr btc.Address) ([]byte, error) {
+	return txscript.PayToAddrScript(addr)
 }
 
-func (w *BitcoinWallet) sweepAddress(ins []wi.TransactionInput, address *btc.Address, key *hd.ExtendedKey, redeemScript *[]byte, feeLevel wi.FeeLevel) (*chainhash.Hash, error) {
-	var internalAddr btc.Address
-	if address != nil {
-		internalAddr = *address
-	} else {
-		internalAddr = w.CurrentAddress(wi.INTERNAL)
-	}
-	script, err := txscript.PayToAddrScript(internalAddr)
+func (bitcoinCoin) ExtractAddress(script []byte, params *chaincfg.Params) (btc.Address, error) {
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(script, params)
 	if err != nil {
 		return nil, err
 	}
-
-	var val int64
-	var inputs []*wire.TxIn
-	additionalPrevScripts := make(map[wire.OutPoint][]byte)
-	for _, in := range ins {
-		val += in.Value.Int64()
-		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
-		if err != nil {
-			return nil, err
-		}
-		script, err := txscript.PayToAddrScript(in.LinkedAddress)
-		if err != nil {
-			return nil, err
-		}
-		outpoint := wire.NewOutPoint(ch, in.OutpointIndex)
-		input := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
-		inputs = append(inputs, input)
-		additionalPrevScripts[*outpoint] = script
-	}
-	out := wire.NewTxOut(val, script)
-
-	txType := P2PKH
-	if redeemScript != nil {
-		txType = P2SH_1of2_Multisig
-		_, err := spvwallet.LockTimeFromRedeemScript(*redeemScript)
-		if err == nil {
-			txType = P2SH_Multisig_Timelock_1Sig
-		}
-	}
-	estimatedSize := EstimateSerializeSize(len(ins), []*wire.TxOut{out}, false, txType)
-
-	// Calculate the fee
-	f := w.GetFeePerByte(feeLevel)
-	feePerByte := int(f.Int64())
-	fee := estimatedSize * feePerByte
-
-	outVal := val - int64(fee)
-	if outVal < 0 {
-		outVal = 0
-	}
-	out.Value = outVal
-
-	tx := &wire.MsgTx{
-		Version:  wire.TxVersion,
-		TxIn:     inputs,
-		TxOut:    []*wire.TxOut{out},
-		LockTime: 0,
-	}
-
-	// BIP 69 sorting
-	txsort.InPlaceSort(tx)
-
-	// Sign tx
-	privKey, err := key.ECPrivKey()
-	if err != nil {
-		return nil, fmt.Errorf("retrieving private key: %s", err.Error())
-	}
-	pk := privKey.PubKey().SerializeCompressed()
-	addressPub, err := btc.NewAddressPubKey(pk, w.params)
-	if err != nil {
-		return nil, fmt.Errorf("generating address pub key: %s", err.Error())
-	}
-
-	getKey := txscript.KeyClosure(func(addr btc.Address) (*btcec.PrivateKey, bool, error) {
-		if addressPub.EncodeAddress() == addr.EncodeAddress() {
-			wif, err := btc.NewWIF(privKey, w.params, true)
-			if err != nil {
-				return nil, false, err
-			}
-			return wif.PrivKey, wif.CompressPubKey, nil
-		}
-		return nil, false, errors.New("Not found")
-	})
-	getScript := txscript.ScriptClosure(func(addr btc.Address) ([]byte, error) {
-		if redeemScript == nil {
-			return []byte{}, nil
-		}
-		return *redeemScript, nil
-	})
-
-	// Check if time locked
-	var timeLocked bool
-	if redeemScript != nil {
-		rs := *redeemScript
-		if rs[0] == txscript.OP_IF {
-			timeLocked = true
-			tx.Version = 2
-			for _, txIn := range tx.TxIn {
-				locktime, err := spvwallet.LockTimeFromRedeemScript(*redeemScript)
-				if err != nil {
-					return nil, err
-				}
-				txIn.Sequence = locktime
-			}
-		}
+	if len(addrs) == 0 {
+		return nil, errors.New("unknown script")
 	}
-
-	hashes := txscript.NewTxSigHashes(tx)
-	for i, txIn := range tx.TxIn {
-		if redeemScript == nil {
-			prevOutScript := additionalPrevScripts[txIn.PreviousOutPoint]
-			script, err := txscript.SignTxOutput(w.params,
-				tx, i, prevOutScript, txscript.SigHashAll, getKey,
-				getScript, txIn.SignatureScript)
-			if err != nil {
-				return nil, errors.New("Failed to sign transaction")
-			}
-			txIn.SignatureScript = script
-		} else {
-			sig, err := txscript.RawTxInWitnessSignature(tx, hashes, i, ins[i].Value.Int64(), *redeemScript, txscript.SigHashAll, privKey)
-			if err != nil {
-				return nil, err
-			}
-			var witness wire.TxWitness
-			if timeLocked {
-				witness = wire.TxWitness{sig, []byte{}}
-			} else {
-				witness = wire.TxWitness{[]byte{}, sig}
-			}
-			witness = append(witness, *redeemScript)
-			txIn.Witness = witness
-		}
-	}
-
-	// broadcast
-	if err := w.Broadcast(tx); err != nil {
-		return nil, err
-	}
-	txid := tx.TxHash()
-	return &txid, nil
+	return addrs[0], nil
 }
 
-func (w *BitcoinWallet) createMultisigSignature(ins []wi.TransactionInput, outs []wi.TransactionOutput, key *hd.ExtendedKey, redeemScript []byte, feePerByte uint64) ([]wi.Signature, error) {
-	var sigs []wi.Signature
-	tx := wire.NewMsgTx(1)
-	for _, in := range ins {
-		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
-		if err != nil {
-			return sigs, err
-		}
-		outpoint := wire.NewOutPoint(ch, in.OutpointIndex)
-		input := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
-		tx.TxIn = append(tx.TxIn, input)
-	}
-	for _, out := range outs {
-		scriptPubKey, err := txscript.PayToAddrScript(out.Address)
-		if err != nil {
-			return sigs, err
-		}
-		output := wire.NewTxOut(out.Value.Int64(), scriptPubKey)
-		tx.TxOut = append(tx.TxOut, output)
-	}
-
-	// Subtract fee
-	txType := P2SH_2of3_Multisig
-	_, err := spvwallet.LockTimeFromRedeemScript(redeemScript)
-	if err == nil {
-		txType = P2SH_Multisig_Timelock_2Sigs
-	}
-	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, txType)
-	fee := estimatedSize * int(feePerByte)
-	if len(tx.TxOut) > 0 {
-		feePerOutput := fee / len(tx.TxOut)
-		for _, output := range tx.TxOut {
-			output.Value -= int64(feePerOutput)
-		}
-	}
-
-	// BIP 69 sorting
-	txsort.InPlaceSort(tx)
-
-	signingKey, err := key.ECPrivKey()
-	if err != nil {
-		return sigs, err
-	}
-
-	hashes := txscript.NewTxSigHashes(tx)
-	for i := range tx.TxIn {
-		sig, err := txscript.RawTxInWitnessSignature(tx, hashes, i, ins[i].Value.Int64(), redeemScript, txscript.SigHashAll, signingKey)
-		if err != nil {
-			continue
-		}
-		bs := wi.Signature{InputIndex: uint32(i), Signature: sig}
-		sigs = append(sigs, bs)
-	}
-	return sigs, nil
+func (bitcoinCoin) ScriptHashAddress(redeemScript []byte, params *chaincfg.Params) (btc.Address, error) {
+	witnessProgram := sha256.Sum256(redeemScript)
+	return btc.NewAddressWitnessScriptHash(witnessProgram[:], params)
 }
 
-func (w *BitcoinWallet) multisign(ins []wi.TransactionInput, outs []wi.TransactionOutput, sigs1 []wi.Signature, sigs2 []wi.Signature, redeemScript []byte, feePerByte uint64, broadcast bool) ([]byte, error) {
-	tx := wire.NewMsgTx(1)
-	for _, in := range ins {
-		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
-		if err != nil {
-			return nil, err
-		}
-		outpoint := wire.NewOutPoint(ch, in.OutpointIndex)
-		input := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
-		tx.TxIn = append(tx.TxIn, input)
-	}
-	for _, out := range outs {
-		scriptPubKey, err := txscript.PayToAddrScript(out.Address)
-		if err != nil {
-			return nil, err
-		}
-		output := wire.NewTxOut(out.Value.Int64(), scriptPubKey)
-		tx.TxOut = append(tx.TxOut, output)
-	}
-
-	// Subtract fee
-	txType := P2SH_2of3_Multisig
-	_, err := spvwallet.LockTimeFromRedeemScript(redeemScript)
-	if err == nil {
-		txType = P2SH_Multisig_Timelock_2Sigs
-	}
-	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, txType)
-	fee := estimatedSize * int(feePerByte)
-	if len(tx.TxOut) > 0 {
-		feePerOutput := fee / len(tx.TxOut)
-		for _, output := range tx.TxOut {
-			output.Value -= int64(feePerOutput)
-		}
-	}
-
-	// BIP 69 sorting
-	txsort.InPlaceSort(tx)
-
-	// Check if time locked
-	var timeLocked bool
-	if redeemScript[0] == txscript.OP_IF {
-		timeLocked = true
-	}
-
-	for i, input := range tx.TxIn {
-		var sig1 []byte
-		var sig2 []byte
-		for _, sig := range sigs1 {
-			if int(sig.InputIndex) == i {
-				sig1 = sig.Signature
-				break
-			}
-		}
-		for _, sig := range sigs2 {
-			if int(sig.InputIndex) == i {
-				sig2 = sig.Signature
-				break
-			}
-		}
-
-		witness := wire.TxWitness{[]byte{}, sig1, sig2}
-
-		if timeLocked {
-			witness = append(witness, []byte{0x01})
-		}
-		witness = append(witness, redeemScript)
-		input.Witness = witness
-	}
-	// broadcast
-	if broadcast {
-		if err := w.Broadcast(tx); err != nil {
-			return nil, err
-		}
-	}
-	var buf bytes.Buffer
-	tx.BtcEncode(&buf, wire.ProtocolVersion, wire.WitnessEncoding)
-	return buf.Bytes(), nil
+func (bitcoinCoin) SignP2PKH(tx *wire.MsgTx, idx int, prevOuts []*wire.TxOut, key *btcec.PrivateKey) ([]byte, error) {
+	return txscript.SignatureScript(tx, idx, prevOuts[idx].PkScript, txscript.SigHashAll, key, true)
 }
 
-func (w *BitcoinWallet) generateMultisigScript(keys []hd.ExtendedKey, threshold int, timeout time.Duration, timeoutKey *hd.ExtendedKey) (addr btc.Address, redeemScript []byte, err error) {
-	if uint32(timeout.Hours()) > 0 && timeoutKey == nil {
-		return nil, nil, errors.New("Timeout key must be non nil when using an escrow timeout")
-	}
-
-	if len(keys) < threshold {
-		return nil, nil, fmt.Errorf("unable to generate multisig script with "+
-			"%d required signatures when there are only %d public "+
-			"keys available", threshold, len(keys))
-	}
-
-	var ecKeys []*btcec.PublicKey
-	for _, key := range keys {
-		ecKey, err := key.ECPubKey()
-		if err != nil {
-			return nil, nil, err
-		}
-		ecKeys = append(ecKeys, ecKey)
-	}
-
-	builder := txscript.NewScriptBuilder()
-	if uint32(timeout.Hours()) == 0 {
-
-		builder.AddInt64(int64(threshold))
-		for _, key := range ecKeys {
-			builder.AddData(key.SerializeCompressed())
-		}
-		builder.AddInt64(int64(len(ecKeys)))
-		builder.AddOp(txscript.OP_CHECKMULTISIG)
-
-	} else {
-		ecKey, err := timeoutKey.ECPubKey()
-		if err != nil {
-			return nil, nil, err
-		}
-		sequenceLock := blockchain.LockTimeToSequence(false, uint32(timeout.Hours()*6))
-		builder.AddOp(txscript.OP_IF)
-		builder.AddInt64(int64(threshold))
-		for _, key := range ecKeys {
-			builder.AddData(key.SerializeCompressed())
-		}
-		builder.AddInt64(int64(len(ecKeys)))
-		builder.AddOp(txscript.OP_CHECKMULTISIG)
-		builder.AddOp(txscript.OP_ELSE).
-			AddInt64(int64(sequenceLock)).
-			AddOp(txscript.OP_CHECKSEQUENCEVERIFY).
-			AddOp(txscript.OP_DROP).
-			AddData(ecKey.SerializeCompressed()).
-			AddOp(txscript.OP_CHECKSIG).
-			AddOp(txscript.OP_ENDIF)
-	}
-	redeemScript, err = builder.Script()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	witnessProgram := sha256.Sum256(redeemScript)
+func (bitcoinCoin) SignScript(tx *wire.MsgTx, idx int, redeemScript []byte, prevOuts []*wire.TxOut, key *btcec.PrivateKey) ([]byte, error) {
+	return txscript.RawTxInWitnessSignature(tx, txscript.NewTxSigHashes(tx), idx, prevOuts[idx].Value, redeemScript, txscript.SigHashAll, key)
+}
 
-	addr, err = btc.NewAddressWitnessScriptHash(witnessProgram[:], w.params)
-	if err != nil {
-		return nil, nil, err
+func (bitcoinCoin) Serialize(tx *wire.MsgTx) ([]byte, chainhash.Hash, error) {
+	var buf bytes.Buffer
+	if err := tx.BtcEncode(&buf, wire.ProtocolVersion, wire.WitnessEncoding); err != nil {
+		return nil, chainhash.Hash{}, err
 	}
-	return addr, redeemScript, nil
+	return buf.Bytes(), tx.TxHash(), nil
 }
 
-func (w *BitcoinWallet) estimateSpendFee(amount int64, feeLevel wi.FeeLevel) (uint64, error) {
-	// Since this is an estimate we can use a dummy output address. Let's use a long one so we don't under estimate.
-	addr, err := btc.DecodeAddress("bc1qxtq7ha2l5qg70atpwp3fus84fx3w0v2w4r2my7gt89ll3w0vnlgspu349h", &chaincfg.MainNetParams)
-	if err != nil {
-		return 0, err
-	}
-	tx, err := w.buildTx(amount, addr, feeLevel, nil)
-	if err != nil {
-		return 0, err
-	}
-	var outval int64
-	for _, output := range tx.TxOut {
-		outval += output.Value
-	}
-	var inval int64
-	utxos, err := w.db.Utxos().GetAll()
-	if err != nil {
-		return 0, err
-	}
-	for _, input := range tx.TxIn {
-		for _, utxo := range utxos {
-			if utxo.Op.Hash.IsEqual(&input.PreviousOutPoint.Hash) && utxo.Op.Index == input.PreviousOutPoint.Index {
-				val, _ := strconv.ParseInt(utxo.Value, 10, 64)
-				inval += val
-				break
-			}
-		}
-	}
-	if inval < outval {
-		return 0, errors.New("Error building transaction: inputs less than outputs")
-	}
-	return uint64(inval - outval), err
+func (bitcoinCoin) ToWire(raw []byte) []byte {
+	return raw
 }
@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"encoding/hex"
 	"github.com/OpenBazaar/multiwallet/util"
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/OpenBazaar/multiwallet/cache"
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
 	"github.com/OpenBazaar/multiwallet/datastore"
 	"github.com/OpenBazaar/multiwallet/keys"
 	"github.com/OpenBazaar/multiwallet/model/mock"
 	"github.com/OpenBazaar/multiwallet/service"
+	"github.com/OpenBazaar/multiwallet/utxo"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -20,6 +23,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/op/go-logging"
 )
 
 type FeeResponse struct {
@@ -53,20 +57,22 @@ func newMockWallet() (*BitcoinWallet, error) {
 
 	fp := spvwallet.NewFeeProvider(2000, 300, 200, 100, "", nil)
 
-	bw := &BitcoinWallet{
-		params: params,
-		km:     km,
-		db:     db,
-		fp:     fp,
-	}
+	bw := &BitcoinWallet{&utxo.Wallet{
+		Coin:        bitcoinCoin{},
+		ChainParams: params,
+		KM:          km,
+		DB:          db,
+		Fees:        fp,
+		Log:         logging.MustGetLogger("bitcoin-wallet"),
+	}}
 	cli := mock.NewMockApiClient(bw.AddressToScript)
 	ws, err := service.NewWalletService(db, km, cli, params, wallet.Bitcoin, cache.NewMockCacher())
 	if err != nil {
 		return nil, err
 	}
 
-	bw.client = cli
-	bw.ws = ws
+	bw.Client = cli
+	bw.WS = ws
 	return bw, nil
 }
 
@@ -76,9 +82,9 @@ func TestWalletService_VerifyWatchScriptFilter(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	keys := w.km.GetKeys()
+	keys := w.KM.GetKeys()
 
-	addr, err := w.km.KeyToAddress(keys[0])
+	addr, err := w.KM.KeyToAddress(keys[0])
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -87,7 +93,7 @@ func TestWalletService_VerifyWatchScriptFilter(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	watchScripts, err := w.db.WatchedScripts().GetAll()
+	watchScripts, err := w.DB.WatchedScripts().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -114,7 +120,7 @@ func TestWalletService_VerifyWatchScriptPut(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	watchScripts, err := w.db.WatchedScripts().GetAll()
+	watchScripts, err := w.DB.WatchedScripts().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -150,63 +156,235 @@ func TestBitcoinWallet_buildTx(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 
-	waitForTxnSync(t, w.db.Txns())
+	waitForTxnSync(t, w.DB.Txns())
 	addr, err := w.DecodeAddress("1AhsMpyyyVyPZ9KDUgwsX3zTDJWWSsRo4f")
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test build normal tx
-	tx, err := w.buildTx(1500000, addr, wallet.NORMAL, nil)
+	tx, err := w.BuildTx(1500000, addr, wallet.NORMAL, nil)
 	if err != nil {
 		t.Error(err)
 	}
 	if !containsOutput(tx, addr) {
 		t.Error("Built tx does not contain the requested output")
 	}
-	if !validInputs(tx, w.db) {
+	if !validInputs(tx, w.DB) {
 		t.Error("Built tx does not contain valid inputs")
 	}
-	if !validChangeAddress(tx, w.db, w.params) {
+	if !validChangeAddress(tx, w.DB, w.ChainParams) {
 		t.Error("Built tx does not contain a valid change output")
 	}
 
 	// Insuffient funds
-	_, err = w.buildTx(1000000000, addr, wallet.NORMAL, nil)
+	_, err = w.BuildTx(1000000000, addr, wallet.NORMAL, nil)
 	if err != wallet.ErrorInsuffientFunds {
 		t.Error("Failed to throw insuffient funds error")
 	}
 
 	// Dust
-	_, err = w.buildTx(1, addr, wallet.NORMAL, nil)
+	_, err = w.BuildTx(1, addr, wallet.NORMAL, nil)
 	if err != wallet.ErrorDustAmount {
 		t.Error("Failed to throw dust error")
 	}
 }
 
+func TestBitcoinWallet_buildSpendManyTx(t *testing.T) {
+	w, err := newMockWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	addr1, err := w.DecodeAddress("1AhsMpyyyVyPZ9KDUgwsX3zTDJWWSsRo4f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr2, err := w.DecodeAddress("1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := []wallet.TransactionOutput{
+		{Address: addr1, Value: *big.NewInt(500000)},
+		{Address: addr2, Value: *big.NewInt(700000)},
+	}
+	tx, err := w.BuildSpendManyTx(outputs, wallet.NORMAL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsOutput(tx, addr1) || !containsOutput(tx, addr2) {
+		t.Error("Built tx does not pay every recipient")
+	}
+	if !validInputs(tx, w.DB) {
+		t.Error("Built tx does not contain valid inputs")
+	}
+	if !validChangeAddress(tx, w.DB, w.ChainParams) {
+		t.Error("Built tx does not contain a valid change output")
+	}
+
+	// One dust output fails the whole batch
+	outputs[1].Value = *big.NewInt(1)
+	if _, err := w.BuildSpendManyTx(outputs, wallet.NORMAL, ""); clientErr.KindOf(err) != clientErr.KindDust {
+		t.Errorf("Expected dust error, got %v", err)
+	}
+
+	if _, err := w.BuildSpendManyTx(nil, wallet.NORMAL, ""); err == nil {
+		t.Error("Expected an error building a tx without outputs")
+	}
+}
+
+func TestBitcoinWallet_buildTxFromOutpoints(t *testing.T) {
+	w, err := newMockWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	addr, err := w.DecodeAddress("1AhsMpyyyVyPZ9KDUgwsX3zTDJWWSsRo4f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxos, err := w.DB.Utxos().GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chosen wallet.Utxo
+	for _, u := range utxos {
+		if !u.WatchOnly {
+			chosen = u
+			break
+		}
+	}
+
+	outputs := []wallet.TransactionOutput{{Address: addr, Value: *big.NewInt(10000)}}
+	tx, err := w.BuildTxFromOutpoints([]wire.OutPoint{chosen.Op}, outputs, wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxIn) != 1 || tx.TxIn[0].PreviousOutPoint != chosen.Op {
+		t.Errorf("Built tx does not spend exactly the chosen output")
+	}
+	if !containsOutput(tx, addr) {
+		t.Error("Built tx does not pay the recipient")
+	}
+	if !validChangeAddress(tx, w.DB, w.ChainParams) {
+		t.Error("Built tx does not contain a valid change output")
+	}
+
+	// Frozen outputs can be neither chosen nor selected
+	if err := w.FreezeUtxo(chosen.Op); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.BuildTxFromOutpoints([]wire.OutPoint{chosen.Op}, outputs, wallet.NORMAL); err == nil {
+		t.Error("Expected an error spending a frozen output")
+	}
+	tx, err = w.BuildSpendManyTx(outputs, wallet.NORMAL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range tx.TxIn {
+		if in.PreviousOutPoint == chosen.Op {
+			t.Error("Coin selection spent a frozen output")
+		}
+	}
+}
+
+func TestBitcoinWallet_Consolidate(t *testing.T) {
+	w, err := newMockWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	w.Consolidation = util.ConsolidationPolicy{MaxUtxoValue: 1e10, MinUtxos: 2}
+
+	plan, err := w.Consolidate(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Txid != "" {
+		t.Error("Dry run broadcast a consolidation")
+	}
+	if plan.WithinFeeLimit {
+		t.Error("Expected automatic consolidation to be disabled without a fee limit")
+	}
+
+	tx, err := w.BuildSweepTx(plan.Utxos, w.CurrentAddress(wallet.INTERNAL), wallet.ECONOMIC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxIn) != len(plan.Utxos) || len(tx.TxOut) != 1 {
+		t.Fatalf("Expected %d inputs and one output, got %d and %d", len(plan.Utxos), len(tx.TxIn), len(tx.TxOut))
+	}
+	if fee := plan.Value - tx.TxOut[0].Value; fee != plan.Fee {
+		t.Errorf("Expected the consolidation to pay the planned fee of %d, paid %d", plan.Fee, fee)
+	}
+}
+
+func TestBitcoinWallet_ConsolidatePending(t *testing.T) {
+	w, err := newMockWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	w.Consolidation = util.ConsolidationPolicy{MaxUtxoValue: 1e10, MinUtxos: 2}
+
+	plan, err := w.Consolidate(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Txid == "" {
+		t.Fatal("Expected the consolidation to be broadcast")
+	}
+	// A server which has not seen the consolidation yet lists its inputs as unspent again
+	spent := make(map[wire.OutPoint]bool)
+	for _, u := range plan.Utxos {
+		spent[u.Op] = true
+		if err := w.DB.Utxos().Put(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	next, err := w.Consolidate(true)
+	if err == util.ErrNothingToConsolidate {
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range next.Utxos {
+		if spent[u.Op] {
+			t.Errorf("Planned consolidating %s which a pending consolidation spends", u.Op)
+		}
+	}
+}
+
 func TestBitcoinWallet_buildSpendAllTx(t *testing.T) {
 	w, err := newMockWallet()
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 
-	waitForTxnSync(t, w.db.Txns())
+	waitForTxnSync(t, w.DB.Txns())
 	addr, err := w.DecodeAddress("1AhsMpyyyVyPZ9KDUgwsX3zTDJWWSsRo4f")
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test build spendAll tx
-	tx, err := w.buildSpendAllTx(addr, wallet.NORMAL)
+	tx, err := w.BuildSpendAllTx(addr, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -222,7 +400,7 @@ func TestBitcoinWallet_buildSpendAllTx(t *testing.T) {
 	if !containsOutput(tx, addr) {
 		t.Error("Built tx does not contain the requested output")
 	}
-	if !validInputs(tx, w.db) {
+	if !validInputs(tx, w.DB) {
 		t.Error("Built tx does not contain valid inputs")
 	}
 	if len(tx.TxOut) != 1 {
@@ -294,22 +472,22 @@ func TestBitcoinWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
-	key3, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key3, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
 	keys := []hdkeychain.ExtendedKey{*key1, *key2, *key3}
 
 	// test without timeout
-	addr, redeemScript, err := w.generateMultisigScript(keys, 2, 0, nil)
+	addr, redeemScript, err := w.GenerateMultisigScript(keys, 2, 0, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -335,11 +513,11 @@ func TestBitcoinWallet_GenerateMultisigScript(t *testing.T) {
 	}
 
 	// test with timeout
-	key4, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key4, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
-	addr, redeemScript, err = w.generateMultisigScript(keys, 2, time.Hour*10, key4)
+	addr, redeemScript, err = w.GenerateMultisigScript(keys, 2, time.Hour*10, key4)
 	if err != nil {
 		t.Error(err)
 	}
@@ -380,9 +558,9 @@ func TestBitcoinWallet_newUnsignedTransaction(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
-	waitForTxnSync(t, w.db.Txns())
-	utxos, err := w.db.Utxos().GetAll()
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
@@ -416,7 +594,7 @@ func TestBitcoinWallet_newUnsignedTransaction(t *testing.T) {
 	}
 
 	// Regular transaction
-	authoredTx, err := newUnsignedTransaction(outputs, btcutil.Amount(1000), inputSource, changeSource)
+	authoredTx, err := utxo.NewUnsignedTransaction(bitcoinFeatures, outputs, btcutil.Amount(1000), inputSource, changeSource)
 	if err != nil {
 		t.Error(err)
 	}
@@ -429,7 +607,7 @@ func TestBitcoinWallet_newUnsignedTransaction(t *testing.T) {
 
 	// Insufficient funds
 	outputs[0].Value = 1000000000
-	_, err = newUnsignedTransaction(outputs, btcutil.Amount(1000), inputSource, changeSource)
+	_, err = utxo.NewUnsignedTransaction(bitcoinFeatures, outputs, btcutil.Amount(1000), inputSource, changeSource)
 	if err == nil {
 		t.Error("Failed to return insuffient funds error")
 	}
@@ -445,7 +623,7 @@ func TestBitcoinWallet_CreateMultisigSignature(t *testing.T) {
 		t.Error(err)
 	}
 
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -508,12 +686,12 @@ func TestBitcoinWallet_Multisign(t *testing.T) {
 		t.Error(err)
 	}
 
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
 
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -557,51 +735,148 @@ func TestBitcoinWallet_bumpFee(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
-	waitForTxnSync(t, w.db.Txns())
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
 	ch, err := chainhash.NewHashFromStr("ff2b865c3b73439912eebf4cce9a15b12c7d7bcdd14ae1110a90541426c4e7c5")
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
 	for _, u := range utxos {
 		if u.Op.Hash.IsEqual(ch) {
 			u.AtHeight = 0
-			w.db.Utxos().Put(u)
+			w.DB.Utxos().Put(u)
 		}
 	}
 
-	w.db.Txns().UpdateHeight(*ch, 0, time.Now())
+	w.DB.Txns().UpdateHeight(*ch, 0, time.Now())
 
 	// Test unconfirmed
-	_, err = w.bumpFee(*ch)
+	_, err = w.BumpFee(*ch)
 	if err != nil {
 		t.Error(err)
 	}
 
-	err = w.db.Txns().UpdateHeight(*ch, 1289597, time.Now())
+	err = w.DB.Txns().UpdateHeight(*ch, 1289597, time.Now())
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test confirmed
-	_, err = w.bumpFee(*ch)
+	_, err = w.BumpFee(*ch)
 	if err == nil {
 		t.Error("Should not be able to bump fee of confirmed txs")
 	}
 }
 
+func TestBitcoinWallet_bumpFeeReplacesTx(t *testing.T) {
+	w, err := newMockWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	addr, err := w.DecodeAddress("1AhsMpyyyVyPZ9KDUgwsX3zTDJWWSsRo4f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := w.BuildTx(1500000, addr, wallet.NORMAL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Broadcast(original); err != nil {
+		t.Fatal(err)
+	}
+
+	originalID := original.TxHash()
+	replacementID, err := w.BumpFee(originalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err := w.DB.Txns().Get(*replacementID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacement := wire.NewMsgTx(wire.TxVersion)
+	if err := replacement.Deserialize(bytes.NewReader(txn.Bytes)); err != nil {
+		t.Fatal(err)
+	}
+	if !containsOutput(replacement, addr) {
+		t.Error("Replacement does not pay the original recipient")
+	}
+	spent := make(map[wire.OutPoint]bool)
+	for _, in := range replacement.TxIn {
+		spent[in.PreviousOutPoint] = true
+	}
+	for _, in := range original.TxIn {
+		if !spent[in.PreviousOutPoint] {
+			t.Errorf("Replacement does not spend original input %s", in.PreviousOutPoint.String())
+		}
+	}
+	var originalOut, replacementOut int64
+	for _, out := range original.TxOut {
+		originalOut += out.Value
+	}
+	for _, out := range replacement.TxOut {
+		replacementOut += out.Value
+	}
+	if len(replacement.TxIn) == len(original.TxIn) && replacementOut >= originalOut {
+		t.Error("Replacement does not pay a higher fee")
+	}
+	if txn, err := w.DB.Txns().Get(originalID); err != nil || txn.Height >= 0 {
+		t.Error("Original transaction was not marked replaced")
+	}
+}
+
+func TestBitcoinWallet_bumpFeeReportsReplacementError(t *testing.T) {
+	w, err := newMockWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	ch, err := chainhash.NewHashFromStr("ff2b865c3b73439912eebf4cce9a15b12c7d7bcdd14ae1110a90541426c4e7c5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err := w.DB.Txns().Get(*ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An unconfirmed copy which does not signal replaceability and has no output of ours for a
+	// child to spend
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(txn.Bytes)); err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range tx.TxIn {
+		in.Sequence = wire.MaxTxInSequenceNum
+	}
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.DB.Txns().Put(buf.Bytes(), tx.TxHash().String(), txn.Value, 0, time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.BumpFee(tx.TxHash()); err != util.ErrNotReplaceable {
+		t.Errorf("Expected the replacement error, got %v", err)
+	}
+}
+
 func TestBitcoinWallet_sweepAddress(t *testing.T) {
 	w, err := newMockWallet()
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
-	waitForTxnSync(t, w.db.Txns())
-	utxos, err := w.db.Utxos().GetAll()
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
@@ -614,7 +889,7 @@ func TestBitcoinWallet_sweepAddress(t *testing.T) {
 			if err != nil {
 				t.Error(err)
 			}
-			key, err = w.km.GetKeyForScript(addr.ScriptAddress())
+			key, err = w.KM.GetKeyForScript(addr.ScriptAddress())
 			if err != nil {
 				t.Error(err)
 			}
@@ -631,7 +906,7 @@ func TestBitcoinWallet_sweepAddress(t *testing.T) {
 		}
 	}
 	// P2PKH addr
-	_, err = w.sweepAddress([]wallet.TransactionInput{in}, nil, key, nil, wallet.NORMAL)
+	_, err = w.SweepAddress([]wallet.TransactionInput{in}, nil, key, nil, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 		return
@@ -656,12 +931,12 @@ func TestBitcoinWallet_sweepAddress(t *testing.T) {
 			}
 		}
 	}
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
 
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -669,7 +944,7 @@ func TestBitcoinWallet_sweepAddress(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	_, err = w.sweepAddress([]wallet.TransactionInput{in}, nil, key1, &redeemScript, wallet.NORMAL)
+	_, err = w.SweepAddress([]wallet.TransactionInput{in}, nil, key1, &redeemScript, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -680,13 +955,13 @@ func TestBitcoinWallet_estimateSpendFee(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
-	waitForTxnSync(t, w.db.Txns())
-	fee, err := w.estimateSpendFee(1000, wallet.NORMAL)
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	fee, err := w.EstimateSpendFee(*big.NewInt(1000), wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
-	if fee == 0 {
+	if fee.Sign() == 0 {
 		t.Error("Returned incorrect fee")
 	}
 }
@@ -0,0 +1,149 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Bitcoin Cash accepts 64 byte Schnorr signatures wherever it accepts ECDSA ones since the May
+// 2019 upgrade. They are smaller than DER encoded ECDSA signatures so P2PKH inputs are signed
+// with them. Multisig inputs stay on ECDSA since OP_CHECKMULTISIG only accepts Schnorr
+// signatures with a different dummy element, which the other parties to an escrow do not expect.
+
+// sigHashForkID marks signatures committing to the input amount under the BIP 143 style
+// algorithm Bitcoin Cash has used since it forked
+const sigHashForkID txscript.SigHashType = 0x40
+
+// schnorrNonceData is the additional data the specification mixes into RFC 6979 nonce
+// generation so Schnorr and ECDSA signatures of the same hash never share a nonce
+var schnorrNonceData = []byte("Schnorr+SHA256  ")
+
+// calcSignatureHash returns the hash signed by input idx of tx spending amt from subScript
+func calcSignatureHash(tx *wire.MsgTx, idx int, subScript []byte, hashType txscript.SigHashType, amt int64) ([]byte, error) {
+	return txscript.CalcWitnessSigHash(subScript, txscript.NewTxSigHashes(tx), hashType|sigHashForkID, tx, idx, amt)
+}
+
+// signP2PKHInput returns the signature script spending the P2PKH output prevOutScript worth amt
+// with a Schnorr signature by key
+func signP2PKHInput(tx *wire.MsgTx, idx int, prevOutScript []byte, key *btcec.PrivateKey, amt int64) ([]byte, error) {
+	hash, err := calcSignatureHash(tx, idx, prevOutScript, txscript.SigHashAll, amt)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := schnorrSign(key, hash)
+	if err != nil {
+		return nil, err
+	}
+	return txscript.NewScriptBuilder().
+		AddData(append(sig, byte(txscript.SigHashAll|sigHashForkID))).
+		AddData(key.PubKey().SerializeCompressed()).
+		Script()
+}
+
+// schnorrSign returns the 64 byte Bitcoin Cash Schnorr signature of hash by key
+func schnorrSign(key *btcec.PrivateKey, hash []byte) ([]byte, error) {
+	curve := btcec.S256()
+	n := curve.Params().N
+	if len(hash) != 32 {
+		return nil, errors.New("hash must be 32 bytes")
+	}
+
+	k := schnorrNonce(key.D, hash)
+	rx, ry := curve.ScalarBaseMult(k.Bytes())
+	// R must have a y coordinate which is a quadratic residue
+	if big.Jacobi(ry, curve.Params().P) != 1 {
+		k.Sub(n, k)
+	}
+
+	e := schnorrChallenge(rx, key.PubKey(), hash)
+	s := new(big.Int).Mul(e, key.D)
+	s.Add(s, k)
+	s.Mod(s, n)
+
+	sig := make([]byte, 64)
+	putScalar(sig[:32], rx)
+	putScalar(sig[32:], s)
+	return sig, nil
+}
+
+// schnorrVerify reports whether sig is a valid Bitcoin Cash Schnorr signature of hash by pub
+func schnorrVerify(pub *btcec.PublicKey, hash, sig []byte) bool {
+	curve := btcec.S256()
+	if len(sig) != 64 || len(hash) != 32 {
+		return false
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if r.Cmp(curve.Params().P) >= 0 || s.Cmp(curve.Params().N) >= 0 {
+		return false
+	}
+
+	// R = sG - eP
+	e := schnorrChallenge(r, pub, hash)
+	e.Sub(curve.Params().N, e)
+	sx, sy := curve.ScalarBaseMult(s.Bytes())
+	ex, ey := curve.ScalarMult(pub.X, pub.Y, e.Bytes())
+	rx, ry := curve.Add(sx, sy, ex, ey)
+	if rx.Sign() == 0 && ry.Sign() == 0 {
+		return false
+	}
+	return big.Jacobi(ry, curve.Params().P) == 1 && rx.Cmp(r) == 0
+}
+
+// schnorrChallenge returns SHA256(r || compressed pub || hash) as a scalar
+func schnorrChallenge(r *big.Int, pub *btcec.PublicKey, hash []byte) *big.Int {
+	var rBytes [32]byte
+	putScalar(rBytes[:], r)
+	h := sha256.New()
+	h.Write(rBytes[:])
+	h.Write(pub.SerializeCompressed())
+	h.Write(hash)
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	return e.Mod(e, btcec.S256().Params().N)
+}
+
+// schnorrNonce generates a nonce deterministically according to RFC 6979 with schnorrNonceData
+// as additional data
+func schnorrNonce(d *big.Int, hash []byte) *big.Int {
+	n := btcec.S256().Params().N
+	mac := func(k []byte, m ...[]byte) []byte {
+		h := hmac.New(sha256.New, k)
+		for _, b := range m {
+			h.Write(b)
+		}
+		return h.Sum(nil)
+	}
+
+	var x, h [32]byte
+	putScalar(x[:], d)
+	putScalar(h[:], new(big.Int).Mod(new(big.Int).SetBytes(hash), n))
+
+	v := bytes.Repeat([]byte{0x01}, 32)
+	k := make([]byte, 32)
+	k = mac(k, v, []byte{0x00}, x[:], h[:], schnorrNonceData)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x[:], h[:], schnorrNonceData)
+	v = mac(k, v)
+	for {
+		v = mac(k, v)
+		secret := new(big.Int).SetBytes(v)
+		if secret.Sign() > 0 && secret.Cmp(n) < 0 {
+			return secret
+		}
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
+
+// putScalar writes n big endian into the 32 byte dst, padding with leading zeros
+func putScalar(dst []byte, n *big.Int) {
+	b := n.Bytes()
+	copy(dst[len(dst)-len(b):], b)
+}
@@ -0,0 +1,121 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/OpenBazaar/multiwallet/utxo"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Vectors from the Bitcoin Cash Schnorr signature specification
+func TestSchnorrVerify(t *testing.T) {
+	tests := []struct {
+		pubKey, msg, sig string
+		valid            bool
+	}{
+		{
+			"0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798",
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"787A848E71043D280C50470E8E1532B2DD5D20EE912A45DBDD2BD1DFBF187EF67031A98831859DC34DFFEEDDA86831842CCD0079E1F92AF177F7F22CC1DCED05",
+			true,
+		},
+		{
+			"02DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+			"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+			"2A298DACAE57395A15D0795DDBFD1DCB564DA82B0F269BC70A74F8220429BA1D1E51A22CCEC35599B8F266912281F8365FFC2D035A230434A1A64DC59F7013FD",
+			true,
+		},
+		{
+			"02DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+			"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C88",
+			"2A298DACAE57395A15D0795DDBFD1DCB564DA82B0F269BC70A74F8220429BA1D1E51A22CCEC35599B8F266912281F8365FFC2D035A230434A1A64DC59F7013FD",
+			false,
+		},
+	}
+	for i, test := range tests {
+		pub, err := btcec.ParsePubKey(mustDecodeHex(t, test.pubKey), btcec.S256())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if schnorrVerify(pub, mustDecodeHex(t, test.msg), mustDecodeHex(t, test.sig)) != test.valid {
+			t.Errorf("Test %d: expected valid=%t", i, test.valid)
+		}
+	}
+}
+
+func TestSchnorrSign(t *testing.T) {
+	// The expected signature was computed with an independent implementation using the RFC 6979
+	// nonce with additional data the specification recommends
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), mustDecodeHex(t, "B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF"))
+	msg := mustDecodeHex(t, "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89")
+	sig, err := schnorrSign(key, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "e87cec707424360691ebf78b40be9bf5fbcfcf4cda8a9e49fb1a550fe00dfb6037170b5c71423897409718c46f7cd8a9f4a398fa5d367a539a60e62aaa2fc11a"
+	if hex.EncodeToString(sig) != expected {
+		t.Errorf("Wrong signature %x", sig)
+	}
+	if !schnorrVerify(key.PubKey(), msg, sig) {
+		t.Error("Failed to verify signature")
+	}
+}
+
+func TestSignP2PKHInput(t *testing.T) {
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), chainhash.DoubleHashB([]byte("key")))
+	prevOutScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).
+		AddData(chainhash.DoubleHashB(key.PubKey().SerializeCompressed())[:20]).
+		AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := wire.NewMsgTx(1)
+	prevHash := chainhash.DoubleHashH([]byte("prev"))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevHash, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(90000, prevOutScript))
+
+	script, err := signP2PKHInput(tx, 0, prevOutScript, key, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(script) != utxo.RedeemP2PKHSchnorrSigScriptSize {
+		t.Errorf("Expected a %d byte signature script, got %d", utxo.RedeemP2PKHSchnorrSigScriptSize, len(script))
+	}
+	pushes, err := txscript.PushedData(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := pushes[0]
+	if sig[64] != byte(txscript.SigHashAll|sigHashForkID) {
+		t.Errorf("Wrong sighash type %x", sig[64])
+	}
+	if !bytes.Equal(pushes[1], key.PubKey().SerializeCompressed()) {
+		t.Error("Wrong public key")
+	}
+	hash, err := calcSignatureHash(tx, 0, prevOutScript, txscript.SigHashAll, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schnorrVerify(key.PubKey(), hash, sig[:64]) {
+		t.Error("Failed to verify signature")
+	}
+	// The signature commits to the amount spent
+	hash, _ = calcSignatureHash(tx, 0, prevOutScript, txscript.SigHashAll, 100001)
+	if schnorrVerify(key.PubKey(), hash, sig[:64]) {
+		t.Error("Expected the signature to be invalid for another amount")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"github.com/OpenBazaar/multiwallet/util"
 	"github.com/gcash/bchd/txscript"
+	"math/big"
 	"os"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/OpenBazaar/multiwallet/keys"
 	"github.com/OpenBazaar/multiwallet/model/mock"
 	"github.com/OpenBazaar/multiwallet/service"
+	"github.com/OpenBazaar/multiwallet/utxo"
 	"github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -23,6 +25,7 @@ import (
 	"github.com/cpacia/bchutil"
 	bchhash "github.com/gcash/bchd/chaincfg/chainhash"
 	bchwire "github.com/gcash/bchd/wire"
+	"github.com/op/go-logging"
 )
 
 type FeeResponse struct {
@@ -55,19 +58,21 @@ func newMockWallet() (*BitcoinCashWallet, error) {
 
 	fp := util.NewFeeProvider(2000, 300, 200, 100, nil)
 
-	bw := &BitcoinCashWallet{
-		params: params,
-		km:     km,
-		db:     db,
-		fp:     fp,
-	}
+	bw := &BitcoinCashWallet{&utxo.Wallet{
+		Coin:        bitcoinCashCoin{},
+		ChainParams: params,
+		KM:          km,
+		DB:          db,
+		Fees:        fp,
+		Log:         logging.MustGetLogger("bitcoincash-wallet"),
+	}}
 	cli := mock.NewMockApiClient(bw.AddressToScript)
 	ws, err := service.NewWalletService(db, km, cli, params, wallet.BitcoinCash, cache.NewMockCacher())
 	if err != nil {
 		return nil, err
 	}
-	bw.client = cli
-	bw.ws = ws
+	bw.Client = cli
+	bw.WS = ws
 	return bw, nil
 }
 
@@ -77,9 +82,9 @@ func TestWalletService_VerifyWatchScriptFilter(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	keys := w.km.GetKeys()
+	keys := w.KM.GetKeys()
 
-	addr, err := w.km.KeyToAddress(keys[0])
+	addr, err := w.KM.KeyToAddress(keys[0])
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -88,7 +93,7 @@ func TestWalletService_VerifyWatchScriptFilter(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	watchScripts, err := w.db.WatchedScripts().GetAll()
+	watchScripts, err := w.DB.WatchedScripts().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -115,7 +120,7 @@ func TestWalletService_VerifyWatchScriptPut(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	watchScripts, err := w.db.WatchedScripts().GetAll()
+	watchScripts, err := w.DB.WatchedScripts().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -152,7 +157,7 @@ func TestBitcoinCashWallet_buildTx(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 
 	addr, err := w.DecodeAddress("qpf464w2g36kyklq9shvyjk9lvuf6ph7jv3k8qpq0m")
@@ -160,7 +165,7 @@ func TestBitcoinCashWallet_buildTx(t *testing.T) {
 		t.Error(err)
 	}
 	// Test build normal tx
-	tx, err := w.buildTx(1500000, addr, wallet.NORMAL, nil)
+	tx, err := w.BuildTx(1500000, addr, wallet.NORMAL, nil)
 	if err != nil {
 		w.DumpTables(os.Stdout)
 		t.Error(err)
@@ -169,21 +174,21 @@ func TestBitcoinCashWallet_buildTx(t *testing.T) {
 	if !containsOutput(tx, addr) {
 		t.Error("Built tx does not contain the requested output")
 	}
-	if !validInputs(tx, w.db) {
+	if !validInputs(tx, w.DB) {
 		t.Error("Built tx does not contain valid inputs")
 	}
-	if !validChangeAddress(tx, w.db, w.params) {
+	if !validChangeAddress(tx, w.DB, w.ChainParams) {
 		t.Error("Built tx does not contain a valid change output")
 	}
 
 	// Insuffient funds
-	_, err = w.buildTx(1000000000, addr, wallet.NORMAL, nil)
+	_, err = w.BuildTx(1000000000, addr, wallet.NORMAL, nil)
 	if err != wallet.ErrorInsuffientFunds {
 		t.Error("Failed to throw insuffient funds error")
 	}
 
 	// Dust
-	_, err = w.buildTx(1, addr, wallet.NORMAL, nil)
+	_, err = w.BuildTx(1, addr, wallet.NORMAL, nil)
 	if err != wallet.ErrorDustAmount {
 		t.Error("Failed to throw dust error")
 	}
@@ -194,21 +199,21 @@ func TestBitcoinCashWallet_buildSpendAllTx(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 
-	waitForTxnSync(t, w.db.Txns())
+	waitForTxnSync(t, w.DB.Txns())
 	addr, err := w.DecodeAddress("qpyafty5hf6uwjtd8y5tvgzeawfeyfhj55ke8l2dy7")
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test build spendAll tx
-	tx, err := w.buildSpendAllTx(addr, wallet.NORMAL)
+	tx, err := w.BuildSpendAllTx(addr, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -224,7 +229,7 @@ func TestBitcoinCashWallet_buildSpendAllTx(t *testing.T) {
 	if !containsOutput(tx, addr) {
 		t.Error("Built tx does not contain the requested output")
 	}
-	if !validInputs(tx, w.db) {
+	if !validInputs(tx, w.DB) {
 		t.Error("Built tx does not contain valid inputs")
 	}
 	if len(tx.TxOut) != 1 {
@@ -317,7 +322,7 @@ func TestBitcoinCashWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -325,7 +330,7 @@ func TestBitcoinCashWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -333,7 +338,7 @@ func TestBitcoinCashWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key3, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key3, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -344,7 +349,7 @@ func TestBitcoinCashWallet_GenerateMultisigScript(t *testing.T) {
 	keys := []hdkeychain.ExtendedKey{*key1, *key2, *key3}
 
 	// test without timeout
-	addr, redeemScript, err := w.generateMultisigScript(keys, 2, 0, nil)
+	addr, redeemScript, err := w.GenerateMultisigScript(keys, 2, 0, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -370,7 +375,7 @@ func TestBitcoinCashWallet_GenerateMultisigScript(t *testing.T) {
 	}
 
 	// test with timeout
-	key4, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key4, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -378,7 +383,7 @@ func TestBitcoinCashWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	addr, redeemScript, err = w.generateMultisigScript(keys, 2, time.Hour*10, key4)
+	addr, redeemScript, err = w.GenerateMultisigScript(keys, 2, time.Hour*10, key4)
 	if err != nil {
 		t.Error(err)
 	}
@@ -413,12 +418,12 @@ func TestBitcoinCashWallet_GenerateMultisigScript(t *testing.T) {
 
 func TestBitcoinCashWallet_newUnsignedTransaction(t *testing.T) {
 	w, err := newMockWallet()
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
@@ -452,7 +457,7 @@ func TestBitcoinCashWallet_newUnsignedTransaction(t *testing.T) {
 	}
 
 	// Regular transaction
-	authoredTx, err := newUnsignedTransaction(outputs, btcutil.Amount(1000), inputSource, changeSource)
+	authoredTx, err := utxo.NewUnsignedTransaction(bitcoinCashFeatures, outputs, btcutil.Amount(1000), inputSource, changeSource)
 	if err != nil {
 		t.Error(err)
 	}
@@ -465,7 +470,7 @@ func TestBitcoinCashWallet_newUnsignedTransaction(t *testing.T) {
 
 	// Insufficient funds
 	outputs[0].Value = 1000000000
-	_, err = newUnsignedTransaction(outputs, btcutil.Amount(1000), inputSource, changeSource)
+	_, err = utxo.NewUnsignedTransaction(bitcoinCashFeatures, outputs, btcutil.Amount(1000), inputSource, changeSource)
 	if err == nil {
 		t.Error("Failed to return insuffient funds error")
 	}
@@ -481,7 +486,7 @@ func TestBitcoinCashWallet_CreateMultisigSignature(t *testing.T) {
 		t.Error(err)
 	}
 
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -544,12 +549,12 @@ func TestBitcoinCashWallet_Multisign(t *testing.T) {
 		t.Error(err)
 	}
 
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
 
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -590,12 +595,12 @@ func TestBitcoinCashWallet_Multisign(t *testing.T) {
 
 func TestBitcoinCashWallet_bumpFee(t *testing.T) {
 	w, err := newMockWallet()
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 	if err != nil {
 		t.Error(err)
 	}
-	txns, err := w.db.Txns().GetAll(false)
+	txns, err := w.DB.Txns().GetAll(false)
 	if err != nil {
 		t.Error(err)
 	}
@@ -603,32 +608,32 @@ func TestBitcoinCashWallet_bumpFee(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
 	for _, u := range utxos {
 		if u.Op.Hash.IsEqual(ch) {
 			u.AtHeight = 0
-			w.db.Utxos().Put(u)
+			w.DB.Utxos().Put(u)
 		}
 	}
 
-	w.db.Txns().UpdateHeight(*ch, 0, time.Now())
+	w.DB.Txns().UpdateHeight(*ch, 0, time.Now())
 
 	// Test unconfirmed
-	_, err = w.bumpFee(*ch)
+	_, err = w.BumpFee(*ch)
 	if err != nil {
 		t.Error(err)
 	}
 
-	err = w.db.Txns().UpdateHeight(*ch, 1289597, time.Now())
+	err = w.DB.Txns().UpdateHeight(*ch, 1289597, time.Now())
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test confirmed
-	_, err = w.bumpFee(*ch)
+	_, err = w.BumpFee(*ch)
 	if err == nil {
 		t.Error("Should not be able to bump fee of confirmed txs")
 	}
@@ -636,12 +641,12 @@ func TestBitcoinCashWallet_bumpFee(t *testing.T) {
 
 func TestBitcoinCashWallet_sweepAddress(t *testing.T) {
 	w, err := newMockWallet()
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
@@ -653,7 +658,7 @@ func TestBitcoinCashWallet_sweepAddress(t *testing.T) {
 			if err != nil {
 				t.Error(err)
 			}
-			key, err = w.km.GetKeyForScript(addr.ScriptAddress())
+			key, err = w.KM.GetKeyForScript(addr.ScriptAddress())
 			if err != nil {
 				t.Error(err)
 			}
@@ -671,7 +676,7 @@ func TestBitcoinCashWallet_sweepAddress(t *testing.T) {
 		}
 	}
 	// P2PKH addr
-	_, err = w.sweepAddress([]wallet.TransactionInput{in}, nil, key, nil, wallet.NORMAL)
+	_, err = w.SweepAddress([]wallet.TransactionInput{in}, nil, key, nil, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 		return
@@ -696,12 +701,12 @@ func TestBitcoinCashWallet_sweepAddress(t *testing.T) {
 			}
 		}
 	}
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
 
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -709,7 +714,7 @@ func TestBitcoinCashWallet_sweepAddress(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	_, err = w.sweepAddress([]wallet.TransactionInput{in}, nil, key1, &redeemScript, wallet.NORMAL)
+	_, err = w.SweepAddress([]wallet.TransactionInput{in}, nil, key1, &redeemScript, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -717,16 +722,16 @@ func TestBitcoinCashWallet_sweepAddress(t *testing.T) {
 
 func TestBitcoinCashWallet_estimateSpendFee(t *testing.T) {
 	w, err := newMockWallet()
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 	if err != nil {
 		t.Error(err)
 	}
-	fee, err := w.estimateSpendFee(1000, wallet.NORMAL)
+	fee, err := w.EstimateSpendFee(*big.NewInt(1000), wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
-	if fee <= 0 {
+	if fee.Sign() <= 0 {
 		t.Error("Returned incorrect fee")
 	}
 }
@@ -0,0 +1,24 @@
+package bitcoinfork
+
+import (
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/multiwallet/exchangerates"
+	"golang.org/x/net/proxy"
+)
+
+// PriceFetcher prices a registered coin.
+//
+// Deprecated: use exchangerates.PriceFetcher.
+type PriceFetcher = exchangerates.PriceFetcher
+
+// NewPriceFetcher returns a fetcher of the median rate of coin of every known provider, fetching
+// the rates in the background.
+//
+// Deprecated: use exchangerates.NewPriceFetcher.
+func NewPriceFetcher(coin *coins.Coin, dialer proxy.Dialer) *PriceFetcher {
+	// Without names every known provider is returned
+	providers, _ := exchangerates.Providers(coin.CurrencyCode, nil)
+	f := exchangerates.NewPriceFetcher(coin.CurrencyCode, coin.UnitsPerCoin(), providers, dialer)
+	go f.Run()
+	return f
+}
@@ -0,0 +1,74 @@
+package bitcoinfork
+
+import (
+	"bytes"
+
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/multiwallet/utxo"
+	wi "github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+)
+
+// forkCoin signs like pre-segwit bitcoin, with P2PKH inputs and multisig escrows paid to a P2SH
+// address of the registered coin. Forks which sign differently need their own package.
+type forkCoin struct {
+	coin *coins.Coin
+}
+
+func (c forkCoin) Features() utxo.Features {
+	return utxo.Features{
+		CoinType: c.coin.CoinType,
+		Currency: wi.CurrencyDefinition{
+			Code:         c.coin.CurrencyCode,
+			Divisibility: c.coin.Divisibility,
+		},
+		P2PKHInput:         utxo.P2PKH,
+		RelayFeePerKb:      c.coin.RelayFeePerKb,
+		DustLimit:          c.coin.DustLimit,
+		MinFeePerByte:      c.coin.MinFeePerByte,
+		SafeConfirmations:  c.coin.SafeConfirmations,
+		FeeEstimateAddress: c.coin.FeeEstimateAddress,
+		Timelocks:          c.coin.Timelocks,
+	}
+}
+
+func (c forkCoin) DecodeAddress(addr string, params *chaincfg.Params) (btc.Address, error) {
+	return c.coin.DecodeAddress(addr, params)
+}
+
+func (c forkCoin) PayToAddrScript(addr btc.Address) ([]byte, error) {
+	return c.coin.PayToAddrScript(addr)
+}
+
+func (c forkCoin) ExtractAddress(script []byte, params *chaincfg.Params) (btc.Address, error) {
+	return c.coin.ExtractAddress(script, params)
+}
+
+func (c forkCoin) ScriptHashAddress(redeemScript []byte, params *chaincfg.Params) (btc.Address, error) {
+	return c.coin.NewAddressScriptHash(redeemScript, params)
+}
+
+func (forkCoin) SignP2PKH(tx *wire.MsgTx, idx int, prevOuts []*wire.TxOut, key *btcec.PrivateKey) ([]byte, error) {
+	return txscript.SignatureScript(tx, idx, prevOuts[idx].PkScript, txscript.SigHashAll, key, true)
+}
+
+func (forkCoin) SignScript(tx *wire.MsgTx, idx int, redeemScript []byte, prevOuts []*wire.TxOut, key *btcec.PrivateKey) ([]byte, error) {
+	return txscript.RawTxInSignature(tx, idx, redeemScript, txscript.SigHashAll, key)
+}
+
+func (forkCoin) Serialize(tx *wire.MsgTx) ([]byte, chainhash.Hash, error) {
+	var buf bytes.Buffer
+	if err := tx.BtcEncode(&buf, wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+		return nil, chainhash.Hash{}, err
+	}
+	return buf.Bytes(), tx.TxHash(), nil
+}
+
+func (forkCoin) ToWire(raw []byte) []byte {
+	return raw
+}
@@ -0,0 +1,321 @@
+package bitcoinfork
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/hdkeychain"
+)
+
+func TestDogecoinWallet_Addresses(t *testing.T) {
+	w, err := newMockWallet(coins.Dogecoin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := w.CurrentAddress(wallet.EXTERNAL)
+	a, ok := addr.(*coins.Address)
+	if !ok {
+		t.Fatalf("Expected a dogecoin address, got %T", addr)
+	}
+	if a.IsScriptHash() {
+		t.Error("Expected a P2PKH address")
+	}
+	if addr.String()[0] != 'D' {
+		t.Errorf("Expected a mainnet address starting with D, got %s", addr.String())
+	}
+	decoded, err := w.DecodeAddress(addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := w.AddressToScript(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromScript, err := w.ScriptToAddress(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromScript.String() != addr.String() {
+		t.Errorf("Expected %s from its script, got %s", addr.String(), fromScript.String())
+	}
+	if !w.HasKey(decoded) {
+		t.Error("Wallet does not have the key of its own address")
+	}
+}
+
+func TestDogecoinWallet_IsDust(t *testing.T) {
+	w, err := newMockWallet(coins.Dogecoin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bitcoin's relay rules would accept this output but dogecoin's soft dust limit does not
+	if !w.IsDust(*big.NewInt(int64(coins.Dogecoin.DustLimit) - 1)) {
+		t.Error("Output below the dust limit not reported as dust")
+	}
+	if w.IsDust(*big.NewInt(int64(coins.Dogecoin.DustLimit))) {
+		t.Error("Output at the dust limit reported as dust")
+	}
+}
+
+func TestDogecoinWallet_GetFeePerByte(t *testing.T) {
+	w, err := newMockWallet(coins.Dogecoin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee := w.GetFeePerByte(wallet.PRIOIRTY)
+	if fee.Int64() != 5000 {
+		t.Errorf("Expected priority fee of 5000, got %s", fee.String())
+	}
+
+	// Configured fees below the recommended minimum are raised to it
+	w.Fees = util.NewFeeProvider(2000, 300, 200, 100, 50, nil)
+	for _, level := range []wallet.FeeLevel{wallet.PRIOIRTY, wallet.NORMAL, wallet.ECONOMIC, wallet.SUPER_ECONOMIC} {
+		fee := w.GetFeePerByte(level)
+		if fee.Uint64() != coins.Dogecoin.MinFeePerByte {
+			t.Errorf("Expected fee level %d to pay %d, got %s", level, coins.Dogecoin.MinFeePerByte, fee.String())
+		}
+	}
+}
+
+func TestDogecoinWallet_buildTx(t *testing.T) {
+	w, err := newMockWallet(coins.Dogecoin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WS.Start()
+	waitForUtxos(t, w.DB)
+
+	addr, err := w.DecodeAddress("DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := w.BuildTx(1500000, addr, wallet.NORMAL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsOutput(w, tx, addr) {
+		t.Error("Built tx does not contain the requested output")
+	}
+	if !validInputs(tx, w.DB) {
+		t.Error("Built tx does not contain valid inputs")
+	}
+	for _, out := range tx.TxOut {
+		if out.Value < int64(coins.Dogecoin.DustLimit) {
+			t.Errorf("Built tx has an output of %d below the dust limit", out.Value)
+		}
+	}
+
+	// Insuffient funds
+	_, err = w.BuildTx(1000000000, addr, wallet.NORMAL, nil)
+	if err != wallet.ErrorInsuffientFunds {
+		t.Error("Failed to throw insuffient funds error")
+	}
+
+	// Above bitcoin's dust threshold but below dogecoin's
+	_, err = w.BuildTx(500000, addr, wallet.NORMAL, nil)
+	if err != wallet.ErrorDustAmount {
+		t.Error("Failed to throw dust error")
+	}
+}
+
+func TestDogecoinCoin_SignP2PKH(t *testing.T) {
+	w, err := newMockWallet(coins.Dogecoin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := w.KM.GetFreshKey(wallet.EXTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.KM.KeyToAddress(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privKey, err := key.ECPrivKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevScript, err := w.AddressToScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevOut := wire.NewTxOut(10000000, prevScript)
+
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(9000000, prevScript))
+
+	sigScript, err := forkCoin{coins.Dogecoin}.SignP2PKH(tx, 0, []*wire.TxOut{prevOut}, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.TxIn[0].SignatureScript = sigScript
+	vm, err := txscript.NewEngine(prevScript, tx, 0, txscript.StandardVerifyFlags, nil, nil, prevOut.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Errorf("Signed input does not verify: %s", err)
+	}
+}
+
+func TestDogecoinWallet_GenerateMultisigScript(t *testing.T) {
+	w, err := newMockWallet(coins.Dogecoin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []hdkeychain.ExtendedKey
+	rs := "52" // OP_2
+	for i := 0; i < 3; i++ {
+		key, err := w.KM.GetFreshKey(wallet.INTERNAL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubkey, err := key.ECPubKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, *key)
+		rs += "21" + hex.EncodeToString(pubkey.SerializeCompressed()) // OP_PUSHDATA(33) pubkey
+	}
+	rs += "53" + // OP_3
+		"ae" // OP_CHECKMULTISIG
+
+	addr, redeemScript, err := w.GenerateMultisigScript(keys, 2, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(redeemScript) != rs {
+		t.Error("Returned invalid redeem script")
+	}
+	if a, ok := addr.(*coins.Address); !ok || !a.IsScriptHash() {
+		t.Fatalf("Expected a P2SH address, got %T", addr)
+	}
+	if !bytes.Equal(addr.ScriptAddress(), btcutil.Hash160(redeemScript)) {
+		t.Error("Address does not pay to the hash of the redeem script")
+	}
+	if c := addr.String()[0]; c != '9' && c != 'A' {
+		t.Errorf("Expected a mainnet P2SH address, got %s", addr.String())
+	}
+
+	// Dogecoin escrows have no timeout branch
+	timeoutKey, err := w.KM.GetFreshKey(wallet.INTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeoutAddr, timeoutScript, err := w.GenerateMultisigScript(keys, 2, time.Hour*10, timeoutKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeoutAddr.String() != addr.String() || !bytes.Equal(timeoutScript, redeemScript) {
+		t.Error("Timeout should be ignored")
+	}
+}
+
+func TestDogecoinWallet_Multisign(t *testing.T) {
+	w, err := newMockWallet(coins.Dogecoin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []hdkeychain.ExtendedKey
+	for i := 0; i < 3; i++ {
+		key, err := w.KM.GetFreshKey(wallet.INTERNAL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, *key)
+	}
+	escrowAddr, redeemScript, err := w.GenerateMultisigScript(keys, 2, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escrowScript, err := w.AddressToScript(escrowAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h1, _ := hex.DecodeString("1a20f4299b4fa1f209428dace31ebf4f23f13abd8ed669cebede118343a6ae05")
+	h2, _ := hex.DecodeString("458d88b4ae9eb4a347f2e7f5592f1da3b9ddf7d40f307f6e5d7bc107a9b3e90e")
+	ins := []wallet.TransactionInput{
+		{OutpointHash: h1, OutpointIndex: 1, Value: *big.NewInt(50000000)},
+		{OutpointHash: h2, OutpointIndex: 0, Value: *big.NewInt(25000000)},
+	}
+	addr, err := w.DecodeAddress("DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outs := []wallet.TransactionOutput{{Address: addr, Value: *big.NewInt(74000000)}}
+
+	feePerByte := *new(big.Int).SetUint64(coins.Dogecoin.MinFeePerByte)
+	sigs1, err := w.CreateMultisigSignature(ins, outs, &keys[0], redeemScript, feePerByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs2, err := w.CreateMultisigSignature(ins, outs, &keys[2], redeemScript, feePerByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sigs1) != 2 || len(sigs2) != 2 {
+		t.Fatal("Expected a signature for each input")
+	}
+	txBytes, err := w.Multisign(ins, outs, sigs1, sigs2, redeemScript, feePerByte, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := wire.NewMsgTx(1)
+	if err := tx.BtcDecode(bytes.NewReader(txBytes), wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+		t.Fatal(err)
+	}
+	for i, in := range tx.TxIn {
+		if len(in.Witness) != 0 {
+			t.Error("Dogecoin inputs must not carry witnesses")
+		}
+		value := int64(25000000)
+		if bytes.Equal(in.PreviousOutPoint.Hash[:], reverse(h1)) {
+			value = 50000000
+		}
+		vm, err := txscript.NewEngine(escrowScript, tx, i, txscript.StandardVerifyFlags, nil, nil, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.Execute(); err != nil {
+			t.Errorf("Input %d does not verify: %s", i, err)
+		}
+	}
+}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func TestDogecoinWallet_estimateSpendFee(t *testing.T) {
+	w, err := newMockWallet(coins.Dogecoin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WS.Start()
+	waitForUtxos(t, w.DB)
+
+	fee, err := w.EstimateSpendFee(*big.NewInt(1500000), wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.Sign() == 0 || new(big.Int).Mod(&fee, big.NewInt(2000)).Sign() != 0 {
+		t.Errorf("Expected a fee paying 2000 per byte, got %s", fee.String())
+	}
+}
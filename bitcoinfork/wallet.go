@@ -0,0 +1,77 @@
+package bitcoinfork
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OpenBazaar/multiwallet/cache"
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/multiwallet/config"
+	"github.com/OpenBazaar/multiwallet/exchangerates"
+	"github.com/OpenBazaar/multiwallet/keys"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/multiwallet/utxo"
+	wi "github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/op/go-logging"
+	"golang.org/x/net/proxy"
+)
+
+// Wallet is the wallet of a coin declared in the coins registry
+type Wallet struct {
+	*utxo.Wallet
+	coin *coins.Coin
+}
+
+var _ = wi.Wallet(&Wallet{})
+
+// NewWallet returns the wallet of a registered coin
+func NewWallet(coin *coins.Coin, cfg config.CoinConfig, mnemonic string, params *chaincfg.Params, proxy proxy.Dialer, cache cache.Cacher, disableExchangeRates bool) (*Wallet, error) {
+	if len(cfg.ClientAPIs) == 0 {
+		return nil, fmt.Errorf("%s has no API endpoint: set the ClientAPIs of its config", coin.Name)
+	}
+	b, err := utxo.NewBase(cfg, mnemonic, params, proxy, cache, coin.CoinType, keyToAddress(coin))
+	if err != nil {
+		return nil, err
+	}
+
+	var er wi.ExchangeRates
+	if !disableExchangeRates {
+		providers, err := exchangerates.Providers(coin.CurrencyCode, cfg.ExchangeRateProviders)
+		if err != nil {
+			return nil, err
+		}
+		fetcher := exchangerates.NewPriceFetcher(coin.CurrencyCode, coin.UnitsPerCoin(), providers, proxy)
+		go fetcher.Run()
+		b.WS.SetExchangeRates(fetcher)
+		er = fetcher
+	}
+
+	fp := util.NewEstimatingFeeProvider(cfg.MaxFee, cfg.HighFee, cfg.MediumFee, cfg.LowFee, cfg.SuperLowFee, er, b.Client)
+
+	return &Wallet{
+		Wallet: utxo.NewWallet(b, forkCoin{coin}, params, fp, er, logging.MustGetLogger(coin.Name+"-wallet")),
+		coin:   coin,
+	}, nil
+}
+
+// keyToAddress returns the function deriving the coin's P2PKH address of a key
+func keyToAddress(coin *coins.Coin) keys.AddrFunc {
+	return func(key *hd.ExtendedKey, params *chaincfg.Params) (btcutil.Address, error) {
+		addr, err := key.Address(params)
+		if err != nil {
+			return nil, err
+		}
+		return coin.NewAddressPubKeyHash(addr.ScriptAddress(), params)
+	}
+}
+
+func (w *Wallet) CurrencyCode() string {
+	if w.ChainParams.Name == chaincfg.MainNetParams.Name {
+		return strings.ToLower(w.coin.CurrencyCode)
+	} else {
+		return "t" + strings.ToLower(w.coin.CurrencyCode)
+	}
+}
@@ -0,0 +1,307 @@
+package bitcoinfork
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/multiwallet/cache"
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/multiwallet/config"
+	"github.com/OpenBazaar/multiwallet/datastore"
+	"github.com/OpenBazaar/multiwallet/keys"
+	"github.com/OpenBazaar/multiwallet/model/mock"
+	"github.com/OpenBazaar/multiwallet/service"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/multiwallet/utxo"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/op/go-logging"
+)
+
+// newMockWallet returns a mainnet wallet of coin paying the coin's default fees
+func newMockWallet(coin *coins.Coin) (*Wallet, error) {
+	mockDb := datastore.NewMockMultiwalletDatastore()
+
+	db, err := mockDb.GetDatastoreForWallet(coin.CoinType)
+	if err != nil {
+		return nil, err
+	}
+	params := &chaincfg.MainNetParams
+
+	seed, err := hex.DecodeString("16c034c59522326867593487c03a8f9615fb248406dd0d4ffb3a6b976a248403")
+	if err != nil {
+		return nil, err
+	}
+	master, err := hdkeychain.NewMaster(seed, params)
+	if err != nil {
+		return nil, err
+	}
+	km, err := keys.NewKeyManager(db.Keys(), params, master, coin.CoinType, keyToAddress(coin))
+	if err != nil {
+		return nil, err
+	}
+
+	fp := util.NewFeeProvider(coin.MaxFee, coin.HighFee, coin.MediumFee, coin.LowFee, coin.SuperLowFee, nil)
+
+	w := &Wallet{
+		Wallet: &utxo.Wallet{
+			Coin:        forkCoin{coin},
+			ChainParams: params,
+			KM:          km,
+			DB:          db,
+			Fees:        fp,
+			Log:         logging.MustGetLogger(coin.Name + "-wallet"),
+		},
+		coin: coin,
+	}
+	cli := mock.NewMockApiClient(w.AddressToScript)
+	ws, err := service.NewWalletService(db, km, cli, params, coin.CoinType, cache.NewMockCacher())
+	if err != nil {
+		return nil, err
+	}
+	w.Client = cli
+	w.WS = ws
+	return w, nil
+}
+
+func waitForUtxos(t *testing.T, db wallet.Datastore) {
+	for i := 0; i < 100; i++ {
+		utxos, err := db.Utxos().GetAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(utxos) > 0 {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("timeout waiting for wallet to sync utxos")
+}
+
+func TestNewWallet_RequiresEndpoint(t *testing.T) {
+	// Neither coin has a default endpoint
+	for _, coin := range []*coins.Coin{coins.Dogecoin, coins.Dash} {
+		if _, err := NewWallet(coin, config.CoinConfig{CoinType: coin.CoinType}, "", &chaincfg.MainNetParams, nil, nil, true); err == nil {
+			t.Errorf("Expected %s to be refused without an API endpoint", coin.Name)
+		}
+	}
+}
+
+func TestWallet_CurrencyCode(t *testing.T) {
+	w, err := newMockWallet(coins.Dash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := w.CurrencyCode(); code != "dash" {
+		t.Errorf("Expected currency code dash, got %s", code)
+	}
+	if code := util.CurrencyCode(coins.Dash.TestnetCoinType); code != "TDASH" {
+		t.Errorf("Expected testnet currency code TDASH, got %s", code)
+	}
+	if units := util.SatoshisPerCoin(coins.Dash.CoinType); units != 100000000 {
+		t.Errorf("Expected 100000000 units per coin, got %v", units)
+	}
+}
+
+func TestWallet_Addresses(t *testing.T) {
+	w, err := newMockWallet(coins.Dash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := w.CurrentAddress(wallet.EXTERNAL)
+	if _, ok := addr.(*coins.Address); !ok {
+		t.Fatalf("Expected a registered coin address, got %T", addr)
+	}
+	if addr.String()[0] != 'X' {
+		t.Errorf("Expected a mainnet address starting with X, got %s", addr.String())
+	}
+	decoded, err := w.DecodeAddress(addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := w.AddressToScript(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromScript, err := w.ScriptToAddress(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromScript.String() != addr.String() {
+		t.Errorf("Expected %s from its script, got %s", addr.String(), fromScript.String())
+	}
+	if !w.HasKey(decoded) {
+		t.Error("Wallet does not have the key of its own address")
+	}
+	if decoded, err := util.DecodeAddress(addr.String(), w.ChainParams); err != nil || decoded.String() != addr.String() {
+		t.Errorf("Expected util to decode %s, got %v", addr.String(), err)
+	}
+}
+
+func TestWallet_BuildTx(t *testing.T) {
+	w, err := newMockWallet(coins.Dash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WS.Start()
+	waitForUtxos(t, w.DB)
+
+	addr, err := w.DecodeAddress(coins.Dash.FeeEstimateAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := w.BuildTx(1500000, addr, wallet.NORMAL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsOutput(w, tx, addr) {
+		t.Error("Built tx does not contain the requested output")
+	}
+	if !validInputs(tx, w.DB) {
+		t.Error("Built tx does not contain valid inputs")
+	}
+
+	// Insuffient funds
+	_, err = w.BuildTx(1000000000, addr, wallet.NORMAL, nil)
+	if err != wallet.ErrorInsuffientFunds {
+		t.Error("Failed to throw insuffient funds error")
+	}
+}
+
+// containsOutput reports whether tx pays w's address addr
+func containsOutput(w *Wallet, tx *wire.MsgTx, addr btcutil.Address) bool {
+	script, _ := w.AddressToScript(addr)
+	for _, o := range tx.TxOut {
+		if bytes.Equal(script, o.PkScript) {
+			return true
+		}
+	}
+	return false
+}
+
+func validInputs(tx *wire.MsgTx, db wallet.Datastore) bool {
+	utxos, _ := db.Utxos().GetAll()
+	uMap := make(map[wire.OutPoint]bool)
+	for _, u := range utxos {
+		uMap[u.Op] = true
+	}
+	for _, in := range tx.TxIn {
+		if !uMap[in.PreviousOutPoint] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestForkCoin_SignP2PKH(t *testing.T) {
+	w, err := newMockWallet(coins.Dash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := w.KM.GetFreshKey(wallet.EXTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.KM.KeyToAddress(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privKey, err := key.ECPrivKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevScript, err := w.AddressToScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevOut := wire.NewTxOut(10000000, prevScript)
+
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(9000000, prevScript))
+
+	sigScript, err := forkCoin{coins.Dash}.SignP2PKH(tx, 0, []*wire.TxOut{prevOut}, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.TxIn[0].SignatureScript = sigScript
+	vm, err := txscript.NewEngine(prevScript, tx, 0, txscript.StandardVerifyFlags, nil, nil, prevOut.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Errorf("Signed input does not verify: %s", err)
+	}
+}
+
+func TestWallet_Multisign(t *testing.T) {
+	w, err := newMockWallet(coins.Dash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []hdkeychain.ExtendedKey
+	for i := 0; i < 3; i++ {
+		key, err := w.KM.GetFreshKey(wallet.INTERNAL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, *key)
+	}
+	escrowAddr, redeemScript, err := w.GenerateMultisigScript(keys, 2, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := escrowAddr.String()[0]; c != '7' {
+		t.Errorf("Expected a mainnet P2SH address starting with 7, got %s", escrowAddr.String())
+	}
+	escrowScript, err := w.AddressToScript(escrowAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h1, _ := hex.DecodeString("1a20f4299b4fa1f209428dace31ebf4f23f13abd8ed669cebede118343a6ae05")
+	ins := []wallet.TransactionInput{
+		{OutpointHash: h1, OutpointIndex: 1, Value: *big.NewInt(50000000)},
+	}
+	addr, err := w.DecodeAddress(coins.Dash.FeeEstimateAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outs := []wallet.TransactionOutput{{Address: addr, Value: *big.NewInt(49000000)}}
+
+	sigs1, err := w.CreateMultisigSignature(ins, outs, &keys[0], redeemScript, *big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs2, err := w.CreateMultisigSignature(ins, outs, &keys[1], redeemScript, *big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := w.Multisign(ins, outs, sigs1, sigs2, redeemScript, *big.NewInt(1), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := wire.NewMsgTx(1)
+	if err := tx.BtcDecode(bytes.NewReader(txBytes), wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxIn[0].Witness) != 0 {
+		t.Error("Inputs must not carry witnesses")
+	}
+	vm, err := txscript.NewEngine(escrowScript, tx, 0, txscript.StandardVerifyFlags, nil, nil, 50000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Errorf("Escrow input does not verify: %s", err)
+	}
+}
@@ -1,16 +1,22 @@
 package cli
 
 import (
+	"encoding/csv"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/OpenBazaar/multiwallet/api"
 	"github.com/OpenBazaar/multiwallet/api/pb"
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/multiwallet/util"
 	"github.com/jessevdk/go-flags"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 func SetupCli(parser *flags.Parser) {
@@ -60,36 +66,140 @@ func SetupCli(parser *flags.Parser) {
 			"3. amount        (integer) The amount to send in satoshi"+
 			"4. feelevel      (string default=normal) The fee level: economic, normal, priority\n\n"+
 			"5. memo          (string) The orderID\n"+
+			"6. coinselection (string default=configured) How to choose the coins to spend: max-value-age, branch-and-bound, knapsack, smallest-first, privacy\n\n"+
 			"Examples:\n"+
 			"> multiwallet spend bitcoin 1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS 1000000\n"+
 			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c 1a3w"+
 			"> multiwallet spend bitcoin 1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS 3000000000 priority\n"+
 			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c 4wq2",
 		&spend)
+	parser.AddCommand("spendmany",
+		"send to many addresses in one transaction",
+		"Pays every recipient listed in a CSV file in a single transaction\n\n"+
+			"Args:\n"+
+			"1. coinType      (string)\n"+
+			"2. file          (string) Path to a CSV file with one address,amount row per recipient. Amounts are in satoshi.\n"+
+			"3. feelevel      (string default=normal) The fee level: economic, normal, priority\n"+
+			"4. memo          (string) The orderID\n"+
+			"5. coinselection (string default=configured) How to choose the coins to spend: max-value-age, branch-and-bound, knapsack, smallest-first, privacy\n\n"+
+			"Examples:\n"+
+			"> multiwallet spendmany bitcoin payouts.csv\n"+
+			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c\n"+
+			"> multiwallet spendmany bitcoin payouts.csv economic\n"+
+			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c",
+		&spendMany)
+	parser.AddCommand("abandontransaction",
+		"abandon a stuck transaction",
+		"Marks an unconfirmed transaction which is not in any server's mempool as dead so the coins it spent can be spent again\n\n"+
+			"Args:\n"+
+			"1. coinType      (string)\n"+
+			"2. txid          (string) The id of the transaction to abandon\n\n"+
+			"Examples:\n"+
+			"> multiwallet abandontransaction bitcoin 82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c\n",
+		&abandonTransaction)
+	parser.AddCommand("listutxos",
+		"list the wallet's unspent outputs",
+		"Returns each unspent output of the wallet with its value, address, height and whether it is frozen\n\n"+
+			"Args:\n"+
+			"1. coinType      (string)\n\n"+
+			"Examples:\n"+
+			"> multiwallet listutxos bitcoin\n"+
+			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c:1 value=150000 address=1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS height=553021 frozen\n",
+		&listUtxos)
+	parser.AddCommand("freezeutxo",
+		"freeze an unspent output",
+		"Stops an unspent output from being spent until it is unfrozen\n\n"+
+			"Args:\n"+
+			"1. coinType      (string)\n"+
+			"2. outpoint      (string) The output to freeze as txid:index\n\n"+
+			"Examples:\n"+
+			"> multiwallet freezeutxo bitcoin 82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c:1\n",
+		&freezeUtxo)
+	parser.AddCommand("unfreezeutxo",
+		"unfreeze an unspent output",
+		"Makes a frozen output spendable again\n\n"+
+			"Args:\n"+
+			"1. coinType      (string)\n"+
+			"2. outpoint      (string) The output to unfreeze as txid:index\n\n"+
+			"Examples:\n"+
+			"> multiwallet unfreezeutxo bitcoin 82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c:1\n",
+		&unfreezeUtxo)
+	parser.AddCommand("spendutxos",
+		"send bitcoins from chosen outputs",
+		"Send bitcoins to the given address spending exactly the given outputs. Anything left after the fee is returned as change.\n\n"+
+			"Args:\n"+
+			"1. coinType      (string)\n"+
+			"2. outpoints     (string) Comma separated txid:index outputs to spend\n"+
+			"3. address       (string) The recipient's bitcoin address\n"+
+			"4. amount        (integer) The amount to send in satoshi\n"+
+			"5. feelevel      (string default=normal) The fee level: economic, normal, priority\n"+
+			"6. memo          (string) The orderID\n\n"+
+			"Examples:\n"+
+			"> multiwallet spendutxos bitcoin 82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c:1 1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS 100000\n"+
+			"ab0b3b4cf1f1ddfa3a2aa8ce7cd68c8b52c6ad40ebde2b33a0d45c9e3cbb0cd5",
+		&spendUtxos)
+	parser.AddCommand("consolidate",
+		"merge small unspent outputs",
+		"Spends the wallet's small unspent outputs to one internal address at the economic fee level so later spends need fewer inputs\n\n"+
+			"Args:\n"+
+			"1. coinType      (string)\n"+
+			"2. dryrun        (string optional) Pass dryrun to show the estimated savings without broadcasting\n\n"+
+			"Examples:\n"+
+			"> multiwallet consolidate bitcoin dryrun\n"+
+			"utxos=42 value=1853000 fee=31250 feePerByte=5 futureFeePerByte=40 savings=211530 withinFeeLimit=true\n"+
+			"> multiwallet consolidate bitcoin\n"+
+			"utxos=42 value=1853000 fee=31250 feePerByte=5 futureFeePerByte=40 savings=211530 withinFeeLimit=true\n"+
+			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c\n",
+		&consolidate)
+	parser.AddCommand("tokenbalances",
+		"get the wallet's token balances",
+		"Returns the fungible amount and number of NFTs held of each CashToken category. Outputs carrying tokens are never spent by bitcoin cash payments.\n\n"+
+			"Args:\n"+
+			"1. coinType      (string)\n\n"+
+			"Examples:\n"+
+			"> multiwallet tokenbalances bitcoincash\n"+
+			"category=b1c0dd2c3b8a2bf5a6e8e9e12b0a6b2b1f0b7e6a4d1a6c8e2e4f9d0c3b2a1f0e amount=1000 nfts=2\n",
+		&tokenBalances)
 	parser.AddCommand("balance",
 		"get the wallet's balances",
 		"Returns the confirmed and unconfirmed balances for the specified coin",
 		&balance)
+	parser.AddCommand("endpointhealth",
+		"list the health of the api endpoints",
+		"Returns the score, latency, error rate and chain tip of each api endpoint for the specified coin. The current endpoint is marked with an asterisk.\n\n"+
+			"Args:\n"+
+			"1. coinType      (string)\n\n"+
+			"Examples:\n"+
+			"> multiwallet endpointhealth bitcoin\n"+
+			"* https://btc.blockbook.api.openbazaar.org/api healthy=true score=0.712 latency=98ms errorRate=0.000 tip=553021 lag=0\n",
+		&endpointHealth)
 }
 
-func coinType(args []string) pb.CoinType {
+// coinType returns the API coin type and SLIP-0044 coin type selecting the coin named by the first
+// argument. Registered coins missing from the CoinType enum are selected by their SLIP-0044 type.
+func coinType(args []string) (pb.CoinType, uint32) {
 	if len(args) == 0 {
-		return pb.CoinType_BITCOIN
+		return pb.CoinType_BITCOIN, 0
 	}
 	switch strings.ToLower(args[0]) {
 	case "bitcoin":
-		return pb.CoinType_BITCOIN
+		return pb.CoinType_BITCOIN, 0
 	case "bitcoincash":
-		return pb.CoinType_BITCOIN_CASH
+		return pb.CoinType_BITCOIN_CASH, 0
 	case "zcash":
-		return pb.CoinType_ZCASH
+		return pb.CoinType_ZCASH, 0
 	case "litecoin":
-		return pb.CoinType_LITECOIN
+		return pb.CoinType_LITECOIN, 0
 	case "ethereum":
-		return pb.CoinType_ETHEREUM
-	default:
-		return pb.CoinType_BITCOIN
+		return pb.CoinType_ETHEREUM, 0
 	}
+	if c, ok := coins.LookupName(args[0]); ok {
+		if t, ok := pb.CoinType_value[strings.ToUpper(c.Name)]; ok {
+			return pb.CoinType(t), 0
+		}
+		return pb.CoinType_BITCOIN, uint32(c.CoinType)
+	}
+	return pb.CoinType_BITCOIN, 0
 }
 
 func newGRPCClient() (pb.APIClient, *grpc.ClientConn, error) {
@@ -102,6 +212,43 @@ func newGRPCClient() (pb.APIClient, *grpc.ClientConn, error) {
 	return client, conn, nil
 }
 
+// describeError turns the status of a failed call into a message for the user, explaining
+// the kind of failure when the server classified it
+func describeError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, d := range st.Details() {
+		detail, ok := d.(*pb.ErrorDetail)
+		if !ok {
+			continue
+		}
+		var description string
+		switch detail.Kind {
+		case pb.ErrorKind_INSUFFICIENT_FUNDS:
+			description = "Insufficient funds to cover the amount and fee"
+		case pb.ErrorKind_DUST_AMOUNT:
+			description = "Amount is too small to send"
+		case pb.ErrorKind_BACKEND_UNAVAILABLE:
+			description = "Wallet server unavailable, try again later"
+		case pb.ErrorKind_INVALID_ADDRESS:
+			description = "Invalid address"
+		case pb.ErrorKind_FEE_TOO_HIGH:
+			description = "Fee is too high"
+		case pb.ErrorKind_REJECTED_BY_MEMPOOL:
+			description = "Transaction rejected by the network"
+		default:
+			continue
+		}
+		if detail.Reason != "" {
+			return fmt.Errorf("%s: %s", description, detail.Reason)
+		}
+		return fmt.Errorf("%s (%s)", description, st.Message())
+	}
+	return errors.New(st.Message())
+}
+
 type Stop struct{}
 
 var stop Stop
@@ -129,7 +276,7 @@ func (x *CurrentAddress) Execute(args []string) error {
 	var purpose pb.KeyPurpose
 	userSelection := ""
 
-	t := coinType(args)
+	t, slip44 := coinType(args)
 	if len(args) == 1 {
 		userSelection = args[0]
 	} else if len(args) == 2 {
@@ -144,9 +291,9 @@ func (x *CurrentAddress) Execute(args []string) error {
 		purpose = pb.KeyPurpose_EXTERNAL
 	}
 
-	resp, err := client.CurrentAddress(context.Background(), &pb.KeySelection{Coin: t, Purpose: purpose})
+	resp, err := client.CurrentAddress(context.Background(), &pb.KeySelection{Coin: t, Slip44: slip44, Purpose: purpose})
 	if err != nil {
-		return err
+		return describeError(err)
 	}
 	fmt.Println(resp.Addr)
 	return nil
@@ -165,7 +312,7 @@ func (x *NewAddress) Execute(args []string) error {
 	if len(args) == 0 {
 		return errors.New("Must select coin type")
 	}
-	t := coinType(args)
+	t, slip44 := coinType(args)
 	var purpose pb.KeyPurpose
 	userSelection := ""
 	if len(args) == 1 {
@@ -181,9 +328,9 @@ func (x *NewAddress) Execute(args []string) error {
 	default:
 		purpose = pb.KeyPurpose_EXTERNAL
 	}
-	resp, err := client.NewAddress(context.Background(), &pb.KeySelection{Coin: t, Purpose: purpose})
+	resp, err := client.NewAddress(context.Background(), &pb.KeySelection{Coin: t, Slip44: slip44, Purpose: purpose})
 	if err != nil {
-		return err
+		return describeError(err)
 	}
 	fmt.Println(resp.Addr)
 	return nil
@@ -202,10 +349,10 @@ func (x *ChainTip) Execute(args []string) error {
 	if len(args) == 0 {
 		return errors.New("Must select coin type")
 	}
-	t := coinType(args)
-	resp, err := client.ChainTip(context.Background(), &pb.CoinSelection{Coin: t})
+	t, slip44 := coinType(args)
+	resp, err := client.ChainTip(context.Background(), &pb.CoinSelection{Coin: t, Slip44: slip44})
 	if err != nil {
-		return err
+		return describeError(err)
 	}
 	fmt.Println(resp.Height)
 	return nil
@@ -224,10 +371,10 @@ func (x *DumpTables) Execute(args []string) error {
 	if len(args) == 0 {
 		return errors.New("Must select coin type")
 	}
-	t := coinType(args)
-	resp, err := client.DumpTables(context.Background(), &pb.CoinSelection{Coin: t})
+	t, slip44 := coinType(args)
+	resp, err := client.DumpTables(context.Background(), &pb.CoinSelection{Coin: t, Slip44: slip44})
 	if err != nil {
-		return err
+		return describeError(err)
 	}
 	for {
 		row, err := resp.Recv()
@@ -249,6 +396,7 @@ func (x *Spend) Execute(args []string) error {
 		feeLevel      pb.FeeLevel
 		referenceID   string
 		userSelection string
+		coinSelection string
 
 		client, conn, err = newGRPCClient()
 	)
@@ -265,41 +413,349 @@ func (x *Spend) Execute(args []string) error {
 		userSelection = args[3]
 		referenceID = args[4]
 	}
+	if len(args) > 5 {
+		coinSelection = args[5]
+	}
 	if len(args) < 4 {
 		return errors.New("Address and amount are required")
 	}
 
+	feeLevel = parseFeeLevel(userSelection)
+
+	amt, err := strconv.Atoi(args[2])
+	if err != nil {
+		return err
+	}
+
+	t, slip44 := coinType(args)
+	resp, err := client.Spend(context.Background(), &pb.SpendInfo{
+		Coin:          t,
+		Slip44:        slip44,
+		Address:       address,
+		Amount:        uint64(amt),
+		FeeLevel:      feeLevel,
+		Memo:          referenceID,
+		CoinSelection: coinSelection,
+	})
+	if err != nil {
+		return describeError(err)
+	}
+
+	fmt.Println(resp.Hash)
+	return nil
+}
+
+func parseFeeLevel(userSelection string) pb.FeeLevel {
 	switch strings.ToLower(userSelection) {
 	case "economic":
-		feeLevel = pb.FeeLevel_ECONOMIC
-	case "normal":
-		feeLevel = pb.FeeLevel_NORMAL
+		return pb.FeeLevel_ECONOMIC
 	case "priority":
-		feeLevel = pb.FeeLevel_PRIORITY
+		return pb.FeeLevel_PRIORITY
 	default:
-		feeLevel = pb.FeeLevel_NORMAL
+		return pb.FeeLevel_NORMAL
 	}
+}
 
-	amt, err := strconv.Atoi(args[2])
+type SpendMany struct{}
+
+var spendMany SpendMany
+
+func (x *SpendMany) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
 	if err != nil {
 		return err
 	}
+	defer conn.Close()
+	if len(args) < 2 {
+		return errors.New("Coin type and recipients file are required")
+	}
+	var userSelection, referenceID, coinSelection string
+	if len(args) > 2 {
+		userSelection = args[2]
+	}
+	if len(args) > 3 {
+		referenceID = args[3]
+	}
+	if len(args) > 4 {
+		coinSelection = args[4]
+	}
 
-	resp, err := client.Spend(context.Background(), &pb.SpendInfo{
-		Coin:     coinType(args),
-		Address:  address,
-		Amount:   uint64(amt),
-		FeeLevel: feeLevel,
-		Memo:     referenceID,
+	f, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	recipients, err := readRecipients(f)
+	if err != nil {
+		return err
+	}
+
+	t, slip44 := coinType(args)
+	resp, err := client.SpendMany(context.Background(), &pb.SpendManyInfo{
+		Coin:          t,
+		Slip44:        slip44,
+		Recipients:    recipients,
+		FeeLevel:      parseFeeLevel(userSelection),
+		Memo:          referenceID,
+		CoinSelection: coinSelection,
 	})
+	if err != nil {
+		return describeError(err)
+	}
+
+	fmt.Println(resp.Hash)
+	return nil
+}
+
+// readRecipients parses address,amount rows. A first row whose amount is not a number is taken
+// to be a header and skipped.
+func readRecipients(r io.Reader) ([]*pb.Recipient, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var recipients []*pb.Recipient
+	for i, row := range rows {
+		amt, err := strconv.ParseUint(strings.TrimSpace(row[1]), 10, 64)
+		if err != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: invalid amount %q", i+1, row[1])
+		}
+		recipients = append(recipients, &pb.Recipient{
+			Address: strings.TrimSpace(row[0]),
+			Amount:  amt,
+		})
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("No recipients found")
+	}
+	return recipients, nil
+}
+
+type AbandonTransaction struct{}
+
+var abandonTransaction AbandonTransaction
+
+func (x *AbandonTransaction) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) < 2 {
+		return errors.New("Coin type and txid are required")
+	}
+	t, slip44 := coinType(args)
+	_, err = client.AbandonTransaction(context.Background(), &pb.Txid{Coin: t, Slip44: slip44, Hash: args[1]})
+	if err != nil {
+		return describeError(err)
+	}
+	fmt.Println("abandoned", args[1])
+	return nil
+}
+
+type ListUtxos struct{}
+
+var listUtxos ListUtxos
+
+func (x *ListUtxos) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) == 0 {
+		return errors.New("Must select coin type")
+	}
+	t, slip44 := coinType(args)
+	resp, err := client.ListUtxos(context.Background(), &pb.CoinSelection{Coin: t, Slip44: slip44})
+	if err != nil {
+		return describeError(err)
+	}
+	for _, u := range resp.Utxos {
+		var flags string
+		if u.WatchOnly {
+			flags += " watchonly"
+		}
+		if u.Frozen {
+			flags += " frozen"
+		}
+		if u.TokenCategory != "" {
+			flags += fmt.Sprintf(" token=%s tokenAmount=%d tokenNFT=%t", u.TokenCategory, u.TokenAmount, u.TokenNFT)
+		}
+		fmt.Printf("%s:%d value=%d address=%s height=%d%s\n", u.Txid, u.Index, u.Value, u.Address, u.Height, flags)
+	}
+	return nil
+}
+
+type FreezeUtxo struct{}
+
+var freezeUtxo FreezeUtxo
+
+func (x *FreezeUtxo) Execute(args []string) error {
+	return setUtxoFrozen(args, true)
+}
+
+type UnfreezeUtxo struct{}
+
+var unfreezeUtxo UnfreezeUtxo
+
+func (x *UnfreezeUtxo) Execute(args []string) error {
+	return setUtxoFrozen(args, false)
+}
+
+func setUtxoFrozen(args []string, freeze bool) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) < 2 {
+		return errors.New("Coin type and outpoint are required")
+	}
+	op, err := parseOutpoint(args[1])
+	if err != nil {
+		return err
+	}
+	t, slip44 := coinType(args)
+	in := &pb.OutpointSelection{Coin: t, Slip44: slip44, Outpoint: op}
+	if freeze {
+		_, err = client.FreezeUtxo(context.Background(), in)
+	} else {
+		_, err = client.UnfreezeUtxo(context.Background(), in)
+	}
+	if err != nil {
+		return describeError(err)
+	}
+	if freeze {
+		fmt.Println("frozen", args[1])
+	} else {
+		fmt.Println("unfrozen", args[1])
+	}
+	return nil
+}
+
+type SpendUtxos struct{}
+
+var spendUtxos SpendUtxos
+
+func (x *SpendUtxos) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) < 4 {
+		return errors.New("Coin type, outpoints, address and amount are required")
+	}
+	var outpoints []*pb.Outpoint
+	for _, s := range strings.Split(args[1], ",") {
+		op, err := parseOutpoint(s)
+		if err != nil {
+			return err
+		}
+		outpoints = append(outpoints, op)
+	}
+	amt, err := strconv.ParseUint(args[3], 10, 64)
 	if err != nil {
 		return err
 	}
+	var userSelection, referenceID string
+	if len(args) > 4 {
+		userSelection = args[4]
+	}
+	if len(args) > 5 {
+		referenceID = args[5]
+	}
+
+	t, slip44 := coinType(args)
+	resp, err := client.SpendOutpoints(context.Background(), &pb.SpendOutpointsInfo{
+		Coin:       t,
+		Slip44:     slip44,
+		Outpoints:  outpoints,
+		Recipients: []*pb.Recipient{{Address: args[2], Amount: amt}},
+		FeeLevel:   parseFeeLevel(userSelection),
+		Memo:       referenceID,
+	})
+	if err != nil {
+		return describeError(err)
+	}
 
 	fmt.Println(resp.Hash)
 	return nil
 }
 
+// parseOutpoint parses an output given as txid:index
+func parseOutpoint(s string) (*pb.Outpoint, error) {
+	op, err := util.ParseOutPoint(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Outpoint{Txid: op.Hash.String(), Index: op.Index}, nil
+}
+
+type Consolidate struct{}
+
+var consolidate Consolidate
+
+func (x *Consolidate) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) == 0 {
+		return errors.New("Must select coin type")
+	}
+	var dryRun bool
+	if len(args) > 1 {
+		if strings.ToLower(args[1]) != "dryrun" {
+			return fmt.Errorf("unknown option %q", args[1])
+		}
+		dryRun = true
+	}
+	t, slip44 := coinType(args)
+	resp, err := client.Consolidate(context.Background(), &pb.ConsolidateInfo{Coin: t, Slip44: slip44, DryRun: dryRun})
+	if err != nil {
+		return describeError(err)
+	}
+	fmt.Printf("utxos=%d value=%d fee=%d feePerByte=%d futureFeePerByte=%d savings=%d withinFeeLimit=%t\n",
+		resp.Utxos, resp.Value, resp.Fee, resp.FeePerByte, resp.FutureFeePerByte, resp.Savings, resp.WithinFeeLimit)
+	if resp.Txid != "" {
+		fmt.Println(resp.Txid)
+	}
+	return nil
+}
+
+type TokenBalances struct{}
+
+var tokenBalances TokenBalances
+
+func (x *TokenBalances) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) == 0 {
+		return errors.New("Must select coin type")
+	}
+	t, slip44 := coinType(args)
+	resp, err := client.TokenBalances(context.Background(), &pb.CoinSelection{Coin: t, Slip44: slip44})
+	if err != nil {
+		return describeError(err)
+	}
+	for _, b := range resp.Balances {
+		fmt.Printf("category=%s amount=%d nfts=%d\n", b.Category, b.Amount, b.Nfts)
+	}
+	return nil
+}
+
 type Balance struct{}
 
 var balance Balance
@@ -313,11 +769,43 @@ func (x *Balance) Execute(args []string) error {
 	if len(args) == 0 {
 		return errors.New("Must select coin type")
 	}
-	t := coinType(args)
-	resp, err := client.Balance(context.Background(), &pb.CoinSelection{Coin: t})
+	t, slip44 := coinType(args)
+	resp, err := client.Balance(context.Background(), &pb.CoinSelection{Coin: t, Slip44: slip44})
 	if err != nil {
-		return err
+		return describeError(err)
 	}
 	fmt.Printf("Confirmed: %d, Unconfirmed: %d\n", resp.Confirmed, resp.Unconfirmed)
 	return nil
 }
+
+type EndpointHealth struct{}
+
+var endpointHealth EndpointHealth
+
+func (x *EndpointHealth) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) == 0 {
+		return errors.New("Must select coin type")
+	}
+	t, slip44 := coinType(args)
+	resp, err := client.EndpointHealth(context.Background(), &pb.CoinSelection{Coin: t, Slip44: slip44})
+	if err != nil {
+		return describeError(err)
+	}
+	for _, e := range resp.Endpoints {
+		marker := " "
+		if e.Current {
+			marker = "*"
+		}
+		fmt.Printf("%s %s healthy=%t score=%.3f latency=%dms errorRate=%.3f tip=%d lag=%d\n",
+			marker, e.Url, e.Healthy, e.Score, e.LatencyMillis, e.ErrorRate, e.TipHeight, e.TipLag)
+		if e.LastError != "" {
+			fmt.Printf("  last error: %s\n", e.LastError)
+		}
+	}
+	return nil
+}
@@ -29,10 +29,18 @@ import (
 	"golang.org/x/net/proxy"
 )
 
-const maxInfightQueries = 25
+const (
+	maxInfightQueries = 25
+	xpubPageSize      = 1000
+	satoshisPerCoin   = 100000000
+)
 
 var Log = logging.MustGetLogger("client")
 
+// ErrXpubUnsupported is returned by the account queries when the server does not
+// provide Blockbook's xpub endpoints. Callers should fall back to querying addresses.
+var ErrXpubUnsupported = errors.New("server does not support xpub queries")
+
 type wsWatchdog struct {
 	client    *BlockBookClient
 	done      chan struct{}
@@ -54,11 +62,9 @@ func (w *wsWatchdog) guardWebsocket() {
 		select {
 		case <-w.wsStopped:
 			Log.Warningf("reconnecting stopped websocket (%s)", w.client.String())
+			disconnectedAt := time.Now()
 			w.client.socketMutex.Lock()
-			if w.client.SocketClient != nil {
-				w.client.SocketClient.Close()
-				w.client.SocketClient = nil
-			}
+			w.client.closeSockets()
 			w.drainAndRollover()
 			if err := w.client.setupListeners(); err != nil {
 				Log.Warningf("failed reconnecting websocket (%s)", w.client.String())
@@ -68,6 +74,7 @@ func (w *wsWatchdog) guardWebsocket() {
 				return
 			}
 			w.client.socketMutex.Unlock()
+			w.client.notifyReconnect(disconnectedAt)
 		case <-w.done:
 			return
 		}
@@ -102,11 +109,19 @@ type BlockBookClient struct {
 	apiUrl            *url.URL
 	blockNotifyChan   chan model.Block
 	closeChan         chan<- error
+	limits            RequestLimits
+	listenAddrs       []string
 	listenLock        sync.Mutex
-	listenQueue       []string
+	native            *nativeSocket
+	nativeUnsupported bool
 	proxyDialer       proxy.Dialer
+	rateLimiter       *tokenBucket
+	reconnectChan     chan time.Time
+	requestSlots      chan struct{}
 	txNotifyChan      chan model.Transaction
 	websocketWatchdog *wsWatchdog
+	xpubUnsupported   bool
+	xpubLock          sync.RWMutex
 
 	HTTPClient   http.Client
 	RequestFunc  func(endpoint, method string, body []byte, query url.Values) (*http.Response, error)
@@ -142,13 +157,23 @@ func NewBlockBookClient(apiUrl string, proxyDialer proxy.Dialer) (*BlockBookClie
 		proxyDialer:     proxyDialer,
 		blockNotifyChan: bch,
 		txNotifyChan:    tch,
+		reconnectChan:   make(chan time.Time, 1),
 		listenLock:      sync.Mutex{},
 	}
 	ic.websocketWatchdog = newWebsocketWatchdog(ic)
 	ic.RequestFunc = ic.doRequest
+	ic.SetRequestLimits(RequestLimits{})
 	return ic, nil
 }
 
+// SetRequestLimits replaces the concurrency and rate limits applied to requests made to
+// the server. It should be called before the client is in use.
+func (i *BlockBookClient) SetRequestLimits(limits RequestLimits) {
+	i.limits = limits.withDefaults()
+	i.requestSlots = make(chan struct{}, i.limits.MaxConcurrentRequests)
+	i.rateLimiter = newTokenBucket(i.limits.RequestsPerSecond, i.limits.RequestBurst)
+}
+
 func (i *BlockBookClient) String() string {
 	return i.apiUrl.Host
 }
@@ -161,6 +186,21 @@ func (i *BlockBookClient) TxChannel() chan model.Transaction {
 	return i.txNotifyChan
 }
 
+// ReconnectChannel receives the time the websocket was lost each time the watchdog
+// reconnects it
+func (i *BlockBookClient) ReconnectChannel() chan time.Time {
+	return i.reconnectChan
+}
+
+// notifyReconnect reports a reconnection unless an earlier one is still waiting to be
+// received, in which case the earlier disconnection time already covers the gap
+func (i *BlockBookClient) notifyReconnect(disconnectedAt time.Time) {
+	select {
+	case i.reconnectChan <- disconnectedAt:
+	default:
+	}
+}
+
 func (i *BlockBookClient) EndpointURL() *url.URL {
 	var u = *i.apiUrl
 	return &u
@@ -180,14 +220,25 @@ func (i *BlockBookClient) Close() {
 	Log.Infof("closing client (%s)...", i.String())
 	i.socketMutex.Lock()
 	defer i.socketMutex.Unlock()
-	if i.SocketClient != nil {
+	if i.SocketClient != nil || i.native != nil {
 		if i.websocketWatchdog != nil {
 			go i.websocketWatchdog.putAway()
 		}
+		i.closeSockets()
+	}
+	i.sendAndDiscardCloseChan(nil)
+}
+
+// closeSockets closes whichever websocket is connected. The caller must hold socketMutex.
+func (i *BlockBookClient) closeSockets() {
+	if i.SocketClient != nil {
 		i.SocketClient.Close()
 		i.SocketClient = nil
 	}
-	i.sendAndDiscardCloseChan(nil)
+	if i.native != nil {
+		i.native.close()
+		i.native = nil
+	}
 }
 
 func (i *BlockBookClient) sendAndDiscardCloseChan(err error) {
@@ -218,7 +269,11 @@ func (i *BlockBookClient) doRequest(endpoint, method string, body []byte, query
 	}
 	req.Header.Add("Content-Type", "application/json")
 
+	var slots = i.requestSlots
+	slots <- struct{}{}
+	i.rateLimiter.wait()
 	resp, err := i.HTTPClient.Do(req)
+	<-slots
 	if err != nil {
 		if urlErr, ok := err.(*url.Error); ok && urlErr.Timeout() {
 			Log.Errorf("timed out executing: %s", err.Error())
@@ -232,11 +287,13 @@ func (i *BlockBookClient) doRequest(endpoint, method string, body []byte, query
 		Log.Errorf(errStr)
 
 		// log body
-		if body, err := ioutil.ReadAll(resp.Body); err != nil {
+		respBody, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
 			Log.Warningf("reading body (%s %s): %s", method, requestUrl.String(), err.Error())
 		} else {
-			if len(body) > 0 {
-				Log.Debugf("not ok response body (%s %s):\n\tstring: %s\n\thexencoded: %s", method, requestUrl.String(), string(body), hex.EncodeToString(body))
+			if len(respBody) > 0 {
+				Log.Debugf("not ok response body (%s %s):\n\tstring: %s\n\thexencoded: %s", method, requestUrl.String(), string(respBody), hex.EncodeToString(respBody))
 			}
 		}
 
@@ -245,17 +302,62 @@ func (i *BlockBookClient) doRequest(endpoint, method string, body []byte, query
 			err := fmt.Errorf("wallet server internal error (%s %s)", method, requestUrl.String())
 			return nil, clientErr.MakeRetryable(clientErr.MakeFatal(err))
 		}
-		return nil, fmt.Errorf("status not ok: %s", resp.Status)
+		// keep the server's explanation so callers can report why the request was refused
+		return nil, clientErr.ClassifyWithReason(clientErr.KindUnknown, serverMessage(respBody), fmt.Errorf("status not ok: %s", resp.Status))
 	}
 	return resp, nil
 }
 
+// serverMessage extracts the error message from the body of a failed response
+func serverMessage(body []byte) string {
+	var res struct {
+		Error interface{} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &res); err == nil && res.Error != nil {
+		switch e := res.Error.(type) {
+		case string:
+			return e
+		case map[string]interface{}:
+			if msg, ok := e["message"].(string); ok {
+				return msg
+			}
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// broadcastError classifies the failure of a broadcast. A server which answered but refused
+// the transaction rejected it from its mempool, for which the reason is kept. Any other error
+// is returned as is so retryable failures are retried. A refusal because the node already has
+// the transaction is not a failure and nil is returned.
+func broadcastError(err error) error {
+	reason := clientErr.ReasonOf(err)
+	if clientErr.IsRetryable(err) || reason == "" {
+		return err
+	}
+	kind := clientErr.KindRejected
+	lower := strings.ToLower(reason)
+	switch {
+	case strings.Contains(lower, "already in mempool"), strings.Contains(lower, "already known"),
+		strings.Contains(lower, "already-known"), strings.Contains(lower, "already in block chain"):
+		return nil
+	case strings.Contains(lower, "absurdly-high-fee"), strings.Contains(lower, "max-fee-exceeded"):
+		kind = clientErr.KindFeeTooHigh
+	case strings.Contains(lower, "dust"):
+		kind = clientErr.KindDust
+	}
+	return clientErr.ClassifyWithReason(kind, reason, fmt.Errorf("error broadcasting tx: %s", reason))
+}
+
 // GetInfo is unused for now so we will not implement it yet
 func (i *BlockBookClient) GetInfo() (*model.Info, error) {
 	return nil, nil
 }
 
 func (i *BlockBookClient) GetTransaction(txid string) (*model.Transaction, error) {
+	if sock := i.nativeClient(); sock != nil {
+		return sock.getTransaction(txid)
+	}
 	type resIn struct {
 		model.Input
 		Addresses []string `json:"addresses"`
@@ -344,90 +446,87 @@ func (i *BlockBookClient) GetRawTransaction(txid string) ([]byte, error) {
 	return nil, nil
 }
 
-// GetTransactions returns the transactions for a given address. If a single address
-// query fails this method will not return an error. Instead it will log the error
-// and returns the transactions for the other addresses.
+// GetTransactions returns the transactions for the given addresses. Addresses are queried
+// concurrently up to the client's request limits and the first failed query aborts the
+// remaining ones and is returned.
 func (i *BlockBookClient) GetTransactions(addrs []btcutil.Address) ([]model.Transaction, error) {
-	var txs []model.Transaction
-	type txsOrError struct {
-		Txs []model.Transaction
-		Err error
-	}
+	return i.GetTransactionsSince(addrs, 0)
+}
+
+// GetTransactionsSince returns the transactions for the given addresses which confirmed at or
+// after fromHeight along with any unconfirmed transactions. A fromHeight of zero returns the
+// full history.
+func (i *BlockBookClient) GetTransactionsSince(addrs []btcutil.Address, fromHeight int) ([]model.Transaction, error) {
 	var (
-		txChan    = make(chan txsOrError)
-		queryChan = make(chan struct{}, maxInfightQueries)
-		wg        sync.WaitGroup
+		txs     []model.Transaction
+		txsLock sync.Mutex
 	)
-	wg.Add(len(addrs))
-	go func() {
-		for _, addr := range addrs {
-			queryChan <- struct{}{}
-			go func(a btcutil.Address) {
-				txs, err := i.getTransactions(maybeConvertCashAddress(a))
-				txChan <- txsOrError{txs, err}
-				<-queryChan
-				wg.Done()
-			}(addr)
-		}
-		wg.Wait()
-		close(txChan)
-	}()
-	for toe := range txChan {
-		if toe.Err != nil {
-			Log.Errorf("Error querying address from blockbook: %s", toe.Err.Error())
-			return nil, toe.Err
-		}
-		txs = append(txs, toe.Txs...)
+	err := forEachBounded(len(addrs), i.limits.MaxConcurrentRequests, func(n int) error {
+		var (
+			addrTxs []model.Transaction
+			err     error
+		)
+		if sock := i.nativeClient(); sock != nil {
+			addrTxs, err = accountTransactions(maybeConvertCashAddress(addrs[n]), fromHeight, func(params accountParams, res *v2Account) error {
+				return sock.call("getAccountInfo", params, res)
+			})
+		} else {
+			addrTxs, err = i.getTransactions(maybeConvertCashAddress(addrs[n]), fromHeight)
+		}
+		if err != nil {
+			return err
+		}
+		txsLock.Lock()
+		txs = append(txs, addrTxs...)
+		txsLock.Unlock()
+		return nil
+	})
+	if err != nil {
+		Log.Errorf("Error querying address from blockbook: %s", err.Error())
+		return nil, err
 	}
 	return txs, nil
 }
 
-func (i *BlockBookClient) getTransactions(addr string) ([]model.Transaction, error) {
+func (i *BlockBookClient) getTransactions(addr string, fromHeight int) ([]model.Transaction, error) {
 	var ret []model.Transaction
 	type resAddr struct {
 		TotalPages   int      `json:"totalPages"`
 		Transactions []string `json:"transactions"`
 	}
-	type txOrError struct {
-		Tx  *model.Transaction
-		Err error
-	}
 	page := 1
 	for {
-		q, err := url.ParseQuery("?page=" + strconv.Itoa(page))
-		if err != nil {
-			return nil, err
+		q := url.Values{}
+		q.Set("page", strconv.Itoa(page))
+		if fromHeight > 0 {
+			q.Set("from", strconv.Itoa(fromHeight))
 		}
 		resp, err := i.RequestFunc("/address/"+addr, http.MethodGet, nil, q)
 		if err != nil {
 			return nil, err
 		}
-		defer resp.Body.Close()
 		res := new(resAddr)
 		decoder := json.NewDecoder(resp.Body)
-		if err = decoder.Decode(res); err != nil {
+		err = decoder.Decode(res)
+		resp.Body.Close()
+		if err != nil {
 			return nil, fmt.Errorf("error decoding addrs response: %s", err)
 		}
-		txChan := make(chan txOrError)
-		go func() {
-			var wg sync.WaitGroup
-			wg.Add(len(res.Transactions))
-			for _, txid := range res.Transactions {
-				go func(id string) {
-					tx, err := i.GetTransaction(id)
-					txChan <- txOrError{tx, err}
-					wg.Done()
-				}(txid)
+		var pageTxs = make([]*model.Transaction, len(res.Transactions))
+		err = forEachBounded(len(res.Transactions), i.limits.MaxConcurrentRequests, func(n int) error {
+			tx, err := i.GetTransaction(res.Transactions[n])
+			if err != nil {
+				return err
 			}
-			wg.Wait()
-			close(txChan)
-		}()
-		for toe := range txChan {
-			if toe.Err != nil {
-				return nil, err
-			}
-			if toe.Tx != nil {
-				ret = append(ret, *toe.Tx)
+			pageTxs[n] = tx
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range pageTxs {
+			if tx != nil {
+				ret = append(ret, *tx)
 			}
 		}
 		if res.TotalPages <= page {
@@ -438,89 +537,345 @@ func (i *BlockBookClient) getTransactions(addr string) ([]model.Transaction, err
 	return ret, nil
 }
 
-// GetUtxos returns the utxos for a given address. If a single address
-// query fails this method will not return an error. Instead it will log the error
-// and returns the transactions for the other addresses.
+// GetUtxos returns the utxos for the given addresses. Addresses are queried concurrently
+// up to the client's request limits and the first failed query aborts the remaining ones
+// and is returned.
 func (i *BlockBookClient) GetUtxos(addrs []btcutil.Address) ([]model.Utxo, error) {
-	var ret []model.Utxo
-	type utxoOrError struct {
-		Utxo *model.Utxo
-		Err  error
-	}
 	var (
-		wg        sync.WaitGroup
-		queryChan = make(chan struct{}, maxInfightQueries)
-		utxoChan  = make(chan utxoOrError)
+		ret     []model.Utxo
+		retLock sync.Mutex
 	)
-	wg.Add(len(addrs))
-	go func() {
-		for _, addr := range addrs {
-			queryChan <- struct{}{}
-			go func(addr btcutil.Address) {
-				defer wg.Done()
-				defer func() {
-					<-queryChan
-				}()
-				resp, err := i.RequestFunc("/utxo/"+maybeConvertCashAddress(addr), http.MethodGet, nil, nil)
-				if err != nil {
-					utxoChan <- utxoOrError{nil, err}
-					return
-				}
-				defer resp.Body.Close()
-				var utxos []model.Utxo
-				decoder := json.NewDecoder(resp.Body)
-				if err = decoder.Decode(&utxos); err != nil {
-					utxoChan <- utxoOrError{nil, err}
-					return
-				}
-				for z, u := range utxos {
-					f, err := model.ToFloat(u.AmountIface)
-					if err != nil {
-						utxoChan <- utxoOrError{nil, err}
-						return
-					}
-					utxos[z].Amount = f
-				}
-				var wg2 sync.WaitGroup
-				wg2.Add(len(utxos))
-				for _, u := range utxos {
-					go func(ut model.Utxo) {
-						defer wg2.Done()
-
-						tx, err := i.GetTransaction(ut.Txid)
-						if err != nil {
-							utxoChan <- utxoOrError{nil, err}
-							return
-						}
-						if len(tx.Outputs)-1 < ut.Vout {
-							utxoChan <- utxoOrError{nil, errors.New("transaction has invalid number of outputs")}
-							return
-						}
-						ut.ScriptPubKey = tx.Outputs[ut.Vout].ScriptPubKey.Hex
-						if len(tx.Outputs[ut.Vout].ScriptPubKey.Addresses[0]) > 0 {
-							ut.Address = maybeTrimCashAddrPrefix(tx.Outputs[ut.Vout].ScriptPubKey.Addresses[0])
-						}
-						utxoChan <- utxoOrError{&ut, nil}
-					}(u)
-				}
-				wg2.Wait()
-			}(addr)
+	if sock := i.nativeClient(); sock != nil {
+		return i.nativeUtxos(sock, addrs)
+	}
+	err := forEachBounded(len(addrs), i.limits.MaxConcurrentRequests, func(n int) error {
+		resp, err := i.RequestFunc("/utxo/"+maybeConvertCashAddress(addrs[n]), http.MethodGet, nil, nil)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		var utxos []model.Utxo
+		decoder := json.NewDecoder(resp.Body)
+		if err = decoder.Decode(&utxos); err != nil {
+			return err
+		}
+		for z, u := range utxos {
+			f, err := model.ToFloat(u.AmountIface)
+			if err != nil {
+				return err
+			}
+			utxos[z].Amount = f
+		}
+		if err := i.fillUtxoScripts(utxos); err != nil {
+			return err
+		}
+		retLock.Lock()
+		ret = append(ret, utxos...)
+		retLock.Unlock()
+		return nil
+	})
+	if err != nil {
+		Log.Errorf("Error querying utxos from blockbook: %s", err.Error())
+		return nil, err
+	}
+	return ret, nil
+}
+
+// fillUtxoScripts looks up the transaction which created each utxo to fill in its
+// scriptPubKey and address
+func (i *BlockBookClient) fillUtxoScripts(utxos []model.Utxo) error {
+	return forEachBounded(len(utxos), i.limits.MaxConcurrentRequests, func(n int) error {
+		tx, err := i.GetTransaction(utxos[n].Txid)
+		if err != nil {
+			return err
+		}
+		var vout = utxos[n].Vout
+		if len(tx.Outputs)-1 < vout {
+			return errors.New("transaction has invalid number of outputs")
+		}
+		utxos[n].ScriptPubKey = tx.Outputs[vout].ScriptPubKey.Hex
+		if len(tx.Outputs[vout].ScriptPubKey.Addresses) > 0 && len(tx.Outputs[vout].ScriptPubKey.Addresses[0]) > 0 {
+			utxos[n].Address = maybeTrimCashAddrPrefix(tx.Outputs[vout].ScriptPubKey.Addresses[0])
+		}
+		return nil
+	})
+}
+
+type v2Vin struct {
+	Txid      string   `json:"txid"`
+	Vout      int      `json:"vout"`
+	Sequence  uint32   `json:"sequence"`
+	N         int      `json:"n"`
+	Addresses []string `json:"addresses"`
+	Value     string   `json:"value"`
+	Hex       string   `json:"hex"`
+}
+
+type v2Vout struct {
+	Value     string   `json:"value"`
+	N         int      `json:"n"`
+	Hex       string   `json:"hex"`
+	Addresses []string `json:"addresses"`
+}
+
+type v2Tx struct {
+	Txid          string   `json:"txid"`
+	Version       int      `json:"version"`
+	Locktime      int      `json:"lockTime"`
+	Vin           []v2Vin  `json:"vin"`
+	Vout          []v2Vout `json:"vout"`
+	BlockHash     string   `json:"blockHash"`
+	BlockHeight   int      `json:"blockHeight"`
+	Confirmations int      `json:"confirmations"`
+	BlockTime     int64    `json:"blockTime"`
+	Hex           string   `json:"hex"`
+}
+
+type v2Token struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type v2Account struct {
+	Page         int       `json:"page"`
+	TotalPages   int       `json:"totalPages"`
+	Transactions []v2Tx    `json:"transactions"`
+	Tokens       []v2Token `json:"tokens"`
+}
+
+func (tx v2Tx) toModel() (model.Transaction, error) {
+	raw, err := hex.DecodeString(tx.Hex)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	ret := model.Transaction{
+		BlockHash:     tx.BlockHash,
+		BlockHeight:   tx.BlockHeight,
+		BlockTime:     tx.BlockTime,
+		Confirmations: tx.Confirmations,
+		Locktime:      tx.Locktime,
+		RawBytes:      raw,
+		Time:          tx.BlockTime,
+		Txid:          tx.Txid,
+		Version:       tx.Version,
+	}
+	// Blockbook reports mempool transactions at height -1
+	if ret.BlockHeight < 0 {
+		ret.BlockHeight = 0
+	}
+	for _, in := range tx.Vin {
+		// Coinbase inputs spend nothing and are reported without a value
+		var sats int64
+		if in.Value != "" {
+			sats, err = strconv.ParseInt(in.Value, 10, 64)
+			if err != nil {
+				return model.Transaction{}, fmt.Errorf("parsing input value: %s", err)
+			}
+		}
+		newIn := model.Input{
+			N:         in.N,
+			Satoshis:  sats,
+			ScriptSig: model.Script{Hex: in.Hex},
+			Sequence:  in.Sequence,
+			Txid:      in.Txid,
+			Value:     float64(sats) / satoshisPerCoin,
+			Vout:      in.Vout,
+		}
+		if len(in.Addresses) > 0 {
+			newIn.Addr = maybeTrimCashAddrPrefix(in.Addresses[0])
+		}
+		ret.Inputs = append(ret.Inputs, newIn)
+	}
+	for _, out := range tx.Vout {
+		sats, err := strconv.ParseInt(out.Value, 10, 64)
+		if err != nil {
+			return model.Transaction{}, fmt.Errorf("parsing output value: %s", err)
 		}
-		wg.Wait()
-		close(utxoChan)
-	}()
-	for toe := range utxoChan {
-		if toe.Err != nil {
-			Log.Errorf("Error querying utxos from blockbook: %s", toe.Err.Error())
-			return nil, toe.Err
+		newOut := model.Output{
+			Value: float64(sats) / satoshisPerCoin,
+			N:     out.N,
+			ScriptPubKey: model.OutScript{
+				Script: model.Script{Hex: out.Hex},
+			},
 		}
-		if toe.Utxo != nil {
-			ret = append(ret, *toe.Utxo)
+		for _, addr := range out.Addresses {
+			newOut.ScriptPubKey.Addresses = append(newOut.ScriptPubKey.Addresses, maybeTrimCashAddrPrefix(addr))
 		}
+		ret.Outputs = append(ret.Outputs, newOut)
 	}
 	return ret, nil
 }
 
+func (i *BlockBookClient) supportsXpub() bool {
+	i.xpubLock.RLock()
+	defer i.xpubLock.RUnlock()
+	return !i.xpubUnsupported
+}
+
+// accountParams are the parameters of an account query. The descriptor, an address or
+// extended public key, is part of the path of a REST request and a parameter of a native
+// websocket request.
+type accountParams map[string]interface{}
+
+func (p accountParams) query() url.Values {
+	q := url.Values{}
+	for k, v := range p {
+		if k != "descriptor" {
+			q.Set(k, fmt.Sprint(v))
+		}
+	}
+	return q
+}
+
+// accountRequest performs one of Blockbook's v2 account queries, over the native websocket
+// when the server provides one and otherwise against the REST endpoint for the descriptor.
+func (i *BlockBookClient) accountRequest(method, endpoint string, params accountParams, res interface{}) error {
+	if sock := i.nativeClient(); sock != nil {
+		return sock.call(method, params, res)
+	}
+	resp, err := i.RequestFunc(endpoint+fmt.Sprint(params["descriptor"]), http.MethodGet, nil, params.query())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(res); err != nil {
+		return fmt.Errorf("error decoding %s response: %s", method, err)
+	}
+	return nil
+}
+
+// xpubRequest performs an account query for an extended public key. Servers which do not
+// provide the query are remembered so later account queries fail fast. Any other failure,
+// such as the server refusing a particular xpub, is returned as is.
+func (i *BlockBookClient) xpubRequest(method, endpoint string, params accountParams, res interface{}) error {
+	if !i.supportsXpub() {
+		return ErrXpubUnsupported
+	}
+	if err := i.accountRequest(method, endpoint, params, res); err != nil {
+		if clientErr.IsRetryable(err) || !unsupportedQuery(err) {
+			return err
+		}
+		Log.Warningf("(%s) xpub query failed, falling back to address queries: %s", i.EndpointURL().String(), err.Error())
+		i.xpubLock.Lock()
+		i.xpubUnsupported = true
+		i.xpubLock.Unlock()
+		return ErrXpubUnsupported
+	}
+	return nil
+}
+
+// unsupportedQuery reports whether err means the server does not provide a query at all,
+// rather than that it failed the query for the parameters given
+func unsupportedQuery(err error) bool {
+	if strings.Contains(err.Error(), "status not ok: 404") {
+		return true
+	}
+	return strings.Contains(strings.ToLower(clientErr.ReasonOf(err)), "not supported")
+}
+
+// GetAccountAddresses returns every address the server derived from the account extended
+// public key while scanning it, including the unused addresses in the gap.
+func (i *BlockBookClient) GetAccountAddresses(xpub string) ([]string, error) {
+	params := accountParams{"descriptor": xpub, "details": "tokens", "tokens": "derived"}
+	res := new(v2Account)
+	if err := i.xpubRequest("getAccountInfo", "/v2/xpub/", params, res); err != nil {
+		return nil, err
+	}
+	var addrs []string
+	for _, token := range res.Tokens {
+		addrs = append(addrs, maybeTrimCashAddrPrefix(token.Name))
+	}
+	return addrs, nil
+}
+
+// GetAccountTransactions returns the transactions for every address derived from the account
+// extended public key which confirmed at or after fromHeight, or the full history if fromHeight
+// is zero. Unlike GetTransactions the full transactions are returned by the server a page at a
+// time so no further requests are needed per txid.
+func (i *BlockBookClient) GetAccountTransactions(xpub string, fromHeight int) ([]model.Transaction, error) {
+	return accountTransactions(xpub, fromHeight, func(params accountParams, res *v2Account) error {
+		return i.xpubRequest("getAccountInfo", "/v2/xpub/", params, res)
+	})
+}
+
+// accountTransactions reads every page of the transaction history of descriptor using request
+func accountTransactions(descriptor string, fromHeight int, request func(accountParams, *v2Account) error) ([]model.Transaction, error) {
+	var ret []model.Transaction
+	page := 1
+	for {
+		params := accountParams{
+			"descriptor": descriptor,
+			"details":    "txs",
+			"page":       page,
+			"pageSize":   xpubPageSize,
+		}
+		if fromHeight > 0 {
+			params["from"] = fromHeight
+		}
+		res := new(v2Account)
+		if err := request(params, res); err != nil {
+			return nil, err
+		}
+		for _, tx := range res.Transactions {
+			mtx, err := tx.toModel()
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, mtx)
+		}
+		if res.TotalPages <= page {
+			break
+		}
+		page++
+	}
+	return ret, nil
+}
+
+type v2Utxo struct {
+	Txid          string `json:"txid"`
+	Vout          int    `json:"vout"`
+	Value         string `json:"value"`
+	Confirmations int    `json:"confirmations"`
+	Address       string `json:"address"`
+}
+
+func v2UtxosToModel(res []v2Utxo) ([]model.Utxo, error) {
+	utxos := make([]model.Utxo, 0, len(res))
+	for _, u := range res {
+		sats, err := strconv.ParseInt(u.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing utxo value: %s", err)
+		}
+		utxos = append(utxos, model.Utxo{
+			Address:       maybeTrimCashAddrPrefix(u.Address),
+			Txid:          u.Txid,
+			Vout:          u.Vout,
+			Amount:        float64(sats) / satoshisPerCoin,
+			Satoshis:      sats,
+			Confirmations: u.Confirmations,
+		})
+	}
+	return utxos, nil
+}
+
+// GetAccountUtxos returns the utxos for every address derived from the account extended
+// public key.
+func (i *BlockBookClient) GetAccountUtxos(xpub string) ([]model.Utxo, error) {
+	var res []v2Utxo
+	if err := i.xpubRequest("getAccountUtxo", "/v2/utxo/", accountParams{"descriptor": xpub}, &res); err != nil {
+		return nil, err
+	}
+	utxos, err := v2UtxosToModel(res)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.fillUtxoScripts(utxos); err != nil {
+		return nil, err
+	}
+	return utxos, nil
+}
+
 func (i *BlockBookClient) BlockNotify() <-chan model.Block {
 	return i.blockNotifyChan
 }
@@ -539,15 +894,30 @@ func (i *BlockBookClient) ListenAddresses(addrs ...btcutil.Address) {
 	i.socketMutex.RLock()
 	defer i.socketMutex.RUnlock()
 
+	watched := make(map[string]bool)
+	for _, addr := range i.listenAddrs {
+		watched[addr] = true
+	}
 	var convertedAddrs []string
 	for _, addr := range addrs {
-		convertedAddrs = append(convertedAddrs, maybeConvertCashAddress(addr))
+		converted := maybeConvertCashAddress(addr)
+		if !watched[converted] {
+			watched[converted] = true
+			convertedAddrs = append(convertedAddrs, converted)
+		}
+	}
+	if len(convertedAddrs) == 0 {
+		return
 	}
+	// Every address is remembered so it can be subscribed again after a reconnect
+	i.listenAddrs = append(i.listenAddrs, convertedAddrs...)
 
-	if i.SocketClient != nil {
+	if i.native != nil {
+		if err := i.native.subscribeAddresses(i.listenAddrs, i.onNativeAddressTx); err != nil {
+			Log.Errorf("subscribing to addresses (%s): %s", i.String(), err.Error())
+		}
+	} else if i.SocketClient != nil {
 		i.SocketClient.Emit("subscribe", []interface{}{"bitcoind/addresstxid", convertedAddrs})
-	} else {
-		i.listenQueue = append(i.listenQueue, convertedAddrs...)
 	}
 }
 
@@ -580,10 +950,23 @@ func (i *BlockBookClient) setupListeners() error {
 	i.listenLock.Lock()
 	defer i.listenLock.Unlock()
 
-	if i.SocketClient != nil {
+	if i.SocketClient != nil || i.native != nil {
 		return nil
 	}
 
+	if !i.nativeUnsupported {
+		err := i.setupNativeListeners()
+		if err == nil {
+			return nil
+		}
+		if err == errNativeSocketUnsupported {
+			Log.Infof("native websocket unavailable, using socket.io (%s)", i.String())
+			i.nativeUnsupported = true
+		} else {
+			Log.Warningf("native websocket (%s): %s", i.String(), err.Error())
+		}
+	}
+
 	client, err := connectSocket(i.apiUrl, i.proxyDialer)
 	if err != nil {
 		Log.Errorf("reconnect websocket (%s): %s", i.String(), err.Error())
@@ -656,10 +1039,9 @@ func (i *BlockBookClient) setupListeners() error {
 		}
 	})
 
-	// Subscribe to queued addresses
-	if len(i.listenQueue) != 0 {
-		i.SocketClient.Emit("subscribe", []interface{}{"bitcoind/addresstxid", i.listenQueue})
-		i.listenQueue = []string{}
+	// Subscribe to every address, including those subscribed before a reconnect
+	if len(i.listenAddrs) != 0 {
+		i.SocketClient.Emit("subscribe", []interface{}{"bitcoind/addresstxid", i.listenAddrs})
 	}
 
 	Log.Infof("websocket connected (%s)", i.String())
@@ -668,9 +1050,12 @@ func (i *BlockBookClient) setupListeners() error {
 
 func (i *BlockBookClient) Broadcast(tx []byte) (string, error) {
 	txHex := hex.EncodeToString(tx)
+	if sock := i.nativeClient(); sock != nil {
+		return sock.sendTransaction(txHex)
+	}
 	resp, err := i.RequestFunc("sendtx/"+txHex, http.MethodGet, nil, nil)
 	if err != nil {
-		return "", fmt.Errorf("error broadcasting tx: %s", err)
+		return "", broadcastError(err)
 	}
 	defer resp.Body.Close()
 
@@ -685,6 +1070,9 @@ func (i *BlockBookClient) Broadcast(tx []byte) (string, error) {
 }
 
 func (i *BlockBookClient) GetBestBlock() (*model.Block, error) {
+	if sock := i.nativeClient(); sock != nil {
+		return sock.getBestBlock()
+	}
 	type backend struct {
 		Blocks        int    `json:"blocks"`
 		BestBlockHash string `json:"bestBlockHash"`
@@ -693,10 +1081,6 @@ func (i *BlockBookClient) GetBestBlock() (*model.Block, error) {
 		Backend backend `json:"backend"`
 	}
 
-	type resBlockHash struct {
-		BlockHash string `json:"blockHash"`
-	}
-
 	resp, err := i.RequestFunc("", http.MethodGet, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("getting block index: %s", err.Error())
@@ -708,27 +1092,42 @@ func (i *BlockBookClient) GetBestBlock() (*model.Block, error) {
 	if err = decoder.Decode(bi); err != nil {
 		return nil, fmt.Errorf("decoding block index: %s", err)
 	}
-	blockIndexPath := "/block-index/" + strconv.Itoa(bi.Backend.Blocks-1)
-
-	resp2, err := i.RequestFunc(blockIndexPath, http.MethodGet, nil, nil)
+	previousHash, err := i.GetBlockHash(bi.Backend.Blocks - 1)
 	if err != nil {
-		return nil, fmt.Errorf("getting block detail (%s): %s", blockIndexPath, err.Error())
-	}
-	defer resp2.Body.Close()
-
-	decoder2 := json.NewDecoder(resp2.Body)
-	bh := new(resBlockHash)
-	if err = decoder2.Decode(bh); err != nil {
-		return nil, fmt.Errorf("decoding block detail: %s", err)
+		return nil, err
 	}
 
 	return &model.Block{
 		Hash:              bi.Backend.BestBlockHash,
 		Height:            bi.Backend.Blocks,
-		PreviousBlockhash: bh.BlockHash,
+		PreviousBlockhash: previousHash,
 	}, nil
 }
 
+// GetBlockHash returns the hash of the block at the given height on the server's best chain
+func (i *BlockBookClient) GetBlockHash(height int) (string, error) {
+	if sock := i.nativeClient(); sock != nil {
+		return sock.getBlockHash(height)
+	}
+	type resBlockHash struct {
+		BlockHash string `json:"blockHash"`
+	}
+
+	blockIndexPath := "/block-index/" + strconv.Itoa(height)
+	resp, err := i.RequestFunc(blockIndexPath, http.MethodGet, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting block detail (%s): %s", blockIndexPath, err.Error())
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	bh := new(resBlockHash)
+	if err = decoder.Decode(bh); err != nil {
+		return "", fmt.Errorf("decoding block detail: %s", err)
+	}
+	return bh.BlockHash, nil
+}
+
 func (i *BlockBookClient) GetBlocksBefore(to time.Time, limit int) (*model.BlockList, error) {
 	resp, err := i.RequestFunc("blocks", http.MethodGet, nil, url.Values{
 		"blockDate":      {to.Format("2006-01-02")},
@@ -748,6 +1147,9 @@ func (i *BlockBookClient) GetBlocksBefore(to time.Time, limit int) (*model.Block
 }
 
 func (i *BlockBookClient) EstimateFee(nbBlocks int) (int, error) {
+	if sock := i.nativeClient(); sock != nil {
+		return sock.estimateFee(nbBlocks)
+	}
 	resp, err := i.RequestFunc("utils/estimatefee", http.MethodGet, nil, url.Values{"nbBlocks": {fmt.Sprint(nbBlocks)}})
 	if err != nil {
 		return 0, err
@@ -0,0 +1,120 @@
+package blockbook
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestsPerSecond = 25
+	defaultRequestBurst      = 50
+)
+
+// RequestLimits bounds the load a client places on its server. MaxConcurrentRequests
+// caps the number of requests in flight at once while RequestsPerSecond and RequestBurst
+// configure a token bucket which every request must draw from. Zero values fall back to
+// the client defaults and a negative RequestsPerSecond disables rate limiting.
+type RequestLimits struct {
+	MaxConcurrentRequests int
+	RequestsPerSecond     float64
+	RequestBurst          int
+}
+
+func (l RequestLimits) withDefaults() RequestLimits {
+	if l.MaxConcurrentRequests <= 0 {
+		l.MaxConcurrentRequests = maxInfightQueries
+	}
+	if l.RequestsPerSecond == 0 {
+		l.RequestsPerSecond = defaultRequestsPerSecond
+	}
+	if l.RequestBurst <= 0 {
+		l.RequestBurst = defaultRequestBurst
+	}
+	return l
+}
+
+// tokenBucket is a rate limiter which allows bursts of up to burst requests and
+// refills at rate tokens per second. A bucket with a non-positive rate never blocks.
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available and takes it
+func (b *tokenBucket) wait() {
+	if b.rate <= 0 {
+		return
+	}
+	for {
+		b.lock.Lock()
+		var now = time.Now()
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.lock.Unlock()
+			return
+		}
+		var delay = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.lock.Unlock()
+		time.Sleep(delay)
+	}
+}
+
+// forEachBounded calls fn for every index in [0, n) using at most workers goroutines.
+// Once any call returns an error no further indexes are started and the first error
+// is returned after the running calls complete.
+func forEachBounded(n, workers int, fn func(i int) error) error {
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		indexes  = make(chan int)
+		done     = make(chan struct{})
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(i); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						close(done)
+					})
+				}
+			}
+		}()
+	}
+	func() {
+		defer close(indexes)
+		for i := 0; i < n; i++ {
+			select {
+			case indexes <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+	wg.Wait()
+	return firstErr
+}
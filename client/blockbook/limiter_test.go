@@ -0,0 +1,87 @@
+package blockbook
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEachBoundedLimitsConcurrency(t *testing.T) {
+	var (
+		workers  = 4
+		inFlight int32
+		maxSeen  int32
+		seenLock sync.Mutex
+		visited  = make(map[int]bool)
+	)
+	err := forEachBounded(50, workers, func(i int) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		seenLock.Lock()
+		if n > maxSeen {
+			maxSeen = n
+		}
+		visited[i] = true
+		seenLock.Unlock()
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxSeen > int32(workers) {
+		t.Errorf("expected at most %d calls in flight, saw %d", workers, maxSeen)
+	}
+	if len(visited) != 50 {
+		t.Errorf("expected every index to be visited, got %d", len(visited))
+	}
+}
+
+func TestForEachBoundedStopsAfterError(t *testing.T) {
+	var (
+		expectedErr = errors.New("query failed")
+		calls       int32
+	)
+	err := forEachBounded(1000, 2, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 3 {
+			return expectedErr
+		}
+		return nil
+	})
+	if err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+	if calls >= 1000 {
+		t.Error("expected dispatching to stop after the first error")
+	}
+}
+
+func TestTokenBucketLimitsRate(t *testing.T) {
+	var (
+		bucket = newTokenBucket(100, 5)
+		start  = time.Now()
+	)
+	for i := 0; i < 15; i++ {
+		bucket.wait()
+	}
+	// 5 tokens are available immediately and the other 10 refill at 100/s
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected requests beyond the burst to be delayed, took %s", elapsed)
+	}
+}
+
+func TestTokenBucketDisabledWithNegativeRate(t *testing.T) {
+	var (
+		bucket = newTokenBucket(RequestLimits{RequestsPerSecond: -1}.withDefaults().RequestsPerSecond, 1)
+		start  = time.Now()
+	)
+	for i := 0; i < 1000; i++ {
+		bucket.wait()
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected disabled bucket to never block, took %s", elapsed)
+	}
+}
@@ -0,0 +1,497 @@
+package blockbook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/btcsuite/btcutil"
+	"github.com/gorilla/websocket"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	nativeSocketPath        = "/websocket"
+	nativeSocketTimeout     = 10 * time.Second
+	nativeRequestTimeout    = 30 * time.Second
+	nativeKeepaliveInterval = 30 * time.Second
+)
+
+var (
+	// errNativeSocketUnsupported is returned when the server refuses the native websocket
+	// handshake, in which case the client falls back to socket.io and the REST API.
+	errNativeSocketUnsupported = errors.New("server does not provide the native websocket api")
+	errNativeSocketClosed      = errors.New("native websocket closed")
+)
+
+type nativeRequest struct {
+	ID     string      `json:"id"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params"`
+}
+
+type nativeResponse struct {
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+type nativeError struct {
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// nativeSocket is a connection to Blockbook's native websocket API. Requests and their
+// responses are matched by id. Subscriptions keep the id of the request which created them
+// and every later message with that id is passed to the subscription's handler.
+type nativeSocket struct {
+	conn      *websocket.Conn
+	host      string
+	writeLock sync.Mutex
+	onClose   func()
+
+	lock          sync.Mutex
+	nextID        int
+	pending       map[string]chan nativeResponse
+	handlers      map[string]func(json.RawMessage)
+	subscriptions map[string]string
+	closing       bool
+	notifications []func()
+	notified      chan struct{}
+	done          chan struct{}
+}
+
+// nativeSocketURL returns the address of the native websocket of the server whose API is
+// served at apiUrl
+func nativeSocketURL(apiUrl *url.URL) *url.URL {
+	u := *apiUrl
+	u.Scheme = "ws"
+	if apiUrl.Scheme == "https" {
+		u.Scheme = "wss"
+	}
+	u.Path = nativeSocketPath
+	u.RawQuery = ""
+	return &u
+}
+
+// dialNativeSocket connects to the native websocket of the server at apiUrl and checks it
+// answers requests. onClose is called if the connection is lost.
+func dialNativeSocket(apiUrl *url.URL, proxyDialer proxy.Dialer, onClose func()) (*nativeSocket, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: nativeSocketTimeout}
+	if proxyDialer != nil {
+		dialer.NetDial = proxyDialer.Dial
+	}
+	conn, resp, err := dialer.Dial(nativeSocketURL(apiUrl).String(), nil)
+	if err != nil {
+		if resp != nil {
+			return nil, errNativeSocketUnsupported
+		}
+		return nil, err
+	}
+	s := &nativeSocket{
+		conn:          conn,
+		host:          apiUrl.Host,
+		onClose:       onClose,
+		pending:       make(map[string]chan nativeResponse),
+		handlers:      make(map[string]func(json.RawMessage)),
+		subscriptions: make(map[string]string),
+		notified:      make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	go s.readLoop()
+	go s.runNotifications()
+
+	var info struct {
+		BestHeight int `json:"bestHeight"`
+	}
+	if err := s.call("getInfo", struct{}{}, &info); err != nil {
+		s.close()
+		if clientErr.IsRetryable(err) {
+			return nil, err
+		}
+		return nil, errNativeSocketUnsupported
+	}
+	go s.keepalive()
+	return s, nil
+}
+
+func (s *nativeSocket) readLoop() {
+	for {
+		var res nativeResponse
+		if err := s.conn.ReadJSON(&res); err != nil {
+			s.lock.Lock()
+			closing := s.closing
+			s.lock.Unlock()
+			if !closing {
+				Log.Warningf("native websocket read (%s): %s", s.host, err.Error())
+			}
+			s.shutdown()
+			return
+		}
+		s.lock.Lock()
+		if ch, ok := s.pending[res.ID]; ok {
+			delete(s.pending, res.ID)
+			s.lock.Unlock()
+			ch <- res
+			continue
+		}
+		if handler, ok := s.handlers[res.ID]; ok {
+			data := res.Data
+			s.notifications = append(s.notifications, func() { handler(data) })
+			select {
+			case s.notified <- struct{}{}:
+			default:
+			}
+		}
+		s.lock.Unlock()
+	}
+}
+
+// runNotifications passes subscription messages to their handlers in the order they arrived.
+// Handlers run outside the read loop so they are free to make requests of their own.
+func (s *nativeSocket) runNotifications() {
+	for {
+		select {
+		case <-s.notified:
+		case <-s.done:
+			return
+		}
+		for {
+			s.lock.Lock()
+			if len(s.notifications) == 0 {
+				s.lock.Unlock()
+				break
+			}
+			notify := s.notifications[0]
+			s.notifications = s.notifications[1:]
+			s.lock.Unlock()
+			notify()
+		}
+	}
+}
+
+func (s *nativeSocket) keepalive() {
+	t := time.NewTicker(nativeKeepaliveInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := s.call("ping", struct{}{}, nil); err != nil {
+				Log.Warningf("native websocket ping (%s): %s", s.host, err.Error())
+				s.conn.Close()
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// shutdown releases everything waiting on the connection and tells the owner if the
+// connection was lost rather than closed
+func (s *nativeSocket) shutdown() {
+	s.lock.Lock()
+	select {
+	case <-s.done:
+		s.lock.Unlock()
+		return
+	default:
+	}
+	close(s.done)
+	lost := !s.closing
+	s.lock.Unlock()
+	s.conn.Close()
+	if lost && s.onClose != nil {
+		s.onClose()
+	}
+}
+
+func (s *nativeSocket) close() {
+	s.lock.Lock()
+	s.closing = true
+	s.lock.Unlock()
+	s.shutdown()
+}
+
+// send writes a request and returns the channel its response will be delivered on. If
+// handler is not nil it receives every later message with the same id.
+func (s *nativeSocket) send(method string, params interface{}, handler func(json.RawMessage)) (string, chan nativeResponse, error) {
+	ch := make(chan nativeResponse, 1)
+	s.lock.Lock()
+	select {
+	case <-s.done:
+		s.lock.Unlock()
+		return "", nil, clientErr.MakeRetryable(errNativeSocketClosed)
+	default:
+	}
+	s.nextID++
+	id := strconv.Itoa(s.nextID)
+	s.pending[id] = ch
+	if handler != nil {
+		s.handlers[id] = handler
+	}
+	s.lock.Unlock()
+
+	s.writeLock.Lock()
+	s.conn.SetWriteDeadline(time.Now().Add(nativeSocketTimeout))
+	err := s.conn.WriteJSON(nativeRequest{ID: id, Method: method, Params: params})
+	s.writeLock.Unlock()
+	if err != nil {
+		s.forget(id)
+		return "", nil, clientErr.MakeRetryable(fmt.Errorf("sending %s (%s): %s", method, s.host, err.Error()))
+	}
+	return id, ch, nil
+}
+
+func (s *nativeSocket) forget(id string) {
+	s.lock.Lock()
+	delete(s.pending, id)
+	delete(s.handlers, id)
+	s.lock.Unlock()
+}
+
+// wait returns the response to a request, decoding its data into result if result is not nil
+func (s *nativeSocket) wait(id, method string, ch chan nativeResponse, result interface{}) error {
+	var res nativeResponse
+	select {
+	case res = <-ch:
+	case <-s.done:
+		s.forget(id)
+		return clientErr.MakeRetryable(errNativeSocketClosed)
+	case <-time.After(nativeRequestTimeout):
+		s.forget(id)
+		return clientErr.MakeRetryable(fmt.Errorf("timed out waiting for %s (%s)", method, s.host))
+	}
+	var e nativeError
+	if err := json.Unmarshal(res.Data, &e); err == nil && e.Error != nil {
+		return clientErr.ClassifyWithReason(clientErr.KindUnknown, e.Error.Message, fmt.Errorf("%s (%s): %s", method, s.host, e.Error.Message))
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(res.Data, result); err != nil {
+		return fmt.Errorf("error decoding %s response: %s", method, err)
+	}
+	return nil
+}
+
+// call makes a request and decodes the data of its response into result
+func (s *nativeSocket) call(method string, params interface{}, result interface{}) error {
+	id, ch, err := s.send(method, params, nil)
+	if err != nil {
+		return err
+	}
+	return s.wait(id, method, ch, result)
+}
+
+// subscribe creates a subscription whose messages are passed to handler. Blockbook keeps a
+// single subscription per method so an earlier subscription with the same method is dropped.
+func (s *nativeSocket) subscribe(method string, params interface{}, handler func(json.RawMessage)) error {
+	id, ch, err := s.send(method, params, handler)
+	if err != nil {
+		return err
+	}
+	var res struct {
+		Subscribed bool `json:"subscribed"`
+	}
+	if err := s.wait(id, method, ch, &res); err != nil {
+		s.forget(id)
+		return err
+	}
+	if !res.Subscribed {
+		s.forget(id)
+		return fmt.Errorf("%s (%s): subscription refused", method, s.host)
+	}
+	s.lock.Lock()
+	if prev, ok := s.subscriptions[method]; ok {
+		delete(s.handlers, prev)
+	}
+	s.subscriptions[method] = id
+	s.lock.Unlock()
+	return nil
+}
+
+// subscribeAddresses watches addrs for new transactions. Each subscription replaces the last
+// one so addrs must hold every address to watch.
+func (s *nativeSocket) subscribeAddresses(addrs []string, handler func(json.RawMessage)) error {
+	return s.subscribe("subscribeAddresses", map[string]interface{}{"addresses": addrs}, handler)
+}
+
+func (s *nativeSocket) getTransaction(txid string) (*model.Transaction, error) {
+	var tx v2Tx
+	if err := s.call("getTransaction", map[string]interface{}{"txid": txid}, &tx); err != nil {
+		return nil, err
+	}
+	ret, err := tx.toModel()
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (s *nativeSocket) getBlockHash(height int) (string, error) {
+	var res struct {
+		Hash string `json:"hash"`
+	}
+	if err := s.call("getBlockHash", map[string]interface{}{"height": height}, &res); err != nil {
+		return "", err
+	}
+	return res.Hash, nil
+}
+
+func (s *nativeSocket) getBestBlock() (*model.Block, error) {
+	var info struct {
+		BestHeight int    `json:"bestHeight"`
+		BestHash   string `json:"bestHash"`
+	}
+	if err := s.call("getInfo", struct{}{}, &info); err != nil {
+		return nil, err
+	}
+	previousHash, err := s.getBlockHash(info.BestHeight - 1)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Block{
+		Hash:              info.BestHash,
+		Height:            info.BestHeight,
+		PreviousBlockhash: previousHash,
+	}, nil
+}
+
+func (s *nativeSocket) sendTransaction(txHex string) (string, error) {
+	var res struct {
+		Txid string `json:"result"`
+	}
+	if err := s.call("sendTransaction", map[string]interface{}{"hex": txHex}, &res); err != nil {
+		return "", broadcastError(err)
+	}
+	return res.Txid, nil
+}
+
+// estimateFee returns the fee in satoshis per kilobyte for confirmation within nbBlocks
+func (s *nativeSocket) estimateFee(nbBlocks int) (int, error) {
+	var res []struct {
+		FeePerUnit string `json:"feePerUnit"`
+	}
+	if err := s.call("estimateFee", map[string]interface{}{"blocks": []int{nbBlocks}}, &res); err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, errors.New("empty fee estimate")
+	}
+	fee, err := strconv.Atoi(res[0].FeePerUnit)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding fee estimate: %s", err)
+	}
+	return fee, nil
+}
+
+// nativeClient returns the native websocket if the client is using one
+func (i *BlockBookClient) nativeClient() *nativeSocket {
+	i.socketMutex.RLock()
+	defer i.socketMutex.RUnlock()
+	return i.native
+}
+
+// nativeUtxos returns the utxos for the given addresses using the native websocket
+func (i *BlockBookClient) nativeUtxos(sock *nativeSocket, addrs []btcutil.Address) ([]model.Utxo, error) {
+	var (
+		ret     []model.Utxo
+		retLock sync.Mutex
+	)
+	err := forEachBounded(len(addrs), i.limits.MaxConcurrentRequests, func(n int) error {
+		var res []v2Utxo
+		if err := sock.call("getAccountUtxo", accountParams{"descriptor": maybeConvertCashAddress(addrs[n])}, &res); err != nil {
+			return err
+		}
+		utxos, err := v2UtxosToModel(res)
+		if err != nil {
+			return err
+		}
+		if err := i.fillUtxoScripts(utxos); err != nil {
+			return err
+		}
+		retLock.Lock()
+		ret = append(ret, utxos...)
+		retLock.Unlock()
+		return nil
+	})
+	if err != nil {
+		Log.Errorf("Error querying utxos from blockbook: %s", err.Error())
+		return nil, err
+	}
+	return ret, nil
+}
+
+// setupNativeListeners connects the native websocket and subscribes to new blocks and every
+// address the client has been asked to listen to. The caller must hold socketMutex and listenLock.
+func (i *BlockBookClient) setupNativeListeners() error {
+	sock, err := dialNativeSocket(i.apiUrl, i.proxyDialer, func() {
+		Log.Warningf("websocket disconnected (%s)", i.String())
+		i.websocketWatchdog.bark()
+	})
+	if err != nil {
+		return err
+	}
+	if err := sock.subscribe("subscribeNewBlock", struct{}{}, func(data json.RawMessage) {
+		i.onNativeBlock(sock, data)
+	}); err != nil {
+		sock.close()
+		return err
+	}
+	if len(i.listenAddrs) != 0 {
+		if err := sock.subscribeAddresses(i.listenAddrs, i.onNativeAddressTx); err != nil {
+			sock.close()
+			return err
+		}
+	}
+	i.native = sock
+	go i.websocketWatchdog.guardWebsocket()
+	Log.Infof("native websocket connected (%s)", i.String())
+	return nil
+}
+
+func (i *BlockBookClient) onNativeBlock(sock *nativeSocket, data json.RawMessage) {
+	var notification struct {
+		Height int    `json:"height"`
+		Hash   string `json:"hash"`
+	}
+	if err := json.Unmarshal(data, &notification); err != nil {
+		Log.Errorf("error decoding block notification: %s", err.Error())
+		return
+	}
+	previousHash, err := sock.getBlockHash(notification.Height - 1)
+	if err != nil {
+		Log.Errorf("error downloading previous block hash: %s", err.Error())
+		return
+	}
+	i.blockNotifyChan <- model.Block{
+		Hash:              notification.Hash,
+		Height:            notification.Height,
+		PreviousBlockhash: previousHash,
+	}
+}
+
+func (i *BlockBookClient) onNativeAddressTx(data json.RawMessage) {
+	var notification struct {
+		Address string `json:"address"`
+		Tx      v2Tx   `json:"tx"`
+	}
+	if err := json.Unmarshal(data, &notification); err != nil {
+		Log.Errorf("error decoding address notification: %s", err.Error())
+		return
+	}
+	tx, err := notification.Tx.toModel()
+	if err != nil {
+		Log.Errorf("error decoding tx after socket notification: %s", err.Error())
+		return
+	}
+	tx.Time = time.Now().Unix()
+	i.txNotifyChan <- tx
+}
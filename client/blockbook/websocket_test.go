@@ -0,0 +1,349 @@
+package blockbook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	fakeAddress = "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"
+	fakeTxid    = "1be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428"
+)
+
+// fakeBlockbook serves the subset of Blockbook's native websocket API used by the client.
+// Every other path responds 404 so tests fail if the client falls back to REST.
+type fakeBlockbook struct {
+	sync.Mutex
+	conn          *websocket.Conn
+	writeLock     sync.Mutex
+	blocks        map[int]string
+	txs           []v2Tx
+	broadcasts    []string
+	subscriptions map[string]string
+	subscribed    []string
+}
+
+func newFakeBlockbook(t *testing.T) (*fakeBlockbook, *httptest.Server) {
+	f := &fakeBlockbook{
+		blocks:        map[int]string{999: "hash999", 1000: "hash1000"},
+		subscriptions: make(map[string]string),
+		txs: []v2Tx{{
+			Txid:          fakeTxid,
+			Version:       1,
+			Vin:           []v2Vin{{Txid: fakeTxid, Vout: 1, Sequence: 0xffffffff, Addresses: []string{"1F1tAaz5x1HUXrCNLbtMDqcw6o5GNn4xqX"}, Value: "30000000"}},
+			Vout:          []v2Vout{{Value: "20000000", N: 0, Hex: "76a91477bff20c60e522dfaa3350c39b030a5d004e839a88ac", Addresses: []string{fakeAddress}}},
+			BlockHash:     "hash1000",
+			BlockHeight:   1000,
+			Confirmations: 1,
+			BlockTime:     1557000000,
+			Hex:           "0100",
+		}},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(nativeSocketPath, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		f.Lock()
+		f.conn = conn
+		f.subscribed = nil
+		f.Unlock()
+		f.serve(conn)
+	})
+	return f, httptest.NewServer(mux)
+}
+
+func (f *fakeBlockbook) serve(conn *websocket.Conn) {
+	for {
+		var req struct {
+			ID     string          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		f.reply(req.ID, f.handle(req.ID, req.Method, req.Params))
+	}
+}
+
+func (f *fakeBlockbook) handle(id, method string, raw json.RawMessage) interface{} {
+	var params struct {
+		Descriptor string   `json:"descriptor"`
+		Txid       string   `json:"txid"`
+		Height     int      `json:"height"`
+		Hex        string   `json:"hex"`
+		Addresses  []string `json:"addresses"`
+	}
+	json.Unmarshal(raw, &params)
+	f.Lock()
+	defer f.Unlock()
+	switch method {
+	case "getInfo":
+		return map[string]interface{}{"bestHeight": 1000, "bestHash": f.blocks[1000]}
+	case "getBlockHash":
+		return map[string]interface{}{"hash": f.blocks[params.Height]}
+	case "getTransaction":
+		for _, tx := range f.txs {
+			if tx.Txid == params.Txid {
+				return tx
+			}
+		}
+		return map[string]interface{}{"error": map[string]string{"message": "tx not found"}}
+	case "getAccountInfo":
+		if params.Descriptor != fakeAddress {
+			return v2Account{Page: 1, TotalPages: 1}
+		}
+		return v2Account{Page: 1, TotalPages: 1, Transactions: f.txs}
+	case "getAccountUtxo":
+		if params.Descriptor != fakeAddress {
+			return []v2Utxo{}
+		}
+		return []v2Utxo{{Txid: fakeTxid, Vout: 0, Value: "20000000", Confirmations: 1, Address: fakeAddress}}
+	case "sendTransaction":
+		if params.Hex == "ff" {
+			return map[string]interface{}{"error": map[string]string{"message": "-26: txn-mempool-conflict"}}
+		}
+		f.broadcasts = append(f.broadcasts, params.Hex)
+		return map[string]string{"result": fakeTxid}
+	case "estimateFee":
+		return []map[string]string{{"feePerUnit": "12000"}}
+	case "subscribeNewBlock", "subscribeAddresses":
+		f.subscriptions[method] = id
+		if method == "subscribeAddresses" {
+			f.subscribed = params.Addresses
+		}
+		return map[string]bool{"subscribed": true}
+	}
+	return map[string]interface{}{"error": map[string]string{"message": "unknown method " + method}}
+}
+
+func (f *fakeBlockbook) reply(id string, data interface{}) {
+	f.Lock()
+	conn := f.conn
+	f.Unlock()
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+	conn.WriteJSON(map[string]interface{}{"id": id, "data": data})
+}
+
+// notify sends a message to the subscription created with method
+func (f *fakeBlockbook) notify(method string, data interface{}) {
+	f.Lock()
+	id := f.subscriptions[method]
+	f.Unlock()
+	f.reply(id, data)
+}
+
+func (f *fakeBlockbook) subscribedAddresses() []string {
+	f.Lock()
+	defer f.Unlock()
+	return f.subscribed
+}
+
+func startNativeClient(t *testing.T) (*BlockBookClient, *fakeBlockbook, func()) {
+	fake, server := newFakeBlockbook(t)
+	client, err := NewBlockBookClient(server.URL+"/api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Start(make(chan error, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if client.nativeClient() == nil {
+		t.Fatal("expected the native websocket to be used")
+	}
+	return client, fake, func() {
+		client.Close()
+		server.Close()
+	}
+}
+
+func TestNativeSocketURL(t *testing.T) {
+	for api, expected := range map[string]string{
+		"https://btc.blockbook.api.openbazaar.org/api": "wss://btc.blockbook.api.openbazaar.org/websocket",
+		"http://localhost:8080/api/?x=1":               "ws://localhost:8080/websocket",
+	} {
+		u, err := url.Parse(api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := nativeSocketURL(u).String(); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+	}
+}
+
+func TestNativeSocketUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	u, err := url.Parse(server.URL + "/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dialNativeSocket(u, nil, nil); err != errNativeSocketUnsupported {
+		t.Errorf("expected the server to be reported unsupported, got %v", err)
+	}
+}
+
+func TestNativeSocketRequests(t *testing.T) {
+	client, fake, stop := startNativeClient(t)
+	defer stop()
+
+	best, err := client.GetBestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if best.Height != 1000 || best.Hash != "hash1000" || best.PreviousBlockhash != "hash999" {
+		t.Errorf("unexpected best block %+v", best)
+	}
+
+	fee, err := client.EstimateFee(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 12000 {
+		t.Errorf("expected fee of 12000, got %d", fee)
+	}
+
+	txid, err := client.Broadcast([]byte{0x01, 0x02})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txid != fakeTxid || len(fake.broadcasts) != 1 || fake.broadcasts[0] != "0102" {
+		t.Errorf("unexpected broadcast %s %v", txid, fake.broadcasts)
+	}
+
+	_, err = client.Broadcast([]byte{0xff})
+	if kind := clientErr.KindOf(err); kind != clientErr.KindRejected {
+		t.Errorf("expected broadcast to be rejected, got %s: %v", kind, err)
+	}
+	if reason := clientErr.ReasonOf(err); reason != "-26: txn-mempool-conflict" {
+		t.Errorf("expected the rejection reason to be kept, got %q", reason)
+	}
+
+	if _, err := client.GetTransaction("00"); err == nil {
+		t.Error("expected error for unknown transaction")
+	}
+
+	addr, err := btcutil.DecodeAddress(fakeAddress, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs, err := client.GetTransactions([]btcutil.Address{addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].Txid != fakeTxid || txs[0].Outputs[0].Value != 0.2 || txs[0].Inputs[0].Satoshis != 30000000 {
+		t.Errorf("unexpected transactions %+v", txs)
+	}
+
+	utxos, err := client.GetUtxos([]btcutil.Address{addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 || utxos[0].Satoshis != 20000000 || utxos[0].ScriptPubKey != "76a91477bff20c60e522dfaa3350c39b030a5d004e839a88ac" {
+		t.Errorf("unexpected utxos %+v", utxos)
+	}
+}
+
+func TestNativeSocketNotifications(t *testing.T) {
+	client, fake, stop := startNativeClient(t)
+	defer stop()
+
+	addr, err := btcutil.DecodeAddress(fakeAddress, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.ListenAddresses(addr)
+	if subscribed := fake.subscribedAddresses(); len(subscribed) != 1 || subscribed[0] != fakeAddress {
+		t.Fatalf("expected address subscription, got %v", subscribed)
+	}
+
+	fake.notify("subscribeAddresses", map[string]interface{}{"address": fakeAddress, "tx": fake.txs[0]})
+	select {
+	case tx := <-client.TxChannel():
+		if tx.Txid != fakeTxid {
+			t.Errorf("expected notification for %s, got %s", fakeTxid, tx.Txid)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for tx notification")
+	}
+
+	fake.Lock()
+	fake.blocks[1001] = "hash1001"
+	fake.Unlock()
+	fake.notify("subscribeNewBlock", map[string]interface{}{"height": 1001, "hash": "hash1001"})
+	select {
+	case block := <-client.BlockChannel():
+		if block.Height != 1001 || block.Hash != "hash1001" || block.PreviousBlockhash != "hash1000" {
+			t.Errorf("unexpected block notification %+v", block)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for block notification")
+	}
+}
+
+func TestNativeSocketIgnoresStaleSubscriptions(t *testing.T) {
+	client, fake, stop := startNativeClient(t)
+	defer stop()
+
+	sock := client.nativeClient()
+	if err := sock.subscribeAddresses([]string{"a"}, func(json.RawMessage) {}); err != nil {
+		t.Fatal(err)
+	}
+	fake.Lock()
+	first := fake.subscriptions["subscribeAddresses"]
+	fake.Unlock()
+	if err := sock.subscribeAddresses([]string{"a", "b"}, func(json.RawMessage) {}); err != nil {
+		t.Fatal(err)
+	}
+	if subscribed := fake.subscribedAddresses(); len(subscribed) != 2 {
+		t.Errorf("expected the subscription to be replaced, got %v", subscribed)
+	}
+	sock.lock.Lock()
+	_, stale := sock.handlers[first]
+	sock.lock.Unlock()
+	if stale {
+		t.Error("expected the replaced subscription's handler to be dropped")
+	}
+}
+
+func TestNativeSocketResubscribesAfterReconnect(t *testing.T) {
+	client, fake, stop := startNativeClient(t)
+	defer stop()
+
+	addr, err := btcutil.DecodeAddress(fakeAddress, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.ListenAddresses(addr)
+
+	fake.Lock()
+	fake.conn.Close()
+	fake.Unlock()
+
+	select {
+	case disconnectedAt := <-client.ReconnectChannel():
+		if time.Since(disconnectedAt) > 5*time.Second {
+			t.Errorf("unexpected disconnection time %s", disconnectedAt)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reconnect")
+	}
+	if subscribed := fake.subscribedAddresses(); len(subscribed) != 1 || subscribed[0] != fakeAddress {
+		t.Errorf("expected addresses to be subscribed again, got %v", subscribed)
+	}
+}
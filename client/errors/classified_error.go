@@ -0,0 +1,131 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+// Kind classifies an error by its cause so callers can react to it and explain
+// it to the user without parsing the message
+type Kind int
+
+const (
+	// KindUnknown is the kind of any error which has not been classified
+	KindUnknown Kind = iota
+	// KindInsufficientFunds indicates the wallet cannot fund the transaction
+	KindInsufficientFunds
+	// KindDust indicates an output is too small to be relayed
+	KindDust
+	// KindBackendUnavailable indicates no wallet server could answer the request
+	KindBackendUnavailable
+	// KindInvalidAddress indicates an address could not be decoded for the coin
+	KindInvalidAddress
+	// KindFeeTooHigh indicates the transaction's fee exceeds what is allowed
+	KindFeeTooHigh
+	// KindRejected indicates the network refused to accept a transaction into
+	// the mempool. The reason given by the server is kept with the error.
+	KindRejected
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindInsufficientFunds:
+		return "insufficient funds"
+	case KindDust:
+		return "dust amount"
+	case KindBackendUnavailable:
+		return "backend unavailable"
+	case KindInvalidAddress:
+		return "invalid address"
+	case KindFeeTooHigh:
+		return "fee too high"
+	case KindRejected:
+		return "rejected by mempool"
+	default:
+		return "unknown error"
+	}
+}
+
+// ClassifiedError is a wrappedError which carries the Kind of the error it
+// wraps along with an optional reason reported by the server
+type ClassifiedError interface {
+	wrappedError
+	Kind() Kind
+	Reason() string
+}
+
+// ClassifiedErrorInstance is a simple type which implements the
+// ClassifiedError interface
+type ClassifiedErrorInstance struct {
+	err    error
+	kind   Kind
+	reason string
+}
+
+// NewClassifiedError is a helper that produces a ClassifiedError
+func NewClassifiedError(kind Kind, errReason string) ClassifiedError {
+	return ClassifiedErrorInstance{err: errors.New(errReason), kind: kind}
+}
+
+// NewClassifiedErrorf is a helper that produces a ClassifiedError
+func NewClassifiedErrorf(kind Kind, format string, args ...interface{}) ClassifiedError {
+	return ClassifiedErrorInstance{err: fmt.Errorf(format, args...), kind: kind}
+}
+
+// Classify wraps an existing error into a ClassifiedError of the given kind.
+// Classify is composable with other wrappedError types.
+func Classify(kind Kind, err error) ClassifiedError {
+	return ClassifiedErrorInstance{err: err, kind: kind}
+}
+
+// ClassifyWithReason wraps an existing error into a ClassifiedError which
+// also records the reason the server gave for the failure
+func ClassifyWithReason(kind Kind, reason string, err error) ClassifiedError {
+	return ClassifiedErrorInstance{err: err, kind: kind, reason: reason}
+}
+
+// Error returns the error message
+func (e ClassifiedErrorInstance) Error() string { return e.err.Error() }
+
+func (e ClassifiedErrorInstance) internalError() error { return e.err }
+
+// Kind returns the classification of the error
+func (e ClassifiedErrorInstance) Kind() Kind { return e.kind }
+
+// Reason returns the reason given by the server, if any
+func (e ClassifiedErrorInstance) Reason() string { return e.reason }
+
+// KindOf returns the outermost classification of err. The sentinel errors of
+// wallet-interface are recognized so errors returned by the coin wallets need
+// not be wrapped. Any other error is KindUnknown.
+func KindOf(err error) Kind {
+	switch err {
+	case nil:
+		return KindUnknown
+	case wallet.ErrInsufficientFunds:
+		return KindInsufficientFunds
+	case wallet.ErrorDustAmount:
+		return KindDust
+	}
+	if cErr, ok := err.(ClassifiedError); ok && cErr.Kind() != KindUnknown {
+		return cErr.Kind()
+	}
+	if wErr, ok := err.(wrappedError); ok {
+		return KindOf(wErr.internalError())
+	}
+	return KindUnknown
+}
+
+// ReasonOf returns the outermost reason recorded for err or an empty string
+// if none was recorded
+func ReasonOf(err error) string {
+	if cErr, ok := err.(ClassifiedError); ok && cErr.Reason() != "" {
+		return cErr.Reason()
+	}
+	if wErr, ok := err.(wrappedError); ok {
+		return ReasonOf(wErr.internalError())
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package errors_test
+
+import (
+	"errors"
+	"testing"
+
+	clientErrs "github.com/OpenBazaar/multiwallet/client/errors"
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+func TestKindOf(t *testing.T) {
+	var (
+		plain      = errors.New("plain error")
+		rejected   = clientErrs.ClassifyWithReason(clientErrs.KindRejected, "txn-mempool-conflict", plain)
+		retryable  = clientErrs.MakeRetryable(clientErrs.Classify(clientErrs.KindBackendUnavailable, plain))
+		overridden = clientErrs.Classify(clientErrs.KindFeeTooHigh, clientErrs.ClassifyWithReason(clientErrs.KindRejected, "absurdly-high-fee", plain))
+	)
+
+	for _, test := range []struct {
+		err    error
+		kind   clientErrs.Kind
+		reason string
+	}{
+		{nil, clientErrs.KindUnknown, ""},
+		{plain, clientErrs.KindUnknown, ""},
+		{wallet.ErrInsufficientFunds, clientErrs.KindInsufficientFunds, ""},
+		{wallet.ErrorDustAmount, clientErrs.KindDust, ""},
+		{rejected, clientErrs.KindRejected, "txn-mempool-conflict"},
+		{retryable, clientErrs.KindBackendUnavailable, ""},
+		{overridden, clientErrs.KindFeeTooHigh, "absurdly-high-fee"},
+		{clientErrs.MakeFatal(clientErrs.Classify(clientErrs.KindUnknown, wallet.ErrInsufficientFunds)), clientErrs.KindInsufficientFunds, ""},
+	} {
+		if kind := clientErrs.KindOf(test.err); kind != test.kind {
+			t.Errorf("expected %v to be %s, got %s", test.err, test.kind, kind)
+		}
+		if reason := clientErrs.ReasonOf(test.err); reason != test.reason {
+			t.Errorf("expected %v to have reason %q, got %q", test.err, test.reason, reason)
+		}
+	}
+	if !clientErrs.IsRetryable(clientErrs.Classify(clientErrs.KindBackendUnavailable, clientErrs.MakeRetryable(plain))) {
+		t.Error("expected classification to preserve retryability")
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/OpenBazaar/multiwallet/client/blockbook"
 	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
@@ -22,11 +24,15 @@ var Log = logging.MustGetLogger("pool")
 // server failure, rotate servers, and retry API requests.
 type ClientPool struct {
 	blockChan        chan model.Block
+	broadcastFanout  bool
 	cancelListenChan context.CancelFunc
+	cancelProbes     context.CancelFunc
+	disconnectedAt   time.Time
 	listenAddrs      []btcutil.Address
 	listenAddrsLock  sync.Mutex
 	poolManager      *rotationManager
 	proxyDialer      proxy.Dialer
+	reconnectChan    chan time.Time
 	txChan           chan model.Transaction
 	unblockStart     chan struct{}
 
@@ -57,11 +63,12 @@ func NewClientPool(endpoints []string, proxyDialer proxy.Dialer) (*ClientPool, e
 
 	var (
 		pool = &ClientPool{
-			blockChan:    make(chan model.Block),
-			poolManager:  &rotationManager{},
-			listenAddrs:  make([]btcutil.Address, 0),
-			txChan:       make(chan model.Transaction),
-			unblockStart: make(chan struct{}, 1),
+			blockChan:     make(chan model.Block),
+			poolManager:   &rotationManager{},
+			listenAddrs:   make([]btcutil.Address, 0),
+			reconnectChan: make(chan time.Time, 1),
+			txChan:        make(chan model.Transaction),
+			unblockStart:  make(chan struct{}, 1),
 		}
 		manager, err = newRotationManager(endpoints, proxyDialer)
 	)
@@ -75,10 +82,20 @@ func NewClientPool(endpoints []string, proxyDialer proxy.Dialer) (*ClientPool, e
 // Start will attempt to connect to the first available server. If it fails to
 // connect it will rotate through the servers to try to find one that works.
 func (p *ClientPool) Start() error {
+	var ctx context.Context
+	ctx, p.cancelProbes = context.WithCancel(context.Background())
+	go p.poolManager.RunProbes(ctx)
 	go p.run()
 	return nil
 }
 
+// SetRequestLimits applies the request limits to every client in the pool
+func (p *ClientPool) SetRequestLimits(limits blockbook.RequestLimits) {
+	for _, c := range p.poolManager.clientCache {
+		c.SetRequestLimits(limits)
+	}
+}
+
 func (p *ClientPool) Clients() []*blockbook.BlockBookClient {
 	var clients []*blockbook.BlockBookClient
 	for _, c := range p.poolManager.clientCache {
@@ -103,7 +120,8 @@ func (p *ClientPool) runLoop() error {
 	var closeChan = make(chan error, 0)
 	defer close(closeChan)
 	if err := p.poolManager.StartCurrent(closeChan); err != nil {
-		Log.Errorf("error starting %s: %s", p.poolManager.currentTarget, err.Error())
+		Log.Errorf("error starting %s: %s", p.poolManager.current(), err.Error())
+		p.poolManager.recordResult(p.poolManager.current(), 0, err)
 		p.poolManager.FailCurrent()
 		p.poolManager.CloseCurrent()
 		return err
@@ -113,7 +131,13 @@ func (p *ClientPool) runLoop() error {
 	go p.listenChans(ctx)
 	defer p.stopWebsocketListening()
 	p.replayListenAddresses()
-	if err := <-closeChan; err != nil {
+	if !p.disconnectedAt.IsZero() {
+		p.notifyReconnect(p.disconnectedAt)
+	}
+	err := <-closeChan
+	p.disconnectedAt = time.Now()
+	if err != nil {
+		p.poolManager.recordResult(p.poolManager.current(), 0, err)
 		p.poolManager.FailCurrent()
 		p.poolManager.CloseCurrent()
 	}
@@ -123,6 +147,10 @@ func (p *ClientPool) runLoop() error {
 // Close proxies the same request to the active client
 func (p *ClientPool) Close() {
 	p.stopWebsocketListening()
+	if p.cancelProbes != nil {
+		p.cancelProbes()
+		p.cancelProbes = nil
+	}
 	p.unblockStart <- struct{}{}
 	p.poolManager.CloseCurrent()
 }
@@ -132,6 +160,11 @@ func (p *ClientPool) PoolManager() *rotationManager {
 	return p.poolManager
 }
 
+// EndpointHealth reports the observed health of each endpoint in the pool
+func (p *ClientPool) EndpointHealth() []EndpointHealth {
+	return p.poolManager.EndpointHealth()
+}
+
 // FailAndCloseCurrentClient cleans up the active client's connections, and
 // signals to the rotation manager that it is unhealthy. The internal runLoop
 // will detect the client's closing and attempt to start the next available.
@@ -151,18 +184,23 @@ func (p *ClientPool) stopWebsocketListening() {
 // listenChans proxies the block and tx chans from the client to the ClientPool's channels
 func (p *ClientPool) listenChans(ctx context.Context) {
 	var (
-		client    = p.poolManager.AcquireCurrent()
-		blockChan = client.BlockChannel()
-		txChan    = client.TxChannel()
+		client        = p.poolManager.AcquireCurrent()
+		target        = p.poolManager.current()
+		blockChan     = client.BlockChannel()
+		txChan        = client.TxChannel()
+		reconnectChan = client.ReconnectChannel()
 	)
 	defer p.poolManager.ReleaseCurrent()
 	go func() {
 		for {
 			select {
 			case block := <-blockChan:
+				p.poolManager.recordTip(target, block.Height)
 				p.blockChan <- block
 			case tx := <-txChan:
 				p.txChan <- tx
+			case disconnectedAt := <-reconnectChan:
+				p.notifyReconnect(disconnectedAt)
 			case <-ctx.Done():
 				return
 			}
@@ -176,12 +214,18 @@ func (p *ClientPool) listenChans(ctx context.Context) {
 // they are Retryable and/or Fatal as defined by client/errors. These error properties
 // can be composed like client/errors.MakeFatal(client/errors.MakeRetryable(err)).
 // This approach should allow individual requests to define how the resulting error
-// should be handled upstream of the request.
+// should be handled upstream of the request. The latency and outcome of each attempt
+// are recorded against the serving endpoint to inform future rotations.
 func (p *ClientPool) executeRequest(queryFunc func(c *blockbook.BlockBookClient) error) error {
 	var err error
 	for e := p.newMaximumTryEnumerator(); e.next(); {
-		var client = p.poolManager.AcquireCurrentWhenReady()
-		if err = queryFunc(client); err != nil {
+		var (
+			client = p.poolManager.AcquireCurrentWhenReady()
+			start  = time.Now()
+		)
+		err = queryFunc(client)
+		p.poolManager.RecordCurrentResult(time.Since(start), err)
+		if err != nil {
 			p.poolManager.ReleaseCurrent()
 			if clientErr.IsFatal(err) || e.isFinal() {
 				Log.Warningf("rotating server due to fatal or exhausted attempts")
@@ -201,7 +245,7 @@ func (p *ClientPool) executeRequest(queryFunc func(c *blockbook.BlockBookClient)
 		}
 	}
 	Log.Errorf("exhausted retry attempts, last error: %s", err.Error())
-	return fmt.Errorf("request failed: %s", err.Error())
+	return clientErr.Classify(clientErr.KindBackendUnavailable, fmt.Errorf("request failed: %s", err.Error()))
 }
 
 // BlockNofity proxies the active client's block channel
@@ -209,11 +253,35 @@ func (p *ClientPool) BlockNotify() <-chan model.Block {
 	return p.blockChan
 }
 
-// Broadcast proxies the same request to the active client
+// SetBroadcastFanout sets whether transactions are broadcast to every healthy endpoint rather
+// than only the active one, so a transaction reaches more than one node's mempool. It should be
+// called before the pool is in use.
+func (p *ClientPool) SetBroadcastFanout(enabled bool) {
+	p.broadcastFanout = enabled
+}
+
+// Broadcast sends the transaction through the active client and, if fan out is enabled, every
+// other healthy endpoint. The broadcast succeeds if any endpoint accepts the transaction. When
+// every endpoint fails the active client's error is returned.
 func (p *ClientPool) Broadcast(tx []byte) (string, error) {
 	var (
 		txid      string
+		accepted  = make(chan string, len(p.poolManager.clientCache))
+		wg        sync.WaitGroup
+		fannedOut bool
 		queryFunc = func(c *blockbook.BlockBookClient) error {
+			if p.broadcastFanout && !fannedOut {
+				fannedOut = true
+				for target, alternate := range p.poolManager.HealthyAlternates(c) {
+					wg.Add(1)
+					go func(target RotationTarget, alternate *blockbook.BlockBookClient) {
+						defer wg.Done()
+						if r, ok := p.broadcastAlternate(target, alternate, tx); ok {
+							accepted <- r
+						}
+					}(target, alternate)
+				}
+			}
 			Log.Debugf("(%s) broadcasting transaction", c.EndpointURL().String())
 			r, err := c.Broadcast(tx)
 			if err != nil {
@@ -225,15 +293,49 @@ func (p *ClientPool) Broadcast(tx []byte) (string, error) {
 	)
 
 	err := p.executeRequest(queryFunc)
-	return txid, err
+	wg.Wait()
+	close(accepted)
+	if err == nil {
+		return txid, nil
+	}
+	for r := range accepted {
+		Log.Infof("broadcast accepted by an alternate endpoint after the active endpoint failed: %s", err.Error())
+		return r, nil
+	}
+	return "", err
 }
 
-// EstimateFee proxies the same request to the active client
+// broadcastAlternate sends the transaction through an endpoint other than the active one and
+// records the outcome against that endpoint's health. It reports whether the endpoint accepted
+// the transaction.
+func (p *ClientPool) broadcastAlternate(target RotationTarget, c *blockbook.BlockBookClient, tx []byte) (string, bool) {
+	var start = time.Now()
+	txid, err := c.Broadcast(tx)
+	if err != nil && clientErr.ReasonOf(err) == "" {
+		p.poolManager.recordResult(target, time.Since(start), err)
+		Log.Warningf("(%s) broadcast failed: %s", c.EndpointURL().String(), err.Error())
+		return "", false
+	}
+	// a rejection is an answer from a working server so it does not count against its health
+	p.poolManager.recordResult(target, time.Since(start), nil)
+	if err != nil {
+		Log.Warningf("(%s) rejected transaction: %s", c.EndpointURL().String(), clientErr.ReasonOf(err))
+		return "", false
+	}
+	return txid, true
+}
+
+// EstimateFee asks the active client and every other healthy endpoint for the fee in satoshis per
+// kilobyte for a transaction to confirm within nBlocks, and returns the median of the estimates so
+// one server with an unusual mempool does not set the fee. The active client's error is returned
+// when no endpoint has an estimate.
 func (p *ClientPool) EstimateFee(nBlocks int) (int, error) {
 	var (
 		fee       int
+		current   *blockbook.BlockBookClient
 		queryFunc = func(c *blockbook.BlockBookClient) error {
 			Log.Debugf("(%s) requesting fee estimate", c.EndpointURL().String())
+			current = c
 			r, err := c.EstimateFee(nBlocks)
 			if err != nil {
 				return clientErr.MakeRetryable(err)
@@ -244,7 +346,43 @@ func (p *ClientPool) EstimateFee(nBlocks int) (int, error) {
 	)
 
 	err := p.executeRequest(queryFunc)
-	return fee, err
+	var (
+		fees    []int
+		feesMtx sync.Mutex
+		wg      sync.WaitGroup
+	)
+	// Servers without enough data to estimate answer with a negative fee
+	if err == nil && fee > 0 {
+		fees = append(fees, fee)
+	}
+	for target, alternate := range p.poolManager.HealthyAlternates(current) {
+		wg.Add(1)
+		go func(target RotationTarget, c *blockbook.BlockBookClient) {
+			defer wg.Done()
+			var start = time.Now()
+			r, altErr := c.EstimateFee(nBlocks)
+			p.poolManager.recordResult(target, time.Since(start), altErr)
+			if altErr != nil || r <= 0 {
+				return
+			}
+			feesMtx.Lock()
+			fees = append(fees, r)
+			feesMtx.Unlock()
+		}(target, alternate)
+	}
+	wg.Wait()
+	if len(fees) == 0 {
+		if err == nil {
+			err = errors.New("no fee estimate available")
+		}
+		return 0, err
+	}
+	sort.Ints(fees)
+	mid := len(fees) / 2
+	if len(fees)%2 == 0 {
+		return (fees[mid-1] + fees[mid]) / 2, nil
+	}
+	return fees[mid], nil
 }
 
 // GetBestBlock proxies the same request to the active client
@@ -257,6 +395,7 @@ func (p *ClientPool) GetBestBlock() (*model.Block, error) {
 			if err != nil {
 				return clientErr.MakeRetryable(err)
 			}
+			p.poolManager.RecordCurrentTip(r.Height)
 			block = r
 			return err
 		}
@@ -266,6 +405,25 @@ func (p *ClientPool) GetBestBlock() (*model.Block, error) {
 	return block, err
 }
 
+// GetBlockHash proxies the same request to the active client
+func (p *ClientPool) GetBlockHash(height int) (string, error) {
+	var (
+		hash      string
+		queryFunc = func(c *blockbook.BlockBookClient) error {
+			Log.Debugf("(%s) request block hash at height %d", c.EndpointURL().String(), height)
+			r, err := c.GetBlockHash(height)
+			if err != nil {
+				return clientErr.MakeRetryable(err)
+			}
+			hash = r
+			return nil
+		}
+	)
+
+	err := p.executeRequest(queryFunc)
+	return hash, err
+}
+
 // GetInfo proxies the same request to the active client
 func (p *ClientPool) GetInfo() (*model.Info, error) {
 	var (
@@ -327,6 +485,25 @@ func (p *ClientPool) GetTransactions(addrs []btcutil.Address) ([]model.Transacti
 	return txs, err
 }
 
+// GetTransactionsSince proxies the same request to the active client
+func (p *ClientPool) GetTransactionsSince(addrs []btcutil.Address, fromHeight int) ([]model.Transaction, error) {
+	var (
+		txs       []model.Transaction
+		queryFunc = func(c *blockbook.BlockBookClient) error {
+			Log.Debugf("(%s) request transactions since height %d for (%d) addrs", c.EndpointURL().String(), fromHeight, len(addrs))
+			r, err := c.GetTransactionsSince(addrs, fromHeight)
+			if err != nil {
+				return err
+			}
+			txs = r
+			return nil
+		}
+	)
+
+	err := p.executeRequest(queryFunc)
+	return txs, err
+}
+
 // GetTransaction proxies the same request to the active client
 func (p *ClientPool) GetTransaction(txid string) (*model.Transaction, error) {
 	var (
@@ -346,6 +523,44 @@ func (p *ClientPool) GetTransaction(txid string) (*model.Transaction, error) {
 	return tx, err
 }
 
+// FindTransaction requests the transaction from the active client and, if that server does not
+// know of it, from every other healthy endpoint. The active client's error is returned when no
+// endpoint has the transaction.
+func (p *ClientPool) FindTransaction(txid string) (*model.Transaction, error) {
+	var (
+		tx        *model.Transaction
+		current   *blockbook.BlockBookClient
+		queryFunc = func(c *blockbook.BlockBookClient) error {
+			Log.Debugf("(%s) request transaction data, txid: %s", c.EndpointURL().String(), txid)
+			current = c
+			r, err := c.GetTransaction(txid)
+			if err != nil {
+				return err
+			}
+			tx = r
+			return nil
+		}
+	)
+	err := p.executeRequest(queryFunc)
+	if err == nil || clientErr.KindOf(err) == clientErr.KindBackendUnavailable {
+		return tx, err
+	}
+	for target, c := range p.poolManager.HealthyAlternates(current) {
+		var start = time.Now()
+		r, altErr := c.GetTransaction(txid)
+		if altErr != nil && clientErr.ReasonOf(altErr) == "" {
+			p.poolManager.recordResult(target, time.Since(start), altErr)
+			continue
+		}
+		p.poolManager.recordResult(target, time.Since(start), nil)
+		if altErr == nil {
+			Log.Debugf("(%s) found transaction unknown to the active endpoint, txid: %s", c.EndpointURL().String(), txid)
+			return r, nil
+		}
+	}
+	return nil, err
+}
+
 // GetUtxos proxies the same request to the active client
 func (p *ClientPool) GetUtxos(addrs []btcutil.Address) ([]model.Utxo, error) {
 	var (
@@ -370,6 +585,60 @@ func (p *ClientPool) GetUtxos(addrs []btcutil.Address) ([]model.Utxo, error) {
 	return utxos, err
 }
 
+// GetAccountAddresses proxies the same request to the active client
+func (p *ClientPool) GetAccountAddresses(xpub string) ([]string, error) {
+	var (
+		addrs     []string
+		queryFunc = func(c *blockbook.BlockBookClient) error {
+			Log.Debugf("(%s) request addresses for account", c.EndpointURL().String())
+			r, err := c.GetAccountAddresses(xpub)
+			if err != nil {
+				return err
+			}
+			addrs = r
+			return nil
+		}
+	)
+	err := p.executeRequest(queryFunc)
+	return addrs, err
+}
+
+// GetAccountTransactions proxies the same request to the active client
+func (p *ClientPool) GetAccountTransactions(xpub string, fromHeight int) ([]model.Transaction, error) {
+	var (
+		txs       []model.Transaction
+		queryFunc = func(c *blockbook.BlockBookClient) error {
+			Log.Debugf("(%s) request transactions for account", c.EndpointURL().String())
+			r, err := c.GetAccountTransactions(xpub, fromHeight)
+			if err != nil {
+				return err
+			}
+			txs = r
+			return nil
+		}
+	)
+	err := p.executeRequest(queryFunc)
+	return txs, err
+}
+
+// GetAccountUtxos proxies the same request to the active client
+func (p *ClientPool) GetAccountUtxos(xpub string) ([]model.Utxo, error) {
+	var (
+		utxos     []model.Utxo
+		queryFunc = func(c *blockbook.BlockBookClient) error {
+			Log.Debugf("(%s) request utxos for account", c.EndpointURL().String())
+			r, err := c.GetAccountUtxos(xpub)
+			if err != nil {
+				return err
+			}
+			utxos = r
+			return nil
+		}
+	)
+	err := p.executeRequest(queryFunc)
+	return utxos, err
+}
+
 // ListenAddresses proxies the same request to the active client
 func (p *ClientPool) ListenAddresses(addrs ...btcutil.Address) {
 	p.listenAddrsLock.Lock()
@@ -389,5 +658,18 @@ func (p *ClientPool) replayListenAddresses() {
 	client.ListenAddresses(p.listenAddrs...)
 }
 
+// ReconnectNotify receives the time notifications stopped each time they resume, either
+// because the active client reconnected its websocket or the pool moved to another server
+func (p *ClientPool) ReconnectNotify() <-chan time.Time { return p.reconnectChan }
+
+// notifyReconnect reports a reconnection unless an earlier one is still waiting to be
+// received, in which case the earlier disconnection time already covers the gap
+func (p *ClientPool) notifyReconnect(disconnectedAt time.Time) {
+	select {
+	case p.reconnectChan <- disconnectedAt:
+	default:
+	}
+}
+
 // TransactionNotify proxies the active client's tx channel
 func (p *ClientPool) TransactionNotify() <-chan model.Transaction { return p.txChan }
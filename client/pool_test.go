@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/OpenBazaar/multiwallet/client"
+	"github.com/OpenBazaar/multiwallet/client/blockbook"
 	"github.com/OpenBazaar/multiwallet/model"
 	"github.com/OpenBazaar/multiwallet/model/mock"
 	"github.com/OpenBazaar/multiwallet/test/factory"
@@ -252,3 +253,238 @@ func TestTransactionNotifyWorksAfterRotation(t *testing.T) {
 	}
 	ticker.Stop()
 }
+
+func TestGetAccountTransactionsReadsXpubPages(t *testing.T) {
+	var (
+		endpoint   = "http://localhost:8332"
+		p, cleanup = mustPrepareClientPool([]string{endpoint})
+		xpub       = "xpub6CUGRUonZSQ4TWtTMmzXdrXDtypWKiKrhko4egpiMZbpiaQL2jkwSB1icqYh2cfDfVxdx4df189oLKnC5fSwqPfgyP3hooxujYzAu3fDVmz"
+		pages      = map[string]string{
+			"1": `{"page":1,"totalPages":2,"transactions":[{"txid":"1be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428","version":1,"blockHeight":500,"confirmations":3,"blockTime":1550000000,"hex":"00","vin":[{"txid":"2be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428","vout":1,"n":0,"addresses":["bitcoincash:qp4jq2ly8yuq7mrrrpjuypx63y0xjpsvmqn8nmxckf"],"value":"150000000"}],"vout":[{"n":0,"hex":"76a914","value":"100000000","addresses":["1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"]}]}]}`,
+			"2": `{"page":2,"totalPages":2,"transactions":[{"txid":"3be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428","version":1,"hex":"00","vin":[{"n":0,"coinbase":"03a0bb0d","isAddress":false}],"vout":[{"n":0,"hex":"76a914","value":"625000000","addresses":["1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"]}]}]}`,
+		}
+	)
+	defer cleanup()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/v2/xpub/%s", endpoint, xpub),
+		func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("details") != "txs" {
+				t.Errorf("expected txs details to be requested, got %s", req.URL.RawQuery)
+			}
+			return httpmock.NewStringResponse(http.StatusOK, pages[req.URL.Query().Get("page")]), nil
+		},
+	)
+
+	txs, err := p.GetAccountTransactions(xpub, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 transactions across both pages, got %d", len(txs))
+	}
+	var tx = txs[0]
+	if tx.BlockHeight != 500 || tx.Confirmations != 3 {
+		t.Errorf("unexpected block info: height %d confirmations %d", tx.BlockHeight, tx.Confirmations)
+	}
+	if tx.Inputs[0].Satoshis != 150000000 || tx.Inputs[0].Value != 1.5 {
+		t.Errorf("unexpected input value: %d sats, %f", tx.Inputs[0].Satoshis, tx.Inputs[0].Value)
+	}
+	if tx.Inputs[0].Addr != "qp4jq2ly8yuq7mrrrpjuypx63y0xjpsvmqn8nmxckf" {
+		t.Errorf("expected cashaddr prefix to be trimmed, got %s", tx.Inputs[0].Addr)
+	}
+	if tx.Outputs[0].Value != 1 || tx.Outputs[0].ScriptPubKey.Hex != "76a914" {
+		t.Errorf("unexpected output: %+v", tx.Outputs[0])
+	}
+	// Coinbase inputs have no value
+	if coinbase := txs[1]; len(coinbase.Inputs) != 1 || coinbase.Inputs[0].Satoshis != 0 || coinbase.Outputs[0].Value != 6.25 {
+		t.Errorf("unexpected coinbase tx: %+v", coinbase)
+	}
+}
+
+func TestAccountQueriesReportUnsupportedServers(t *testing.T) {
+	var (
+		endpoint   = "http://localhost:8332"
+		p, cleanup = mustPrepareClientPool([]string{endpoint})
+		xpub       = "xpub6CUGRUonZSQ4TWtTMmzXdrXDtypWKiKrhko4egpiMZbpiaQL2jkwSB1icqYh2cfDfVxdx4df189oLKnC5fSwqPfgyP3hooxujYzAu3fDVmz"
+		requests   int
+	)
+	defer cleanup()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/v2/xpub/%s", endpoint, xpub),
+		func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(http.StatusNotFound, ""), nil
+		},
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.GetAccountAddresses(xpub); err != blockbook.ErrXpubUnsupported {
+			t.Errorf("expected ErrXpubUnsupported, got %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected unsupported server to be remembered after 1 request, got %d", requests)
+	}
+}
+
+func TestAccountQueriesKeepServersWhichRejectAnXpub(t *testing.T) {
+	var (
+		endpoint   = "http://localhost:8332"
+		p, cleanup = mustPrepareClientPool([]string{endpoint})
+		xpub       = "xpub6CUGRUonZSQ4TWtTMmzXdrXDtypWKiKrhko4egpiMZbpiaQL2jkwSB1icqYh2cfDfVxdx4df189oLKnC5fSwqPfgyP3hooxujYzAu3fDVmz"
+		response   = `{"error":"Invalid xpub"}`
+		requests   int
+	)
+	defer cleanup()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/v2/xpub/%s", endpoint, xpub),
+		func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(http.StatusBadRequest, response), nil
+		},
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.GetAccountAddresses(xpub); err == nil || err == blockbook.ErrXpubUnsupported {
+			t.Errorf("expected the rejection of the xpub, got %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected a rejected xpub not to mark the server unsupported, got %d requests", requests)
+	}
+
+	// A server which answers that the query is not supported is remembered
+	response = `{"error":"xpub queries not supported"}`
+	for i := 0; i < 2; i++ {
+		if _, err := p.GetAccountAddresses(xpub); err != blockbook.ErrXpubUnsupported {
+			t.Errorf("expected ErrXpubUnsupported, got %v", err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("expected unsupported server to be remembered after 1 request, got %d", requests)
+	}
+}
+
+func TestBroadcastFansOutToHealthyEndpoints(t *testing.T) {
+	var (
+		endpointOne = "http://localhost:8332"
+		endpointTwo = "http://localhost:8336"
+		p, cleanup  = mustPrepareClientPool([]string{endpointOne, endpointTwo})
+		requests    = make(chan string, 4)
+	)
+	defer cleanup()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/sendtx/00", endpointOne),
+		func(req *http.Request) (*http.Response, error) {
+			requests <- endpointOne
+			return httpmock.NewStringResponse(http.StatusBadRequest, `{"error":"-26: txn-mempool-conflict"}`), nil
+		},
+	)
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/sendtx/00", endpointTwo),
+		func(req *http.Request) (*http.Response, error) {
+			requests <- endpointTwo
+			return httpmock.NewStringResponse(http.StatusOK, `{"result":"abc"}`), nil
+		},
+	)
+
+	p.SetBroadcastFanout(true)
+	txid, err := p.Broadcast([]byte{0x00})
+	if err != nil {
+		t.Fatalf("expected broadcast accepted by one endpoint to succeed, got %s", err)
+	}
+	if txid != "abc" {
+		t.Errorf("expected txid from the accepting endpoint, got %s", txid)
+	}
+	if len(requests) != 2 {
+		t.Errorf("expected both endpoints to receive the transaction, got %d requests", len(requests))
+	}
+}
+
+func TestBroadcastWithoutFanoutUsesActiveEndpoint(t *testing.T) {
+	var (
+		endpointOne = "http://localhost:8332"
+		endpointTwo = "http://localhost:8336"
+		p, cleanup  = mustPrepareClientPool([]string{endpointOne, endpointTwo})
+		requests    = make(chan string, 4)
+		accept      = func(req *http.Request) (*http.Response, error) {
+			requests <- req.URL.Host
+			return httpmock.NewStringResponse(http.StatusOK, `{"result":"abc"}`), nil
+		}
+	)
+	defer cleanup()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/sendtx/00", endpointOne), accept)
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/sendtx/00", endpointTwo), accept)
+
+	if _, err := p.Broadcast([]byte{0x00}); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("expected only the active endpoint to receive the transaction, got %d requests", len(requests))
+	}
+}
+
+func TestFindTransactionAsksOtherEndpoints(t *testing.T) {
+	var (
+		endpointOne = "http://localhost:8332"
+		endpointTwo = "http://localhost:8336"
+		p, cleanup  = mustPrepareClientPool([]string{endpointOne, endpointTwo})
+		expectedTx  = factory.NewTransaction()
+		txid        = "1be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428"
+	)
+	defer cleanup()
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/tx/%s", endpointOne, txid),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(http.StatusBadRequest, `{"error":"Transaction not found"}`), nil
+		},
+	)
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/tx/%s", endpointTwo, txid),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusOK, expectedTx)
+		},
+	)
+
+	if _, err := p.FindTransaction(txid); err != nil {
+		t.Errorf("expected the transaction to be found on the other endpoint, got %s", err)
+	}
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/tx/%s", endpointTwo, txid),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(http.StatusBadRequest, `{"error":"Transaction not found"}`), nil
+		},
+	)
+	if _, err := p.FindTransaction(txid); err == nil {
+		t.Error("expected an error when no endpoint knows the transaction")
+	}
+}
+
+func TestEstimateFeeTakesMedianOfEndpoints(t *testing.T) {
+	var (
+		endpoints  = []string{"http://localhost:8332", "http://localhost:8336", "http://localhost:8340"}
+		p, cleanup = mustPrepareClientPool(endpoints)
+	)
+	defer cleanup()
+
+	for i, fee := range []string{"0.0001", "0.0002", "0.01"} {
+		httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/utils/estimatefee", endpoints[i]),
+			httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`{"6": %s}`, fee)))
+	}
+
+	fee, err := p.EstimateFee(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 20000 {
+		t.Errorf("expected the median estimate of 20000 satoshis per kilobyte, got %d", fee)
+	}
+
+	// An endpoint without an estimate is left out of the median
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("%s/utils/estimatefee", endpoints[2]),
+		httpmock.NewStringResponder(http.StatusOK, `{"6": -1}`))
+	if fee, err = p.EstimateFee(6); err != nil {
+		t.Fatal(err)
+	}
+	if fee != 15000 {
+		t.Errorf("expected the median of the two estimates of 15000 satoshis per kilobyte, got %d", fee)
+	}
+}
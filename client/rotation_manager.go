@@ -1,7 +1,9 @@
 package client
 
 import (
+	"context"
 	"errors"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -9,11 +11,33 @@ import (
 	"golang.org/x/net/proxy"
 )
 
-var maximumBackoff = 60 * time.Second
+var (
+	maximumBackoff = 60 * time.Second
+
+	// probeInterval is how long an endpoint may sit idle before it is probed
+	// for its latency and chain tip
+	probeInterval = 1 * time.Minute
+	// latencyReference is the latency at which an endpoint's latency score is
+	// halved
+	latencyReference = 250 * time.Millisecond
+	// scoreSmoothing is the weight given to each new observation when updating
+	// the latency and error rate moving averages
+	scoreSmoothing = 0.2
+	// minimumScore keeps every healthy endpoint selectable, however poorly it
+	// has performed
+	minimumScore = 0.01
+)
 
 type healthState struct {
 	lastFailedAt    time.Time
 	backoffDuration time.Duration
+
+	latency    time.Duration
+	errorRate  float64
+	samples    int
+	tipHeight  int
+	lastError  string
+	lastSeenAt time.Time
 }
 
 func (h *healthState) markUnhealthy() {
@@ -41,6 +65,62 @@ func (h *healthState) nextAvailable() time.Time {
 	return h.lastFailedAt.Add(h.backoffDuration)
 }
 
+func (h *healthState) recordSuccess(latency time.Duration) {
+	if h.samples == 0 {
+		h.latency = latency
+	} else {
+		h.latency = time.Duration((1-scoreSmoothing)*float64(h.latency) + scoreSmoothing*float64(latency))
+	}
+	h.errorRate = (1 - scoreSmoothing) * h.errorRate
+	h.samples++
+	h.lastSeenAt = time.Now()
+}
+
+func (h *healthState) recordFailure(err error) {
+	h.errorRate = (1-scoreSmoothing)*h.errorRate + scoreSmoothing
+	h.samples++
+	h.lastSeenAt = time.Now()
+	if err != nil {
+		h.lastError = err.Error()
+	}
+}
+
+func (h *healthState) recordTip(height int) {
+	if height > h.tipHeight {
+		h.tipHeight = height
+	}
+}
+
+// tipLag returns how many blocks this endpoint trails the best known tip. An
+// endpoint which has not reported a tip yet is not considered lagging.
+func (h *healthState) tipLag(bestTip int) int {
+	if h.tipHeight == 0 || bestTip <= h.tipHeight {
+		return 0
+	}
+	return bestTip - h.tipHeight
+}
+
+// score rates the endpoint between minimumScore and 1 by combining its
+// latency, error rate and distance from the best known chain tip. Endpoints
+// without observations are scored as though they responded at the
+// latencyReference.
+func (h *healthState) score(bestTip int) float64 {
+	var latency = h.latency
+	if h.samples == 0 {
+		latency = latencyReference
+	}
+	var (
+		latencyScore = float64(latencyReference) / float64(latencyReference+latency)
+		errorScore   = 1 - h.errorRate
+		lagScore     = 1 / float64(1+h.tipLag(bestTip))
+		score        = latencyScore * errorScore * lagScore
+	)
+	if score < minimumScore {
+		return minimumScore
+	}
+	return score
+}
+
 const nilTarget = RotationTarget("")
 
 type (
@@ -50,7 +130,30 @@ type (
 		currentTarget RotationTarget
 		targetHealth  map[RotationTarget]*healthState
 		rotateLock    sync.RWMutex
+		healthLock    sync.Mutex
+		random        *rand.Rand
 		started       bool
+
+		// currentLock guards currentTarget, which is only changed while holding rotateLock, for
+		// readers which may already hold rotateLock for reading. Taking rotateLock again there
+		// could deadlock behind a waiting rotation.
+		currentLock sync.Mutex
+	}
+
+	// EndpointHealth is a snapshot of the observed health of a single endpoint
+	EndpointHealth struct {
+		Target          RotationTarget
+		Current         bool
+		Healthy         bool
+		Score           float64
+		Latency         time.Duration
+		ErrorRate       float64
+		TipHeight       int
+		TipLag          int
+		LastError       string
+		LastFailedAt    time.Time
+		NextAvailableAt time.Time
+		LastSeenAt      time.Time
 	}
 )
 
@@ -71,6 +174,7 @@ func newRotationManager(targets []string, proxyDialer proxy.Dialer) (*rotationMa
 		clientCache:   clients,
 		currentTarget: nilTarget,
 		targetHealth:  targetHealth,
+		random:        rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 	return m, nil
 }
@@ -123,7 +227,7 @@ func (r *rotationManager) CloseCurrent() {
 			r.clientCache[r.currentTarget].Close()
 		}
 		r.started = false
-		r.currentTarget = nilTarget
+		r.setCurrent(nilTarget)
 	}
 }
 
@@ -159,12 +263,79 @@ func (r *rotationManager) FailCurrent() {
 
 	hs, ok := r.targetHealth[r.currentTarget]
 	if ok {
+		r.healthLock.Lock()
 		hs.markUnhealthy()
+		r.healthLock.Unlock()
+	}
+}
+
+// RecordCurrentResult updates the current client's latency and error rate with the outcome
+// of a request. It must be called between AcquireCurrent and ReleaseCurrent so the result is
+// attributed to the client which served the request.
+func (r *rotationManager) RecordCurrentResult(latency time.Duration, err error) {
+	r.recordResult(r.current(), latency, err)
+}
+
+// RecordCurrentTip notes the chain height last reported by the current client. Like
+// RecordCurrentResult, it must be called while the current client is acquired.
+func (r *rotationManager) RecordCurrentTip(height int) {
+	r.recordTip(r.current(), height)
+}
+
+// current returns the target of the current client without taking rotateLock
+func (r *rotationManager) current() RotationTarget {
+	r.currentLock.Lock()
+	defer r.currentLock.Unlock()
+	return r.currentTarget
+}
+
+// setCurrent changes the current target. The caller must hold rotateLock.
+func (r *rotationManager) setCurrent(target RotationTarget) {
+	r.currentLock.Lock()
+	r.currentTarget = target
+	r.currentLock.Unlock()
+}
+
+func (r *rotationManager) recordResult(target RotationTarget, latency time.Duration, err error) {
+	hs, ok := r.targetHealth[target]
+	if !ok {
+		return
+	}
+	r.healthLock.Lock()
+	defer r.healthLock.Unlock()
+	if err != nil {
+		hs.recordFailure(err)
+		return
+	}
+	hs.recordSuccess(latency)
+}
+
+func (r *rotationManager) recordTip(target RotationTarget, height int) {
+	hs, ok := r.targetHealth[target]
+	if !ok {
+		return
+	}
+	r.healthLock.Lock()
+	hs.recordTip(height)
+	r.healthLock.Unlock()
+}
+
+// bestTip returns the highest chain tip reported by any endpoint. The caller
+// must hold the healthLock.
+func (r *rotationManager) bestTip() int {
+	var best int
+	for _, hs := range r.targetHealth {
+		if hs.tipHeight > best {
+			best = hs.tipHeight
+		}
 	}
+	return best
 }
 
-// SelectNext finds the next healthy and available server to activate with StartCurrent. This call will
-// block until a server is healthy and available.
+// SelectNext finds the next healthy and available server to activate with StartCurrent. Healthy
+// servers are chosen at random, weighted by their score, so faster and more reliable servers
+// which are keeping up with the chain are preferred. This call will block until a server is
+// healthy and available.
 func (r *rotationManager) SelectNext() {
 	r.lock()
 	defer r.unlock()
@@ -175,16 +346,143 @@ func (r *rotationManager) SelectNext() {
 			if time.Now().Before(nextAvailableAt) {
 				continue
 			}
-			for target, health := range r.targetHealth {
-				if health.isHealthy() {
-					r.currentTarget = target
-					return
-				}
+			if target, score, ok := r.selectWeighted(); ok {
+				Log.Infof("selected %s with score %.3f", target, score)
+				r.setCurrent(target)
+				return
+			}
+			r.healthLock.Lock()
+			for _, health := range r.targetHealth {
 				if health.nextAvailable().After(nextAvailableAt) {
 					nextAvailableAt = health.nextAvailable()
 				}
 			}
+			r.healthLock.Unlock()
+		}
+	}
+}
+
+// selectWeighted picks a healthy target at random in proportion to its score. It returns
+// false when no target is healthy.
+func (r *rotationManager) selectWeighted() (RotationTarget, float64, bool) {
+	r.healthLock.Lock()
+	defer r.healthLock.Unlock()
+
+	var (
+		bestTip    = r.bestTip()
+		candidates []RotationTarget
+		scores     []float64
+		total      float64
+	)
+	for target, health := range r.targetHealth {
+		if !health.isHealthy() {
+			continue
+		}
+		score := health.score(bestTip)
+		candidates = append(candidates, target)
+		scores = append(scores, score)
+		total += score
+	}
+	if len(candidates) == 0 {
+		return nilTarget, 0, false
+	}
+	var pick = r.random.Float64() * total
+	for i, target := range candidates {
+		if pick < scores[i] {
+			return target, scores[i], true
+		}
+		pick -= scores[i]
+	}
+	var last = len(candidates) - 1
+	return candidates[last], scores[last], true
+}
+
+// EndpointHealth returns a snapshot of the health of every endpoint managed by the pool.
+func (r *rotationManager) EndpointHealth() []EndpointHealth {
+	r.rLock()
+	var current = r.currentTarget
+	r.rUnlock()
+
+	r.healthLock.Lock()
+	defer r.healthLock.Unlock()
+
+	var (
+		bestTip = r.bestTip()
+		report  = make([]EndpointHealth, 0, len(r.targetHealth))
+	)
+	for target, health := range r.targetHealth {
+		report = append(report, EndpointHealth{
+			Target:          target,
+			Current:         target == current,
+			Healthy:         health.isHealthy(),
+			Score:           health.score(bestTip),
+			Latency:         health.latency,
+			ErrorRate:       health.errorRate,
+			TipHeight:       health.tipHeight,
+			TipLag:          health.tipLag(bestTip),
+			LastError:       health.lastError,
+			LastFailedAt:    health.lastFailedAt,
+			NextAvailableAt: health.nextAvailable(),
+			LastSeenAt:      health.lastSeenAt,
+		})
+	}
+	return report
+}
+
+// HealthyAlternates returns the clients of every healthy endpoint other than the one given
+func (r *rotationManager) HealthyAlternates(current *blockbook.BlockBookClient) map[RotationTarget]*blockbook.BlockBookClient {
+	r.healthLock.Lock()
+	defer r.healthLock.Unlock()
+	var alternates = make(map[RotationTarget]*blockbook.BlockBookClient)
+	for target, client := range r.clientCache {
+		if client != current && r.targetHealth[target].isHealthy() {
+			alternates[target] = client
+		}
+	}
+	return alternates
+}
+
+// RunProbes periodically probes endpoints which have not been used recently so their scores
+// reflect current conditions when the next rotation happens. It returns when ctx is done.
+func (r *rotationManager) RunProbes(ctx context.Context) {
+	var t = time.NewTicker(probeInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			r.probeIdleTargets()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *rotationManager) probeIdleTargets() {
+	r.rLock()
+	var current = r.currentTarget
+	r.rUnlock()
+
+	for target, client := range r.clientCache {
+		r.healthLock.Lock()
+		var idle = time.Since(r.targetHealth[target].lastSeenAt) >= probeInterval
+		r.healthLock.Unlock()
+		if !idle {
+			continue
+		}
+
+		var start = time.Now()
+		block, err := client.GetBestBlock()
+		r.recordResult(target, time.Since(start), err)
+		if err != nil {
+			Log.Debugf("(%s) probe failed: %s", target, err.Error())
+			if target != current {
+				r.healthLock.Lock()
+				r.targetHealth[target].markUnhealthy()
+				r.healthLock.Unlock()
+			}
+			continue
 		}
+		r.recordTip(target, block.Height)
 	}
 }
 
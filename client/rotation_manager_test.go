@@ -0,0 +1,158 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthStateScorePenalizesLatencyErrorsAndTipLag(t *testing.T) {
+	var (
+		baseline = &healthState{}
+		slow     = &healthState{}
+		flaky    = &healthState{}
+		lagging  = &healthState{}
+	)
+	baseline.recordSuccess(50 * time.Millisecond)
+	baseline.recordTip(100)
+	slow.recordSuccess(2 * time.Second)
+	slow.recordTip(100)
+	flaky.recordSuccess(50 * time.Millisecond)
+	flaky.recordFailure(errors.New("timeout"))
+	flaky.recordTip(100)
+	lagging.recordSuccess(50 * time.Millisecond)
+	lagging.recordTip(95)
+
+	var bestTip = 100
+	for name, h := range map[string]*healthState{"slow": slow, "flaky": flaky, "lagging": lagging} {
+		if h.score(bestTip) >= baseline.score(bestTip) {
+			t.Errorf("expected %s endpoint to score below baseline, got %f >= %f", name, h.score(bestTip), baseline.score(bestTip))
+		}
+	}
+	if lagging.tipLag(bestTip) != 5 {
+		t.Errorf("expected tip lag of 5, got %d", lagging.tipLag(bestTip))
+	}
+	if flaky.lastError != "timeout" {
+		t.Errorf("expected last error to be recorded, got %q", flaky.lastError)
+	}
+}
+
+func TestHealthStateScoreHasFloor(t *testing.T) {
+	var h = &healthState{}
+	for i := 0; i < 50; i++ {
+		h.recordFailure(errors.New("unavailable"))
+	}
+	if h.score(0) != minimumScore {
+		t.Errorf("expected score to be floored at %f, got %f", minimumScore, h.score(0))
+	}
+}
+
+func TestSelectNextPrefersHigherScoredTargets(t *testing.T) {
+	var (
+		fast   = "http://localhost:8332"
+		slow   = "http://localhost:8336"
+		r, err = newRotationManager([]string{fast, slow}, nil)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.recordResult(RotationTarget(fast), 10*time.Millisecond, nil)
+	r.recordResult(RotationTarget(slow), 10*time.Second, nil)
+
+	var selections = make(map[RotationTarget]int)
+	for i := 0; i < 200; i++ {
+		r.SelectNext()
+		selections[r.currentTarget]++
+		r.currentTarget = nilTarget
+	}
+	if selections[RotationTarget(fast)] <= selections[RotationTarget(slow)] {
+		t.Errorf("expected fast target to be selected more often, got %v", selections)
+	}
+}
+
+func TestSelectNextSkipsUnhealthyTargets(t *testing.T) {
+	var (
+		healthy   = "http://localhost:8332"
+		unhealthy = "http://localhost:8336"
+		r, err    = newRotationManager([]string{healthy, unhealthy}, nil)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.targetHealth[RotationTarget(unhealthy)].markUnhealthy()
+
+	for i := 0; i < 20; i++ {
+		r.SelectNext()
+		if r.currentTarget != RotationTarget(healthy) {
+			t.Fatalf("expected %s to be selected, got %s", healthy, r.currentTarget)
+		}
+		r.currentTarget = nilTarget
+	}
+}
+
+func TestEndpointHealthReportsEachTarget(t *testing.T) {
+	var (
+		endpointOne = "http://localhost:8332"
+		endpointTwo = "http://localhost:8336"
+		r, err      = newRotationManager([]string{endpointOne, endpointTwo}, nil)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.currentTarget = RotationTarget(endpointOne)
+	r.recordTip(RotationTarget(endpointOne), 110)
+	r.recordTip(RotationTarget(endpointTwo), 100)
+	r.recordResult(RotationTarget(endpointTwo), 0, errors.New("connection refused"))
+
+	var report = r.EndpointHealth()
+	if len(report) != 2 {
+		t.Fatalf("expected health for 2 endpoints, got %d", len(report))
+	}
+	for _, h := range report {
+		switch h.Target {
+		case RotationTarget(endpointOne):
+			if !h.Current {
+				t.Error("expected first endpoint to be reported as current")
+			}
+			if h.TipLag != 0 {
+				t.Errorf("expected no tip lag for first endpoint, got %d", h.TipLag)
+			}
+		case RotationTarget(endpointTwo):
+			if h.Current {
+				t.Error("expected second endpoint to not be reported as current")
+			}
+			if h.TipLag != 10 {
+				t.Errorf("expected tip lag of 10 for second endpoint, got %d", h.TipLag)
+			}
+			if h.LastError != "connection refused" {
+				t.Errorf("expected last error to be reported, got %q", h.LastError)
+			}
+		default:
+			t.Errorf("unexpected target in report: %s", h.Target)
+		}
+	}
+}
+
+func TestRecordCurrentResultDuringRotation(t *testing.T) {
+	var (
+		endpointOne = "http://localhost:8332"
+		endpointTwo = "http://localhost:8336"
+		r, err      = newRotationManager([]string{endpointOne, endpointTwo}, nil)
+		done        = make(chan struct{})
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			r.SelectNext()
+			r.CloseCurrent()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		r.RecordCurrentResult(time.Millisecond, nil)
+		r.RecordCurrentTip(100)
+	}
+	<-done
+}
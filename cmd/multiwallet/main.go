@@ -9,6 +9,7 @@ import (
 	"github.com/OpenBazaar/multiwallet"
 	"github.com/OpenBazaar/multiwallet/api"
 	"github.com/OpenBazaar/multiwallet/cli"
+	"github.com/OpenBazaar/multiwallet/coins"
 	"github.com/OpenBazaar/multiwallet/config"
 	wi "github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -20,7 +21,8 @@ const WALLET_VERSION = "0.1.0"
 var parser = flags.NewParser(nil, flags.Default)
 
 type Start struct {
-	Testnet bool `short:"t" long:"testnet" description:"use the test network"`
+	Testnet   bool              `short:"t" long:"testnet" description:"use the test network"`
+	Endpoints map[string]string `long:"endpoint" description:"the API endpoint of a registered coin as name:url, which coins without a default endpoint need to start"`
 }
 type Version struct{}
 
@@ -67,7 +69,23 @@ func (x *Start) Execute(args []string) error {
 	if x.Testnet {
 		params = &chaincfg.TestNet3Params
 	}
+	for _, c := range coins.All() {
+		network := c.MainNet
+		if x.Testnet {
+			network = c.TestNet
+		}
+		if len(network.Endpoints) == 0 && x.Endpoints[c.Name] == "" {
+			fmt.Printf("Not starting %s without an API endpoint, pass --endpoint %s:<url>\n", c.Name, c.Name)
+			continue
+		}
+		m[c.CoinType] = true
+	}
 	cfg := config.NewDefaultConfig(m, params)
+	for i, coin := range cfg.Coins {
+		if c, ok := coins.Lookup(coin.CoinType); ok && x.Endpoints[c.Name] != "" {
+			cfg.Coins[i].ClientAPIs = []string{x.Endpoints[c.Name]}
+		}
+	}
 	cfg.Mnemonic = "bottle author ability expose illegal saddle antique setup pledge wife innocent treat"
 	var err error
 	mw, err = multiwallet.NewMultiWallet(cfg)
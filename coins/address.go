@@ -0,0 +1,137 @@
+package coins
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/ripemd160"
+)
+
+var (
+	// ErrChecksumMismatch describes an error where decoding failed due
+	// to a bad checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrUnknownAddressType describes an error where an address can not
+	// decoded as a specific address type due to the string encoding
+	// begining with an identifier byte unknown to the coin's network.
+	ErrUnknownAddressType = errors.New("unknown address type")
+)
+
+// DecodeAddress decodes a base58 P2PKH or P2SH address of the coin on the network of params
+func (c *Coin) DecodeAddress(addr string, params *chaincfg.Params) (btcutil.Address, error) {
+	decoded, netID, err := base58.CheckDecode(addr)
+	if err != nil {
+		if err == base58.ErrChecksum {
+			return nil, ErrChecksumMismatch
+		}
+		return nil, errors.New("decoded address is of unknown format")
+	}
+	if len(decoded) != ripemd160.Size {
+		return nil, errors.New("decoded address is of unknown size")
+	}
+	net := c.Network(params)
+	switch netID {
+	case net.PubKeyHashAddrID:
+		return c.NewAddressPubKeyHash(decoded, params)
+	case net.ScriptHashAddrID:
+		return c.NewAddressScriptHashFromHash(decoded, params)
+	default:
+		return nil, ErrUnknownAddressType
+	}
+}
+
+// NewAddressPubKeyHash returns the coin's P2PKH address of the 20 byte pkHash
+func (c *Coin) NewAddressPubKeyHash(pkHash []byte, params *chaincfg.Params) (*Address, error) {
+	return c.newAddress(pkHash, c.Network(params).PubKeyHashAddrID, false)
+}
+
+// NewAddressScriptHash returns the coin's P2SH address of serializedScript
+func (c *Coin) NewAddressScriptHash(serializedScript []byte, params *chaincfg.Params) (*Address, error) {
+	return c.NewAddressScriptHashFromHash(btcutil.Hash160(serializedScript), params)
+}
+
+// NewAddressScriptHashFromHash returns the coin's P2SH address of the 20 byte scriptHash
+func (c *Coin) NewAddressScriptHashFromHash(scriptHash []byte, params *chaincfg.Params) (*Address, error) {
+	return c.newAddress(scriptHash, c.Network(params).ScriptHashAddrID, true)
+}
+
+func (c *Coin) newAddress(hash []byte, netID byte, scriptHash bool) (*Address, error) {
+	if len(hash) != ripemd160.Size {
+		return nil, errors.New("hash must be 20 bytes")
+	}
+	addr := &Address{coin: c, netID: netID, scriptHash: scriptHash}
+	copy(addr.hash[:], hash)
+	return addr, nil
+}
+
+// PayToAddrScript returns the output script paying addr, which must be an address of the coin
+func (c *Coin) PayToAddrScript(addr btcutil.Address) ([]byte, error) {
+	a, ok := addr.(*Address)
+	if !ok || a == nil || a.coin != c {
+		return nil, fmt.Errorf("unable to generate payment script for unsupported "+
+			"address type %T", addr)
+	}
+	if a.scriptHash {
+		return txscript.NewScriptBuilder().AddOp(txscript.OP_HASH160).AddData(a.hash[:]).
+			AddOp(txscript.OP_EQUAL).Script()
+	}
+	return txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).
+		AddData(a.hash[:]).AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).
+		Script()
+}
+
+// ExtractAddress returns the address paid by a P2PKH or P2SH output script. Any other script
+// type is an error.
+func (c *Coin) ExtractAddress(pkScript []byte, params *chaincfg.Params) (btcutil.Address, error) {
+	if len(pkScript) == 1+1+20+1 && pkScript[0] == 0xa9 && pkScript[1] == 0x14 && pkScript[22] == 0x87 {
+		return c.NewAddressScriptHashFromHash(pkScript[2:22], params)
+	} else if len(pkScript) == 1+1+1+20+1+1 && pkScript[0] == 0x76 && pkScript[1] == 0xa9 && pkScript[2] == 0x14 && pkScript[23] == 0x88 && pkScript[24] == 0xac {
+		return c.NewAddressPubKeyHash(pkScript[3:23], params)
+	}
+	return nil, errors.New("unknown script type")
+}
+
+// Address is a P2PKH or P2SH address of a registered coin
+type Address struct {
+	coin       *Coin
+	hash       [ripemd160.Size]byte
+	netID      byte
+	scriptHash bool
+}
+
+// EncodeAddress returns the base58check encoding of the address.  Part of the
+// Address interface.
+func (a *Address) EncodeAddress() string {
+	return base58.CheckEncode(a.hash[:], a.netID)
+}
+
+// ScriptAddress returns the hash included in the output script paying the
+// address.  Part of the Address interface.
+func (a *Address) ScriptAddress() []byte {
+	return a.hash[:]
+}
+
+// IsForNet returns whether or not the address is associated with the coin's
+// network of params.  Part of the Address interface.
+func (a *Address) IsForNet(params *chaincfg.Params) bool {
+	net := a.coin.Network(params)
+	if a.scriptHash {
+		return a.netID == net.ScriptHashAddrID
+	}
+	return a.netID == net.PubKeyHashAddrID
+}
+
+// String returns the encoded address so the type can be used as a fmt.Stringer
+func (a *Address) String() string {
+	return a.EncodeAddress()
+}
+
+// IsScriptHash reports whether the address pays to a script hash rather than a pubkey hash
+func (a *Address) IsScriptHash() bool {
+	return a.scriptHash
+}
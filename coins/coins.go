@@ -0,0 +1,146 @@
+package coins
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+)
+
+// Coin declares a bitcoin derived coin which differs from bitcoin only in its parameters. A
+// registered coin gets a wallet built on the shared UTXO engine and is known to the config,
+// service, API and CLI without any of them having to name it.
+type Coin struct {
+	// Name is the lower case name the CLI takes. The gRPC CoinType enum names a coin in upper case,
+	// and requests select a coin missing from it by its CoinType.
+	Name string
+
+	// CurrencyCode is the ticker symbol of the coin on mainnet. Testnet prefixes it with T.
+	CurrencyCode string
+
+	// CoinType is the coin's SLIP-0044 index, which is also the BIP44 coin type its keys are
+	// derived under. TestnetCoinType is the wallet-interface convention of a million more.
+	CoinType        wallet.CoinType
+	TestnetCoinType wallet.CoinType
+
+	// Divisibility is the number of decimal places of the coin's base unit
+	Divisibility int64
+
+	MainNet Network
+	TestNet Network
+
+	// RelayFeePerKb is the network's minimum relay fee, which outputs are checked against for dust
+	RelayFeePerKb btcutil.Amount
+
+	// DustLimit and MinFeePerByte are the optional flat dust limit and fee floor of the network
+	DustLimit     btcutil.Amount
+	MinFeePerByte uint64
+
+	// SafeConfirmations is the number of confirmations from which a transaction is reported as
+	// confirmed
+	SafeConfirmations int32
+
+	// FeeEstimateAddress is a mainnet P2PKH address paid when estimating the fee of a spend
+	FeeEstimateAddress string
+
+	// Timelocks is set when the network enforces CHECKSEQUENCEVERIFY so escrows may time out
+	Timelocks bool
+
+	// Default fees per byte for each fee level and the most ever paid
+	SuperLowFee uint64
+	LowFee      uint64
+	MediumFee   uint64
+	HighFee     uint64
+	MaxFee      uint64
+}
+
+// Network holds the parameters of a coin which differ between mainnet and testnet
+type Network struct {
+	PubKeyHashAddrID byte
+	ScriptHashAddrID byte
+
+	// Endpoints are the default Blockbook APIs of the network. Coins without one can only be used
+	// with APIs configured in ClientAPIs.
+	Endpoints []string
+}
+
+var (
+	registryMtx sync.RWMutex
+	registry    = make(map[wallet.CoinType]*Coin)
+)
+
+// Register adds coin to the registry. It panics if the coin type or name is already taken, so
+// coins are best registered from an init function.
+func Register(coin *Coin) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+	for _, c := range registry {
+		if c.CoinType == coin.CoinType || c.TestnetCoinType == coin.TestnetCoinType || c.Name == coin.Name {
+			panic("coins: duplicate registration of " + coin.Name)
+		}
+	}
+	registry[coin.CoinType] = coin
+	registry[coin.TestnetCoinType] = coin
+}
+
+// Lookup returns the registered coin of coinType, which may be its mainnet or testnet type
+func Lookup(coinType wallet.CoinType) (*Coin, bool) {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	coin, ok := registry[coinType]
+	return coin, ok
+}
+
+// LookupName returns the registered coin called name, ignoring case
+func LookupName(name string) (*Coin, bool) {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	for _, coin := range registry {
+		if strings.EqualFold(coin.Name, name) {
+			return coin, true
+		}
+	}
+	return nil, false
+}
+
+// All returns every registered coin ordered by coin type
+func All() []*Coin {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	var all []*Coin
+	for ct, coin := range registry {
+		if ct == coin.CoinType {
+			all = append(all, coin)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].CoinType < all[j].CoinType })
+	return all
+}
+
+// Network returns the parameters of the coin's network matching params
+func (c *Coin) Network(params *chaincfg.Params) Network {
+	if params.Name == chaincfg.MainNetParams.Name {
+		return c.MainNet
+	}
+	return c.TestNet
+}
+
+// WalletCoinType returns the coin type the wallet of the coin is keyed by on params' network
+func (c *Coin) WalletCoinType(params *chaincfg.Params) wallet.CoinType {
+	if params.Name == chaincfg.MainNetParams.Name {
+		return c.CoinType
+	}
+	return c.TestnetCoinType
+}
+
+// UnitsPerCoin returns the number of base units in one coin
+func (c *Coin) UnitsPerCoin() int64 {
+	units := int64(1)
+	for i := int64(0); i < c.Divisibility; i++ {
+		units *= 10
+	}
+	return units
+}
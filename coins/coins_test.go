@@ -0,0 +1,226 @@
+package coins
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestLookup(t *testing.T) {
+	c, ok := Lookup(5)
+	if !ok || c != Dash {
+		t.Fatal("Dash not registered under its mainnet coin type")
+	}
+	c, ok = Lookup(1000005)
+	if !ok || c != Dash {
+		t.Fatal("Dash not registered under its testnet coin type")
+	}
+	if _, ok := Lookup(0); ok {
+		t.Error("Bitcoin is not a registered coin")
+	}
+	c, ok = LookupName("DASH")
+	if !ok || c != Dash {
+		t.Error("Dash not found by its upper case name")
+	}
+	c, ok = LookupName("dogecoin")
+	if !ok || c != Dogecoin {
+		t.Error("Dogecoin not found by its name")
+	}
+	if _, ok := LookupName("bitcoin"); ok {
+		t.Error("Bitcoin is not a registered coin")
+	}
+}
+
+func TestAll(t *testing.T) {
+	all := All()
+	found := 0
+	for i, c := range all {
+		if c == Dash {
+			found++
+		}
+		if i > 0 && all[i-1].CoinType >= c.CoinType {
+			t.Error("Coins are not ordered by coin type")
+		}
+	}
+	if found != 1 {
+		t.Errorf("Expected Dash once in all coins, found it %d times", found)
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Registering a taken coin type did not panic")
+		}
+	}()
+	Register(&Coin{Name: "dash2", CoinType: Dash.CoinType, TestnetCoinType: 2000005})
+}
+
+func TestCoin_WalletCoinType(t *testing.T) {
+	if ct := Dash.WalletCoinType(&chaincfg.MainNetParams); ct != 5 {
+		t.Errorf("Expected mainnet coin type 5, got %d", ct)
+	}
+	if ct := Dash.WalletCoinType(&chaincfg.TestNet3Params); ct != 1000005 {
+		t.Errorf("Expected testnet coin type 1000005, got %d", ct)
+	}
+	if units := Dash.UnitsPerCoin(); units != 100000000 {
+		t.Errorf("Expected 100000000 units per coin, got %d", units)
+	}
+}
+
+var dataElement = []byte{203, 72, 18, 50, 41, 156, 213, 116, 49, 81, 172, 75, 45, 99, 174, 25, 142, 123, 176, 169}
+
+var dataElement2 = []byte{118, 160, 64, 83, 189, 160, 168, 139, 218, 81, 119, 184, 106, 21, 195, 178, 159, 85, 152, 115}
+
+func TestDecodeDashAddress(t *testing.T) {
+	for _, test := range []struct {
+		addr       string
+		params     *chaincfg.Params
+		scriptHash bool
+	}{
+		{"XuDhLnGcNvn4yMXzdnfE58df9YvEACwqHA", &chaincfg.MainNetParams, false},
+		{"7dDtTpzGa3tGi3WH7cDSCdYEcP3iCkX85n", &chaincfg.MainNetParams, true},
+		{"yerJMjM3pUS9K6TYCdyd7A41RqQbgZh1EH", &chaincfg.TestNet3Params, false},
+		{"8qEhR9t8hbGuALvYBsDPf1MbVtpYKAmWrK", &chaincfg.TestNet3Params, true},
+	} {
+		addr, err := Dash.DecodeAddress(test.addr, test.params)
+		if err != nil {
+			t.Errorf("Decoding %s: %s", test.addr, err)
+			continue
+		}
+		if addr.String() != test.addr {
+			t.Errorf("Expected %s, got %s", test.addr, addr.String())
+		}
+		if addr.(*Address).IsScriptHash() != test.scriptHash {
+			t.Errorf("Decoded %s as the wrong address type", test.addr)
+		}
+		if !addr.IsForNet(test.params) {
+			t.Errorf("Decoded %s for the wrong network", test.addr)
+		}
+	}
+}
+
+func TestDecodeDogecoinAddress(t *testing.T) {
+	for _, test := range []struct {
+		addr       string
+		params     *chaincfg.Params
+		scriptHash bool
+	}{
+		{"DPfx3nZMidTmMR81WVLZmN7UCM4qY2F33t", &chaincfg.MainNetParams, false},
+		{"A3FWNUmzq8fXceLoG8DM7PAxPQbNUjae2B", &chaincfg.MainNetParams, true},
+		{"nnj1moJGebvVEPhCYJz21mhmSDT8ZBuq9p", &chaincfg.TestNet3Params, false},
+		{"2N44ThNe8NXHyv4bsX8AoVCXquBRW94Ls7W", &chaincfg.TestNet3Params, true},
+	} {
+		addr, err := Dogecoin.DecodeAddress(test.addr, test.params)
+		if err != nil {
+			t.Errorf("Decoding %s: %s", test.addr, err)
+			continue
+		}
+		if addr.String() != test.addr {
+			t.Errorf("Expected %s, got %s", test.addr, addr.String())
+		}
+		if addr.(*Address).IsScriptHash() != test.scriptHash {
+			t.Errorf("Decoded %s as the wrong address type", test.addr)
+		}
+	}
+	addr, err := Dogecoin.NewAddressPubKeyHash(dataElement, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.String() != "DPfx3nZMidTmMR81WVLZmN7UCM4qY2F33t" {
+		t.Errorf("Expected DPfx3nZMidTmMR81WVLZmN7UCM4qY2F33t, got %s", addr.String())
+	}
+	// Dash and dogecoin addresses are not interchangeable
+	if _, err := Dogecoin.DecodeAddress("XuDhLnGcNvn4yMXzdnfE58df9YvEACwqHA", &chaincfg.MainNetParams); err != ErrUnknownAddressType {
+		t.Errorf("Expected %v decoding a dash address, got %v", ErrUnknownAddressType, err)
+	}
+}
+
+func TestDecodeAddressWrongNetwork(t *testing.T) {
+	if _, err := Dash.DecodeAddress("XuDhLnGcNvn4yMXzdnfE58df9YvEACwqHA", &chaincfg.TestNet3Params); err != ErrUnknownAddressType {
+		t.Errorf("Expected %v decoding a mainnet address on testnet, got %v", ErrUnknownAddressType, err)
+	}
+	// A bitcoin address is not a dash one
+	if _, err := Dash.DecodeAddress("1KXrWXciRDZUpQwQmuM1DbwsKDLYAYsVLR", &chaincfg.MainNetParams); err != ErrUnknownAddressType {
+		t.Errorf("Expected %v decoding a bitcoin address, got %v", ErrUnknownAddressType, err)
+	}
+	if _, err := Dash.DecodeAddress("XuDhLnGcNvn4yMXzdnfE58df9YvEACwqHB", &chaincfg.MainNetParams); err != ErrChecksumMismatch {
+		t.Errorf("Expected %v, got %v", ErrChecksumMismatch, err)
+	}
+}
+
+func TestAddress_EncodeAddress(t *testing.T) {
+	addr, err := Dash.NewAddressPubKeyHash(dataElement, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.String() != "XuDhLnGcNvn4yMXzdnfE58df9YvEACwqHA" {
+		t.Errorf("Expected XuDhLnGcNvn4yMXzdnfE58df9YvEACwqHA, got %s", addr.String())
+	}
+	if !addr.IsForNet(&chaincfg.MainNetParams) || addr.IsForNet(&chaincfg.TestNet3Params) {
+		t.Error("Address is for the wrong network")
+	}
+	shAddr, err := Dash.NewAddressScriptHashFromHash(dataElement2, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shAddr.String() != "8qEhR9t8hbGuALvYBsDPf1MbVtpYKAmWrK" {
+		t.Errorf("Expected 8qEhR9t8hbGuALvYBsDPf1MbVtpYKAmWrK, got %s", shAddr.String())
+	}
+	if _, err := Dash.NewAddressPubKeyHash(dataElement[:19], &chaincfg.MainNetParams); err == nil {
+		t.Error("Expected an error for a short hash")
+	}
+}
+
+func TestCoin_PayToAddrScript(t *testing.T) {
+	addr, err := Dash.NewAddressPubKeyHash(dataElement, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := Dash.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := append(append([]byte{0x76, 0xa9, 0x14}, dataElement...), 0x88, 0xac)
+	if !bytes.Equal(script, expected) {
+		t.Errorf("Expected P2PKH script %x, got %x", expected, script)
+	}
+	extracted, err := Dash.ExtractAddress(script, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extracted.String() != addr.String() {
+		t.Errorf("Expected %s extracted from script, got %s", addr.String(), extracted.String())
+	}
+
+	shAddr, err := Dash.NewAddressScriptHashFromHash(dataElement2, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err = Dash.PayToAddrScript(shAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = append(append([]byte{0xa9, 0x14}, dataElement2...), 0x87)
+	if !bytes.Equal(script, expected) {
+		t.Errorf("Expected P2SH script %x, got %x", expected, script)
+	}
+	extracted, err = Dash.ExtractAddress(script, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extracted.String() != shAddr.String() {
+		t.Errorf("Expected %s extracted from script, got %s", shAddr.String(), extracted.String())
+	}
+
+	// Another coin's address is not paid to
+	other := &Coin{Name: "other", MainNet: Dash.MainNet}
+	otherAddr, err := other.NewAddressPubKeyHash(dataElement, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Dash.PayToAddrScript(otherAddr); err == nil {
+		t.Error("Expected an error paying another coin's address")
+	}
+}
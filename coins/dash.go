@@ -0,0 +1,37 @@
+package coins
+
+// Dash is bitcoin with its own address prefixes and no segwit. Its InstantSend and
+// PrivateSend features are not used by the wallet. There is no default API endpoint, so one must
+// be configured.
+var Dash = &Coin{
+	Name:         "dash",
+	CurrencyCode: "DASH",
+
+	CoinType:        5,
+	TestnetCoinType: 1000005,
+	Divisibility:    8,
+
+	MainNet: Network{
+		PubKeyHashAddrID: 0x4c, // starts with X
+		ScriptHashAddrID: 0x10, // starts with 7
+	},
+	TestNet: Network{
+		PubKeyHashAddrID: 0x8c, // starts with y
+		ScriptHashAddrID: 0x13, // starts with 8 or 9
+	},
+
+	RelayFeePerKb:      1000,
+	SafeConfirmations:  24,
+	FeeEstimateAddress: "XuMnVEGXLrqh2WPyyec59EmMTqS5dHRCbx",
+	Timelocks:          true,
+
+	SuperLowFee: 1,
+	LowFee:      1,
+	MediumFee:   2,
+	HighFee:     5,
+	MaxFee:      200,
+}
+
+func init() {
+	Register(Dash)
+}
@@ -0,0 +1,41 @@
+package coins
+
+// Dogecoin is pre-segwit bitcoin with its own address prefixes and much higher relay fees. Its
+// soft dust limit charges relay nodes' extra fee for every output below 0.01 DOGE, so the wallet
+// neither pays nor keeps change below it. There is no default API endpoint, so one must be
+// configured.
+var Dogecoin = &Coin{
+	Name:         "dogecoin",
+	CurrencyCode: "DOGE",
+
+	CoinType:        3,
+	TestnetCoinType: 1000003,
+	Divisibility:    8,
+
+	MainNet: Network{
+		PubKeyHashAddrID: 0x1e, // starts with D
+		ScriptHashAddrID: 0x16, // starts with 9 or A
+	},
+	TestNet: Network{
+		PubKeyHashAddrID: 0x71, // starts with n
+		ScriptHashAddrID: 0xc4, // starts with 2
+	},
+
+	RelayFeePerKb: 100000,
+	DustLimit:     1000000,
+	// Dogecoin Core recommends 0.01 DOGE per kilobyte. Transactions paying less relay slowly
+	// if at all.
+	MinFeePerByte:      1000,
+	SafeConfirmations:  60,
+	FeeEstimateAddress: "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L",
+
+	SuperLowFee: 1000,
+	LowFee:      1000,
+	MediumFee:   2000,
+	HighFee:     5000,
+	MaxFee:      100000,
+}
+
+func init() {
+	Register(Dogecoin)
+}
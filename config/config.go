@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/OpenBazaar/multiwallet/cache"
+	"github.com/OpenBazaar/multiwallet/coins"
 	"github.com/OpenBazaar/multiwallet/datastore"
 	"github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -50,7 +51,9 @@ type CoinConfig struct {
 	// The type of coin to configure
 	CoinType wallet.CoinType
 
-	// The default fee-per-byte for each level
+	// The default fee-per-byte for each level. Coins other than bitcoin pay the backend's fee
+	// estimate for each level's confirmation target and only fall back to these when the backend
+	// has no estimate and the coin's USD price is unknown.
 	SuperLowFee uint64
 	LowFee      uint64
 	MediumFee   uint64
@@ -59,15 +62,46 @@ type CoinConfig struct {
 	// The highest allowable fee-per-byte
 	MaxFee uint64
 
-	// External API to query to look up fees. If this field is nil then the default fees will be used.
-	// If the API is unreachable then the default fees will likewise be used. If the API returns a fee
-	// greater than MaxFee then the MaxFee will be used in place. The API response must be formatted as
-	// { "fastestFee": 40, "halfHourFee": 20, "hourFee": 10 }
+	// Deprecated: fees are estimated by the coin's API servers. FeeAPI is ignored.
 	FeeAPI string
 
+	// The exchange rate APIs to price the coin with, by name: openbazaar, coingecko, kraken, bitfinex
+	// or poloniex. Each rate is the median of those which answered. If empty every API known to price
+	// the coin is used.
+	ExchangeRateProviders []string
+
 	// The trusted APIs to use for querying for balances and listening to blockchain events.
 	ClientAPIs []string
 
+	// Limits on the load placed on each client API. MaxConcurrentRequests caps the requests in flight
+	// at once and RequestsPerSecond/RequestBurst configure a token bucket shared by all requests. Zero
+	// values use the client defaults and a negative RequestsPerSecond disables rate limiting.
+	MaxConcurrentRequests int
+	RequestsPerSecond     float64
+	RequestBurst          int
+
+	// Broadcast transactions to every healthy client API rather than only the active one so they
+	// still propagate if one server's node has a poorly connected mempool.
+	BroadcastToAllEndpoints bool
+
+	// The number of blocks an unconfirmed transaction may be missing from the mempool of every client
+	// API before it is abandoned and the coins it spent become spendable again. Zero uses the default.
+	AbandonAfterBlocks uint32
+
+	// How the coins funding a spend are chosen: max-value-age, branch-and-bound, knapsack,
+	// smallest-first or privacy. Empty uses max-value-age. Batch spends may override it.
+	CoinSelection string
+
+	// Small confirmed utxos are consolidated into one output paying an internal address when the
+	// economic fee-per-byte is at or below ConsolidateBelowFee and doing so is expected to save
+	// fees. Utxos worth less than ConsolidateMaxUtxoValue are merged, at least ConsolidateMinUtxos
+	// and at most ConsolidateMaxUtxos at a time. A zero ConsolidateBelowFee disables automatic
+	// consolidation and other zero values use the defaults.
+	ConsolidateBelowFee     uint64
+	ConsolidateMaxUtxoValue uint64
+	ConsolidateMinUtxos     int
+	ConsolidateMaxUtxos     int
+
 	// An implementation of the Datastore interface for each desired coin
 	DB wallet.Datastore
 
@@ -102,11 +136,10 @@ func NewDefaultConfig(coinTypes map[wallet.CoinType]bool, params *chaincfg.Param
 				//"https://test-insight.bitpay.com/api",
 			}
 		}
-		feeApi := "https://btc.fees.openbazaar.org"
 		db, _ := mockDB.GetDatastoreForWallet(wallet.Bitcoin)
 		btcCfg := CoinConfig{
 			CoinType:    wallet.Bitcoin,
-			FeeAPI:      feeApi,
+			FeeAPI:      "",
 			SuperLowFee: 70,
 			LowFee:      140,
 			MediumFee:   160,
@@ -235,5 +268,26 @@ func NewDefaultConfig(coinTypes map[wallet.CoinType]bool, params *chaincfg.Param
 		}
 		cfg.Coins = append(cfg.Coins, ethCfg)
 	}
+	for _, c := range coins.All() {
+		if !coinTypes[c.CoinType] {
+			continue
+		}
+		apiEndpoints := c.MainNet.Endpoints
+		if testnet {
+			apiEndpoints = c.TestNet.Endpoints
+		}
+		db, _ := mockDB.GetDatastoreForWallet(c.CoinType)
+		cfg.Coins = append(cfg.Coins, CoinConfig{
+			CoinType:    c.CoinType,
+			FeeAPI:      "",
+			SuperLowFee: c.SuperLowFee,
+			LowFee:      c.LowFee,
+			MediumFee:   c.MediumFee,
+			HighFee:     c.HighFee,
+			MaxFee:      c.MaxFee,
+			ClientAPIs:  apiEndpoints,
+			DB:          db,
+		})
+	}
 	return cfg
 }
@@ -9,9 +9,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/multiwallet/model"
 	"github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 )
 
 type MockDatastore struct {
@@ -74,6 +77,15 @@ func NewMockMultiwalletDatastore() *MockMultiwalletDatastore {
 		&MockTxnStore{txns: make(map[string]*txnStoreEntry)},
 		&MockWatchedScriptsStore{scripts: make(map[string][]byte)},
 	})
+	for _, c := range coins.All() {
+		db[c.CoinType] = wallet.Datastore(&MockDatastore{
+			&MockKeyStore{Keys: make(map[string]*KeyStoreEntry)},
+			&MockUtxoStore{utxos: make(map[string]*wallet.Utxo)},
+			&MockStxoStore{stxos: make(map[string]*wallet.Stxo)},
+			&MockTxnStore{txns: make(map[string]*txnStoreEntry)},
+			&MockWatchedScriptsStore{scripts: make(map[string][]byte)},
+		})
+	}
 	return &MockMultiwalletDatastore{db: db}
 }
 
@@ -238,7 +250,8 @@ func (m *MockKeyStore) GetLookaheadWindows() map[wallet.KeyPurpose]int {
 }
 
 type MockUtxoStore struct {
-	utxos map[string]*wallet.Utxo
+	utxos  map[string]*wallet.Utxo
+	frozen map[wire.OutPoint]bool
 	sync.Mutex
 }
 
@@ -284,6 +297,33 @@ func (m *MockUtxoStore) Delete(utxo wallet.Utxo) error {
 	return nil
 }
 
+func (m *MockUtxoStore) Freeze(op wire.OutPoint) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.frozen == nil {
+		m.frozen = make(map[wire.OutPoint]bool)
+	}
+	m.frozen[op] = true
+	return nil
+}
+
+func (m *MockUtxoStore) Unfreeze(op wire.OutPoint) error {
+	m.Lock()
+	defer m.Unlock()
+	delete(m.frozen, op)
+	return nil
+}
+
+func (m *MockUtxoStore) GetFrozen() ([]wire.OutPoint, error) {
+	m.Lock()
+	defer m.Unlock()
+	var ops []wire.OutPoint
+	for op := range m.frozen {
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
 type MockStxoStore struct {
 	stxos map[string]*wallet.Stxo
 	sync.Mutex
@@ -326,7 +366,8 @@ type txnStoreEntry struct {
 }
 
 type MockTxnStore struct {
-	txns map[string]*txnStoreEntry
+	txns  map[string]*txnStoreEntry
+	rates map[string]model.RateSnapshot
 	sync.Mutex
 }
 
@@ -402,6 +443,26 @@ func (m *MockTxnStore) Delete(txid *chainhash.Hash) error {
 	return nil
 }
 
+func (m *MockTxnStore) PutRates(txid string, snapshot model.RateSnapshot) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.rates == nil {
+		m.rates = make(map[string]model.RateSnapshot)
+	}
+	m.rates[txid] = snapshot
+	return nil
+}
+
+func (m *MockTxnStore) GetRates(txid string) (*model.RateSnapshot, error) {
+	m.Lock()
+	defer m.Unlock()
+	snapshot, ok := m.rates[txid]
+	if !ok {
+		return nil, nil
+	}
+	return &snapshot, nil
+}
+
 type MockWatchedScriptsStore struct {
 	scripts map[string][]byte
 	sync.Mutex
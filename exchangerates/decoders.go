@@ -0,0 +1,154 @@
+package exchangerates
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// OpenBazaarDecoder reads the OpenBazaar ticker, which prices every currency it tracks, fiat and
+// crypto, in bitcoin
+type OpenBazaarDecoder struct{}
+
+// CoinGeckoDecoder reads CoinGecko's simple price API for the coin with the CoinGecko ID
+type CoinGeckoDecoder struct {
+	ID string
+}
+
+// KrakenDecoder reads Kraken's ticker of a single pair of the coin against bitcoin
+type KrakenDecoder struct{}
+
+// BitfinexDecoder reads Bitfinex's ticker of a trading pair of the coin against bitcoin
+type BitfinexDecoder struct{}
+
+// PoloniexDecoder reads Poloniex's price of a market of the coin against bitcoin
+type PoloniexDecoder struct{}
+
+func (OpenBazaarDecoder) Decode(dat interface{}, code string) (map[string]float64, error) {
+	data, ok := dat.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("OpenBazaarDecoder type assertion failure")
+	}
+	coinRate := 1.0
+	if code != "BTC" {
+		coin, ok := data[code].(map[string]interface{})
+		if !ok {
+			return nil, errors.New("OpenBazaarDecoder: field `" + code + "` not found")
+		}
+		coinRate, ok = coin["last"].(float64)
+		if !ok || coinRate == 0 {
+			return nil, errors.New("OpenBazaarDecoder: field `" + code + ".last` not found")
+		}
+	}
+	rates := make(map[string]float64)
+	for k, v := range data {
+		if k == "timestamp" {
+			continue
+		}
+		val, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("OpenBazaarDecoder type assertion failure")
+		}
+		price, ok := val["last"].(float64)
+		if !ok {
+			return nil, errors.New("OpenBazaarDecoder: field `" + k + ".last` not found")
+		}
+		rates[k] = price / coinRate
+	}
+	return rates, nil
+}
+
+func (d CoinGeckoDecoder) Decode(dat interface{}, code string) (map[string]float64, error) {
+	data, ok := dat.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("CoinGeckoDecoder type assertion failure")
+	}
+	coin, ok := data[d.ID].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("CoinGeckoDecoder: field `" + d.ID + "` not found")
+	}
+	rates := make(map[string]float64)
+	for k, v := range coin {
+		price, ok := v.(float64)
+		if !ok {
+			return nil, errors.New("CoinGeckoDecoder type assertion failure")
+		}
+		rates[strings.ToUpper(k)] = price
+	}
+	if len(rates) == 0 {
+		return nil, errors.New("CoinGeckoDecoder: no prices found")
+	}
+	return rates, nil
+}
+
+func (KrakenDecoder) Decode(dat interface{}, code string) (map[string]float64, error) {
+	obj, ok := dat.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("KrakenDecoder type assertion failure")
+	}
+	result, ok := obj["result"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("KrakenDecoder: field `result` not found")
+	}
+	// The result is keyed by Kraken's name of the pair, which need not be the one asked for
+	if len(result) != 1 {
+		return nil, errors.New("KrakenDecoder: expected a single pair")
+	}
+	for _, pair := range result {
+		pairMap, ok := pair.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("KrakenDecoder type assertion failure")
+		}
+		c, ok := pairMap["c"].([]interface{})
+		if !ok || len(c) == 0 {
+			return nil, errors.New("KrakenDecoder: field `c` not found")
+		}
+		rateStr, ok := c[0].(string)
+		if !ok {
+			return nil, errors.New("KrakenDecoder type assertion failure")
+		}
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil {
+			return nil, err
+		}
+		return btcRate(rate)
+	}
+	return nil, errors.New("KrakenDecoder: expected a single pair")
+}
+
+func (BitfinexDecoder) Decode(dat interface{}, code string) (map[string]float64, error) {
+	// [BID, BID_SIZE, ASK, ASK_SIZE, DAILY_CHANGE, DAILY_CHANGE_RELATIVE, LAST_PRICE, ...]
+	ticker, ok := dat.([]interface{})
+	if !ok || len(ticker) < 7 {
+		return nil, errors.New("BitfinexDecoder type assertion failure")
+	}
+	rate, ok := ticker[6].(float64)
+	if !ok {
+		return nil, errors.New("BitfinexDecoder: last price not found")
+	}
+	return btcRate(rate)
+}
+
+func (PoloniexDecoder) Decode(dat interface{}, code string) (map[string]float64, error) {
+	obj, ok := dat.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("PoloniexDecoder type assertion failure")
+	}
+	rateStr, ok := obj["price"].(string)
+	if !ok {
+		return nil, errors.New("PoloniexDecoder: field `price` not found")
+	}
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil {
+		return nil, err
+	}
+	return btcRate(rate)
+}
+
+// btcRate returns the rates of a coin priced at rate bitcoin
+func btcRate(rate float64) (map[string]float64, error) {
+	if rate <= 0 {
+		return nil, errors.New("bitcoin price data not available")
+	}
+	return map[string]float64{"BTC": rate}, nil
+}
@@ -0,0 +1,98 @@
+package exchangerates
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	var dat interface{}
+	if err := json.Unmarshal([]byte(s), &dat); err != nil {
+		t.Fatal(err)
+	}
+	return dat
+}
+
+func TestOpenBazaarDecoder(t *testing.T) {
+	response := `{
+		"BCH": {"ask": 32.09, "bid": 32.08, "last": 32, "timestamp": "Tue, 02 Aug 2016 00:20:45 -0000"},
+		"BTC": {"last": 1},
+		"USD": {"ask": 600.5, "bid": 599.5, "last": 600, "timestamp": "Tue, 02 Aug 2016 00:20:45 -0000"},
+		"timestamp": "Tue, 02 Aug 2016 00:20:45 -0000"
+	}`
+	rates, err := OpenBazaarDecoder{}.Decode(decodeJSON(t, response), "BCH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates["USD"] != 18.75 || rates["BTC"] != 1.0/32 || rates["BCH"] != 1 {
+		t.Errorf("Incorrect rates decoded: %v", rates)
+	}
+	rates, err = OpenBazaarDecoder{}.Decode(decodeJSON(t, response), "BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates["USD"] != 600 {
+		t.Errorf("Expected bitcoin at 600 USD, got %f", rates["USD"])
+	}
+
+	// Missing coin
+	if _, err := (OpenBazaarDecoder{}).Decode(decodeJSON(t, response), "LTC"); err == nil {
+		t.Error("Expected an error for a coin missing from the ticker")
+	}
+	// Missing last price
+	if _, err := (OpenBazaarDecoder{}).Decode(decodeJSON(t, `{"BCH": {"last": 32}, "ZWL": {"ask": 196806.48}}`), "BCH"); err == nil {
+		t.Error("Expected an error for a currency without a last price")
+	}
+	// Invalid JSON
+	if _, err := (OpenBazaarDecoder{}).Decode(decodeJSON(t, `[1, 2]`), "BCH"); err == nil {
+		t.Error("Expected an error for a response which is not an object")
+	}
+}
+
+func TestCoinGeckoDecoder(t *testing.T) {
+	rates, err := CoinGeckoDecoder{ID: "zcash"}.Decode(decodeJSON(t, `{"zcash": {"usd": 30.5, "eur": 28, "btc": 0.0005}}`), "ZEC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 3 || rates["USD"] != 30.5 || rates["EUR"] != 28 || rates["BTC"] != 0.0005 {
+		t.Errorf("Incorrect rates decoded: %v", rates)
+	}
+	if _, err := (CoinGeckoDecoder{ID: "zcash"}).Decode(decodeJSON(t, `{"litecoin": {"usd": 70}}`), "ZEC"); err == nil {
+		t.Error("Expected an error for a response without the coin")
+	}
+	if _, err := (CoinGeckoDecoder{ID: "zcash"}).Decode(decodeJSON(t, `{"zcash": {}}`), "ZEC"); err == nil {
+		t.Error("Expected an error for a response without prices")
+	}
+}
+
+func TestBitcoinPairDecoders(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		decoder  Decoder
+		response string
+		valid    bool
+	}{
+		{"kraken", KrakenDecoder{}, `{"error": [], "result": {"XXDGXXBT": {"a": ["0.0000025", "1", "1"], "c": ["0.0000024", "500"]}}}`, true},
+		{"kraken without result", KrakenDecoder{}, `{"error": ["EQuery:Unknown asset pair"]}`, false},
+		{"kraken with a bad price", KrakenDecoder{}, `{"error": [], "result": {"XXDGXXBT": {"c": ["abc", "500"]}}}`, false},
+		{"bitfinex", BitfinexDecoder{}, `[0.0000023, 100, 0.0000025, 100, 0, 0, 0.0000024, 1000, 0.0000026, 0.0000022]`, true},
+		{"bitfinex error", BitfinexDecoder{}, `["error", 10020, "symbol: invalid"]`, false},
+		{"poloniex", PoloniexDecoder{}, `{"symbol": "DOGE_BTC", "price": "0.0000024", "time": 1700000000000}`, true},
+		{"poloniex zero price", PoloniexDecoder{}, `{"symbol": "DOGE_BTC", "price": "0"}`, false},
+	} {
+		rates, err := test.decoder.Decode(decodeJSON(t, test.response), "DOGE")
+		if !test.valid {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if len(rates) != 1 || rates["BTC"] != 0.0000024 {
+			t.Errorf("%s: expected only a BTC rate of 0.0000024, got %v", test.name, rates)
+		}
+	}
+}
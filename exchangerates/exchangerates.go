@@ -0,0 +1,278 @@
+// Package exchangerates looks up the price of a coin in fiat and other currencies. Each coin is
+// priced by several rate APIs and the rate of each currency is the median of the APIs which
+// answered recently, so a single API going down or quoting a bad price does not move it.
+package exchangerates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/op/go-logging"
+	"golang.org/x/net/proxy"
+)
+
+var log = logging.MustGetLogger("exchangerates")
+
+const (
+	// DefaultMaxAge is how long the rates of a provider are used after it last answered
+	DefaultMaxAge = time.Hour
+
+	// DefaultMaxDeviation is how far, as a fraction of the median, a provider's rate may be from
+	// the median of all providers before it is ignored as an outlier
+	DefaultMaxDeviation = 0.1
+
+	// refreshInterval is how often Run fetches the rates
+	refreshInterval = time.Minute * 15
+
+	// minRefreshInterval is how soon after the last fetch GetLatestRate fetches the rates again
+	minRefreshInterval = time.Minute
+)
+
+// ErrNotTracked is returned for a currency none of the providers currently prices the coin in
+var ErrNotTracked = errors.New("Currency not tracked")
+
+// Decoder reads the rates of a coin from the response of a rate API
+type Decoder interface {
+	// Decode returns the price of one coin of code, in each currency the decoded JSON response dat
+	// quotes it in. Decoders of exchanges trading the coin against bitcoin only return its BTC
+	// price, which the fetcher converts to the other currencies.
+	Decode(dat interface{}, code string) (map[string]float64, error)
+}
+
+// Provider is a rate API and the decoder of its responses
+type Provider struct {
+	Name    string
+	URL     string
+	Decoder Decoder
+}
+
+type quote struct {
+	rates   map[string]float64
+	fetched time.Time
+}
+
+// PriceFetcher prices a coin with the median rate of its providers. It implements the
+// wallet.ExchangeRates interface.
+type PriceFetcher struct {
+	code      string
+	units     int64
+	providers []Provider
+	client    *http.Client
+
+	// bitcoin converts the rates of providers quoting the coin only in BTC. It is nil when
+	// pricing bitcoin itself.
+	bitcoin *PriceFetcher
+
+	// MaxAge and MaxDeviation default to DefaultMaxAge and DefaultMaxDeviation
+	MaxAge       time.Duration
+	MaxDeviation float64
+
+	mtx         sync.Mutex
+	quotes      map[string]quote
+	lastFetched time.Time
+}
+
+var _ = wallet.ExchangeRates(&PriceFetcher{})
+
+// NewPriceFetcher returns a fetcher pricing the coin with currency code code from providers. The
+// rates are not fetched until Run is started or the latest rates are asked for.
+func NewPriceFetcher(code string, unitsPerCoin int64, providers []Provider, dialer proxy.Dialer) *PriceFetcher {
+	var client *http.Client
+	if dialer != nil {
+		dial := dialer.Dial
+		tbTransport := &http.Transport{Dial: dial}
+		client = &http.Client{Transport: tbTransport, Timeout: time.Minute}
+	} else {
+		client = &http.Client{Timeout: time.Minute}
+	}
+	f := newPriceFetcher(code, unitsPerCoin, providers, client)
+	if f.code != "BTC" {
+		f.bitcoin = newPriceFetcher("BTC", 100000000, knownProviders["BTC"], client)
+	}
+	return f
+}
+
+func newPriceFetcher(code string, unitsPerCoin int64, providers []Provider, client *http.Client) *PriceFetcher {
+	return &PriceFetcher{
+		code:         util.NormalizeCurrencyCode(code),
+		units:        unitsPerCoin,
+		providers:    providers,
+		client:       client,
+		MaxAge:       DefaultMaxAge,
+		MaxDeviation: DefaultMaxDeviation,
+		quotes:       make(map[string]quote),
+	}
+}
+
+func (f *PriceFetcher) GetExchangeRate(currencyCode string) (float64, error) {
+	price, ok := f.rates()[util.NormalizeCurrencyCode(currencyCode)]
+	if !ok {
+		return 0, ErrNotTracked
+	}
+	return price, nil
+}
+
+// GetLatestRate fetches the rates, unless they were fetched within the last minute, and returns
+// the rate of currencyCode
+func (f *PriceFetcher) GetLatestRate(currencyCode string) (float64, error) {
+	f.mtx.Lock()
+	recent := time.Since(f.lastFetched) < minRefreshInterval
+	f.mtx.Unlock()
+	if !recent {
+		f.fetchCurrentRates()
+	}
+	return f.GetExchangeRate(currencyCode)
+}
+
+func (f *PriceFetcher) GetAllRates(cacheOK bool) (map[string]float64, error) {
+	if !cacheOK {
+		if err := f.fetchCurrentRates(); err != nil {
+			return nil, err
+		}
+	}
+	return f.rates(), nil
+}
+
+func (f *PriceFetcher) UnitsPerCoin() int64 {
+	return f.units
+}
+
+// Run fetches the rates every 15 minutes. It does not return.
+func (f *PriceFetcher) Run() {
+	f.fetchCurrentRates()
+	ticker := time.NewTicker(refreshInterval)
+	for range ticker.C {
+		f.fetchCurrentRates()
+	}
+}
+
+// fetchCurrentRates queries every provider at once and keeps the rates of those which answered
+func (f *PriceFetcher) fetchCurrentRates() error {
+	results := make([]map[string]float64, len(f.providers))
+	var wg sync.WaitGroup
+	for i, provider := range f.providers {
+		wg.Add(1)
+		go func(i int, provider Provider) {
+			defer wg.Done()
+			rates, err := f.fetch(provider)
+			if err != nil {
+				log.Warningf("fetching %s exchange rates from %s: %s", f.code, provider.Name, err)
+				return
+			}
+			results[i] = rates
+		}(i, provider)
+	}
+	wg.Wait()
+
+	// Providers quoting the coin in bitcoin alone are priced in the other currencies through
+	// bitcoin's rates
+	var btcRates map[string]float64
+	for _, rates := range results {
+		if f.bitcoin != nil && rates != nil && len(rates) == 1 && rates["BTC"] > 0 {
+			btcRates, _ = f.bitcoin.GetAllRates(false)
+			break
+		}
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	now := time.Now()
+	f.lastFetched = now
+	fetched := false
+	for i, rates := range results {
+		if rates == nil {
+			continue
+		}
+		if btc, ok := rates["BTC"]; ok && len(rates) == 1 {
+			for currency, rate := range btcRates {
+				if currency != f.code {
+					rates[currency] = btc * rate
+				}
+			}
+		}
+		f.quotes[f.providers[i].Name] = quote{rates: rates, fetched: now}
+		fetched = true
+	}
+	if !fetched {
+		return errors.New("all exchange rate API queries failed")
+	}
+	return nil
+}
+
+func (f *PriceFetcher) fetch(provider Provider) (map[string]float64, error) {
+	resp, err := f.client.Get(provider.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var dat interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&dat); err != nil {
+		return nil, err
+	}
+	return provider.Decoder.Decode(dat, f.code)
+}
+
+// rates returns the median rate of each currency quoted by a provider within MaxAge
+func (f *PriceFetcher) rates() map[string]float64 {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	byCurrency := make(map[string][]float64)
+	for _, q := range f.quotes {
+		if time.Since(q.fetched) > f.MaxAge {
+			continue
+		}
+		for currency, rate := range q.rates {
+			if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+				continue
+			}
+			byCurrency[currency] = append(byCurrency[currency], rate)
+		}
+	}
+	rates := make(map[string]float64, len(byCurrency))
+	for currency, quoted := range byCurrency {
+		rates[currency] = median(rejectOutliers(quoted, f.MaxDeviation))
+	}
+	return rates
+}
+
+// rejectOutliers drops the rates further than maxDeviation of the median from the median. At least
+// three rates are needed to tell which are wrong, and if none are close to the median there is no
+// agreement to keep, so in both cases every rate is returned.
+func rejectOutliers(rates []float64, maxDeviation float64) []float64 {
+	if len(rates) < 3 {
+		return rates
+	}
+	m := median(rates)
+	var kept []float64
+	for _, rate := range rates {
+		if math.Abs(rate-m) <= maxDeviation*m {
+			kept = append(kept, rate)
+		}
+	}
+	if len(kept) == 0 {
+		return rates
+	}
+	return kept
+}
+
+func median(rates []float64) float64 {
+	sorted := make([]float64, len(rates))
+	copy(sorted, rates)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
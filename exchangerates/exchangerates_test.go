@@ -0,0 +1,212 @@
+package exchangerates
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeServers are local rate APIs serving canned responses
+type fakeServers []*httptest.Server
+
+// provider serves body, or the status if it is not http.StatusOK, from a new server
+func (s *fakeServers) provider(name string, status int, body string, decoder Decoder) Provider {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	*s = append(*s, srv)
+	return Provider{Name: name, URL: srv.URL, Decoder: decoder}
+}
+
+func (s *fakeServers) Close() {
+	for _, srv := range *s {
+		srv.Close()
+	}
+}
+
+func coinGeckoLTC(usd, btc string) string {
+	return `{"litecoin": {"usd": ` + usd + `, "btc": ` + btc + `}}`
+}
+
+func TestPriceFetcher_Median(t *testing.T) {
+	var srvs fakeServers
+	defer srvs.Close()
+
+	f := newPriceFetcher("LTC", 100000000, []Provider{
+		srvs.provider("a", http.StatusOK, coinGeckoLTC("100", "0.002"), CoinGeckoDecoder{ID: "litecoin"}),
+		srvs.provider("b", http.StatusOK, coinGeckoLTC("102", "0.002"), CoinGeckoDecoder{ID: "litecoin"}),
+		// An outlier which would move the mean but not the median
+		srvs.provider("c", http.StatusOK, coinGeckoLTC("150", "0.002"), CoinGeckoDecoder{ID: "litecoin"}),
+	}, http.DefaultClient)
+
+	rates, err := f.GetAllRates(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates["USD"] != 101 {
+		t.Errorf("Expected the median of the providers agreeing of 101, got %f", rates["USD"])
+	}
+	if rates["BTC"] != 0.002 {
+		t.Errorf("Expected a BTC rate of 0.002, got %f", rates["BTC"])
+	}
+	rate, err := f.GetExchangeRate("usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 101 {
+		t.Errorf("Expected rate of 101 for lower case usd, got %f", rate)
+	}
+	if _, err := f.GetExchangeRate("EUR"); err != ErrNotTracked {
+		t.Errorf("Expected %v for an unquoted currency, got %v", ErrNotTracked, err)
+	}
+	if f.UnitsPerCoin() != 100000000 {
+		t.Errorf("Expected 100000000 units per coin, got %d", f.UnitsPerCoin())
+	}
+}
+
+func TestPriceFetcher_FailedProviders(t *testing.T) {
+	var srvs fakeServers
+	defer srvs.Close()
+
+	f := newPriceFetcher("LTC", 100000000, []Provider{
+		srvs.provider("down", http.StatusServiceUnavailable, "", CoinGeckoDecoder{ID: "litecoin"}),
+		srvs.provider("garbage", http.StatusOK, "<html>", CoinGeckoDecoder{ID: "litecoin"}),
+		srvs.provider("up", http.StatusOK, coinGeckoLTC("100", "0.002"), CoinGeckoDecoder{ID: "litecoin"}),
+	}, http.DefaultClient)
+
+	rate, err := f.GetLatestRate("USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 100 {
+		t.Errorf("Expected the rate of the only provider answering of 100, got %f", rate)
+	}
+
+	f = newPriceFetcher("LTC", 100000000, []Provider{
+		srvs.provider("down", http.StatusServiceUnavailable, "", CoinGeckoDecoder{ID: "litecoin"}),
+	}, http.DefaultClient)
+	if _, err := f.GetAllRates(false); err == nil {
+		t.Error("Expected an error when every provider fails")
+	}
+	if _, err := f.GetLatestRate("USD"); err != ErrNotTracked {
+		t.Errorf("Expected %v without any rates, got %v", ErrNotTracked, err)
+	}
+}
+
+func TestPriceFetcher_Stale(t *testing.T) {
+	var srvs fakeServers
+	defer srvs.Close()
+
+	f := newPriceFetcher("LTC", 100000000, []Provider{
+		srvs.provider("old", http.StatusOK, coinGeckoLTC("50", "0.001"), CoinGeckoDecoder{ID: "litecoin"}),
+		srvs.provider("new", http.StatusOK, coinGeckoLTC("100", "0.002"), CoinGeckoDecoder{ID: "litecoin"}),
+	}, http.DefaultClient)
+	if _, err := f.GetAllRates(false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first provider has not answered since before the limit
+	f.quotes["old"] = quote{rates: f.quotes["old"].rates, fetched: time.Now().Add(-2 * DefaultMaxAge)}
+	rate, err := f.GetExchangeRate("USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 100 {
+		t.Errorf("Expected the stale rate to be ignored giving 100, got %f", rate)
+	}
+
+	f.quotes["new"] = quote{rates: f.quotes["new"].rates, fetched: time.Now().Add(-2 * DefaultMaxAge)}
+	if _, err := f.GetExchangeRate("USD"); err != ErrNotTracked {
+		t.Errorf("Expected %v once every rate is stale, got %v", ErrNotTracked, err)
+	}
+	rates, err := f.GetAllRates(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 0 {
+		t.Errorf("Expected no rates once every rate is stale, got %v", rates)
+	}
+}
+
+func TestPriceFetcher_BitcoinQuotes(t *testing.T) {
+	var srvs fakeServers
+	defer srvs.Close()
+
+	ticker := `{
+		"BTC": {"last": 1},
+		"LTC": {"last": 500},
+		"USD": {"last": 50000},
+		"EUR": {"last": 40000},
+		"timestamp": "Tue, 02 Aug 2016 00:20:45 -0000"
+	}`
+	f := newPriceFetcher("LTC", 100000000, []Provider{
+		srvs.provider(Kraken, http.StatusOK, `{"error": [], "result": {"XLTCXXBT": {"c": ["0.002", "1.0"]}}}`, KrakenDecoder{}),
+	}, http.DefaultClient)
+	f.bitcoin = newPriceFetcher("BTC", 100000000, []Provider{
+		srvs.provider(OpenBazaar, http.StatusOK, ticker, OpenBazaarDecoder{}),
+	}, http.DefaultClient)
+
+	rates, err := f.GetAllRates(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for currency, expected := range map[string]float64{"BTC": 0.002, "USD": 100, "EUR": 80} {
+		if math.Abs(rates[currency]-expected) > 1e-9 {
+			t.Errorf("Expected %s rate of %f, got %f", currency, expected, rates[currency])
+		}
+	}
+	if _, ok := rates["LTC"]; ok {
+		t.Error("Coin should not be priced in itself through bitcoin")
+	}
+}
+
+func TestRejectOutliers(t *testing.T) {
+	for _, test := range []struct {
+		rates    []float64
+		expected float64
+	}{
+		{[]float64{100}, 100},
+		{[]float64{100, 200}, 150},
+		{[]float64{100, 105, 1000}, 102.5},
+		{[]float64{1, 100, 101, 102, 99}, 100.5},
+		// Without agreement every rate is kept
+		{[]float64{1, 1, 100, 100}, 50.5},
+	} {
+		if m := median(rejectOutliers(test.rates, DefaultMaxDeviation)); m != test.expected {
+			t.Errorf("Expected %f from %v, got %f", test.expected, test.rates, m)
+		}
+	}
+}
+
+func TestProviders(t *testing.T) {
+	providers, err := Providers("ltc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(providers) != len(knownProviders["LTC"]) {
+		t.Errorf("Expected every known LTC provider, got %d", len(providers))
+	}
+
+	providers, err = Providers("LTC", []string{"Kraken", CoinGecko})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(providers) != 2 || providers[0].Name != Kraken || providers[1].Name != CoinGecko {
+		t.Errorf("Expected the kraken and coingecko providers, got %v", providers)
+	}
+
+	if _, err := Providers("BCH", []string{Bitfinex}); err == nil {
+		t.Error("Expected an error for a provider not pricing the coin")
+	}
+
+	providers, err = Providers("XYZ", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(providers) != 1 || providers[0].Name != OpenBazaar {
+		t.Errorf("Expected the OpenBazaar ticker for an unknown coin, got %v", providers)
+	}
+}
@@ -0,0 +1,76 @@
+package exchangerates
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OpenBazaar/multiwallet/util"
+)
+
+// Names of the rate APIs known to the package
+const (
+	OpenBazaar = "openbazaar"
+	CoinGecko  = "coingecko"
+	Kraken     = "kraken"
+	Bitfinex   = "bitfinex"
+	Poloniex   = "poloniex"
+)
+
+// coinGeckoCurrencies are the currencies CoinGecko is asked to price a coin in
+const coinGeckoCurrencies = "btc,usd,eur,gbp,jpy,cad,aud,chf,cny,hkd,krw,inr,brl,rub,mxn,sgd,nzd,sek,nok,dkk,pln,try,zar"
+
+var openBazaarTicker = Provider{OpenBazaar, "https://ticker.openbazaar.org/api", OpenBazaarDecoder{}}
+
+func coinGecko(id string) Provider {
+	return Provider{CoinGecko, "https://api.coingecko.com/api/v3/simple/price?ids=" + id + "&vs_currencies=" + coinGeckoCurrencies, CoinGeckoDecoder{ID: id}}
+}
+
+func kraken(pair string) Provider {
+	return Provider{Kraken, "https://api.kraken.com/0/public/Ticker?pair=" + pair, KrakenDecoder{}}
+}
+
+func bitfinex(symbol string) Provider {
+	return Provider{Bitfinex, "https://api-pub.bitfinex.com/v2/ticker/t" + symbol, BitfinexDecoder{}}
+}
+
+func poloniex(symbol string) Provider {
+	return Provider{Poloniex, "https://api.poloniex.com/markets/" + symbol + "/price", PoloniexDecoder{}}
+}
+
+// knownProviders are the rate APIs pricing each coin, by currency code
+var knownProviders = map[string][]Provider{
+	"BTC":  {openBazaarTicker, coinGecko("bitcoin")},
+	"BCH":  {openBazaarTicker, coinGecko("bitcoin-cash"), kraken("BCHXBT")},
+	"LTC":  {openBazaarTicker, coinGecko("litecoin"), kraken("LTCXBT"), bitfinex("LTCBTC"), poloniex("LTC_BTC")},
+	"ZEC":  {openBazaarTicker, coinGecko("zcash"), kraken("ZECXBT")},
+	"DOGE": {openBazaarTicker, coinGecko("dogecoin"), kraken("XDGXBT"), poloniex("DOGE_BTC")},
+	"DASH": {openBazaarTicker, coinGecko("dash"), kraken("DASHXBT")},
+}
+
+// Providers returns the rate APIs named in names which price the coin with currency code code.
+// With no names every API known to price the coin is returned, which is at least the OpenBazaar
+// ticker.
+func Providers(code string, names []string) ([]Provider, error) {
+	known, ok := knownProviders[util.NormalizeCurrencyCode(code)]
+	if !ok {
+		known = []Provider{openBazaarTicker}
+	}
+	if len(names) == 0 {
+		return known, nil
+	}
+	var providers []Provider
+	for _, name := range names {
+		found := false
+		for _, p := range known {
+			if strings.EqualFold(p.Name, name) {
+				providers = append(providers, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no %s exchange rate provider named %s", code, name)
+		}
+	}
+	return providers, nil
+}
@@ -14,6 +14,7 @@ type KeyManager struct {
 	datastore wallet.Keys
 	params    *chaincfg.Params
 
+	accountKey  *hd.ExtendedKey
 	internalKey *hd.ExtendedKey
 	externalKey *hd.ExtendedKey
 
@@ -24,13 +25,22 @@ type KeyManager struct {
 type AddrFunc func(k *hd.ExtendedKey, net *chaincfg.Params) (btcutil.Address, error)
 
 func NewKeyManager(db wallet.Keys, params *chaincfg.Params, masterPrivKey *hd.ExtendedKey, coinType wallet.CoinType, getAddr AddrFunc) (*KeyManager, error) {
-	internal, external, err := Bip44Derivation(masterPrivKey, coinType)
+	account, err := bip44Account(masterPrivKey, coinType)
+	if err != nil {
+		return nil, err
+	}
+	internal, external, err := accountChains(account)
+	if err != nil {
+		return nil, err
+	}
+	accountPub, err := account.Neuter()
 	if err != nil {
 		return nil, err
 	}
 	km := &KeyManager{
 		datastore:   db,
 		params:      params,
+		accountKey:  accountPub,
 		internalKey: internal,
 		externalKey: external,
 		coinType:    coinType,
@@ -44,21 +54,31 @@ func NewKeyManager(db wallet.Keys, params *chaincfg.Params, masterPrivKey *hd.Ex
 
 // m / purpose' / coin_type' / account' / change / address_index
 func Bip44Derivation(masterPrivKey *hd.ExtendedKey, coinType wallet.CoinType) (internal, external *hd.ExtendedKey, err error) {
+	account, err := bip44Account(masterPrivKey, coinType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return accountChains(account)
+}
+
+// m / purpose' / coin_type' / account'
+func bip44Account(masterPrivKey *hd.ExtendedKey, coinType wallet.CoinType) (*hd.ExtendedKey, error) {
 	// Purpose = bip44
 	fourtyFour, err := masterPrivKey.Child(hd.HardenedKeyStart + 44)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	// Cointype
 	bitcoin, err := fourtyFour.Child(hd.HardenedKeyStart + uint32(coinType))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	// Account = 0
-	account, err := bitcoin.Child(hd.HardenedKeyStart + 0)
-	if err != nil {
-		return nil, nil, err
-	}
+	return bitcoin.Child(hd.HardenedKeyStart + 0)
+}
+
+// account' / change
+func accountChains(account *hd.ExtendedKey) (internal, external *hd.ExtendedKey, err error) {
 	// Change(0) = external
 	external, err = account.Child(0)
 	if err != nil {
@@ -72,6 +92,12 @@ func Bip44Derivation(masterPrivKey *hd.ExtendedKey, coinType wallet.CoinType) (i
 	return internal, external, nil
 }
 
+// AccountXPub returns the serialized extended public key for the wallet's BIP44 account,
+// suitable for servers which can scan every address derived from it
+func (km *KeyManager) AccountXPub() string {
+	return km.accountKey.String()
+}
+
 func (km *KeyManager) GetCurrentKey(purpose wallet.KeyPurpose) (*hd.ExtendedKey, error) {
 	i, err := km.datastore.GetUnused(purpose)
 	if err != nil {
@@ -0,0 +1,100 @@
+// Package mweb reads litecoin transactions carrying MimbleWimble Extension Block data.
+//
+// Since MWEB activated, litecoin extends the segwit serialization of a transaction with a third
+// flag bit. When it is set the serialized MWEB transaction follows the witnesses, just before the
+// lock time (LIP-0003). The integrating HogEx transaction of each block sets the bit with an empty
+// MWEB transaction, and peg-ins and peg-outs move coins between canonical outputs and the MWEB.
+// btcd's wire package rejects any flag but the witness one, so such transactions are reduced to
+// their canonical part before they are decoded. The txid only commits to the canonical part, so
+// it is unchanged.
+package mweb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// witnessFlag is set in the flag byte when the transaction has witnesses
+	witnessFlag = 0x01
+
+	// mwebFlag is set in the flag byte when the transaction carries MWEB data
+	mwebFlag = 0x08
+)
+
+var (
+	// ErrNoCanonicalData describes a pure MWEB transaction, which has neither
+	// canonical inputs nor outputs and so nothing a wallet watching canonical
+	// addresses can use.
+	ErrNoCanonicalData = errors.New("mweb: transaction has no canonical inputs or outputs")
+
+	// ErrMalformed describes a transaction whose MWEB flag is set but which
+	// leaves no room for the MWEB data and lock time.
+	ErrMalformed = errors.New("mweb: malformed transaction")
+)
+
+// HasMWEB reports whether the serialized transaction raw sets the MWEB flag
+func HasMWEB(raw []byte) bool {
+	// version, marker, flag
+	return len(raw) > 5 && raw[4] == 0x00 && raw[5]&mwebFlag != 0
+}
+
+// Strip returns the transaction raw without its MWEB data, serialized as btcd does. Transactions
+// without MWEB data are returned unchanged.
+func Strip(raw []byte) ([]byte, error) {
+	if !HasMWEB(raw) {
+		return raw, nil
+	}
+	tx, err := Decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tx.BtcEncode(&buf, wire.ProtocolVersion, wire.WitnessEncoding); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode decodes the canonical part of a litecoin transaction, skipping any MWEB data
+func Decode(raw []byte) (*wire.MsgTx, error) {
+	tx := wire.NewMsgTx(1)
+	if !HasMWEB(raw) {
+		if err := tx.BtcDecode(bytes.NewReader(raw), wire.ProtocolVersion, wire.WitnessEncoding); err != nil {
+			return nil, err
+		}
+		return tx, nil
+	}
+
+	// Decode the canonical fields with the MWEB flag cleared, or without the
+	// marker and flag altogether if there are no witnesses either
+	flags := raw[5] &^ mwebFlag
+	enc := wire.WitnessEncoding
+	canonical := make([]byte, 0, len(raw))
+	canonical = append(canonical, raw[:4]...)
+	if flags == 0 {
+		enc = wire.BaseEncoding
+	} else {
+		canonical = append(canonical, 0x00, flags)
+	}
+	canonical = append(canonical, raw[6:]...)
+
+	r := bytes.NewReader(canonical)
+	if err := tx.BtcDecode(r, wire.ProtocolVersion, enc); err != nil {
+		return nil, err
+	}
+	if len(tx.TxIn) == 0 && len(tx.TxOut) == 0 {
+		return nil, ErrNoCanonicalData
+	}
+
+	// The decoder took the first four bytes of the MWEB data for the lock
+	// time. At least its one byte presence flag must remain before the real one.
+	if r.Len() < 1 {
+		return nil, ErrMalformed
+	}
+	tx.LockTime = binary.LittleEndian.Uint32(raw[len(raw)-4:])
+	return tx, nil
+}
@@ -0,0 +1,132 @@
+package mweb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// canonicalTx returns a transaction spending one input to a P2WPKH output and a peg-in output
+func canonicalTx(witness bool) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	in := wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 1), nil, nil)
+	if witness {
+		in.Witness = wire.TxWitness{[]byte{0x30, 0x01}, []byte{0x02, 0x03}}
+	}
+	tx.AddTxIn(in)
+	tx.AddTxOut(wire.NewTxOut(100000, append([]byte{0x00, 0x14}, make([]byte, 20)...)))
+	// A peg-in pays the MWEB kernel hash with witness version 9
+	tx.AddTxOut(wire.NewTxOut(50000, append([]byte{0x59, 0x20}, make([]byte, 32)...)))
+	tx.LockTime = 1234567
+	return tx
+}
+
+// withMWEB serializes tx as litecoin does with the MWEB flag set and mwebData after the witnesses
+func withMWEB(t *testing.T, tx *wire.MsgTx, mwebData []byte) []byte {
+	var buf bytes.Buffer
+	if err := tx.BtcEncode(&buf, wire.ProtocolVersion, wire.WitnessEncoding); err != nil {
+		t.Fatal(err)
+	}
+	raw := buf.Bytes()
+	var out []byte
+	out = append(out, raw[:4]...)
+	if tx.HasWitness() {
+		out = append(out, 0x00, witnessFlag|mwebFlag)
+		out = append(out, raw[6:len(raw)-4]...)
+	} else {
+		out = append(out, 0x00, mwebFlag)
+		out = append(out, raw[4:len(raw)-4]...)
+	}
+	out = append(out, mwebData...)
+	return append(out, raw[len(raw)-4:]...)
+}
+
+func serialize(t *testing.T, tx *wire.MsgTx) []byte {
+	var buf bytes.Buffer
+	if err := tx.BtcEncode(&buf, wire.ProtocolVersion, wire.WitnessEncoding); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestStrip(t *testing.T) {
+	// Not a real MWEB transaction, only a body for the decoder to skip
+	mwebTx := append([]byte{0x01}, bytes.Repeat([]byte{0xab}, 300)...)
+
+	for _, test := range []struct {
+		name     string
+		witness  bool
+		mwebData []byte
+	}{
+		{"witness and MWEB transaction", true, mwebTx},
+		{"peg-out without witnesses", false, mwebTx},
+		// The HogEx only sets the flag, with an absent MWEB transaction
+		{"HogEx", false, []byte{0x00}},
+		{"HogEx with witnesses", true, []byte{0x00}},
+	} {
+		tx := canonicalTx(test.witness)
+		raw := withMWEB(t, tx, test.mwebData)
+		if !HasMWEB(raw) {
+			t.Errorf("%s: MWEB flag not detected", test.name)
+			continue
+		}
+		stripped, err := Strip(raw)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if !bytes.Equal(stripped, serialize(t, tx)) {
+			t.Errorf("%s: expected %x, got %x", test.name, serialize(t, tx), stripped)
+		}
+		decoded, err := Decode(raw)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if decoded.TxHash() != tx.TxHash() {
+			t.Errorf("%s: expected txid %s, got %s", test.name, tx.TxHash(), decoded.TxHash())
+		}
+		if decoded.LockTime != tx.LockTime {
+			t.Errorf("%s: expected lock time %d, got %d", test.name, tx.LockTime, decoded.LockTime)
+		}
+		if len(decoded.TxOut) != 2 || decoded.TxOut[1].Value != 50000 {
+			t.Errorf("%s: peg-in output not decoded", test.name)
+		}
+	}
+}
+
+func TestStripWithoutMWEB(t *testing.T) {
+	for _, witness := range []bool{true, false} {
+		raw := serialize(t, canonicalTx(witness))
+		if HasMWEB(raw) {
+			t.Error("MWEB flag detected in a canonical transaction")
+		}
+		stripped, err := Strip(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(stripped, raw) {
+			t.Error("Transaction without MWEB data was changed")
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	// A pure MWEB transaction has no canonical inputs or outputs
+	raw := []byte{0x02, 0x00, 0x00, 0x00, 0x00, mwebFlag, 0x00, 0x00, 0x01, 0xab, 0xab, 0xab, 0xab, 0x00, 0x00, 0x00, 0x00}
+	if _, err := Decode(raw); err != ErrNoCanonicalData {
+		t.Errorf("Expected %v, got %v", ErrNoCanonicalData, err)
+	}
+
+	// The flag is set but the lock time directly follows the witnesses
+	tx := canonicalTx(true)
+	raw = withMWEB(t, tx, nil)
+	if _, err := Decode(raw); err != ErrMalformed {
+		t.Errorf("Expected %v, got %v", ErrMalformed, err)
+	}
+	if _, err := Strip(raw[:20]); err == nil {
+		t.Error("Expected an error stripping a truncated transaction")
+	}
+}
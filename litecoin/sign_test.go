@@ -3,6 +3,7 @@ package litecoin
 import (
 	"bytes"
 	"encoding/hex"
+	"math/big"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/OpenBazaar/multiwallet/model/mock"
 	"github.com/OpenBazaar/multiwallet/service"
 	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/multiwallet/utxo"
 	"github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -20,6 +22,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/op/go-logging"
 )
 
 type FeeResponse struct {
@@ -53,19 +56,21 @@ func newMockWallet() (*LitecoinWallet, error) {
 
 	fp := util.NewFeeProvider(2000, 300, 200, 100, nil)
 
-	bw := &LitecoinWallet{
-		params: params,
-		km:     km,
-		db:     db,
-		fp:     fp,
-	}
+	bw := &LitecoinWallet{&utxo.Wallet{
+		Coin:        litecoinCoin{},
+		ChainParams: params,
+		KM:          km,
+		DB:          db,
+		Fees:        fp,
+		Log:         logging.MustGetLogger("litecoin-wallet"),
+	}}
 	cli := mock.NewMockApiClient(bw.AddressToScript)
 	ws, err := service.NewWalletService(db, km, cli, params, wallet.Litecoin, cache.NewMockCacher())
 	if err != nil {
 		return nil, err
 	}
-	bw.client = cli
-	bw.ws = ws
+	bw.Client = cli
+	bw.WS = ws
 	return bw, nil
 }
 
@@ -75,9 +80,9 @@ func TestWalletService_VerifyWatchScriptFilter(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	keys := w.km.GetKeys()
+	keys := w.KM.GetKeys()
 
-	addr, err := w.km.KeyToAddress(keys[0])
+	addr, err := w.KM.KeyToAddress(keys[0])
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -86,7 +91,7 @@ func TestWalletService_VerifyWatchScriptFilter(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	watchScripts, err := w.db.WatchedScripts().GetAll()
+	watchScripts, err := w.DB.WatchedScripts().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -113,7 +118,7 @@ func TestWalletService_VerifyWatchScriptPut(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	watchScripts, err := w.db.WatchedScripts().GetAll()
+	watchScripts, err := w.DB.WatchedScripts().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -149,36 +154,36 @@ func TestLitecoinWallet_buildTx(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
-	waitForTxnSync(t, w.db.Txns())
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
 	addr, err := w.DecodeAddress("Lep9b95MtHofxS72Hjdg4Wfmr43sHetrZT")
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test build normal tx
-	tx, err := w.buildTx(1500000, addr, wallet.NORMAL, nil)
+	tx, err := w.BuildTx(1500000, addr, wallet.NORMAL, nil)
 	if err != nil {
 		t.Error(err)
 	}
 	if !containsOutput(tx, addr) {
 		t.Error("Built tx does not contain the requested output")
 	}
-	if !validInputs(tx, w.db) {
+	if !validInputs(tx, w.DB) {
 		t.Error("Built tx does not contain valid inputs")
 	}
-	if !validChangeAddress(tx, w.db, w.params) {
+	if !validChangeAddress(tx, w.DB, w.ChainParams) {
 		t.Error("Built tx does not contain a valid change output")
 	}
 
 	// Insuffient funds
-	_, err = w.buildTx(1000000000, addr, wallet.NORMAL, nil)
+	_, err = w.BuildTx(1000000000, addr, wallet.NORMAL, nil)
 	if err != wallet.ErrorInsuffientFunds {
 		t.Error("Failed to throw insuffient funds error")
 	}
 
 	// Dust
-	_, err = w.buildTx(1, addr, wallet.NORMAL, nil)
+	_, err = w.BuildTx(1, addr, wallet.NORMAL, nil)
 	if err != wallet.ErrorDustAmount {
 		t.Error("Failed to throw dust error")
 	}
@@ -189,21 +194,21 @@ func TestLitecoinWallet_buildSpendAllTx(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 
-	waitForTxnSync(t, w.db.Txns())
+	waitForTxnSync(t, w.DB.Txns())
 	addr, err := w.DecodeAddress("Lep9b95MtHofxS72Hjdg4Wfmr43sHetrZT")
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test build spendAll tx
-	tx, err := w.buildSpendAllTx(addr, wallet.NORMAL)
+	tx, err := w.BuildSpendAllTx(addr, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -219,7 +224,7 @@ func TestLitecoinWallet_buildSpendAllTx(t *testing.T) {
 	if !containsOutput(tx, addr) {
 		t.Error("Built tx does not contain the requested output")
 	}
-	if !validInputs(tx, w.db) {
+	if !validInputs(tx, w.DB) {
 		t.Error("Built tx does not contain valid inputs")
 	}
 	if len(tx.TxOut) != 1 {
@@ -291,7 +296,7 @@ func TestLitecoinWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -299,7 +304,7 @@ func TestLitecoinWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -307,7 +312,7 @@ func TestLitecoinWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key3, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key3, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -318,7 +323,7 @@ func TestLitecoinWallet_GenerateMultisigScript(t *testing.T) {
 	keys := []hdkeychain.ExtendedKey{*key1, *key2, *key3}
 
 	// test without timeout
-	addr, redeemScript, err := w.generateMultisigScript(keys, 2, 0, nil)
+	addr, redeemScript, err := w.GenerateMultisigScript(keys, 2, 0, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -341,7 +346,7 @@ func TestLitecoinWallet_GenerateMultisigScript(t *testing.T) {
 	}
 
 	// test with timeout
-	key4, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key4, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -349,7 +354,7 @@ func TestLitecoinWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	addr, redeemScript, err = w.generateMultisigScript(keys, 2, time.Hour*10, key4)
+	addr, redeemScript, err = w.GenerateMultisigScript(keys, 2, time.Hour*10, key4)
 	if err != nil {
 		t.Error(err)
 	}
@@ -387,9 +392,9 @@ func TestLitecoinWallet_newUnsignedTransaction(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
-	waitForTxnSync(t, w.db.Txns())
-	utxos, err := w.db.Utxos().GetAll()
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
@@ -423,7 +428,7 @@ func TestLitecoinWallet_newUnsignedTransaction(t *testing.T) {
 	}
 
 	// Regular transaction
-	authoredTx, err := newUnsignedTransaction(outputs, btcutil.Amount(1000), inputSource, changeSource)
+	authoredTx, err := utxo.NewUnsignedTransaction(litecoinFeatures, outputs, btcutil.Amount(1000), inputSource, changeSource)
 	if err != nil {
 		t.Error(err)
 	}
@@ -436,7 +441,7 @@ func TestLitecoinWallet_newUnsignedTransaction(t *testing.T) {
 
 	// Insufficient funds
 	outputs[0].Value = 1000000000
-	_, err = newUnsignedTransaction(outputs, btcutil.Amount(1000), inputSource, changeSource)
+	_, err = utxo.NewUnsignedTransaction(litecoinFeatures, outputs, btcutil.Amount(1000), inputSource, changeSource)
 	if err == nil {
 		t.Error("Failed to return insuffient funds error")
 	}
@@ -452,7 +457,7 @@ func TestLitecoinWallet_CreateMultisigSignature(t *testing.T) {
 		t.Error(err)
 	}
 
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -515,12 +520,12 @@ func TestLitecoinWallet_Multisign(t *testing.T) {
 		t.Error(err)
 	}
 
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
 
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -564,9 +569,9 @@ func TestLitecoinWallet_bumpFee(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
-	waitForTxnSync(t, w.db.Txns())
-	txns, err := w.db.Txns().GetAll(false)
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	txns, err := w.DB.Txns().GetAll(false)
 	if err != nil {
 		t.Error(err)
 	}
@@ -574,32 +579,32 @@ func TestLitecoinWallet_bumpFee(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
 	for _, u := range utxos {
 		if u.Op.Hash.IsEqual(ch) {
 			u.AtHeight = 0
-			w.db.Utxos().Put(u)
+			w.DB.Utxos().Put(u)
 		}
 	}
 
-	w.db.Txns().UpdateHeight(*ch, 0, time.Now())
+	w.DB.Txns().UpdateHeight(*ch, 0, time.Now())
 
 	// Test unconfirmed
-	_, err = w.bumpFee(*ch)
+	_, err = w.BumpFee(*ch)
 	if err != nil {
 		t.Error(err)
 	}
 
-	err = w.db.Txns().UpdateHeight(*ch, 1289597, time.Now())
+	err = w.DB.Txns().UpdateHeight(*ch, 1289597, time.Now())
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test confirmed
-	_, err = w.bumpFee(*ch)
+	_, err = w.BumpFee(*ch)
 	if err == nil {
 		t.Error("Should not be able to bump fee of confirmed txs")
 	}
@@ -610,9 +615,9 @@ func TestLitecoinWallet_sweepAddress(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
-	waitForTxnSync(t, w.db.Txns())
-	utxos, err := w.db.Utxos().GetAll()
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
@@ -625,7 +630,7 @@ func TestLitecoinWallet_sweepAddress(t *testing.T) {
 			if err != nil {
 				t.Error(err)
 			}
-			key, err = w.km.GetKeyForScript(addr.ScriptAddress())
+			key, err = w.KM.GetKeyForScript(addr.ScriptAddress())
 			if err != nil {
 				t.Error(err)
 			}
@@ -642,7 +647,7 @@ func TestLitecoinWallet_sweepAddress(t *testing.T) {
 		}
 	}
 	// P2PKH addr
-	_, err = w.sweepAddress([]wallet.TransactionInput{in}, nil, key, nil, wallet.NORMAL)
+	_, err = w.SweepAddress([]wallet.TransactionInput{in}, nil, key, nil, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 		return
@@ -667,12 +672,12 @@ func TestLitecoinWallet_sweepAddress(t *testing.T) {
 			}
 		}
 	}
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
 
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -680,7 +685,7 @@ func TestLitecoinWallet_sweepAddress(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	_, err = w.sweepAddress([]wallet.TransactionInput{in}, nil, key1, &redeemScript, wallet.NORMAL)
+	_, err = w.SweepAddress([]wallet.TransactionInput{in}, nil, key1, &redeemScript, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -691,13 +696,13 @@ func TestLitecoinWallet_estimateSpendFee(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
-	waitForTxnSync(t, w.db.Txns())
-	fee, err := w.estimateSpendFee(1000, wallet.NORMAL)
+	w.WS.Start()
+	waitForTxnSync(t, w.DB.Txns())
+	fee, err := w.EstimateSpendFee(*big.NewInt(1000), wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
-	if fee <= 0 {
+	if fee.Sign() <= 0 {
 		t.Error("Returned incorrect fee")
 	}
 }
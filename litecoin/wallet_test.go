@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"github.com/OpenBazaar/multiwallet/datastore"
 	"github.com/OpenBazaar/multiwallet/keys"
+	"github.com/OpenBazaar/multiwallet/utxo"
 	"github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcutil/hdkeychain"
@@ -22,7 +23,7 @@ func TestLitecoinWallet_CurrentAddress(t *testing.T) {
 		if strings.HasPrefix(strings.ToLower(addr.String()), "ltc1") {
 			t.Errorf("Address %s hash ltc1 prefix: seed %x", addr, seed)
 		}
-		if err := w.db.Keys().MarkKeyAsUsed(addr.ScriptAddress()); err != nil {
+		if err := w.DB.Keys().MarkKeyAsUsed(addr.ScriptAddress()); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -63,9 +64,9 @@ func createWalletAndSeed() (*LitecoinWallet, []byte, error) {
 		return nil, nil, err
 	}
 
-	return &LitecoinWallet{
-		db:     db,
-		km:     km,
-		params: &chaincfg.MainNetParams,
-	}, seed, nil
+	return &LitecoinWallet{&utxo.Wallet{
+		DB:          db,
+		KM:          km,
+		ChainParams: &chaincfg.MainNetParams,
+	}}, seed, nil
 }
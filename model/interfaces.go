@@ -1,6 +1,11 @@
 package model
 
-import "github.com/btcsuite/btcutil"
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
 
 type APIClient interface {
 
@@ -45,6 +50,76 @@ type APIClient interface {
 	Close()
 }
 
+// AccountAPIClient is implemented by clients which can query every address derived from
+// an account extended public key in a single call rather than one address at a time
+type AccountAPIClient interface {
+
+	// Get back the addresses derived from the xpub which the server scanned
+	GetAccountAddresses(xpub string) ([]string, error)
+
+	// Get back the transactions for the addresses derived from the xpub which confirmed at or
+	// after fromHeight along with any unconfirmed transactions. A fromHeight of zero returns
+	// the full history.
+	GetAccountTransactions(xpub string, fromHeight int) ([]Transaction, error)
+
+	// Get back all spendable UTXOs for the addresses derived from the xpub
+	GetAccountUtxos(xpub string) ([]Utxo, error)
+}
+
+// IncrementalAPIClient is implemented by clients which can limit history queries to
+// transactions confirmed at or after a given height
+type IncrementalAPIClient interface {
+
+	// Get back the transactions for the given list of addresses which confirmed at or after
+	// fromHeight along with any unconfirmed transactions
+	GetTransactionsSince(addrs []btcutil.Address, fromHeight int) ([]Transaction, error)
+}
+
+// BlockHashAPIClient is implemented by clients which can look up the hash of the block at a
+// height on the server's best chain
+type BlockHashAPIClient interface {
+
+	// Get the hash of the best chain's block at the given height
+	GetBlockHash(height int) (string, error)
+}
+
+// ReconnectNotifier is implemented by clients whose notification connection can drop and be
+// re-established. Blocks and transactions announced while disconnected are never delivered
+// so listeners should query for anything they missed.
+type ReconnectNotifier interface {
+
+	// Returns a channel which receives the time the connection was lost each time it is
+	// re-established
+	ReconnectNotify() <-chan time.Time
+}
+
+// TransactionSearchAPIClient is implemented by clients backed by several servers which can
+// look for a transaction on each of them rather than only the one in use
+type TransactionSearchAPIClient interface {
+
+	// Get back the transaction from the first server which knows of it
+	FindTransaction(txid string) (*Transaction, error)
+}
+
+// FrozenUtxoStore is implemented by utxo stores which can persist the outputs the user has frozen
+// so that coin selection leaves them alone. Wallets whose datastore lacks it keep the frozen
+// outputs in their cache instead.
+type FrozenUtxoStore interface {
+	Freeze(op wire.OutPoint) error
+	Unfreeze(op wire.OutPoint) error
+	GetFrozen() ([]wire.OutPoint, error)
+}
+
+// TxnRateStore is implemented by txn stores which can persist the exchange rates recorded when
+// each transaction was first seen. Wallets whose datastore lacks it keep the rates in their cache
+// instead.
+type TxnRateStore interface {
+	PutRates(txid string, snapshot RateSnapshot) error
+
+	// GetRates returns nil if no rates were recorded for the transaction
+	GetRates(txid string) (*RateSnapshot, error)
+}
+
 type SocketClient interface {
 
 	// Set callback for method
@@ -0,0 +1,272 @@
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/btcsuite/btcutil"
+)
+
+// ScriptedAPIClient is an APIClient backed by a chain and transaction history which
+// tests can rewrite between calls to simulate new blocks, confirmations and reorgs.
+// Transactions are confirmed by setting their BlockHash; heights and confirmations
+// are derived from the current chain so a transaction in an orphaned block reverts
+// to unconfirmed just as it would on a real server.
+type ScriptedAPIClient struct {
+	lock      sync.Mutex
+	blocks    []model.Block
+	txs       []model.Transaction
+	blockChan chan model.Block
+	txChan    chan model.Transaction
+
+	Broadcasts   [][]byte
+	broadcastErr error
+}
+
+// NewScriptedAPIClient returns a client whose chain starts with a block at startHeight
+func NewScriptedAPIClient(startHeight int, startHash string) *ScriptedAPIClient {
+	return &ScriptedAPIClient{
+		blocks:    []model.Block{{Hash: startHash, Height: startHeight}},
+		blockChan: make(chan model.Block),
+		txChan:    make(chan model.Transaction),
+	}
+}
+
+// Extend mines blocks with the given hashes on top of the current tip and returns them
+func (m *ScriptedAPIClient) Extend(hashes ...string) []model.Block {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var mined []model.Block
+	for _, hash := range hashes {
+		tip := m.blocks[len(m.blocks)-1]
+		block := model.Block{Hash: hash, Height: tip.Height + 1, PreviousBlockhash: tip.Hash}
+		m.blocks = append(m.blocks, block)
+		mined = append(mined, block)
+	}
+	return mined
+}
+
+// Reorg discards every block above forkHeight and mines blocks with the given hashes
+// in their place
+func (m *ScriptedAPIClient) Reorg(forkHeight int, hashes ...string) []model.Block {
+	m.lock.Lock()
+	for i, b := range m.blocks {
+		if b.Height == forkHeight {
+			m.blocks = m.blocks[:i+1]
+			break
+		}
+	}
+	m.lock.Unlock()
+	return m.Extend(hashes...)
+}
+
+// AddTransaction adds tx to the history, confirming it in the block with blockHash
+// or leaving it unconfirmed if blockHash is empty
+func (m *ScriptedAPIClient) AddTransaction(tx model.Transaction, blockHash string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tx.BlockHash = blockHash
+	m.txs = append(m.txs, tx)
+}
+
+// Confirm moves an existing transaction into the block with blockHash
+func (m *ScriptedAPIClient) Confirm(txid, blockHash string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i := range m.txs {
+		if m.txs[i].Txid == txid {
+			m.txs[i].BlockHash = blockHash
+		}
+	}
+}
+
+// Remove drops a transaction from the history, as if it was evicted from the mempool
+func (m *ScriptedAPIClient) Remove(txid string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i := range m.txs {
+		if m.txs[i].Txid == txid {
+			m.txs = append(m.txs[:i], m.txs[i+1:]...)
+			return
+		}
+	}
+}
+
+// SendBlock notifies the listener of a block as the server's websocket would
+func (m *ScriptedAPIClient) SendBlock(block model.Block) { m.blockChan <- block }
+
+// SendTransaction notifies the listener of a transaction as the server's websocket would
+func (m *ScriptedAPIClient) SendTransaction(txid string) {
+	tx, err := m.GetTransaction(txid)
+	if err != nil {
+		panic(err)
+	}
+	m.txChan <- *tx
+}
+
+// withChainState fills in the height and confirmations of tx from the current chain.
+// The caller must hold the lock.
+func (m *ScriptedAPIClient) withChainState(tx model.Transaction) model.Transaction {
+	tx.BlockHeight, tx.Confirmations = 0, 0
+	tip := m.blocks[len(m.blocks)-1]
+	for _, b := range m.blocks {
+		if tx.BlockHash != "" && b.Hash == tx.BlockHash {
+			tx.BlockHeight = b.Height
+			tx.Confirmations = tip.Height - b.Height + 1
+			tx.BlockTime = b.Time
+		}
+	}
+	if tx.Confirmations == 0 {
+		tx.BlockHash = ""
+	}
+	return tx
+}
+
+func touchesAddress(tx model.Transaction, addrs []btcutil.Address) bool {
+	for _, addr := range addrs {
+		for _, in := range tx.Inputs {
+			if in.Addr == addr.String() {
+				return true
+			}
+		}
+		for _, out := range tx.Outputs {
+			for _, a := range out.ScriptPubKey.Addresses {
+				if a == addr.String() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (m *ScriptedAPIClient) Start() error {
+	return nil
+}
+
+func (m *ScriptedAPIClient) GetInfo() (*model.Info, error) {
+	return &MockInfo, nil
+}
+
+func (m *ScriptedAPIClient) GetTransaction(txid string) (*model.Transaction, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, tx := range m.txs {
+		if tx.Txid == txid {
+			ret := m.withChainState(tx)
+			return &ret, nil
+		}
+	}
+	return nil, fmt.Errorf("tx %s not found", txid)
+}
+
+func (m *ScriptedAPIClient) GetRawTransaction(txid string) ([]byte, error) {
+	tx, err := m.GetTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+	return tx.RawBytes, nil
+}
+
+func (m *ScriptedAPIClient) GetTransactions(addrs []btcutil.Address) ([]model.Transaction, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var txs []model.Transaction
+	for _, tx := range m.txs {
+		if touchesAddress(tx, addrs) {
+			txs = append(txs, m.withChainState(tx))
+		}
+	}
+	return txs, nil
+}
+
+func (m *ScriptedAPIClient) GetUtxos(addrs []btcutil.Address) ([]model.Utxo, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	spent := make(map[string]bool)
+	for _, tx := range m.txs {
+		for _, in := range tx.Inputs {
+			spent[fmt.Sprintf("%s:%d", in.Txid, in.Vout)] = true
+		}
+	}
+	var utxos []model.Utxo
+	for _, tx := range m.txs {
+		tx = m.withChainState(tx)
+		for _, out := range tx.Outputs {
+			if spent[fmt.Sprintf("%s:%d", tx.Txid, out.N)] || len(out.ScriptPubKey.Addresses) == 0 {
+				continue
+			}
+			for _, addr := range addrs {
+				if out.ScriptPubKey.Addresses[0] == addr.String() {
+					utxos = append(utxos, model.Utxo{
+						Address:       addr.String(),
+						Txid:          tx.Txid,
+						Vout:          out.N,
+						ScriptPubKey:  out.ScriptPubKey.Hex,
+						Amount:        out.Value,
+						Satoshis:      int64(out.Value * 100000000),
+						Confirmations: tx.Confirmations,
+					})
+				}
+			}
+		}
+	}
+	return utxos, nil
+}
+
+func (m *ScriptedAPIClient) BlockNotify() <-chan model.Block {
+	return m.blockChan
+}
+
+func (m *ScriptedAPIClient) TransactionNotify() <-chan model.Transaction {
+	return m.txChan
+}
+
+func (m *ScriptedAPIClient) ListenAddresses(addrs ...btcutil.Address) {}
+
+// FailBroadcasts makes every following broadcast return err, or succeed again if err is nil
+func (m *ScriptedAPIClient) FailBroadcasts(err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.broadcastErr = err
+}
+
+// BroadcastCount returns the number of transactions broadcast so far
+func (m *ScriptedAPIClient) BroadcastCount() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.Broadcasts)
+}
+
+func (m *ScriptedAPIClient) Broadcast(tx []byte) (string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.Broadcasts = append(m.Broadcasts, tx)
+	return "", m.broadcastErr
+}
+
+func (m *ScriptedAPIClient) GetBestBlock() (*model.Block, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tip := m.blocks[len(m.blocks)-1]
+	return &tip, nil
+}
+
+func (m *ScriptedAPIClient) GetBlockHash(height int) (string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, b := range m.blocks {
+		if b.Height == height {
+			return b.Hash, nil
+		}
+	}
+	return "", errors.New("block not found")
+}
+
+func (m *ScriptedAPIClient) EstimateFee(nBlocks int) (int, error) {
+	return nBlocks, nil
+}
+
+func (m *ScriptedAPIClient) Close() {}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Status struct {
 	Info Info `json:"info"`
 }
@@ -169,3 +171,9 @@ type AddressTxid struct {
 	Address string `json:"address"`
 	Txid    string `json:"txid"`
 }
+
+// RateSnapshot is the price of a coin in each currency at the time a transaction was first seen
+type RateSnapshot struct {
+	Rates    map[string]float64 `json:"rates"`
+	Recorded time.Time          `json:"recorded"`
+}
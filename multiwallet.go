@@ -8,7 +8,9 @@ import (
 	eth "github.com/OpenBazaar/go-ethwallet/wallet"
 	"github.com/OpenBazaar/multiwallet/bitcoin"
 	"github.com/OpenBazaar/multiwallet/bitcoincash"
+	"github.com/OpenBazaar/multiwallet/bitcoinfork"
 	"github.com/OpenBazaar/multiwallet/client/blockbook"
+	"github.com/OpenBazaar/multiwallet/coins"
 	"github.com/OpenBazaar/multiwallet/config"
 	"github.com/OpenBazaar/multiwallet/litecoin"
 	"github.com/OpenBazaar/multiwallet/service"
@@ -98,6 +100,16 @@ func NewMultiWallet(cfg *config.Config) (MultiWallet, error) {
 			} else {
 				multiwallet[wallet.TestnetEthereum] = w
 			}
+		default:
+			c, ok := coins.Lookup(coin.CoinType)
+			if !ok {
+				return nil, UnsuppertedCoinError
+			}
+			w, err = bitcoinfork.NewWallet(c, coin, cfg.Mnemonic, cfg.Params, cfg.Proxy, cfg.Cache, cfg.DisableExchangeRates)
+			if err != nil {
+				return nil, err
+			}
+			multiwallet[c.WalletCoinType(cfg.Params)] = w
 		}
 	}
 	return multiwallet, nil
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// defaultAbandonAfterBlocks is how many blocks an unconfirmed transaction may be missing from
+// the mempool of every server before it is abandoned
+const defaultAbandonAfterBlocks = 144
+
+// SetAbandonAfter sets how many blocks an unconfirmed transaction may be missing from the
+// mempool of every server before it is abandoned. Zero keeps the default. It should be called
+// before the service is started.
+func (ws *WalletService) SetAbandonAfter(blocks uint32) {
+	if blocks > 0 {
+		ws.abandonAfter = blocks
+	}
+}
+
+// findTransaction looks the transaction up on every server the client knows of if it can,
+// otherwise on the server in use
+func (ws *WalletService) findTransaction(txid string) (*model.Transaction, error) {
+	if finder, ok := ws.client.(model.TransactionSearchAPIClient); ok {
+		return finder.FindTransaction(txid)
+	}
+	return ws.client.GetTransaction(txid)
+}
+
+// AbandonTransaction gives up on one of our unconfirmed transactions which is not in any
+// server's mempool. It is marked dead, the coins it spent become spendable again and the
+// listeners are notified.
+func (ws *WalletService) AbandonTransaction(txid string) error {
+	txHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return err
+	}
+	txn, err := ws.db.Txns().Get(*txHash)
+	if err != nil {
+		return fmt.Errorf("transaction %s not found", txid)
+	}
+	switch {
+	case txn.Height > 0:
+		return errors.New("cannot abandon a confirmed transaction")
+	case txn.Height < 0:
+		return errors.New("transaction is already dead")
+	}
+	_, err = ws.findTransaction(txid)
+	if err == nil {
+		return errors.New("cannot abandon a transaction which is still in the mempool")
+	}
+	if clientErr.KindOf(err) == clientErr.KindBackendUnavailable {
+		return err
+	}
+	ws.abandon(txn, "abandoned by the user")
+	return nil
+}
+
+// abandon marks the transaction dead, returns the coins it spent to our utxos and notifies the
+// listeners
+func (ws *WalletService) abandon(txn wallet.Txn, reason string) {
+	Log.Warningf("abandoning %s tx %s: %s", util.CurrencyCode(ws.coinType), txn.Txid, reason)
+	if !ws.setDead(txn) {
+		return
+	}
+	ws.restoreInputs(txn.Txid)
+	ws.forgetBroadcast(txn.Txid)
+	ws.notifyDead(txn)
+}
+
+// restoreInputs saves the outputs of ours which txid spent as utxos again. The outputs are
+// looked up on the server since the transactions which created them may not be stored.
+func (ws *WalletService) restoreInputs(txid string) {
+	addrs := ws.getStoredAddresses()
+	ws.lock.RLock()
+	chainHeight := int32(ws.chainHeight)
+	ws.lock.RUnlock()
+
+	for _, op := range ws.spentBy(txid) {
+		parent, err := ws.client.GetTransaction(op.Hash.String())
+		if err != nil {
+			Log.Warningf("looking up %s coin %s spent by abandoned tx: %s", util.CurrencyCode(ws.coinType), op.String(), err.Error())
+			continue
+		}
+		for _, out := range parent.Outputs {
+			if out.N != int(op.Index) || len(out.ScriptPubKey.Addresses) == 0 {
+				continue
+			}
+			addr := out.ScriptPubKey.Addresses[0]
+			if _, ok := addrs[addr]; !ok {
+				continue
+			}
+			ws.saveSingleUtxoToDB(model.Utxo{
+				Address:       addr,
+				Txid:          parent.Txid,
+				Vout:          out.N,
+				ScriptPubKey:  out.ScriptPubKey.Hex,
+				Amount:        out.Value,
+				Satoshis:      int64(math.Round(out.Value * util.SatoshisPerCoin(ws.coinType))),
+				Confirmations: parent.Confirmations,
+			}, addrs, chainHeight)
+		}
+	}
+}
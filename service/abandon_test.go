@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/btcsuite/btcutil"
+)
+
+const abandonTestTxid = "0c4d7a2f6b1e9c3d5a8f7e6b4c2d1a0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b"
+
+// mockAbandonWalletService returns a service synced to a scripted chain with an unconfirmed
+// transaction of ours spending the payment confirmed in block 1002
+func mockAbandonWalletService(t *testing.T) (*WalletService, *callbackRecorder, func(int) model.Block) {
+	ws, cli, recorder := mockReorgWalletService(t)
+	var payee btcutil.Address
+	for _, sa := range ws.getStoredAddresses() {
+		payee = sa.Addr
+		break
+	}
+	spend := paymentTo(payee, abandonTestTxid, 0)
+	spend.Outputs[0].ScriptPubKey.Addresses = []string{"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"}
+	spend.Inputs = []model.Input{{Txid: reorgTestTxid, Vout: 0, Addr: payee.String(), Value: 0.1}}
+	ws.ProcessIncomingTransaction(spend)
+	if utxoExists(t, ws, reorgTestTxid) {
+		t.Fatal("expected the spent payment to be removed from the utxos")
+	}
+	mine := func(height int) model.Block {
+		return cli.Extend(testBlockHash(0, height))[0]
+	}
+	return ws, recorder, mine
+}
+
+func TestWalletService_abandonsTransactionsMissingFromMempool(t *testing.T) {
+	ws, recorder, mine := mockAbandonWalletService(t)
+	ws.SetAbandonAfter(2)
+
+	for height := 1003; height <= 1004; height++ {
+		ws.processIncomingBlock(mine(height))
+		waitFor(t, "transaction to be rebroadcast", func() bool {
+			rec, ok := ws.BroadcastStatus(abandonTestTxid)
+			return ok && rec.Attempts == height-1002
+		})
+		if h := mustGetHeight(t, ws, abandonTestTxid); h != 0 {
+			t.Fatalf("expected transaction to stay unconfirmed at %d, got height %d", height, h)
+		}
+	}
+
+	ws.processIncomingBlock(mine(1005))
+	waitFor(t, "transaction to be abandoned", func() bool {
+		return recorder.sawHeight(abandonTestTxid, deadTxHeight)
+	})
+	if h := mustGetHeight(t, ws, abandonTestTxid); h != deadTxHeight {
+		t.Errorf("expected abandoned transaction to be dead, got height %d", h)
+	}
+	if !utxoExists(t, ws, reorgTestTxid) {
+		t.Error("expected the coin spent by the abandoned transaction to be spendable again")
+	}
+	if _, ok := ws.BroadcastStatus(abandonTestTxid); ok {
+		t.Error("expected the broadcast record of the abandoned transaction to be dropped")
+	}
+}
+
+func TestWalletService_AbandonTransaction(t *testing.T) {
+	ws, recorder, _ := mockAbandonWalletService(t)
+
+	if err := ws.AbandonTransaction(reorgTestTxid); err == nil {
+		t.Error("expected abandoning a confirmed transaction to fail")
+	}
+	if err := ws.AbandonTransaction(abandonTestTxid); err != nil {
+		t.Fatal(err)
+	}
+	if h := mustGetHeight(t, ws, abandonTestTxid); h != deadTxHeight {
+		t.Errorf("expected abandoned transaction to be dead, got height %d", h)
+	}
+	if !utxoExists(t, ws, reorgTestTxid) {
+		t.Error("expected the coin spent by the abandoned transaction to be spendable again")
+	}
+	waitFor(t, "listeners to hear of the abandoned transaction", func() bool {
+		return recorder.sawHeight(abandonTestTxid, deadTxHeight)
+	})
+	if err := ws.AbandonTransaction(abandonTestTxid); err == nil {
+		t.Error("expected abandoning a dead transaction to fail")
+	}
+}
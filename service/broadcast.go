@@ -0,0 +1,214 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+// BroadcastState is what we last learned about one of our unconfirmed transactions
+// from the network
+type BroadcastState string
+
+const (
+	// BroadcastPending is the state of a transaction which no server has answered for yet
+	BroadcastPending BroadcastState = "pending"
+	// BroadcastAccepted is the state of a transaction which a server holds in its mempool
+	BroadcastAccepted BroadcastState = "accepted"
+	// BroadcastRejected is the state of a transaction which the network refused. It is not
+	// rebroadcast.
+	BroadcastRejected BroadcastState = "rejected"
+	// BroadcastDropped is the state of a transaction which was accepted but has since
+	// disappeared from the server's mempool
+	BroadcastDropped BroadcastState = "dropped"
+)
+
+// broadcastRecordTTL is how long the record of a transaction which is no longer unconfirmed in
+// the db is kept before it is pruned
+const broadcastRecordTTL = 24 * time.Hour
+
+// BroadcastRecord holds the broadcast state of a transaction along with the reason the network
+// gave for rejecting it, if any. MissingSince is the height of the first block after which no
+// server knew of the transaction, or zero while it is in a mempool.
+type BroadcastRecord struct {
+	State        BroadcastState `json:"state"`
+	Reason       string         `json:"reason,omitempty"`
+	Attempts     int            `json:"attempts"`
+	LastAttempt  time.Time      `json:"lastAttempt"`
+	MissingSince uint32         `json:"missingSince,omitempty"`
+}
+
+func (ws *WalletService) broadcastStateKey() string {
+	return ws.cacheKey("broadcast-state")
+}
+
+// loadBroadcastRecords reads the persisted records the first time they are needed. The caller
+// must hold ws.broadcastsLock.
+func (ws *WalletService) loadBroadcastRecords() {
+	if ws.broadcasts != nil {
+		return
+	}
+	ws.broadcasts = make(map[string]*BroadcastRecord)
+	b, err := ws.cache.Get(ws.broadcastStateKey())
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(b, &ws.broadcasts); err != nil {
+		Log.Warningf("discarding unreadable %s broadcast state: %s", util.CurrencyCode(ws.coinType), err.Error())
+		ws.broadcasts = make(map[string]*BroadcastRecord)
+	}
+}
+
+// saveBroadcastRecords persists the records. The caller must hold ws.broadcastsLock.
+func (ws *WalletService) saveBroadcastRecords() {
+	b, err := json.Marshal(ws.broadcasts)
+	if err != nil {
+		Log.Errorf("marshaling %s broadcast state: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+	if err := ws.cache.Set(ws.broadcastStateKey(), b); err != nil {
+		Log.Errorf("saving %s broadcast state: %s", util.CurrencyCode(ws.coinType), err.Error())
+	}
+}
+
+// BroadcastStatus returns the broadcast record of txid and whether one exists
+func (ws *WalletService) BroadcastStatus(txid string) (BroadcastRecord, bool) {
+	ws.broadcastsLock.Lock()
+	defer ws.broadcastsLock.Unlock()
+	ws.loadBroadcastRecords()
+	rec, ok := ws.broadcasts[txid]
+	if !ok {
+		return BroadcastRecord{}, false
+	}
+	return *rec, true
+}
+
+// BroadcastTransaction sends the serialized transaction txid to the network and records the
+// outcome. Wallets should use it rather than broadcasting through the client directly so the
+// rebroadcast loop knows which transactions need to be sent again.
+func (ws *WalletService) BroadcastTransaction(txid string, raw []byte) error {
+	ws.broadcastsLock.Lock()
+	ws.loadBroadcastRecords()
+	rec, ok := ws.broadcasts[txid]
+	if !ok {
+		rec = &BroadcastRecord{State: BroadcastPending}
+		ws.broadcasts[txid] = rec
+	}
+	rec.Attempts++
+	rec.LastAttempt = time.Now()
+	ws.broadcastsLock.Unlock()
+
+	_, err := ws.client.Broadcast(raw)
+
+	ws.broadcastsLock.Lock()
+	defer ws.broadcastsLock.Unlock()
+	switch clientErr.KindOf(err) {
+	case clientErr.KindRejected, clientErr.KindFeeTooHigh, clientErr.KindDust:
+		rec.State, rec.Reason = BroadcastRejected, clientErr.ReasonOf(err)
+		Log.Warningf("%s tx %s rejected: %s", util.CurrencyCode(ws.coinType), txid, rec.Reason)
+	default:
+		if err == nil {
+			rec.State, rec.Reason = BroadcastAccepted, ""
+		}
+	}
+	ws.saveBroadcastRecords()
+	return err
+}
+
+// rebroadcast handles an unconfirmed transaction which no server knows of at the given chain
+// height. It is sent again unless the network already rejected it, and abandoned once it has
+// been missing for ws.abandonAfter blocks.
+func (ws *WalletService) rebroadcast(txn wallet.Txn, height uint32) {
+	ws.broadcastsLock.Lock()
+	ws.loadBroadcastRecords()
+	rec, ok := ws.broadcasts[txn.Txid]
+	if !ok {
+		rec = &BroadcastRecord{State: BroadcastPending}
+		ws.broadcasts[txn.Txid] = rec
+	}
+	if rec.MissingSince == 0 || rec.MissingSince > height {
+		rec.MissingSince = height
+	}
+	if rec.State == BroadcastAccepted {
+		Log.Warningf("%s tx %s was dropped from the mempool", util.CurrencyCode(ws.coinType), txn.Txid)
+		rec.State = BroadcastDropped
+	}
+	var (
+		state   = rec.State
+		reason  = rec.Reason
+		missing = height - rec.MissingSince
+	)
+	ws.saveBroadcastRecords()
+	ws.broadcastsLock.Unlock()
+
+	if missing >= ws.abandonAfter {
+		ws.abandon(txn, fmt.Sprintf("missing from every mempool for %d blocks", missing))
+		return
+	}
+	if state == BroadcastRejected {
+		Log.Debugf("not rebroadcasting rejected %s tx %s: %s", util.CurrencyCode(ws.coinType), txn.Txid, reason)
+		return
+	}
+	Log.Debugf("rebroadcasting unconfirmed %s tx %s", util.CurrencyCode(ws.coinType), txn.Txid)
+	if err := ws.BroadcastTransaction(txn.Txid, txn.Bytes); err != nil {
+		Log.Errorf("rebroadcasting unconfirmed %s tx %s: %s", util.CurrencyCode(ws.coinType), txn.Txid, err.Error())
+	}
+}
+
+// markBroadcastAccepted records that the server holds txid in its mempool
+func (ws *WalletService) markBroadcastAccepted(txid string) {
+	ws.broadcastsLock.Lock()
+	defer ws.broadcastsLock.Unlock()
+	ws.loadBroadcastRecords()
+	rec, ok := ws.broadcasts[txid]
+	if ok && rec.State == BroadcastAccepted && rec.MissingSince == 0 {
+		return
+	}
+	if !ok {
+		rec = &BroadcastRecord{}
+		ws.broadcasts[txid] = rec
+	}
+	rec.State, rec.Reason, rec.MissingSince = BroadcastAccepted, "", 0
+	ws.saveBroadcastRecords()
+}
+
+// forgetBroadcast drops the record of txid once it no longer needs broadcasting
+func (ws *WalletService) forgetBroadcast(txid string) {
+	ws.broadcastsLock.Lock()
+	defer ws.broadcastsLock.Unlock()
+	ws.loadBroadcastRecords()
+	if _, ok := ws.broadcasts[txid]; !ok {
+		return
+	}
+	delete(ws.broadcasts, txid)
+	ws.saveBroadcastRecords()
+}
+
+// pruneBroadcastRecords drops the records of transactions which are no longer unconfirmed in
+// txs, such as those which failed to broadcast and were never stored. Recent records are kept
+// since the transaction may not have been stored yet.
+func (ws *WalletService) pruneBroadcastRecords(txs []wallet.Txn) {
+	unconfirmed := make(map[string]bool)
+	for _, tx := range txs {
+		if tx.Height == 0 {
+			unconfirmed[tx.Txid] = true
+		}
+	}
+	ws.broadcastsLock.Lock()
+	defer ws.broadcastsLock.Unlock()
+	ws.loadBroadcastRecords()
+	var pruned bool
+	for txid, rec := range ws.broadcasts {
+		if !unconfirmed[txid] && time.Since(rec.LastAttempt) > broadcastRecordTTL {
+			delete(ws.broadcasts, txid)
+			pruned = true
+		}
+	}
+	if pruned {
+		ws.saveBroadcastRecords()
+	}
+}
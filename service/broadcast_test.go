@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+const broadcastTestTxid = "b54e3b6bc4d3e4c7b1cfc3e1a2d1bd4b1c9d6e2fa4fbc58e7a3cbd0d1b9a8c71"
+
+func broadcastStateIs(ws *WalletService, txid string, state BroadcastState, attempts int) func() bool {
+	return func() bool {
+		rec, ok := ws.BroadcastStatus(txid)
+		return ok && rec.State == state && rec.Attempts == attempts
+	}
+}
+
+func TestWalletService_broadcastStateDrivesRebroadcasts(t *testing.T) {
+	ws, cli, _ := mockReorgWalletService(t)
+	addrs := ws.getStoredAddresses()
+	for _, sa := range addrs {
+		cli.AddTransaction(paymentTo(sa.Addr, broadcastTestTxid, 0), "")
+		break
+	}
+	ws.syncTxs(addrs, nil)
+	if err := ws.BroadcastTransaction(broadcastTestTxid, []byte{0x01}); err != nil {
+		t.Fatal(err)
+	}
+	if !broadcastStateIs(ws, broadcastTestTxid, BroadcastAccepted, 1)() {
+		t.Fatal("expected broadcast transaction to be accepted")
+	}
+
+	// The transaction leaves the mempool and the server is unreachable when we try again
+	cli.Remove(broadcastTestTxid)
+	cli.FailBroadcasts(clientErr.Classify(clientErr.KindBackendUnavailable, errors.New("connection refused")))
+	ws.processIncomingBlock(cli.Extend(testBlockHash(0, 1003))[0])
+	waitFor(t, "transaction to be marked dropped", broadcastStateIs(ws, broadcastTestTxid, BroadcastDropped, 2))
+
+	// The next attempt is refused so it is not sent again
+	cli.FailBroadcasts(clientErr.ClassifyWithReason(clientErr.KindRejected, "txn-mempool-conflict", errors.New("rejected")))
+	ws.processIncomingBlock(cli.Extend(testBlockHash(0, 1004))[0])
+	waitFor(t, "transaction to be marked rejected", broadcastStateIs(ws, broadcastTestTxid, BroadcastRejected, 3))
+	if rec, _ := ws.BroadcastStatus(broadcastTestTxid); rec.Reason != "txn-mempool-conflict" {
+		t.Errorf("expected rejection reason to be kept, got %q", rec.Reason)
+	}
+	cli.FailBroadcasts(nil)
+	sent := cli.BroadcastCount()
+	hash, err := chainhash.NewHashFromStr(broadcastTestTxid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err := ws.db.Txns().Get(*hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.rebroadcast(txn, 1004)
+	if n := cli.BroadcastCount(); n != sent {
+		t.Errorf("expected rejected transaction not to be rebroadcast, got %d more broadcasts", n-sent)
+	}
+
+	// Once confirmed the record is no longer needed
+	for _, sa := range addrs {
+		cli.AddTransaction(paymentTo(sa.Addr, broadcastTestTxid, 0), testBlockHash(0, 1005))
+		break
+	}
+	ws.processIncomingBlock(cli.Extend(testBlockHash(0, 1005))[0])
+	waitFor(t, "broadcast record to be forgotten", func() bool {
+		_, ok := ws.BroadcastStatus(broadcastTestTxid)
+		return !ok
+	})
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// deadTxHeight is the height given to transactions which were double spent or replaced. As in
+// spvwallet a negative height marks the transaction as one which will never confirm.
+const deadTxHeight = -1
+
+// maxRBFSequence is the highest input sequence number which signals BIP125 replaceability
+const maxRBFSequence = 0xfffffffd
+
+// spendIndex records which of our transactions spend each outpoint so transactions spending
+// the same outpoint can be detected. Replaceable records the transactions signalling BIP125
+// and Dead those which were double spent or replaced.
+type spendIndex struct {
+	Spends      map[string]string `json:"spends"`
+	Replaceable map[string]bool   `json:"replaceable"`
+	Dead        map[string]bool   `json:"dead"`
+}
+
+func outpointKey(txid string, vout int) string {
+	return fmt.Sprintf("%s:%d", txid, vout)
+}
+
+func (ws *WalletService) spendIndexKey() string {
+	return ws.cacheKey("spend-index")
+}
+
+// loadSpendIndex reads the persisted index the first time it is needed. The caller must hold
+// ws.spendsLock.
+func (ws *WalletService) loadSpendIndex() {
+	if ws.spends != nil {
+		return
+	}
+	ws.spends = &spendIndex{
+		Spends:      make(map[string]string),
+		Replaceable: make(map[string]bool),
+		Dead:        make(map[string]bool),
+	}
+	b, err := ws.cache.Get(ws.spendIndexKey())
+	if err != nil {
+		return
+	}
+	var idx spendIndex
+	if err := json.Unmarshal(b, &idx); err != nil {
+		Log.Warningf("discarding unreadable %s spend index: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+	for k, v := range idx.Spends {
+		ws.spends.Spends[k] = v
+	}
+	for k, v := range idx.Replaceable {
+		ws.spends.Replaceable[k] = v
+	}
+	for k, v := range idx.Dead {
+		ws.spends.Dead[k] = v
+	}
+}
+
+// saveSpendIndex persists the index. The caller must hold ws.spendsLock.
+func (ws *WalletService) saveSpendIndex() {
+	b, err := json.Marshal(ws.spends)
+	if err != nil {
+		Log.Errorf("marshaling %s spend index: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+	if err := ws.cache.Set(ws.spendIndexKey(), b); err != nil {
+		Log.Errorf("saving %s spend index: %s", util.CurrencyCode(ws.coinType), err.Error())
+	}
+}
+
+// findConflicts returns the txids of the other transactions we know of which spend any of the
+// same outpoints as tx
+func (ws *WalletService) findConflicts(tx model.Transaction) []string {
+	ws.spendsLock.Lock()
+	defer ws.spendsLock.Unlock()
+	ws.loadSpendIndex()
+
+	var (
+		conflicts []string
+		seen      = make(map[string]bool)
+	)
+	for _, in := range tx.Inputs {
+		spender, ok := ws.spends.Spends[outpointKey(in.Txid, in.Vout)]
+		if ok && spender != tx.Txid && !seen[spender] && !ws.spends.Dead[spender] {
+			seen[spender] = true
+			conflicts = append(conflicts, spender)
+		}
+	}
+	return conflicts
+}
+
+// isReplaceable reports whether the transaction signalled BIP125 replaceability
+func (ws *WalletService) isReplaceable(txid string) bool {
+	ws.spendsLock.Lock()
+	defer ws.spendsLock.Unlock()
+	ws.loadSpendIndex()
+	return ws.spends.Replaceable[txid]
+}
+
+// isDead reports whether the transaction was double spent or replaced
+func (ws *WalletService) isDead(txid string) bool {
+	ws.spendsLock.Lock()
+	defer ws.spendsLock.Unlock()
+	ws.loadSpendIndex()
+	return ws.spends.Dead[txid]
+}
+
+// recordSpends adds the outpoints spent by a live tx to the index. When two transactions spend
+// the same outpoint the one recorded last is kept since the other has been marked dead.
+func (ws *WalletService) recordSpends(tx model.Transaction) {
+	ws.spendsLock.Lock()
+	defer ws.spendsLock.Unlock()
+	ws.loadSpendIndex()
+
+	for _, in := range tx.Inputs {
+		ws.spends.Spends[outpointKey(in.Txid, in.Vout)] = tx.Txid
+		if in.Sequence <= maxRBFSequence {
+			ws.spends.Replaceable[tx.Txid] = true
+		}
+	}
+	delete(ws.spends.Dead, tx.Txid)
+	ws.saveSpendIndex()
+}
+
+// recordDead marks the transaction as one which will never confirm
+func (ws *WalletService) recordDead(txid string) {
+	ws.spendsLock.Lock()
+	defer ws.spendsLock.Unlock()
+	ws.loadSpendIndex()
+	ws.spends.Dead[txid] = true
+	ws.saveSpendIndex()
+}
+
+// spentBy returns the outpoints the transaction spends according to the index
+func (ws *WalletService) spentBy(txid string) []wire.OutPoint {
+	ws.spendsLock.Lock()
+	defer ws.spendsLock.Unlock()
+	ws.loadSpendIndex()
+
+	var ops []wire.OutPoint
+	for key, spender := range ws.spends.Spends {
+		if spender != txid {
+			continue
+		}
+		op, err := util.ParseOutPoint(key)
+		if err != nil {
+			continue
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// resolveConflicts checks tx, which is about to be saved at height, against the transactions
+// spending the same outpoints. Conflicting transactions which can no longer confirm are marked
+// dead and the height tx should be saved at is returned, which is deadTxHeight if tx itself
+// can no longer confirm.
+//
+// A confirmed tx kills every unconfirmed conflict. An unconfirmed tx conflicting with a confirmed
+// one is dead. Between two unconfirmed transactions a BIP125 replaceable one is replaced, while
+// for a double spend of a non-replaceable transaction we cannot know which will confirm so both
+// are marked dead until one of them confirms.
+func (ws *WalletService) resolveConflicts(tx model.Transaction, height int32) int32 {
+	if height <= 0 && ws.isDead(tx.Txid) {
+		return deadTxHeight
+	}
+	for _, txid := range ws.findConflicts(tx) {
+		txHash, err := chainhash.NewHashFromStr(txid)
+		if err != nil {
+			continue
+		}
+		conflict, err := ws.db.Txns().Get(*txHash)
+		if err != nil {
+			continue
+		}
+		switch {
+		case height > 0:
+			ws.markDead(conflict, "double spent", tx.Txid)
+		case conflict.Height > 0:
+			Log.Warningf("%s tx %s double spends confirmed tx %s", util.CurrencyCode(ws.coinType), tx.Txid, txid)
+			height = deadTxHeight
+		case ws.isReplaceable(txid):
+			ws.markDead(conflict, "replaced", tx.Txid)
+		default:
+			ws.markDead(conflict, "double spent", tx.Txid)
+			height = deadTxHeight
+		}
+	}
+	return height
+}
+
+// markDead gives a transaction which can never confirm a negative height, removes the utxos it
+// created and notifies the listeners
+func (ws *WalletService) markDead(txn wallet.Txn, reason, conflictTxid string) {
+	Log.Warningf("%s tx %s was %s by %s", util.CurrencyCode(ws.coinType), txn.Txid, reason, conflictTxid)
+	if ws.setDead(txn) {
+		ws.notifyDead(txn)
+	}
+}
+
+// setDead gives the transaction a negative height and removes the utxos it created. It reports
+// whether the transaction was updated.
+func (ws *WalletService) setDead(txn wallet.Txn) bool {
+	txHash, err := chainhash.NewHashFromStr(txn.Txid)
+	if err != nil {
+		Log.Errorf("error converting to txHash for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return false
+	}
+	if err := ws.db.Txns().UpdateHeight(*txHash, deadTxHeight, txn.Timestamp); err != nil {
+		Log.Errorf("marking tx (%s) dead: %s", txn.Txid, err.Error())
+		return false
+	}
+	ws.recordDead(txn.Txid)
+	utxos, err := ws.db.Utxos().GetAll()
+	if err != nil {
+		Log.Errorf("error loading %s utxos: %s", util.CurrencyCode(ws.coinType), err.Error())
+	}
+	for _, u := range utxos {
+		if u.Op.Hash.IsEqual(txHash) {
+			if err := ws.db.Utxos().Delete(u); err != nil {
+				Log.Errorf("deleting utxo of dead tx (%s): %s", txn.Txid, err.Error())
+			}
+		}
+	}
+	return true
+}
+
+// notifyDead tells the listeners the transaction will never confirm
+func (ws *WalletService) notifyDead(txn wallet.Txn) {
+	value, ok := new(big.Int).SetString(txn.Value, 10)
+	if !ok {
+		value = new(big.Int)
+	}
+	ws.callbackListeners(wallet.TransactionCallback{
+		Txid:      txn.Txid,
+		Value:     *value,
+		Height:    deadTxHeight,
+		Timestamp: txn.Timestamp,
+		WatchOnly: txn.WatchOnly,
+	})
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	conflictFundingTxid = "6e8bdb6c5bf4b5d2a1b3c7d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0"
+	conflictOriginal    = "1be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428"
+	conflictReplacement = "54ebaa07c42216393b9d5816e40dd608593b92c42e2d6525f45bdd36bce8fe4d"
+)
+
+// conflictingPayment pays addr by spending the same funding outpoint as every other
+// conflictingPayment
+func conflictingPayment(addr btcutil.Address, txid string, sequence uint32, height int) model.Transaction {
+	tx := paymentTo(addr, txid, height)
+	tx.Inputs = []model.Input{{
+		Txid:     conflictFundingTxid,
+		Vout:     0,
+		Sequence: sequence,
+		Addr:     "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		Value:    0.2,
+	}}
+	return tx
+}
+
+func mockConflictWalletService(t *testing.T) (*WalletService, btcutil.Address, *callbackRecorder) {
+	ws, err := mockWalletService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		recorder = &callbackRecorder{}
+		payee    btcutil.Address
+	)
+	for _, sa := range ws.getStoredAddresses() {
+		payee = sa.Addr
+		break
+	}
+	ws.chainHeight = 1000
+	ws.AddTransactionListener(recorder.record)
+	return ws, payee, recorder
+}
+
+func mustGetHeight(t *testing.T, ws *WalletService, txid string) int32 {
+	txHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err := ws.db.Txns().Get(*txHash)
+	if err != nil {
+		t.Fatalf("expected %s to be saved: %s", txid, err)
+	}
+	return txn.Height
+}
+
+func utxoExists(t *testing.T, ws *WalletService, txid string) bool {
+	utxos, err := ws.db.Utxos().GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range utxos {
+		if u.Op.Hash.String() == txid {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWalletService_replacementMarksOriginalDead(t *testing.T) {
+	ws, payee, recorder := mockConflictWalletService(t)
+
+	ws.ProcessIncomingTransaction(conflictingPayment(payee, conflictOriginal, maxRBFSequence, 0))
+	ws.ProcessIncomingTransaction(conflictingPayment(payee, conflictReplacement, maxRBFSequence, 0))
+
+	if h := mustGetHeight(t, ws, conflictOriginal); h != deadTxHeight {
+		t.Errorf("expected replaced tx to be dead, got height %d", h)
+	}
+	if h := mustGetHeight(t, ws, conflictReplacement); h != 0 {
+		t.Errorf("expected replacement to be unconfirmed, got height %d", h)
+	}
+	if !recorder.sawHeight(conflictOriginal, deadTxHeight) {
+		t.Error("expected listeners to be told the original was replaced")
+	}
+	if utxoExists(t, ws, conflictOriginal) {
+		t.Error("expected utxo created by the replaced tx to be removed")
+	}
+	if !utxoExists(t, ws, conflictReplacement) {
+		t.Error("expected utxo created by the replacement to be saved")
+	}
+}
+
+func TestWalletService_unconfirmedDoubleSpendMarksBothDead(t *testing.T) {
+	ws, payee, recorder := mockConflictWalletService(t)
+
+	ws.ProcessIncomingTransaction(conflictingPayment(payee, conflictOriginal, 0xffffffff, 0))
+	ws.ProcessIncomingTransaction(conflictingPayment(payee, conflictReplacement, 0xffffffff, 0))
+
+	for _, txid := range []string{conflictOriginal, conflictReplacement} {
+		if h := mustGetHeight(t, ws, txid); h != deadTxHeight {
+			t.Errorf("expected double spent tx %s to be dead, got height %d", txid, h)
+		}
+		if !recorder.sawHeight(txid, deadTxHeight) {
+			t.Errorf("expected listeners to be told %s was double spent", txid)
+		}
+	}
+
+	// Whichever confirms is revived
+	ws.ProcessIncomingTransaction(conflictingPayment(payee, conflictReplacement, 0xffffffff, 999))
+	if h := mustGetHeight(t, ws, conflictReplacement); h != 999 {
+		t.Errorf("expected confirmed double spend to be revived at 999, got height %d", h)
+	}
+	if h := mustGetHeight(t, ws, conflictOriginal); h != deadTxHeight {
+		t.Errorf("expected losing tx to stay dead, got height %d", h)
+	}
+}
+
+func TestWalletService_confirmedConflictWins(t *testing.T) {
+	ws, payee, _ := mockConflictWalletService(t)
+
+	ws.ProcessIncomingTransaction(conflictingPayment(payee, conflictOriginal, 0xffffffff, 0))
+	ws.ProcessIncomingTransaction(conflictingPayment(payee, conflictReplacement, 0xffffffff, 999))
+
+	if h := mustGetHeight(t, ws, conflictOriginal); h != deadTxHeight {
+		t.Errorf("expected unconfirmed tx to be dead, got height %d", h)
+	}
+	if h := mustGetHeight(t, ws, conflictReplacement); h != 999 {
+		t.Errorf("expected confirmed tx at 999, got height %d", h)
+	}
+
+	// A late arriving conflict with the confirmed tx is dead on arrival
+	const late = "830bf683ab8eec1a75d891689e2989f846508bc7d500cb026ef671c2d1dce20c"
+	ws.ProcessIncomingTransaction(conflictingPayment(payee, late, maxRBFSequence, 0))
+	if h := mustGetHeight(t, ws, late); h != deadTxHeight {
+		t.Errorf("expected conflict with a confirmed tx to be dead, got height %d", h)
+	}
+	if utxoExists(t, ws, late) {
+		t.Error("expected no utxo to be saved for a dead tx")
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// UtxoStatus is an unspent output of the wallet along with whether the user has frozen it and
+// the CashToken it carries, if any
+type UtxoStatus struct {
+	Utxo   wallet.Utxo
+	Frozen bool
+	Token  *util.CashToken
+}
+
+func (ws *WalletService) frozenUtxosKey() string {
+	return ws.cacheKey("frozen-utxos")
+}
+
+// frozenOutpoints returns the frozen outpoints from the datastore if it can hold them and from
+// the cache otherwise. The caller must hold ws.frozenLock.
+func (ws *WalletService) frozenOutpoints() (map[wire.OutPoint]bool, error) {
+	frozen := make(map[wire.OutPoint]bool)
+	if store, ok := ws.db.Utxos().(model.FrozenUtxoStore); ok {
+		ops, err := store.GetFrozen()
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range ops {
+			frozen[op] = true
+		}
+		return frozen, nil
+	}
+	b, err := ws.cache.Get(ws.frozenUtxosKey())
+	if err != nil {
+		// Nothing has been frozen yet
+		return frozen, nil
+	}
+	var ops []string
+	if err := json.Unmarshal(b, &ops); err != nil {
+		return nil, fmt.Errorf("reading frozen %s utxos: %s", util.CurrencyCode(ws.coinType), err.Error())
+	}
+	for _, s := range ops {
+		op, err := util.ParseOutPoint(s)
+		if err != nil {
+			return nil, err
+		}
+		frozen[op] = true
+	}
+	return frozen, nil
+}
+
+// setFrozen freezes or unfreezes op. The caller must hold ws.frozenLock.
+func (ws *WalletService) setFrozen(op wire.OutPoint, freeze bool) error {
+	if store, ok := ws.db.Utxos().(model.FrozenUtxoStore); ok {
+		if freeze {
+			return store.Freeze(op)
+		}
+		return store.Unfreeze(op)
+	}
+	frozen, err := ws.frozenOutpoints()
+	if err != nil {
+		return err
+	}
+	if frozen[op] == freeze {
+		return nil
+	}
+	if freeze {
+		frozen[op] = true
+	} else {
+		delete(frozen, op)
+	}
+	ops := make([]string, 0, len(frozen))
+	for op := range frozen {
+		ops = append(ops, op.String())
+	}
+	b, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+	return ws.cache.Set(ws.frozenUtxosKey(), b)
+}
+
+// FreezeUtxo stops op from being chosen by coin selection or spent explicitly until it is
+// unfrozen. It must be an unspent output of the wallet.
+func (ws *WalletService) FreezeUtxo(op wire.OutPoint) error {
+	utxos, err := ws.db.Utxos().GetAll()
+	if err != nil {
+		return err
+	}
+	var found bool
+	for _, u := range utxos {
+		if util.OutPointsEqual(u.Op, op) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is not an unspent output of the wallet", op.String())
+	}
+	ws.frozenLock.Lock()
+	defer ws.frozenLock.Unlock()
+	return ws.setFrozen(op, true)
+}
+
+// UnfreezeUtxo makes a frozen output spendable again
+func (ws *WalletService) UnfreezeUtxo(op wire.OutPoint) error {
+	ws.frozenLock.Lock()
+	defer ws.frozenLock.Unlock()
+	return ws.setFrozen(op, false)
+}
+
+// UtxoStatuses returns every unspent output of the wallet, whether it is frozen and the token it
+// carries
+func (ws *WalletService) UtxoStatuses() ([]UtxoStatus, error) {
+	utxos, err := ws.db.Utxos().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	ws.frozenLock.Lock()
+	frozen, err := ws.frozenOutpoints()
+	ws.frozenLock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	ws.tokenLock.Lock()
+	tokens, err := ws.tokenUtxos()
+	ws.tokenLock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]UtxoStatus, 0, len(utxos))
+	for _, u := range utxos {
+		statuses = append(statuses, UtxoStatus{Utxo: u, Frozen: frozen[u.Op], Token: tokens[u.Op]})
+	}
+	return statuses, nil
+}
+
+// SpendableUtxos returns the unspent outputs which may fund a transaction, leaving out those which
+// are frozen or carry a token which the spend would burn
+func (ws *WalletService) SpendableUtxos() ([]wallet.Utxo, error) {
+	statuses, err := ws.UtxoStatuses()
+	if err != nil {
+		return nil, err
+	}
+	var utxos []wallet.Utxo
+	for _, s := range statuses {
+		if !s.Frozen && s.Token == nil {
+			utxos = append(utxos, s.Utxo)
+		}
+	}
+	return utxos, nil
+}
+
+// IsFrozen reports whether op has been frozen
+func (ws *WalletService) IsFrozen(op wire.OutPoint) (bool, error) {
+	ws.frozenLock.Lock()
+	defer ws.frozenLock.Unlock()
+	frozen, err := ws.frozenOutpoints()
+	if err != nil {
+		return false, err
+	}
+	return frozen[op], nil
+}
+
+// SelectUtxos returns the unspent outputs at outpoints for a spend whose inputs were chosen by the
+// user. Outpoints which are frozen, carry a token, are listed twice or are not ours are refused.
+func (ws *WalletService) SelectUtxos(outpoints []wire.OutPoint) ([]wallet.Utxo, error) {
+	if len(outpoints) == 0 {
+		return nil, errors.New("no outputs to spend")
+	}
+	statuses, err := ws.UtxoStatuses()
+	if err != nil {
+		return nil, err
+	}
+	byOutpoint := make(map[wire.OutPoint]UtxoStatus, len(statuses))
+	for _, s := range statuses {
+		byOutpoint[s.Utxo.Op] = s
+	}
+	seen := make(map[wire.OutPoint]bool, len(outpoints))
+	utxos := make([]wallet.Utxo, 0, len(outpoints))
+	for _, op := range outpoints {
+		s, ok := byOutpoint[op]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%s is not an unspent output of the wallet", op.String())
+		case s.Frozen:
+			return nil, fmt.Errorf("%s is frozen", op.String())
+		case s.Token != nil:
+			return nil, fmt.Errorf("%s carries a CashToken", op.String())
+		case seen[op]:
+			return nil, fmt.Errorf("%s is listed more than once", op.String())
+		}
+		seen[op] = true
+		utxos = append(utxos, s.Utxo)
+	}
+	return utxos, nil
+}
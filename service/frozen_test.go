@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// plainUtxoDatastore hides the frozen utxo support of the mock datastore so the cache is used
+type plainUtxoDatastore struct {
+	wallet.Datastore
+}
+
+func (d plainUtxoDatastore) Utxos() wallet.Utxos {
+	return struct{ wallet.Utxos }{d.Datastore.Utxos()}
+}
+
+// mockFrozenWalletService returns a service holding two utxos
+func mockFrozenWalletService(t *testing.T, plain bool) (*WalletService, []wire.OutPoint) {
+	ws, err := mockWalletService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain {
+		ws.db = plainUtxoDatastore{ws.db}
+	}
+	var ops []wire.OutPoint
+	for i, value := range []string{"100000", "250000"} {
+		hash := chainhash.DoubleHashH([]byte{byte(i)})
+		op := *wire.NewOutPoint(&hash, uint32(i))
+		if err := ws.db.Utxos().Put(wallet.Utxo{Op: op, AtHeight: 1000, Value: value}); err != nil {
+			t.Fatal(err)
+		}
+		ops = append(ops, op)
+	}
+	return ws, ops
+}
+
+func TestWalletService_FreezeUtxo(t *testing.T) {
+	for _, plain := range []bool{false, true} {
+		ws, ops := mockFrozenWalletService(t, plain)
+		if err := ws.FreezeUtxo(ops[0]); err != nil {
+			t.Fatal(err)
+		}
+		if frozen, err := ws.IsFrozen(ops[0]); err != nil || !frozen {
+			t.Errorf("plain=%t: expected the output to be frozen, got %t %v", plain, frozen, err)
+		}
+		spendable, err := ws.SpendableUtxos()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(spendable) != 1 || spendable[0].Op != ops[1] {
+			t.Errorf("plain=%t: expected only the unfrozen output to be spendable, got %v", plain, spendable)
+		}
+		statuses, err := ws.UtxoStatuses()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range statuses {
+			if s.Frozen != (s.Utxo.Op == ops[0]) {
+				t.Errorf("plain=%t: wrong frozen state for %s", plain, s.Utxo.Op.String())
+			}
+		}
+
+		if err := ws.UnfreezeUtxo(ops[0]); err != nil {
+			t.Fatal(err)
+		}
+		if spendable, _ := ws.SpendableUtxos(); len(spendable) != 2 {
+			t.Errorf("plain=%t: expected both outputs to be spendable after unfreezing, got %d", plain, len(spendable))
+		}
+	}
+}
+
+func TestWalletService_FreezeUnknownUtxo(t *testing.T) {
+	ws, _ := mockFrozenWalletService(t, false)
+	hash := chainhash.DoubleHashH([]byte("unknown"))
+	if err := ws.FreezeUtxo(*wire.NewOutPoint(&hash, 0)); err == nil {
+		t.Error("expected an output which is not ours to be refused")
+	}
+}
+
+func TestWalletService_SelectUtxos(t *testing.T) {
+	ws, ops := mockFrozenWalletService(t, false)
+	utxos, err := ws.SelectUtxos([]wire.OutPoint{ops[1], ops[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 2 || utxos[0].Op != ops[1] || utxos[1].Op != ops[0] {
+		t.Errorf("expected the outputs in the order given, got %v", utxos)
+	}
+
+	if _, err := ws.SelectUtxos(nil); err == nil {
+		t.Error("expected an empty selection to be refused")
+	}
+	if _, err := ws.SelectUtxos([]wire.OutPoint{ops[0], ops[0]}); err == nil {
+		t.Error("expected an output listed twice to be refused")
+	}
+	if err := ws.FreezeUtxo(ops[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ws.SelectUtxos([]wire.OutPoint{ops[0]}); err == nil {
+		t.Error("expected a frozen output to be refused")
+	}
+}
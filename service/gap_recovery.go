@@ -0,0 +1,72 @@
+package service
+
+import (
+	"time"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcutil"
+)
+
+// recoverGap catches up on anything announced while the client's notification connection was
+// down. The current best block is processed as if it had been announced and the history of
+// our addresses since the last block we saw is passed through ProcessIncomingTransaction so
+// the listeners hear about every transaction they missed.
+func (ws *WalletService) recoverGap(disconnectedAt time.Time) {
+	Log.Infof("recovering %s notifications missed since %s", util.CurrencyCode(ws.coinType), disconnectedAt.Format(time.RFC3339))
+	ws.lock.RLock()
+	lastHeight, lastHash := ws.chainHeight, ws.bestBlock
+	ws.lock.RUnlock()
+
+	best, err := ws.client.GetBestBlock()
+	if err != nil {
+		Log.Errorf("error querying API for %s chain height: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+	if best.Hash != lastHash {
+		ws.processIncomingBlock(*best)
+	}
+
+	var addrs []btcutil.Address
+	for _, sa := range ws.getStoredAddresses() {
+		addrs = append(addrs, sa.Addr)
+	}
+	var txs []model.Transaction
+	if incremental, ok := ws.client.(model.IncrementalAPIClient); ok && lastHeight > 0 {
+		txs, err = incremental.GetTransactionsSince(addrs, int(lastHeight))
+	} else {
+		txs, err = ws.client.GetTransactions(addrs)
+	}
+	if err != nil {
+		Log.Errorf("error downloading %s txs missed while disconnected: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+
+	ws.lock.RLock()
+	chainHeight := int32(ws.chainHeight)
+	ws.lock.RUnlock()
+	for _, tx := range txs {
+		if !ws.alreadyKnown(tx, chainHeight) {
+			ws.ProcessIncomingTransaction(tx)
+		}
+	}
+}
+
+// alreadyKnown reports whether tx is saved at the height it has now, in which case nothing
+// about it was missed
+func (ws *WalletService) alreadyKnown(tx model.Transaction, chainHeight int32) bool {
+	txHash, err := chainhash.NewHashFromStr(tx.Txid)
+	if err != nil {
+		return false
+	}
+	stored, err := ws.db.Txns().Get(*txHash)
+	if err != nil {
+		return false
+	}
+	height := txHeight(tx, chainHeight)
+	if stored.Height == deadTxHeight {
+		return height <= 0
+	}
+	return stored.Height == height
+}
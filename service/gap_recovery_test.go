@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	gapConfirmedTxid   = "54ebaa07c42216393b9d5816e40dd608593b92c42e2d6525f45bdd36bce8fe4d"
+	gapUnconfirmedTxid = "830bf683ab8eec1a75d891689e2989f846508bc7d500cb026ef671c2d1dce20c"
+)
+
+func (r *callbackRecorder) count(txid string) int {
+	r.Lock()
+	defer r.Unlock()
+	var n int
+	for _, cb := range r.callbacks {
+		if cb.Txid == txid {
+			n++
+		}
+	}
+	return n
+}
+
+func TestWalletService_recoverGap(t *testing.T) {
+	ws, cli, recorder := mockReorgWalletService(t)
+	known := recorder.count(reorgTestTxid)
+
+	// While disconnected two blocks are mined, one paying us, and another payment
+	// enters the mempool
+	disconnectedAt := time.Now()
+	var payees []btcutil.Address
+	for _, sa := range ws.getStoredAddresses() {
+		payees = append(payees, sa.Addr)
+	}
+	cli.Extend(testBlockHash(0, 1003), testBlockHash(0, 1004))
+	cli.AddTransaction(paymentTo(payees[0], gapConfirmedTxid, 0), testBlockHash(0, 1004))
+	cli.AddTransaction(paymentTo(payees[1], gapUnconfirmedTxid, 0), "")
+
+	ws.recoverGap(disconnectedAt)
+
+	height, hash := ws.ChainTip()
+	if height != 1004 || hash.String() != testBlockHash(0, 1004) {
+		t.Errorf("expected tip to catch up to 1004, got %d %s", height, hash.String())
+	}
+	if h := mustGetHeight(t, ws, gapConfirmedTxid); h != 1004 {
+		t.Errorf("expected missed payment confirmed at 1004, got %d", h)
+	}
+	if h := mustGetHeight(t, ws, gapUnconfirmedTxid); h != 0 {
+		t.Errorf("expected missed mempool payment to be unconfirmed, got %d", h)
+	}
+	waitFor(t, "listeners to hear about missed payments", func() bool {
+		return recorder.sawHeight(gapConfirmedTxid, 1004) && recorder.sawHeight(gapUnconfirmedTxid, 0)
+	})
+	if n := recorder.count(reorgTestTxid); n != known {
+		t.Errorf("expected no new callbacks for the unchanged payment, got %d more", n-known)
+	}
+	if h := mustGetHeight(t, ws, reorgTestTxid); h != 1002 {
+		t.Errorf("expected payment to stay confirmed at 1002, got %d", h)
+	}
+}
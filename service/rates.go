@@ -0,0 +1,90 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+// maxRateSnapshotAge is how old a transaction may be when it is first seen for the current rates
+// to be recorded as its rates. Older transactions, found when syncing the history of a restored
+// wallet, would get rates which have nothing to do with what they were worth.
+const maxRateSnapshotAge = time.Hour
+
+// SetExchangeRates sets where the rates recorded for new transactions come from. Without it no
+// rates are recorded. It should be called before the service is started.
+func (ws *WalletService) SetExchangeRates(er wallet.ExchangeRates) {
+	ws.exchangeRates = er
+}
+
+func (ws *WalletService) txnRatesKey(txid string) string {
+	return fmt.Sprintf("txn-rates-%s-%s", util.CurrencyCode(ws.coinType), txid)
+}
+
+// recordRates saves the current exchange rates as the rates at receipt of the transaction txid
+// with timestamp ts, in the datastore if it can hold them and in the cache otherwise
+func (ws *WalletService) recordRates(txid string, ts time.Time) {
+	if ws.exchangeRates == nil || time.Since(ts) > maxRateSnapshotAge {
+		return
+	}
+	rates, err := ws.exchangeRates.GetAllRates(true)
+	if err != nil || len(rates) == 0 {
+		Log.Warningf("no %s exchange rates to record for tx (%s)", util.CurrencyCode(ws.coinType), txid)
+		return
+	}
+	snapshot := model.RateSnapshot{Rates: rates, Recorded: time.Now()}
+	if store, ok := ws.db.Txns().(model.TxnRateStore); ok {
+		err = store.PutRates(txid, snapshot)
+	} else {
+		var b []byte
+		b, err = json.Marshal(snapshot)
+		if err == nil {
+			err = ws.cache.Set(ws.txnRatesKey(txid), b)
+		}
+	}
+	if err != nil {
+		Log.Errorf("recording exchange rates for tx (%s): %s", txid, err.Error())
+	}
+}
+
+// RatesAtReceipt returns the exchange rates recorded when the transaction txid was first seen, or
+// nil if none were
+func (ws *WalletService) RatesAtReceipt(txid string) (*model.RateSnapshot, error) {
+	if store, ok := ws.db.Txns().(model.TxnRateStore); ok {
+		return store.GetRates(txid)
+	}
+	b, err := ws.cache.Get(ws.txnRatesKey(txid))
+	if err != nil {
+		// No rates were recorded
+		return nil, nil
+	}
+	var snapshot model.RateSnapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return nil, fmt.Errorf("reading %s rates of tx (%s): %s", util.CurrencyCode(ws.coinType), txid, err.Error())
+	}
+	return &snapshot, nil
+}
+
+// ValueAtReceipt returns what txn was worth in each currency when it was first seen, valued with
+// the rates recorded then. It returns nil if no rates were recorded.
+func (ws *WalletService) ValueAtReceipt(txn wallet.Txn) (map[string]float64, error) {
+	snapshot, err := ws.RatesAtReceipt(txn.Txid)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+	value, err := strconv.ParseFloat(txn.Value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing value of tx (%s): %s", txn.Txid, err.Error())
+	}
+	coins := value / util.SatoshisPerCoin(ws.coinType)
+	values := make(map[string]float64, len(snapshot.Rates))
+	for currency, rate := range snapshot.Rates {
+		values[currency] = coins * rate
+	}
+	return values, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// fixedRates prices the coin at the same rates until they are changed
+type fixedRates map[string]float64
+
+func (r fixedRates) GetExchangeRate(currencyCode string) (float64, error) {
+	return r[currencyCode], nil
+}
+
+func (r fixedRates) GetLatestRate(currencyCode string) (float64, error) {
+	return r[currencyCode], nil
+}
+
+func (r fixedRates) GetAllRates(cacheOK bool) (map[string]float64, error) {
+	rates := make(map[string]float64, len(r))
+	for k, v := range r {
+		rates[k] = v
+	}
+	return rates, nil
+}
+
+func (r fixedRates) UnitsPerCoin() int64 {
+	return 100000000
+}
+
+// plainTxnDatastore hides the rate support of the mock datastore so the cache is used
+type plainTxnDatastore struct {
+	wallet.Datastore
+}
+
+func (d plainTxnDatastore) Txns() wallet.Txns {
+	return struct{ wallet.Txns }{d.Datastore.Txns()}
+}
+
+// spendingTx returns a transaction spending 0.002 coins from ourAddress, confirmed at blockTime
+// or unconfirmed if it is zero
+func spendingTx(seed byte, blockTime time.Time) model.Transaction {
+	u := model.Transaction{
+		Txid:    chainhash.DoubleHashH([]byte{seed}).String(),
+		Version: 2,
+		Inputs: []model.Input{{
+			Txid:  chainhash.DoubleHashH([]byte{seed, seed}).String(),
+			Vout:  1,
+			Addr:  "ourAddress",
+			Value: 0.002,
+		}},
+	}
+	if !blockTime.IsZero() {
+		u.Confirmations = 1
+		u.BlockTime = blockTime.Unix()
+	}
+	return u
+}
+
+func TestWalletService_ValueAtReceipt(t *testing.T) {
+	for _, plain := range []bool{false, true} {
+		ws, err := mockWalletService()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plain {
+			ws.db = plainTxnDatastore{ws.db}
+		}
+		rates := fixedRates{"USD": 50000, "EUR": 40000}
+		ws.SetExchangeRates(rates)
+		addrs := map[string]storedAddress{"ourAddress": {}}
+
+		u := spendingTx(1, time.Time{})
+		ws.saveSingleTxToDB(u, 100, addrs)
+
+		// Seeing the transaction again, once confirmed, keeps the rates it was received at
+		rates["USD"] = 60000
+		u.Confirmations = 1
+		u.BlockTime = time.Now().Unix()
+		ws.saveSingleTxToDB(u, 100, addrs)
+
+		txid, _ := chainhash.NewHashFromStr(u.Txid)
+		txn, err := ws.db.Txns().Get(*txid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values, err := ws.ValueAtReceipt(txn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 2 || values["USD"] != -100 || values["EUR"] != -80 {
+			t.Errorf("Expected values of -100 USD and -80 EUR at receipt, got %v", values)
+		}
+		snapshot, err := ws.RatesAtReceipt(u.Txid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot == nil || time.Since(snapshot.Recorded) > time.Minute {
+			t.Errorf("Expected rates recorded just now, got %v", snapshot)
+		}
+	}
+}
+
+func TestWalletService_ValueAtReceiptHistorical(t *testing.T) {
+	ws, err := mockWalletService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.SetExchangeRates(fixedRates{"USD": 50000})
+
+	// Today's rates say nothing about what a transaction from last year was worth
+	u := spendingTx(2, time.Now().AddDate(-1, 0, 0))
+	ws.saveSingleTxToDB(u, 100, map[string]storedAddress{"ourAddress": {}})
+
+	txid, _ := chainhash.NewHashFromStr(u.Txid)
+	txn, err := ws.db.Txns().Get(*txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := ws.ValueAtReceipt(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values != nil {
+		t.Errorf("Expected no value at receipt for a historical transaction, got %v", values)
+	}
+}
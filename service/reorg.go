@@ -0,0 +1,149 @@
+package service
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// maxBlockHistory is the number of recent block hashes kept for locating the fork point of a reorg
+const maxBlockHistory = 100
+
+func (ws *WalletService) blockHistoryKey() string {
+	return ws.cacheKey("block-history")
+}
+
+// loadBlockHistory reads the recent block hashes persisted by a previous run
+func (ws *WalletService) loadBlockHistory() {
+	b, err := ws.cache.Get(ws.blockHistoryKey())
+	if err != nil {
+		return
+	}
+	var history []HashAndHeight
+	if err := json.Unmarshal(b, &history); err != nil {
+		Log.Warningf("discarding unreadable %s block history: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+	ws.blockHistory = history
+}
+
+// recordBlock adds a block to the recent history, replacing any blocks at or above its height
+// since they can no longer be on the best chain. The caller must hold ws.lock.
+func (ws *WalletService) recordBlock(hh HashAndHeight) error {
+	var history []HashAndHeight
+	for _, h := range ws.blockHistory {
+		if h.Height < hh.Height {
+			history = append(history, h)
+		}
+	}
+	history = append(history, hh)
+	if len(history) > maxBlockHistory {
+		history = history[len(history)-maxBlockHistory:]
+	}
+	ws.blockHistory = history
+
+	b, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	return ws.cache.Set(ws.blockHistoryKey(), b)
+}
+
+// findForkPoint returns the height of the highest block in our recent history which is also an
+// ancestor of block. If none of the history is on the new chain the fork is deeper than we can
+// see and the height below the oldest recorded block is returned. ok is false if there is no
+// history to compare against.
+func (ws *WalletService) findForkPoint(block model.Block) (forkHeight int32, ok bool) {
+	ws.lock.RLock()
+	history := make([]HashAndHeight, len(ws.blockHistory))
+	copy(history, ws.blockHistory)
+	ws.lock.RUnlock()
+
+	if len(history) == 0 {
+		return 0, false
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Height > history[j].Height })
+
+	hashAPI, canQuery := ws.client.(model.BlockHashAPIClient)
+	for _, h := range history {
+		var chainHash string
+		switch {
+		case int(h.Height) > block.Height:
+			continue
+		case int(h.Height) == block.Height:
+			chainHash = block.Hash
+		case int(h.Height) == block.Height-1:
+			chainHash = block.PreviousBlockhash
+		case canQuery:
+			hash, err := hashAPI.GetBlockHash(int(h.Height))
+			if err != nil {
+				Log.Errorf("error querying %s block hash at height %d: %s", util.CurrencyCode(ws.coinType), h.Height, err.Error())
+				return int32(history[len(history)-1].Height) - 1, true
+			}
+			chainHash = hash
+		default:
+			return int32(history[len(history)-1].Height) - 1, true
+		}
+		if chainHash == h.Hash {
+			return int32(h.Height), true
+		}
+	}
+	return int32(history[len(history)-1].Height) - 1, true
+}
+
+// rollbackTo marks every transaction and utxo confirmed above forkHeight as unconfirmed and tells
+// the listeners about each transaction whose state changed. Heights are restored by the next sync.
+func (ws *WalletService) rollbackTo(forkHeight int32) {
+	txns, err := ws.db.Txns().GetAll(true)
+	if err != nil {
+		Log.Errorf("error loading %s txs from db: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+	for _, txn := range txns {
+		if txn.Height <= forkHeight {
+			continue
+		}
+		txHash, err := chainhash.NewHashFromStr(txn.Txid)
+		if err != nil {
+			Log.Errorf("error converting to txHash for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
+			continue
+		}
+		if err := ws.db.Txns().UpdateHeight(*txHash, 0, txn.Timestamp); err != nil {
+			Log.Errorf("resetting height for tx (%s): %s", txn.Txid, err.Error())
+			continue
+		}
+		Log.Infof("%s tx %s at height %d was orphaned by reorg", util.CurrencyCode(ws.coinType), txn.Txid, txn.Height)
+		value, ok := new(big.Int).SetString(txn.Value, 10)
+		if !ok {
+			value = new(big.Int)
+		}
+		ws.callbackListeners(wallet.TransactionCallback{
+			Txid:      txn.Txid,
+			Value:     *value,
+			Height:    0,
+			Timestamp: txn.Timestamp,
+			WatchOnly: txn.WatchOnly,
+		})
+	}
+
+	utxos, err := ws.db.Utxos().GetAll()
+	if err != nil {
+		Log.Errorf("error loading %s utxos from db: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+	for _, u := range utxos {
+		if u.AtHeight <= forkHeight {
+			continue
+		}
+		u.AtHeight = 0
+		if err := ws.db.Utxos().Put(u); err != nil {
+			Log.Errorf("resetting utxo (%s) height: %s", u.Op.Hash.String(), err.Error())
+		}
+	}
+	ws.rewindSyncCursors(forkHeight)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/multiwallet/model/mock"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcutil"
+)
+
+const reorgTestTxid = "1be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428"
+
+func testBlockHash(fork, height int) string {
+	return fmt.Sprintf("%032x%032x", fork, height)
+}
+
+type callbackRecorder struct {
+	sync.Mutex
+	callbacks []wallet.TransactionCallback
+}
+
+func (r *callbackRecorder) record(cb wallet.TransactionCallback) {
+	r.Lock()
+	defer r.Unlock()
+	r.callbacks = append(r.callbacks, cb)
+}
+
+func (r *callbackRecorder) sawHeight(txid string, height int32) bool {
+	r.Lock()
+	defer r.Unlock()
+	for _, cb := range r.callbacks {
+		if cb.Txid == txid && cb.Height == height {
+			return true
+		}
+	}
+	return false
+}
+
+// mockReorgWalletService returns a service synced to a scripted chain of blocks 1000-1002 with
+// a payment to one of our addresses confirmed in block 1002
+func mockReorgWalletService(t *testing.T) (*WalletService, *mock.ScriptedAPIClient, *callbackRecorder) {
+	ws, err := mockWalletService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		addrs    = ws.getStoredAddresses()
+		cli      = mock.NewScriptedAPIClient(999, testBlockHash(0, 999))
+		recorder = &callbackRecorder{}
+		payee    btcutil.Address
+	)
+	for _, sa := range addrs {
+		payee = sa.Addr
+		break
+	}
+	ws.client = cli
+	ws.AddTransactionListener(recorder.record)
+
+	blocks := cli.Extend(testBlockHash(0, 1000), testBlockHash(0, 1001), testBlockHash(0, 1002))
+	ws.lock.Lock()
+	if err := ws.saveHashAndHeight(blocks[0].Hash, uint32(blocks[0].Height)); err != nil {
+		t.Fatal(err)
+	}
+	ws.lock.Unlock()
+	for _, block := range blocks[1:] {
+		ws.processIncomingBlock(block)
+	}
+	cli.AddTransaction(paymentTo(payee, reorgTestTxid, 0), testBlockHash(0, 1002))
+	ws.syncTxs(addrs, nil)
+	ws.syncUtxos(addrs, nil)
+
+	if txn := mustGetTxn(t, ws); txn.Height != 1002 {
+		t.Fatalf("expected payment to be confirmed at 1002 before the reorg, got %d", txn.Height)
+	}
+	return ws, cli, recorder
+}
+
+func mustGetTxn(t *testing.T, ws *WalletService) wallet.Txn {
+	hash, err := chainhash.NewHashFromStr(reorgTestTxid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err := ws.db.Txns().Get(*hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txn
+}
+
+func waitFor(t *testing.T, description string, condition func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", description)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWalletService_reorgRollsBackOrphanedTransactions(t *testing.T) {
+	ws, cli, recorder := mockReorgWalletService(t)
+
+	// Block 1002 is orphaned and the payment returns to the mempool
+	newBlocks := cli.Reorg(1001, testBlockHash(1, 1002), testBlockHash(1, 1003))
+	ws.processIncomingBlock(newBlocks[len(newBlocks)-1])
+
+	if txn := mustGetTxn(t, ws); txn.Height != 0 {
+		t.Errorf("expected orphaned payment to be unconfirmed, got height %d", txn.Height)
+	}
+	if !recorder.sawHeight(reorgTestTxid, 0) {
+		t.Error("expected listeners to be told the payment is unconfirmed")
+	}
+	utxos, err := ws.db.Utxos().GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range utxos {
+		if u.Op.Hash.String() == reorgTestTxid && u.AtHeight != 0 {
+			t.Errorf("expected orphaned utxo to be unconfirmed, got height %d", u.AtHeight)
+		}
+	}
+	height, hash := ws.ChainTip()
+	if height != 1003 || hash.String() != testBlockHash(1, 1003) {
+		t.Errorf("expected tip to move to the new chain, got %d %s", height, hash.String())
+	}
+}
+
+func TestWalletService_reorgReconfirmsTransactionsOnNewChain(t *testing.T) {
+	ws, cli, recorder := mockReorgWalletService(t)
+
+	// The payment is mined again in the replacement for block 1003
+	newBlocks := cli.Reorg(1001, testBlockHash(1, 1002), testBlockHash(1, 1003))
+	cli.Confirm(reorgTestTxid, testBlockHash(1, 1003))
+	ws.processIncomingBlock(newBlocks[len(newBlocks)-1])
+
+	if !recorder.sawHeight(reorgTestTxid, 0) {
+		t.Error("expected listeners to be told the payment was orphaned")
+	}
+	waitFor(t, "payment to confirm on the new chain", func() bool {
+		return mustGetTxn(t, ws).Height == 1003 && recorder.sawHeight(reorgTestTxid, 1003)
+	})
+}
+
+func TestWalletService_findForkPoint(t *testing.T) {
+	ws, cli, _ := mockReorgWalletService(t)
+
+	// A block extending our tip is not a fork
+	next := cli.Extend(testBlockHash(0, 1003))[0]
+	if fork, ok := ws.findForkPoint(next); !ok || fork != 1002 {
+		t.Errorf("expected fork point at our tip 1002, got %d %t", fork, ok)
+	}
+
+	// A reorg deeper than the recorded history rolls back everything we know of
+	deep := cli.Reorg(999, testBlockHash(2, 1000), testBlockHash(2, 1001), testBlockHash(2, 1002), testBlockHash(2, 1003))
+	if fork, ok := ws.findForkPoint(deep[len(deep)-1]); !ok || fork != 999 {
+		t.Errorf("expected fork point below the history at 999, got %d %t", fork, ok)
+	}
+
+	ws.blockHistory = nil
+	if _, ok := ws.findForkPoint(model.Block{Hash: testBlockHash(3, 1004), Height: 1004}); ok {
+		t.Error("expected no fork point without any history")
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/btcsuite/btcutil"
+)
+
+// cursorReorgDepth is how far below the chain height a cursor without a transaction
+// is rewound when it is next queried so that a reorg up to this depth is still seen.
+const cursorReorgDepth = 10
+
+// syncCursor records how much of the history for an address (or an account xpub) has
+// already been downloaded. Height is the highest confirmed height we have seen and Txid
+// is a transaction confirmed at that height, if any. Finding Txid at Height again on the
+// next query proves no reorg has crossed the cursor.
+type syncCursor struct {
+	Height int    `json:"height"`
+	Txid   string `json:"txid"`
+}
+
+// fromHeight returns the height from which history should next be requested
+func (c syncCursor) fromHeight() int {
+	if c.Txid != "" {
+		return c.Height
+	}
+	if c.Height <= cursorReorgDepth {
+		return 0
+	}
+	return c.Height - cursorReorgDepth
+}
+
+// confirmedIn reports whether the cursor is still valid given the history downloaded
+// since it. A cursor is invalid if its transaction is missing or moved to another height.
+func (c syncCursor) confirmedIn(txs []model.Transaction, chainHeight int32) bool {
+	if c.Txid == "" {
+		return true
+	}
+	for _, tx := range txs {
+		if tx.Txid == c.Txid {
+			return int(txHeight(tx, chainHeight)) == c.Height
+		}
+	}
+	return false
+}
+
+// advance returns the cursor moved past the given transactions. Without a confirmed
+// transaction to anchor it the cursor moves up to the chain height.
+func (c syncCursor) advance(txs []model.Transaction, chainHeight int32) syncCursor {
+	for _, tx := range txs {
+		if h := int(txHeight(tx, chainHeight)); h > 0 && h >= c.Height {
+			c = syncCursor{Height: h, Txid: tx.Txid}
+		}
+	}
+	if c.Txid == "" && int(chainHeight) > c.Height {
+		c.Height = int(chainHeight)
+	}
+	return c
+}
+
+// txHeight returns the height a transaction confirmed at or zero if it is unconfirmed.
+// The height reported by the server is preferred as confirmations are relative to its tip.
+func txHeight(tx model.Transaction, chainHeight int32) int32 {
+	if tx.Confirmations <= 0 {
+		return 0
+	}
+	if tx.BlockHeight > 0 {
+		return int32(tx.BlockHeight)
+	}
+	return chainHeight - (int32(tx.Confirmations) - 1)
+}
+
+// txsForAddress filters txs down to those which spend from or pay to addr
+func txsForAddress(txs []model.Transaction, addr string) []model.Transaction {
+	var ret []model.Transaction
+	for _, tx := range txs {
+		if txTouchesAddress(tx, addr) {
+			ret = append(ret, tx)
+		}
+	}
+	return ret
+}
+
+func txTouchesAddress(tx model.Transaction, addr string) bool {
+	for _, in := range tx.Inputs {
+		if in.Addr == addr {
+			return true
+		}
+	}
+	for _, out := range tx.Outputs {
+		if len(out.ScriptPubKey.Addresses) > 0 && out.ScriptPubKey.Addresses[0] == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// groupByCursor groups the addresses by the height their history should be requested
+// from. Addresses without a cursor are grouped under zero for a full query.
+func groupByCursor(addrs []btcutil.Address, cursors map[string]syncCursor) map[int][]btcutil.Address {
+	groups := make(map[int][]btcutil.Address)
+	for _, addr := range addrs {
+		var from int
+		if c, ok := cursors[addr.String()]; ok {
+			from = c.fromHeight()
+		}
+		groups[from] = append(groups[from], addr)
+	}
+	return groups
+}
+
+func (ws *WalletService) syncCursorsKey() string {
+	return ws.cacheKey("sync-cursors")
+}
+
+// loadSyncCursors returns the persisted cursors keyed by address or account xpub
+func (ws *WalletService) loadSyncCursors() map[string]syncCursor {
+	cursors := make(map[string]syncCursor)
+	b, err := ws.cache.Get(ws.syncCursorsKey())
+	if err != nil {
+		return cursors
+	}
+	if err := json.Unmarshal(b, &cursors); err != nil {
+		Log.Warningf("discarding unreadable %s sync cursors: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return make(map[string]syncCursor)
+	}
+	return cursors
+}
+
+func (ws *WalletService) saveSyncCursors(cursors map[string]syncCursor) {
+	b, err := json.Marshal(cursors)
+	if err != nil {
+		Log.Errorf("marshaling %s sync cursors: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+	if err := ws.cache.Set(ws.syncCursorsKey(), b); err != nil {
+		Log.Errorf("saving %s sync cursors: %s", util.CurrencyCode(ws.coinType), err.Error())
+	}
+}
+
+// rewindSyncCursors moves every cursor above forkHeight back to it so the history orphaned
+// by a reorg is requested again
+func (ws *WalletService) rewindSyncCursors(forkHeight int32) {
+	cursors := ws.loadSyncCursors()
+	for key, c := range cursors {
+		if c.Height > int(forkHeight) {
+			cursors[key] = syncCursor{Height: int(forkHeight)}
+		}
+	}
+	ws.saveSyncCursors(cursors)
+}
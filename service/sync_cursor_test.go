@@ -0,0 +1,158 @@
+package service
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+// incrementalClient serves a scripted history and records how it was queried
+type incrementalClient struct {
+	model.APIClient
+
+	history      map[string][]model.Transaction
+	fullQueries  []btcutil.Address
+	sinceQueries map[int][]btcutil.Address
+}
+
+func (c *incrementalClient) GetTransactions(addrs []btcutil.Address) ([]model.Transaction, error) {
+	c.fullQueries = append(c.fullQueries, addrs...)
+	return c.GetTransactionsSince(addrs, 0)
+}
+
+func (c *incrementalClient) GetTransactionsSince(addrs []btcutil.Address, fromHeight int) ([]model.Transaction, error) {
+	if fromHeight > 0 {
+		c.sinceQueries[fromHeight] = append(c.sinceQueries[fromHeight], addrs...)
+	}
+	var txs []model.Transaction
+	for _, addr := range addrs {
+		for _, tx := range c.history[addr.String()] {
+			if fromHeight == 0 || tx.Confirmations == 0 || tx.BlockHeight >= fromHeight {
+				txs = append(txs, tx)
+			}
+		}
+	}
+	return txs, nil
+}
+
+func (c *incrementalClient) reset() {
+	c.fullQueries = nil
+	c.sinceQueries = make(map[int][]btcutil.Address)
+}
+
+func paymentTo(addr btcutil.Address, txid string, height int) model.Transaction {
+	script, _ := txscript.PayToAddrScript(addr)
+	tx := model.Transaction{
+		Txid:        txid,
+		Version:     1,
+		BlockHeight: height,
+		Outputs: []model.Output{{
+			Value: 0.1,
+			ScriptPubKey: model.OutScript{
+				Script:    model.Script{Hex: hex.EncodeToString(script)},
+				Addresses: []string{addr.String()},
+			},
+		}},
+	}
+	if height > 0 {
+		tx.Confirmations = 1000 - height + 1
+	}
+	return tx
+}
+
+func TestSyncCursorFromHeight(t *testing.T) {
+	if h := (syncCursor{Height: 500, Txid: "abc"}).fromHeight(); h != 500 {
+		t.Errorf("expected cursor with a tx to be queried from its height, got %d", h)
+	}
+	if h := (syncCursor{Height: 500}).fromHeight(); h != 500-cursorReorgDepth {
+		t.Errorf("expected cursor without a tx to be rewound by the reorg depth, got %d", h)
+	}
+	if h := (syncCursor{Height: 5}).fromHeight(); h != 0 {
+		t.Errorf("expected shallow cursor to be queried in full, got %d", h)
+	}
+}
+
+func TestSyncCursorAdvance(t *testing.T) {
+	var (
+		txs = []model.Transaction{
+			{Txid: "a", BlockHeight: 900, Confirmations: 101},
+			{Txid: "b", BlockHeight: 950, Confirmations: 51},
+			{Txid: "c"},
+		}
+		cursor = syncCursor{}.advance(txs, 1000)
+	)
+	if cursor.Height != 950 || cursor.Txid != "b" {
+		t.Errorf("expected cursor to move to the highest confirmed tx, got %+v", cursor)
+	}
+	if !cursor.confirmedIn(txs, 1000) {
+		t.Error("expected cursor to be confirmed by the history it was built from")
+	}
+	moved := []model.Transaction{{Txid: "b", BlockHeight: 951, Confirmations: 50}}
+	if cursor.confirmedIn(moved, 1000) {
+		t.Error("expected cursor to be invalid once its tx moved height")
+	}
+	if cursor.confirmedIn(nil, 1000) {
+		t.Error("expected cursor to be invalid once its tx disappeared")
+	}
+	if empty := (syncCursor{}).advance(nil, 1000); empty.Height != 1000 || empty.Txid != "" {
+		t.Errorf("expected empty history to move cursor to the chain height, got %+v", empty)
+	}
+}
+
+func TestWalletService_syncTxsIncremental(t *testing.T) {
+	ws, err := mockWalletService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		addrs = ws.getStoredAddresses()
+		used  btcutil.Address
+	)
+	for _, sa := range addrs {
+		used = sa.Addr
+		break
+	}
+	cli := &incrementalClient{
+		APIClient: ws.client,
+		history: map[string][]model.Transaction{
+			used.String(): {paymentTo(used, "1be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428", 980)},
+		},
+	}
+	cli.reset()
+	ws.client = cli
+	ws.chainHeight = 1000
+
+	// The first sync has no cursors and downloads everything
+	ws.syncTxs(addrs, nil)
+	if len(cli.fullQueries) != len(addrs) || len(cli.sinceQueries) != 0 {
+		t.Fatalf("expected a full query for all %d addresses, got %d full and %v incremental", len(addrs), len(cli.fullQueries), cli.sinceQueries)
+	}
+	cursors := ws.loadSyncCursors()
+	if c := cursors[used.String()]; c.Height != 980 || c.Txid != "1be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428" {
+		t.Errorf("expected cursor at the payment, got %+v", c)
+	}
+
+	// The next sync only asks for history after the cursors
+	cli.reset()
+	ws.syncTxs(addrs, nil)
+	if len(cli.fullQueries) != 0 {
+		t.Errorf("expected no full queries, got %d", len(cli.fullQueries))
+	}
+	if len(cli.sinceQueries[980]) != 1 || len(cli.sinceQueries[1000-cursorReorgDepth]) != len(addrs)-1 {
+		t.Errorf("expected incremental queries from each cursor, got %v", cli.sinceQueries)
+	}
+
+	// A reorg which moves the cursor tx forces a full resync of only that address
+	cli.history[used.String()] = []model.Transaction{paymentTo(used, "1be612e4f2b79af279e0b307337924072b819b3aca09fcb20370dd9492b83428", 981)}
+	cli.reset()
+	ws.syncTxs(addrs, nil)
+	if len(cli.fullQueries) != 1 || cli.fullQueries[0].String() != used.String() {
+		t.Errorf("expected a full resync of %s only, got %v", used, cli.fullQueries)
+	}
+	if c := ws.loadSyncCursors()[used.String()]; c.Height != 981 {
+		t.Errorf("expected cursor to follow the reorg, got %+v", c)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TokenBalance is the CashTokens of one category held by the wallet
+type TokenBalance struct {
+	Category string
+	Amount   uint64
+	NFTs     int
+}
+
+// tokenRecord is how a CashToken is kept in the cache
+type tokenRecord struct {
+	Category   string `json:"category"`
+	Amount     uint64 `json:"amount,omitempty"`
+	HasNFT     bool   `json:"hasNFT,omitempty"`
+	Capability byte   `json:"capability,omitempty"`
+	Commitment string `json:"commitment,omitempty"`
+}
+
+func (ws *WalletService) tokenUtxosKey() string {
+	return ws.cacheKey("token-utxos")
+}
+
+// tokenUtxos returns the tokens carried by outputs of the wallet. Entries for outputs which have
+// since been spent are kept, so callers look up the outpoints they hold. The caller must hold
+// ws.tokenLock.
+func (ws *WalletService) tokenUtxos() (map[wire.OutPoint]*util.CashToken, error) {
+	tokens := make(map[wire.OutPoint]*util.CashToken)
+	b, err := ws.cache.Get(ws.tokenUtxosKey())
+	if err != nil {
+		// No token has been received yet
+		return tokens, nil
+	}
+	var records map[string]tokenRecord
+	if err := json.Unmarshal(b, &records); err != nil {
+		return nil, fmt.Errorf("reading %s token utxos: %s", util.CurrencyCode(ws.coinType), err.Error())
+	}
+	for s, r := range records {
+		op, err := util.ParseOutPoint(s)
+		if err != nil {
+			return nil, err
+		}
+		category, err := chainhash.NewHashFromStr(r.Category)
+		if err != nil {
+			return nil, err
+		}
+		commitment, err := hex.DecodeString(r.Commitment)
+		if err != nil {
+			return nil, err
+		}
+		tokens[op] = &util.CashToken{
+			Category:   *category,
+			Amount:     r.Amount,
+			HasNFT:     r.HasNFT,
+			Capability: util.NFTCapability(r.Capability),
+			Commitment: commitment,
+		}
+	}
+	return tokens, nil
+}
+
+// saveTokenUtxo records that op carries token
+func (ws *WalletService) saveTokenUtxo(op wire.OutPoint, token *util.CashToken) error {
+	ws.tokenLock.Lock()
+	defer ws.tokenLock.Unlock()
+	tokens, err := ws.tokenUtxos()
+	if err != nil {
+		return err
+	}
+	tokens[op] = token
+	records := make(map[string]tokenRecord, len(tokens))
+	for op, t := range tokens {
+		records[op.String()] = tokenRecord{
+			Category:   t.Category.String(),
+			Amount:     t.Amount,
+			HasNFT:     t.HasNFT,
+			Capability: byte(t.Capability),
+			Commitment: hex.EncodeToString(t.Commitment),
+		}
+	}
+	b, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return ws.cache.Set(ws.tokenUtxosKey(), b)
+}
+
+// TokenBalances returns the CashTokens held by the wallet by category, leaving out watch only
+// outputs
+func (ws *WalletService) TokenBalances() ([]TokenBalance, error) {
+	statuses, err := ws.UtxoStatuses()
+	if err != nil {
+		return nil, err
+	}
+	byCategory := make(map[string]*TokenBalance)
+	for _, s := range statuses {
+		if s.Token == nil || s.Utxo.WatchOnly {
+			continue
+		}
+		category := s.Token.Category.String()
+		balance, ok := byCategory[category]
+		if !ok {
+			balance = &TokenBalance{Category: category}
+			byCategory[category] = balance
+		}
+		balance.Amount += s.Token.Amount
+		if s.Token.HasNFT {
+			balance.NFTs++
+		}
+	}
+	balances := make([]TokenBalance, 0, len(byCategory))
+	for _, b := range byCategory {
+		balances = append(balances, *b)
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Category < balances[j].Category })
+	return balances, nil
+}
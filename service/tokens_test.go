@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestWalletService_SaveTokenUtxo(t *testing.T) {
+	ws, err := mockWalletService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.coinType = wallet.BitcoinCash
+	addrs := map[string]storedAddress{"qp3wjpa3tjlj042z2wv7hahsldgwhwy0rq9sywjpyy": {}}
+
+	lockingScript := "76a9148132712c3ff19f3a151234616777420a6d7ef22688ac"
+	category := chainhash.DoubleHashH([]byte("category"))
+	plain := chainhash.DoubleHashH([]byte("plain"))
+	tokenTx := chainhash.DoubleHashH([]byte("token"))
+	ws.saveSingleUtxoToDB(model.Utxo{
+		Txid:          plain.String(),
+		Address:       "qp3wjpa3tjlj042z2wv7hahsldgwhwy0rq9sywjpyy",
+		ScriptPubKey:  lockingScript,
+		Satoshis:      100000,
+		Confirmations: 1,
+	}, addrs, 1000)
+	// Fungible and non-fungible tokens, both received twice
+	for i, prefix := range []string{"10fde803", "20", "10fde803", "6002cafe"} {
+		ws.saveSingleUtxoToDB(model.Utxo{
+			Txid:          tokenTx.String(),
+			Vout:          i,
+			Address:       "qp3wjpa3tjlj042z2wv7hahsldgwhwy0rq9sywjpyy",
+			ScriptPubKey:  "ef" + hex.EncodeToString(category[:]) + prefix + lockingScript,
+			Satoshis:      1000,
+			Confirmations: 1,
+		}, addrs, 1000)
+	}
+
+	statuses, err := ws.UtxoStatuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, _ := hex.DecodeString(lockingScript)
+	for _, s := range statuses {
+		if !bytes.Equal(s.Utxo.ScriptPubkey, script) {
+			t.Errorf("expected %s to be saved with the locking script, got %x", s.Utxo.Op.String(), s.Utxo.ScriptPubkey)
+		}
+		if (s.Token != nil) != (s.Utxo.Op.Hash == tokenTx) {
+			t.Errorf("wrong token for %s", s.Utxo.Op.String())
+		}
+	}
+
+	spendable, err := ws.SpendableUtxos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spendable) != 1 || spendable[0].Op.Hash != plain {
+		t.Errorf("expected only the plain output to be spendable, got %v", spendable)
+	}
+	if _, err := ws.SelectUtxos([]wire.OutPoint{*wire.NewOutPoint(&tokenTx, 0)}); err == nil {
+		t.Error("expected an output carrying a token to be refused")
+	}
+
+	balances, err := ws.TokenBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balances) != 1 || balances[0].Category != category.String() || balances[0].Amount != 2000 || balances[0].NFTs != 2 {
+		t.Errorf("wrong token balances %+v", balances)
+	}
+}
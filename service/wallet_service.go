@@ -12,8 +12,11 @@ import (
 	"time"
 
 	"github.com/OpenBazaar/multiwallet/cache"
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
+	"github.com/OpenBazaar/multiwallet/coins"
 	"github.com/OpenBazaar/multiwallet/keys"
 	laddr "github.com/OpenBazaar/multiwallet/litecoin/address"
+	"github.com/OpenBazaar/multiwallet/litecoin/mweb"
 	"github.com/OpenBazaar/multiwallet/model"
 	"github.com/OpenBazaar/multiwallet/util"
 	zaddr "github.com/OpenBazaar/multiwallet/zcash/address"
@@ -36,14 +39,28 @@ type WalletService struct {
 	params   *chaincfg.Params
 	coinType wallet.CoinType
 
-	chainHeight uint32
-	bestBlock   string
-	cache       cache.Cacher
+	chainHeight  uint32
+	bestBlock    string
+	blockHistory []HashAndHeight
+	cache        cache.Cacher
 
-	listeners []func(wallet.TransactionCallback)
+	listeners      []func(wallet.TransactionCallback)
+	blockListeners []func(height uint32)
 
 	lock sync.RWMutex
 
+	spends     *spendIndex
+	spendsLock sync.Mutex
+
+	broadcasts     map[string]*BroadcastRecord
+	broadcastsLock sync.Mutex
+	abandonAfter   uint32
+
+	frozenLock sync.Mutex
+	tokenLock  sync.Mutex
+
+	exchangeRates wallet.ExchangeRates
+
 	doneChan chan struct{}
 }
 
@@ -66,10 +83,11 @@ func NewWalletService(db wallet.Datastore, km *keys.KeyManager, client model.API
 			chainHeight: 0,
 			bestBlock:   nullHash,
 
-			cache:     cache,
-			listeners: []func(wallet.TransactionCallback){},
-			lock:      sync.RWMutex{},
-			doneChan:  make(chan struct{}),
+			cache:        cache,
+			listeners:    []func(wallet.TransactionCallback){},
+			lock:         sync.RWMutex{},
+			abandonAfter: defaultAbandonAfterBlocks,
+			doneChan:     make(chan struct{}),
 		}
 		marshaledHeight, err = cache.Get(ws.bestHeightKey())
 	)
@@ -85,11 +103,12 @@ func NewWalletService(db wallet.Datastore, km *keys.KeyManager, client model.API
 		ws.bestBlock = hh.Hash
 		ws.chainHeight = hh.Height
 	}
+	ws.loadBlockHistory()
 	return ws, nil
 }
 
 func (ws *WalletService) Start() {
-	Log.Noticef("starting %s WalletService", ws.coinType.String())
+	Log.Noticef("starting %s WalletService", util.CurrencyCode(ws.coinType))
 	go ws.UpdateState()
 	go ws.listen()
 }
@@ -112,6 +131,12 @@ func (ws *WalletService) AddTransactionListener(callback func(callback wallet.Tr
 	ws.listeners = append(ws.listeners, callback)
 }
 
+// AddBlockListener registers a callback to run with the height of each new block. It should be
+// called before the service is started.
+func (ws *WalletService) AddBlockListener(callback func(height uint32)) {
+	ws.blockListeners = append(ws.blockListeners, callback)
+}
+
 // InvokeTransactionListeners will invoke the transaction listeners for the updation of order state
 func (ws *WalletService) InvokeTransactionListeners(callback wallet.TransactionCallback) {
 	for _, l := range ws.listeners {
@@ -124,7 +149,13 @@ func (ws *WalletService) listen() {
 		addrs     = ws.getStoredAddresses()
 		txChan    = ws.client.TransactionNotify()
 		blockChan = ws.client.BlockNotify()
+
+		// Left nil, and so never ready, for clients which cannot report reconnections
+		reconnectChan <-chan time.Time
 	)
+	if notifier, ok := ws.client.(model.ReconnectNotifier); ok {
+		reconnectChan = notifier.ReconnectNotify()
+	}
 
 	var listenAddrs []btcutil.Address
 	for _, sa := range addrs {
@@ -140,21 +171,26 @@ func (ws *WalletService) listen() {
 			go ws.ProcessIncomingTransaction(tx)
 		case block := <-blockChan:
 			go ws.processIncomingBlock(block)
+		case disconnectedAt := <-reconnectChan:
+			go ws.recoverGap(disconnectedAt)
 		}
 	}
 }
 
 // This is a transaction fresh off the wire. Let's save it to the db.
 func (ws *WalletService) ProcessIncomingTransaction(tx model.Transaction) {
-	Log.Debugf("new incoming %s transaction: %s", ws.coinType.String(), tx.Txid)
+	Log.Debugf("new incoming %s transaction: %s", util.CurrencyCode(ws.coinType), tx.Txid)
 	addrs := ws.getStoredAddresses()
 	ws.lock.RLock()
 	chainHeight := int32(ws.chainHeight)
 	ws.lock.RUnlock()
-	ws.saveSingleTxToDB(tx, chainHeight, addrs)
+	if dead := ws.saveSingleTxToDB(tx, chainHeight, addrs); dead {
+		// A double spent or replaced transaction neither creates nor spends utxos
+		return
+	}
 	utxos, err := ws.db.Utxos().GetAll()
 	if err != nil {
-		Log.Errorf("error loading %s utxos: %s", ws.coinType.String(), err.Error())
+		Log.Errorf("error loading %s utxos: %s", util.CurrencyCode(ws.coinType), err.Error())
 	}
 
 	for _, sa := range addrs {
@@ -192,21 +228,38 @@ func (ws *WalletService) ProcessIncomingTransaction(tx model.Transaction) {
 
 // A new block was found let's update our chain height and best hash and check for a reorg
 func (ws *WalletService) processIncomingBlock(block model.Block) {
-	Log.Infof("received new %s block at height %d: %s", ws.coinType.String(), block.Height, block.Hash)
+	Log.Infof("received new %s block at height %d: %s", util.CurrencyCode(ws.coinType), block.Height, block.Hash)
 	ws.lock.RLock()
-	currentBest := ws.bestBlock
+	currentBest, currentHeight := ws.bestBlock, int32(ws.chainHeight)
 	ws.lock.RUnlock()
 
+	// REORG! Roll back anything confirmed in orphaned blocks then rescan all transactions
+	// and utxos to see where they ended up
+	reorg := currentBest != block.PreviousBlockhash && currentBest != block.Hash
+	if reorg {
+		if forkHeight, ok := ws.findForkPoint(block); ok && forkHeight == currentHeight {
+			// Our tip is still on the best chain, we only missed the blocks since
+			Log.Infof("%s chain advanced %d blocks since our tip", util.CurrencyCode(ws.coinType), int32(block.Height)-currentHeight)
+			reorg = false
+		} else if ok {
+			Log.Warningf("%s chain reorg detected: rolling back to height %d and rescanning wallet", util.CurrencyCode(ws.coinType), forkHeight)
+			ws.rollbackTo(forkHeight)
+		} else {
+			Log.Warningf("%s chain reorg detected: rescanning wallet", util.CurrencyCode(ws.coinType))
+		}
+	}
+
 	ws.lock.Lock()
 	err := ws.saveHashAndHeight(block.Hash, uint32(block.Height))
 	if err != nil {
-		Log.Errorf("update %s blockchain height: %s", ws.coinType.String(), err.Error())
+		Log.Errorf("update %s blockchain height: %s", util.CurrencyCode(ws.coinType), err.Error())
 	}
 	ws.lock.Unlock()
+	for _, l := range ws.blockListeners {
+		go l(uint32(block.Height))
+	}
 
-	// REORG! Rescan all transactions and utxos to see if anything changed
-	if currentBest != block.PreviousBlockhash && currentBest != block.Hash {
-		Log.Warningf("%s chain reorg detected: rescanning wallet", ws.coinType.String())
+	if reorg {
 		ws.UpdateState()
 		return
 	}
@@ -214,22 +267,28 @@ func (ws *WalletService) processIncomingBlock(block model.Block) {
 	// Query db for unconfirmed txs and utxos then query API to get current height
 	txs, err := ws.db.Txns().GetAll(true)
 	if err != nil {
-		Log.Errorf("error loading %s txs from db: %s", ws.coinType.String(), err.Error())
+		Log.Errorf("error loading %s txs from db: %s", util.CurrencyCode(ws.coinType), err.Error())
 		return
 	}
 	utxos, err := ws.db.Utxos().GetAll()
 	if err != nil {
-		Log.Errorf("error loading %s txs from db: %s", ws.coinType.String(), err.Error())
+		Log.Errorf("error loading %s txs from db: %s", util.CurrencyCode(ws.coinType), err.Error())
 		return
 	}
 	addrs := ws.getStoredAddresses()
 	for _, tx := range txs {
 		if tx.Height == 0 {
-			Log.Debugf("broadcasting unconfirmed txid %s", tx.Txid)
+			Log.Debugf("checking unconfirmed txid %s", tx.Txid)
 			go func(txn wallet.Txn) {
-				ret, err := ws.client.GetTransaction(txn.Txid)
+				ret, err := ws.findTransaction(txn.Txid)
+				if clientErr.KindOf(err) == clientErr.KindBackendUnavailable {
+					Log.Errorf("error fetching unconfirmed %s tx: %s", util.CurrencyCode(ws.coinType), err.Error())
+					return
+				}
 				if err != nil {
-					Log.Errorf("error fetching unconfirmed %s tx: %s", ws.coinType.String(), err.Error())
+					// No server knows the transaction so it never reached or has since left
+					// the mempool
+					ws.rebroadcast(txn, uint32(block.Height))
 					return
 				}
 				if ret.Confirmations > 0 {
@@ -244,58 +303,113 @@ func (ws *WalletService) processIncomingBlock(block model.Block) {
 							continue
 						}
 					}
+					ws.forgetBroadcast(txn.Txid)
 					return
 				}
-				// Rebroadcast unconfirmed transactions
-				_, err = ws.client.Broadcast(tx.Bytes)
-				if err != nil {
-					Log.Errorf("broadcasting unconfirmed utxo: %s", err.Error())
-				}
+				ws.markBroadcastAccepted(txn.Txid)
 			}(tx)
 		}
 	}
+	ws.pruneBroadcastRecords(txs)
 }
 
 // updateState will query the API for both UTXOs and TXs relevant to our wallet and then update
 // the db state to match the API responses.
 func (ws *WalletService) UpdateState() {
 	// Start by fetching the chain height from the API
-	Log.Debugf("updating %s chain state", ws.coinType.String())
+	Log.Debugf("updating %s chain state", util.CurrencyCode(ws.coinType))
 	best, err := ws.client.GetBestBlock()
 	if err == nil {
-		Log.Debugf("%s chain height: %d", ws.coinType.String(), best.Height)
+		Log.Debugf("%s chain height: %d", util.CurrencyCode(ws.coinType), best.Height)
 		ws.lock.Lock()
 		err = ws.saveHashAndHeight(best.Hash, uint32(best.Height))
 		if err != nil {
-			Log.Errorf("updating %s blockchain height: %s", ws.coinType.String(), err.Error())
+			Log.Errorf("updating %s blockchain height: %s", util.CurrencyCode(ws.coinType), err.Error())
 		}
 		ws.lock.Unlock()
 	} else {
-		Log.Errorf("error querying API for %s chain height: %s", ws.coinType.String(), err.Error())
+		Log.Errorf("error querying API for %s chain height: %s", util.CurrencyCode(ws.coinType), err.Error())
 	}
 
 	// Load wallet addresses and watch only addresses from the db
 	addrs := ws.getStoredAddresses()
+	account := ws.accountQuery(addrs)
+
+	go ws.syncUtxos(addrs, account)
+	go ws.syncTxs(addrs, account)
+
+}
+
+// Query API for UTXOs and synchronize db state. When account is not nil its xpub is queried in
+// place of the addresses it covers.
+func (ws *WalletService) syncUtxos(addrs map[string]storedAddress, account *accountScan) {
+	Log.Debugf("querying for %s utxos", util.CurrencyCode(ws.coinType))
+	var (
+		utxos []model.Utxo
+		query = addressesToQuery(addrs)
+	)
+	if account != nil {
+		accountUtxos, err := account.client.GetAccountUtxos(account.xpub)
+		if err != nil {
+			Log.Warningf("error downloading account utxos for %s, querying addresses: %s", util.CurrencyCode(ws.coinType), err.Error())
+		} else {
+			utxos, query = accountUtxos, account.remaining
+		}
+	}
+	if len(query) > 0 {
+		addrUtxos, err := ws.client.GetUtxos(query)
+		if err != nil {
+			Log.Errorf("error downloading utxos for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
+			return
+		}
+		utxos = append(utxos, addrUtxos...)
+	}
+	Log.Debugf("downloaded %d %s utxos", len(utxos), util.CurrencyCode(ws.coinType))
+	ws.saveUtxosToDB(utxos, addrs)
+}
 
-	go ws.syncUtxos(addrs)
-	go ws.syncTxs(addrs)
+// accountScan is an account xpub the API can query in place of the addresses derived from it.
+// The remaining addresses are those it does not cover and which must be queried individually.
+type accountScan struct {
+	client    model.AccountAPIClient
+	xpub      string
+	remaining []btcutil.Address
+}
 
+// accountQuery checks whether the API can find our derived addresses by scanning the account
+// xpub. If it can, the addresses it does not cover (watch only addresses and any beyond its
+// gap limit) are returned with it so they can be queried individually. It returns nil if the
+// API cannot scan the account.
+func (ws *WalletService) accountQuery(addrs map[string]storedAddress) *accountScan {
+	client, ok := ws.client.(model.AccountAPIClient)
+	if !ok {
+		return nil
+	}
+	xpub := ws.km.AccountXPub()
+	scanned, err := client.GetAccountAddresses(xpub)
+	if err != nil {
+		Log.Debugf("account queries unavailable for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return nil
+	}
+	covered := make(map[string]bool, len(scanned))
+	for _, addr := range scanned {
+		covered[addr] = true
+	}
+	account := &accountScan{client: client, xpub: xpub}
+	for addr, sa := range addrs {
+		if !covered[addr] {
+			account.remaining = append(account.remaining, sa.Addr)
+		}
+	}
+	return account
 }
 
-// Query API for UTXOs and synchronize db state
-func (ws *WalletService) syncUtxos(addrs map[string]storedAddress) {
-	Log.Debugf("querying for %s utxos", ws.coinType.String())
+func addressesToQuery(addrs map[string]storedAddress) []btcutil.Address {
 	var query []btcutil.Address
 	for _, sa := range addrs {
 		query = append(query, sa.Addr)
 	}
-	utxos, err := ws.client.GetUtxos(query)
-	if err != nil {
-		Log.Errorf("error downloading utxos for %s: %s", ws.coinType.String(), err.Error())
-	} else {
-		Log.Debugf("downloaded %d %s utxos", len(utxos), ws.coinType.String())
-		ws.saveUtxosToDB(utxos, addrs)
-	}
+	return query
 }
 
 // For each API response we will have to figure out height at which the UTXO has confirmed (if it has) and
@@ -305,7 +419,7 @@ func (ws *WalletService) saveUtxosToDB(utxos []model.Utxo, addrs map[string]stor
 	// Get current utxos
 	currentUtxos, err := ws.db.Utxos().GetAll()
 	if err != nil {
-		Log.Error("error loading utxos for %s: %s", ws.coinType.String(), err.Error())
+		Log.Error("error loading utxos for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
 		return
 	}
 
@@ -318,7 +432,7 @@ func (ws *WalletService) saveUtxosToDB(utxos []model.Utxo, addrs map[string]stor
 	for _, u := range utxos {
 		ch, err := chainhash.NewHashFromStr(u.Txid)
 		if err != nil {
-			Log.Error("error converting to chainhash for %s: %s", ws.coinType.String(), err.Error())
+			Log.Error("error converting to chainhash for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
 			continue
 		}
 		newU := wallet.Utxo{
@@ -341,15 +455,33 @@ func (ws *WalletService) saveUtxosToDB(utxos []model.Utxo, addrs map[string]stor
 func (ws *WalletService) saveSingleUtxoToDB(u model.Utxo, addrs map[string]storedAddress, chainHeight int32) {
 	ch, err := chainhash.NewHashFromStr(u.Txid)
 	if err != nil {
-		Log.Error("error converting to chainhash for %s: %s", ws.coinType.String(), err.Error())
+		Log.Error("error converting to chainhash for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
 		return
 	}
 	scriptBytes, err := hex.DecodeString(u.ScriptPubKey)
 	if err != nil {
-		Log.Error("error converting to script bytes for %s: %s", ws.coinType.String(), err.Error())
+		Log.Error("error converting to script bytes for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
 		return
 	}
 
+	op := *wire.NewOutPoint(ch, uint32(u.Vout))
+
+	// Keep the token aside so the output is stored with the script which locks it
+	if ws.coinType == wallet.BitcoinCash {
+		token, lockingScript, err := util.ParseTokenPrefix(scriptBytes)
+		if err != nil {
+			Log.Errorf("parsing token prefix of %s: %s", op.String(), err.Error())
+			return
+		}
+		if token != nil {
+			if err := ws.saveTokenUtxo(op, token); err != nil {
+				Log.Errorf("saving token of %s: %s", op.String(), err.Error())
+				return
+			}
+			scriptBytes = lockingScript
+		}
+	}
+
 	var watchOnly bool
 	sa, ok := addrs[u.Address]
 	if sa.WatchOnly || !ok {
@@ -362,7 +494,7 @@ func (ws *WalletService) saveSingleUtxoToDB(u model.Utxo, addrs map[string]store
 	}
 
 	newU := wallet.Utxo{
-		Op:           *wire.NewOutPoint(ch, uint32(u.Vout)),
+		Op:           op,
 		Value:        strconv.FormatInt(u.Satoshis, 10),
 		WatchOnly:    watchOnly,
 		ScriptPubkey: scriptBytes,
@@ -382,22 +514,113 @@ func serializeUtxo(u wallet.Utxo) string {
 	return ser
 }
 
-// Query API for TXs and synchronize db state
-func (ws *WalletService) syncTxs(addrs map[string]storedAddress) {
-	Log.Debugf("querying for %s transactions", ws.coinType.String())
-	var query []btcutil.Address
-	for _, sa := range addrs {
-		query = append(query, sa.Addr)
+// Query API for TXs and synchronize db state. Only history newer than each address's sync cursor
+// is requested; addresses without a cursor or whose cursor was crossed by a reorg are queried in full.
+// When account is not nil its xpub is queried in place of the addresses it covers.
+func (ws *WalletService) syncTxs(addrs map[string]storedAddress, account *accountScan) {
+	Log.Debugf("querying for %s transactions", util.CurrencyCode(ws.coinType))
+	ws.lock.RLock()
+	chainHeight := int32(ws.chainHeight)
+	ws.lock.RUnlock()
+
+	var (
+		txs     []model.Transaction
+		query   = addressesToQuery(addrs)
+		cursors = make(map[string]syncCursor)
+		updated = make(map[string]syncCursor)
+	)
+	// Cursor heights are meaningless until we know the chain height
+	if chainHeight > 0 {
+		cursors = ws.loadSyncCursors()
+	}
+	if account != nil {
+		cursor, hasCursor := cursors[account.xpub]
+		accountTxs, advanced, err := ws.downloadAccountTxs(account.client, account.xpub, cursor, hasCursor, chainHeight)
+		if err != nil {
+			Log.Warningf("error downloading account txs for %s, querying addresses: %s", util.CurrencyCode(ws.coinType), err.Error())
+		} else {
+			txs, query = accountTxs, account.remaining
+			updated[account.xpub] = advanced
+		}
 	}
-	txs, err := ws.client.GetTransactions(query)
+	addrTxs, err := ws.downloadAddressTxs(query, cursors, updated, chainHeight)
 	if err != nil {
-		Log.Errorf("error downloading txs for %s: %s", ws.coinType.String(), err.Error())
-	} else {
-		Log.Debugf("downloaded %d %s transactions", len(txs), ws.coinType.String())
-		ws.saveTxsToDB(txs, addrs)
+		Log.Errorf("error downloading txs for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return
+	}
+	txs = append(txs, addrTxs...)
+	Log.Debugf("downloaded %d %s transactions", len(txs), util.CurrencyCode(ws.coinType))
+	ws.saveTxsToDB(txs, addrs)
+	if chainHeight > 0 {
+		ws.saveSyncCursors(updated)
 	}
 }
 
+// downloadAccountTxs requests the account history newer than the cursor, falling back to the
+// full history if there is no cursor or a reorg has crossed it, and returns the advanced cursor
+func (ws *WalletService) downloadAccountTxs(client model.AccountAPIClient, xpub string, cursor syncCursor, hasCursor bool, chainHeight int32) ([]model.Transaction, syncCursor, error) {
+	if hasCursor {
+		txs, err := client.GetAccountTransactions(xpub, cursor.fromHeight())
+		if err != nil {
+			return nil, syncCursor{}, err
+		}
+		if cursor.confirmedIn(txs, chainHeight) {
+			return txs, cursor.advance(txs, chainHeight), nil
+		}
+		Log.Infof("%s reorg crossed account sync cursor at height %d: downloading full history", util.CurrencyCode(ws.coinType), cursor.Height)
+	}
+	txs, err := client.GetAccountTransactions(xpub, 0)
+	if err != nil {
+		return nil, syncCursor{}, err
+	}
+	return txs, syncCursor{}.advance(txs, chainHeight), nil
+}
+
+// downloadAddressTxs requests the history newer than each address's cursor and records the
+// advanced cursors in updated. Addresses without a cursor, or whose cursor a reorg has crossed,
+// have their full history requested instead.
+func (ws *WalletService) downloadAddressTxs(query []btcutil.Address, cursors, updated map[string]syncCursor, chainHeight int32) ([]model.Transaction, error) {
+	var (
+		txs    []model.Transaction
+		resync []btcutil.Address
+	)
+	incremental, ok := ws.client.(model.IncrementalAPIClient)
+	for from, group := range groupByCursor(query, cursors) {
+		if from == 0 || !ok {
+			resync = append(resync, group...)
+			continue
+		}
+		groupTxs, err := incremental.GetTransactionsSince(group, from)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range group {
+			var (
+				cursor  = cursors[addr.String()]
+				addrTxs = txsForAddress(groupTxs, addr.String())
+			)
+			if !cursor.confirmedIn(addrTxs, chainHeight) {
+				Log.Infof("%s reorg crossed sync cursor for %s at height %d: downloading full history", util.CurrencyCode(ws.coinType), addr.String(), cursor.Height)
+				resync = append(resync, addr)
+				continue
+			}
+			updated[addr.String()] = cursor.advance(addrTxs, chainHeight)
+		}
+		txs = append(txs, groupTxs...)
+	}
+	if len(resync) > 0 {
+		fullTxs, err := ws.client.GetTransactions(resync)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range resync {
+			updated[addr.String()] = syncCursor{}.advance(txsForAddress(fullTxs, addr.String()), chainHeight)
+		}
+		txs = append(txs, fullTxs...)
+	}
+	return txs, nil
+}
+
 // For each API response we will need to determine the net coins leaving/entering the wallet as well as determine
 // if the transaction was exclusively for our `watch only` addresses. We will also build a Tx object suitable
 // for saving to the db and delete any existing txs not returned by the API. Finally, for any output matching a key
@@ -413,7 +636,8 @@ func (ws *WalletService) saveTxsToDB(txns []model.Transaction, addrs map[string]
 	}
 }
 
-func (ws *WalletService) saveSingleTxToDB(u model.Transaction, chainHeight int32, addrs map[string]storedAddress) {
+// saveSingleTxToDB returns true if the transaction was double spent or replaced
+func (ws *WalletService) saveSingleTxToDB(u model.Transaction, chainHeight int32, addrs map[string]storedAddress) (dead bool) {
 	msgTx := wire.NewMsgTx(int32(u.Version))
 	msgTx.LockTime = uint32(u.Locktime)
 	hits := 0
@@ -426,20 +650,20 @@ func (ws *WalletService) saveSingleTxToDB(u model.Transaction, chainHeight int32
 
 	txHash, err := chainhash.NewHashFromStr(u.Txid)
 	if err != nil {
-		Log.Errorf("error converting to txHash for %s: %s", ws.coinType.String(), err.Error())
-		return
+		Log.Errorf("error converting to txHash for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return false
 	}
 	var relevant bool
 	cb := wallet.TransactionCallback{Txid: txHash.String(), Height: height, Timestamp: time.Unix(u.Time, 0)}
 	for _, in := range u.Inputs {
 		ch, err := chainhash.NewHashFromStr(in.Txid)
 		if err != nil {
-			Log.Errorf("error converting to chainhash for %s: %s", ws.coinType.String(), err.Error())
+			Log.Errorf("error converting to chainhash for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
 			continue
 		}
 		script, err := hex.DecodeString(in.ScriptSig.Hex)
 		if err != nil {
-			Log.Errorf("error converting to scriptsig for %s: %s", ws.coinType.String(), err.Error())
+			Log.Errorf("error converting to scriptsig for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
 			continue
 		}
 		op := wire.NewOutPoint(ch, uint32(in.Vout))
@@ -454,8 +678,8 @@ func (ws *WalletService) saveSingleTxToDB(u model.Transaction, chainHeight int32
 		msgTx.TxIn = append(msgTx.TxIn, txin)
 		h, err := hex.DecodeString(op.Hash.String())
 		if err != nil {
-			Log.Errorf("error converting outpoint hash for %s: %s", ws.coinType.String(), err.Error())
-			return
+			Log.Errorf("error converting outpoint hash for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
+			return false
 		}
 		v := big.NewInt(int64(math.Round(in.Value * float64(util.SatoshisPerCoin(ws.coinType)))))
 		cbin := wallet.TransactionInput{
@@ -479,7 +703,7 @@ func (ws *WalletService) saveSingleTxToDB(u model.Transaction, chainHeight int32
 	for i, out := range u.Outputs {
 		script, err := hex.DecodeString(out.ScriptPubKey.Hex)
 		if err != nil {
-			Log.Errorf("error converting to scriptPubkey for %s: %s", ws.coinType.String(), err.Error())
+			Log.Errorf("error converting to scriptPubkey for %s: %s", util.CurrencyCode(ws.coinType), err.Error())
 			continue
 		}
 		var addr btcutil.Address
@@ -520,9 +744,18 @@ func (ws *WalletService) saveSingleTxToDB(u model.Transaction, chainHeight int32
 
 	if !relevant {
 		Log.Warningf("abort saving irrelevant txid (%s) to db", u.Txid)
-		return
+		return false
 	}
 
+	// Check for double spends and replacements before saving
+	height = ws.resolveConflicts(u, height)
+	if height == deadTxHeight {
+		ws.recordDead(u.Txid)
+	} else {
+		ws.recordSpends(u)
+	}
+	cb.Height = height
+
 	cb.Value = *value
 	cb.WatchOnly = (hits == 0)
 	saved, err := ws.db.Txns().Get(*txHash)
@@ -533,16 +766,20 @@ func (ws *WalletService) saveSingleTxToDB(u model.Transaction, chainHeight int32
 		}
 		var txBytes []byte
 		if len(u.RawBytes) > 0 {
-			txBytes = u.RawBytes
+			txBytes = ws.canonicalTxBytes(u.RawBytes)
 		} else {
 			var buf bytes.Buffer
 			msgTx.BtcEncode(&buf, wire.ProtocolVersion, wire.BaseEncoding)
 			txBytes = buf.Bytes()
 		}
+		firstSeen := err != nil
 		err = ws.db.Txns().Put(txBytes, txHash.String(), value.String(), int(height), ts, hits == 0)
 		if err != nil {
 			Log.Errorf("putting txid (%s): %s", txHash.String(), err.Error())
-			return
+			return false
+		}
+		if firstSeen {
+			ws.recordRates(txHash.String(), ts)
 		}
 		cb.Timestamp = ts
 		ws.callbackListeners(cb)
@@ -550,13 +787,28 @@ func (ws *WalletService) saveSingleTxToDB(u model.Transaction, chainHeight int32
 		err := ws.db.Txns().UpdateHeight(*txHash, int(height), time.Unix(u.BlockTime, 0))
 		if err != nil {
 			Log.Errorf("updating height for tx (%s): %s", txHash.String(), err.Error())
-			return
+			return false
 		}
 		if saved.Height != height {
 			cb.Timestamp = saved.Timestamp
 			ws.callbackListeners(cb)
 		}
 	}
+	return height == deadTxHeight
+}
+
+// canonicalTxBytes returns the serialized transaction raw as it is saved to the db. Litecoin
+// transactions are saved without their MWEB data so they decode with the wire package.
+func (ws *WalletService) canonicalTxBytes(raw []byte) []byte {
+	if ws.coinType != wallet.Litecoin {
+		return raw
+	}
+	stripped, err := mweb.Strip(raw)
+	if err != nil {
+		Log.Warningf("stripping mweb data from %s tx: %s", util.CurrencyCode(ws.coinType), err.Error())
+		return raw
+	}
+	return stripped
 }
 
 func (ws *WalletService) callbackListeners(cb wallet.TransactionCallback) {
@@ -576,14 +828,14 @@ func (ws *WalletService) getStoredAddresses() map[string]storedAddress {
 	for _, key := range keys {
 		addr, err := ws.km.KeyToAddress(key)
 		if err != nil {
-			Log.Warningf("error getting %s address for key: %s", ws.coinType.String(), err.Error())
+			Log.Warningf("error getting %s address for key: %s", util.CurrencyCode(ws.coinType), err.Error())
 			continue
 		}
 		addrs[addr.String()] = storedAddress{addr, false}
 	}
 	watchScripts, err := ws.db.WatchedScripts().GetAll()
 	if err != nil {
-		Log.Errorf("error loading %s watch scripts: %s", ws.coinType.String(), err.Error())
+		Log.Errorf("error loading %s watch scripts: %s", util.CurrencyCode(ws.coinType), err.Error())
 		return addrs
 	}
 
@@ -593,35 +845,47 @@ func (ws *WalletService) getStoredAddresses() map[string]storedAddress {
 		case wallet.Bitcoin:
 			_, addrSlice, _, err := txscript.ExtractPkScriptAddrs(script, ws.params)
 			if err != nil {
-				Log.Warningf("error serializing %s script: %s", ws.coinType.String(), err.Error())
+				Log.Warningf("error serializing %s script: %s", util.CurrencyCode(ws.coinType), err.Error())
 				continue
 			}
 			if len(addrs) == 0 {
-				Log.Warningf("error serializing %s script: %s", ws.coinType.String(), "Unknown script")
+				Log.Warningf("error serializing %s script: %s", util.CurrencyCode(ws.coinType), "Unknown script")
 				continue
 			}
 			addr = addrSlice[0]
 		case wallet.BitcoinCash:
 			cashAddr, err := bchutil.ExtractPkScriptAddrs(script, ws.params)
 			if err != nil {
-				Log.Warningf("error serializing %s script: %s", ws.coinType.String(), err.Error())
+				Log.Warningf("error serializing %s script: %s", util.CurrencyCode(ws.coinType), err.Error())
 				continue
 			}
 			addr = cashAddr
 		case wallet.Zcash:
 			zAddr, err := zaddr.ExtractPkScriptAddrs(script, ws.params)
 			if err != nil {
-				Log.Warningf("error serializing %s script: %s", ws.coinType.String(), err.Error())
+				Log.Warningf("error serializing %s script: %s", util.CurrencyCode(ws.coinType), err.Error())
 				continue
 			}
 			addr = zAddr
 		case wallet.Litecoin:
 			ltcAddr, err := laddr.ExtractPkScriptAddrs(script, ws.params)
 			if err != nil {
-				Log.Warningf("error serializing %s script: %s", ws.coinType.String(), err.Error())
+				Log.Warningf("error serializing %s script: %s", util.CurrencyCode(ws.coinType), err.Error())
 				continue
 			}
 			addr = ltcAddr
+		default:
+			c, ok := coins.Lookup(ws.coinType)
+			if !ok {
+				Log.Warningf("error serializing %s script: %s", util.CurrencyCode(ws.coinType), "Unknown coin")
+				continue
+			}
+			coinAddr, err := c.ExtractAddress(script, ws.params)
+			if err != nil {
+				Log.Warningf("error serializing %s script: %s", util.CurrencyCode(ws.coinType), err.Error())
+				continue
+			}
+			addr = coinAddr
 		}
 		if _, ok := addrs[addr.String()]; !ok {
 			addrs[addr.String()] = storedAddress{addr, true}
@@ -643,9 +907,24 @@ func (ws *WalletService) saveHashAndHeight(hash string, height uint32) error {
 	}
 	ws.chainHeight = height
 	ws.bestBlock = hash
+	if err := ws.recordBlock(hh); err != nil {
+		return err
+	}
 	return ws.cache.Set(ws.bestHeightKey(), b)
 }
 
 func (ws *WalletService) bestHeightKey() string {
-	return fmt.Sprintf("best-height-%s", ws.coinType.String())
+	return ws.cacheKey("best-height")
+}
+
+// cacheKey returns the key the named state of the wallet's coin is cached under. Coins which
+// predate the coin registry keep the keys named by their CoinType so existing caches are still
+// found. Registered coins are named by currency code since their CoinType has no name.
+func (ws *WalletService) cacheKey(name string) string {
+	switch ws.coinType {
+	case wallet.Bitcoin, wallet.BitcoinCash, wallet.Litecoin, wallet.Zcash,
+		wallet.TestnetBitcoin, wallet.TestnetBitcoinCash, wallet.TestnetLitecoin, wallet.TestnetZcash:
+		return fmt.Sprintf("%s-%s", name, ws.coinType.String())
+	}
+	return fmt.Sprintf("%s-%s", name, util.CurrencyCode(ws.coinType))
 }
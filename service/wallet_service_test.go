@@ -1,18 +1,22 @@
 package service
 
 import (
+	"bytes"
 	"encoding/hex"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/OpenBazaar/multiwallet/cache"
+	"github.com/OpenBazaar/multiwallet/coins"
 	"github.com/OpenBazaar/multiwallet/datastore"
 	"github.com/OpenBazaar/multiwallet/keys"
+	"github.com/OpenBazaar/multiwallet/model"
 	"github.com/OpenBazaar/multiwallet/model/mock"
 	"github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
@@ -69,7 +73,7 @@ func TestWalletService_syncTxs(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	ws.syncTxs(ws.getStoredAddresses())
+	ws.syncTxs(ws.getStoredAddresses(), nil)
 
 	txns, err := ws.db.Txns().GetAll(true)
 	if err != nil {
@@ -118,7 +122,7 @@ func TestWalletService_syncUtxos(t *testing.T) {
 	if err := ws.db.WatchedScripts().Put(script); err != nil {
 		t.Fatal(err)
 	}
-	ws.syncUtxos(ws.getStoredAddresses())
+	ws.syncUtxos(ws.getStoredAddresses(), nil)
 
 	utxos, err := ws.db.Utxos().GetAll()
 	if err != nil {
@@ -168,8 +172,8 @@ func TestWalletService_TestSyncWatchOnly(t *testing.T) {
 	if err := ws.db.WatchedScripts().Put(script); err != nil {
 		t.Fatal(err)
 	}
-	ws.syncTxs(ws.getStoredAddresses())
-	ws.syncUtxos(ws.getStoredAddresses())
+	ws.syncTxs(ws.getStoredAddresses(), nil)
+	ws.syncUtxos(ws.getStoredAddresses(), nil)
 
 	txns, err := ws.db.Txns().GetAll(true)
 	if err != nil {
@@ -492,3 +496,114 @@ func TestWalletService_getStoredAddresses(t *testing.T) {
 		}
 	}
 }
+
+func TestWalletService_saveSingleTxToDB_MWEB(t *testing.T) {
+	ws, err := mockWalletService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.coinType = wallet.Litecoin
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(100000, []byte{txscript.OP_TRUE}))
+	var buf bytes.Buffer
+	if err := tx.BtcEncode(&buf, wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+		t.Fatal(err)
+	}
+	canonical := buf.Bytes()
+
+	// Set the MWEB flag with an absent MWEB transaction, as the HogEx does
+	raw := append([]byte{}, canonical[:4]...)
+	raw = append(raw, 0x00, 0x08)
+	raw = append(raw, canonical[4:len(canonical)-4]...)
+	raw = append(raw, 0x00)
+	raw = append(raw, canonical[len(canonical)-4:]...)
+
+	u := model.Transaction{
+		Txid:    tx.TxHash().String(),
+		Version: 2,
+		Inputs: []model.Input{{
+			Txid:  tx.TxIn[0].PreviousOutPoint.Hash.String(),
+			Vout:  1,
+			Addr:  "ourAddress",
+			Value: 0.002,
+		}},
+		RawBytes: raw,
+	}
+	ws.saveSingleTxToDB(u, 100, map[string]storedAddress{"ourAddress": {}})
+
+	saved, err := ws.db.Txns().Get(tx.TxHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(saved.Bytes, canonical) {
+		t.Errorf("Expected the tx saved without MWEB data as %x, got %x", canonical, saved.Bytes)
+	}
+}
+
+// accountClient scans every address of the account and signals each account query
+type accountClient struct {
+	model.APIClient
+
+	scans   chan struct{}
+	queries chan string
+}
+
+func (c *accountClient) GetAccountAddresses(xpub string) ([]string, error) {
+	c.scans <- struct{}{}
+	return nil, nil
+}
+
+func (c *accountClient) GetAccountTransactions(xpub string, fromHeight int) ([]model.Transaction, error) {
+	c.queries <- "txs"
+	return nil, nil
+}
+
+func (c *accountClient) GetAccountUtxos(xpub string) ([]model.Utxo, error) {
+	c.queries <- "utxos"
+	return nil, nil
+}
+
+func TestWalletService_UpdateStateScansAccountOnce(t *testing.T) {
+	ws, err := mockWalletService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &accountClient{
+		APIClient: ws.client,
+		scans:     make(chan struct{}, 2),
+		queries:   make(chan string, 2),
+	}
+	ws.client = client
+	ws.UpdateState()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-client.queries:
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out waiting for the account to be queried")
+		}
+	}
+	if scans := len(client.scans); scans != 1 {
+		t.Errorf("expected the account to be scanned once per update, got %d scans", scans)
+	}
+}
+
+func TestWalletService_cacheKey(t *testing.T) {
+	// Coins which predate the registry keep their keys so existing caches are found
+	btc := wallet.Bitcoin
+	dogecoin := coins.Dogecoin.CoinType
+	for _, test := range []struct {
+		coinType wallet.CoinType
+		expected string
+	}{
+		{btc, "best-height-" + btc.String()},
+		{dogecoin, "best-height-DOGE"},
+	} {
+		ws := &WalletService{coinType: test.coinType}
+		if key := ws.bestHeightKey(); key != test.expected {
+			t.Errorf("Expected key %s, got %s", test.expected, key)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"github.com/OpenBazaar/multiwallet/coins"
 	liteaddr "github.com/OpenBazaar/multiwallet/litecoin/address"
 	zaddr "github.com/OpenBazaar/multiwallet/zcash/address"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -26,5 +27,10 @@ func DecodeAddress(address string, params *chaincfg.Params) (btcutil.Address, er
 	if addr, err := zaddr.DecodeAddress(address, params); err == nil {
 		return addr, nil
 	}
+	for _, c := range coins.All() {
+		if addr, err := c.DecodeAddress(address, params); err == nil {
+			return addr, nil
+		}
+	}
 	return nil, errors.New("unknown address")
 }
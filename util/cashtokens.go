@@ -0,0 +1,90 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Bitcoin Cash outputs may carry a CashToken, encoded as a prefix of the locking script. A plain
+// BCH spend does not carry tokens forward, so spending such an output burns its token.
+
+const (
+	cashTokenPrefix = 0xef
+
+	tokenReservedBit         = 0x80
+	tokenHasCommitmentLength = 0x40
+	tokenHasNFT              = 0x20
+	tokenHasAmount           = 0x10
+	tokenCapabilityMask      = 0x0f
+)
+
+// NFTCapability is what the holder of a non-fungible token may do with its category
+type NFTCapability byte
+
+const (
+	NFTCapabilityNone    NFTCapability = 0x00
+	NFTCapabilityMutable NFTCapability = 0x01
+	NFTCapabilityMinting NFTCapability = 0x02
+)
+
+// ErrInvalidTokenPrefix is returned when a locking script starts with a malformed token prefix
+var ErrInvalidTokenPrefix = errors.New("invalid CashToken prefix")
+
+// CashToken is the token carried by a Bitcoin Cash output. It holds fungible tokens if Amount is
+// non-zero and a non-fungible token if HasNFT is set.
+type CashToken struct {
+	Category   chainhash.Hash
+	Amount     uint64
+	HasNFT     bool
+	Capability NFTCapability
+	Commitment []byte
+}
+
+// ParseTokenPrefix splits script into the token it carries and its locking script. Scripts
+// without a token prefix are returned unchanged with a nil token.
+func ParseTokenPrefix(script []byte) (*CashToken, []byte, error) {
+	if len(script) == 0 || script[0] != cashTokenPrefix {
+		return nil, script, nil
+	}
+	r := bytes.NewReader(script[1:])
+	token := new(CashToken)
+	if _, err := io.ReadFull(r, token.Category[:]); err != nil {
+		return nil, nil, ErrInvalidTokenPrefix
+	}
+	bitfield, err := r.ReadByte()
+	if err != nil {
+		return nil, nil, ErrInvalidTokenPrefix
+	}
+	token.HasNFT = bitfield&tokenHasNFT != 0
+	token.Capability = NFTCapability(bitfield & tokenCapabilityMask)
+	switch {
+	case bitfield&tokenReservedBit != 0,
+		token.Capability > NFTCapabilityMinting,
+		bitfield&(tokenHasNFT|tokenHasAmount) == 0,
+		!token.HasNFT && (bitfield&tokenHasCommitmentLength != 0 || token.Capability != NFTCapabilityNone):
+		return nil, nil, ErrInvalidTokenPrefix
+	}
+	if bitfield&tokenHasCommitmentLength != 0 {
+		length, err := wire.ReadVarInt(r, 0)
+		if err != nil || length == 0 || length > uint64(r.Len()) {
+			return nil, nil, ErrInvalidTokenPrefix
+		}
+		token.Commitment = make([]byte, length)
+		if _, err := io.ReadFull(r, token.Commitment); err != nil {
+			return nil, nil, ErrInvalidTokenPrefix
+		}
+	}
+	if bitfield&tokenHasAmount != 0 {
+		amount, err := wire.ReadVarInt(r, 0)
+		if err != nil || amount == 0 || amount > math.MaxInt64 {
+			return nil, nil, ErrInvalidTokenPrefix
+		}
+		token.Amount = amount
+	}
+	return token, script[len(script)-r.Len():], nil
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestParseTokenPrefix(t *testing.T) {
+	p2pkh, _ := hex.DecodeString("76a9148132712c3ff19f3a151234616777420a6d7ef22688ac")
+	category := bytes.Repeat([]byte{0xbb}, 32)
+	prefixed := func(fields ...[]byte) []byte {
+		script := append([]byte{0xef}, category...)
+		for _, f := range fields {
+			script = append(script, f...)
+		}
+		return append(script, p2pkh...)
+	}
+
+	token, script, err := ParseTokenPrefix(p2pkh)
+	if err != nil || token != nil || !bytes.Equal(script, p2pkh) {
+		t.Errorf("expected a plain script to be returned unchanged, got %v %x %v", token, script, err)
+	}
+
+	// Fungible tokens only
+	token, script, err = ParseTokenPrefix(prefixed([]byte{0x10, 0xfd, 0xe8, 0x03}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Amount != 1000 || token.HasNFT || !bytes.Equal(token.Category[:], category) || !bytes.Equal(script, p2pkh) {
+		t.Errorf("failed to parse fungible token: %+v", token)
+	}
+
+	// A minting NFT with a commitment alongside fungible tokens
+	token, script, err = ParseTokenPrefix(prefixed([]byte{0x72, 0x02, 0xca, 0xfe, 0x05}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !token.HasNFT || token.Capability != NFTCapabilityMinting || !bytes.Equal(token.Commitment, []byte{0xca, 0xfe}) || token.Amount != 5 || !bytes.Equal(script, p2pkh) {
+		t.Errorf("failed to parse NFT: %+v", token)
+	}
+
+	invalid := [][]byte{
+		prefixed([]byte{0x00}),                   // neither NFT nor amount
+		prefixed([]byte{0x90, 0x01}),             // reserved bit
+		prefixed([]byte{0x23}),                   // unknown capability
+		prefixed([]byte{0x11, 0x01}),             // capability without NFT
+		prefixed([]byte{0x10, 0x00}),             // zero amount
+		prefixed([]byte{0x10, 0xfd, 0x05, 0x00}), // amount not minimally encoded
+		prefixed([]byte{0x60, 0x00}),             // empty commitment
+		append([]byte{0xef}, category[:10]...),
+	}
+	for i, s := range invalid {
+		if _, _, err := ParseTokenPrefix(s); err != ErrInvalidTokenPrefix {
+			t.Errorf("expected prefix %d to be invalid, got %v", i, err)
+		}
+	}
+}
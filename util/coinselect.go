@@ -0,0 +1,385 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+)
+
+// CoinSelectionStrategy names a way of choosing which coins fund a transaction
+type CoinSelectionStrategy string
+
+const (
+	// MaxValueAge prefers the coins with the highest value times confirmations. It is the default.
+	MaxValueAge CoinSelectionStrategy = "max-value-age"
+	// BranchAndBound looks for a set of coins which pays the target closely enough that no change
+	// output is needed, falling back to Knapsack when there is none
+	BranchAndBound CoinSelectionStrategy = "branch-and-bound"
+	// Knapsack picks the subset of coins which comes closest to the target, or the smallest coin
+	// which covers it if that is closer
+	Knapsack CoinSelectionStrategy = "knapsack"
+	// SmallestFirst spends the smallest coins first to consolidate them while fees are low
+	SmallestFirst CoinSelectionStrategy = "smallest-first"
+	// Privacy spends all the coins of as few addresses as possible so fewer of our addresses are
+	// linked together and none is left holding a partially spent balance
+	Privacy CoinSelectionStrategy = "privacy"
+)
+
+// MaxCoinSelectionInputs is the most coins a strategy will select for one transaction
+const MaxCoinSelectionInputs = 10000
+
+// ParseCoinSelectionStrategy returns the strategy named by s. An empty name is the default.
+func ParseCoinSelectionStrategy(s string) (CoinSelectionStrategy, error) {
+	switch strategy := CoinSelectionStrategy(s); strategy {
+	case "":
+		return MaxValueAge, nil
+	case MaxValueAge, BranchAndBound, Knapsack, SmallestFirst, Privacy:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown coin selection strategy %q", s)
+}
+
+// NewCoinSelector returns a selector for the strategy. Change below changeThreshold is not worth
+// creating and is left to the miners, so branch and bound accepts any selection paying up to that
+// much over the target.
+func NewCoinSelector(strategy CoinSelectionStrategy, changeThreshold btcutil.Amount) (coinset.CoinSelector, error) {
+	strategy, err := ParseCoinSelectionStrategy(string(strategy))
+	if err != nil {
+		return nil, err
+	}
+	switch strategy {
+	case BranchAndBound:
+		return &BranchAndBoundSelector{
+			MaxInputs:    MaxCoinSelectionInputs,
+			CostOfChange: changeThreshold,
+			Fallback:     &KnapsackSelector{MaxInputs: MaxCoinSelectionInputs, MinChange: changeThreshold},
+		}, nil
+	case Knapsack:
+		return &KnapsackSelector{MaxInputs: MaxCoinSelectionInputs, MinChange: changeThreshold}, nil
+	case SmallestFirst:
+		return &SmallestFirstSelector{MaxInputs: MaxCoinSelectionInputs}, nil
+	case Privacy:
+		return &PrivacySelector{MaxInputs: MaxCoinSelectionInputs}, nil
+	default:
+		return &coinset.MaxValueAgeCoinSelector{MaxInputs: MaxCoinSelectionInputs, MinChangeAmount: btcutil.Amount(0)}, nil
+	}
+}
+
+func sumCoins(coins []coinset.Coin) btcutil.Amount {
+	var total btcutil.Amount
+	for _, c := range coins {
+		total += c.Value()
+	}
+	return total
+}
+
+func sortedCoins(coins []coinset.Coin, descending bool) []coinset.Coin {
+	sorted := make([]coinset.Coin, len(coins))
+	copy(sorted, coins)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if descending {
+			return sorted[i].Value() > sorted[j].Value()
+		}
+		return sorted[i].Value() < sorted[j].Value()
+	})
+	return sorted
+}
+
+// defaultBranchAndBoundTries bounds the search so a large wallet cannot stall a spend
+const defaultBranchAndBoundTries = 100000
+
+// BranchAndBoundSelector searches for the set of coins whose total exceeds the target by the least,
+// and by no more than CostOfChange, so the transaction needs no change output
+type BranchAndBoundSelector struct {
+	MaxInputs    int
+	MaxTries     int
+	CostOfChange btcutil.Amount
+
+	// Fallback selects the coins when there is no changeless selection. Without one an error
+	// is returned.
+	Fallback coinset.CoinSelector
+}
+
+// CoinSelect satisfies the coinset.CoinSelector interface
+func (s *BranchAndBoundSelector) CoinSelect(target btcutil.Amount, coins []coinset.Coin) (coinset.Coins, error) {
+	if best := s.search(target, coins); best != nil {
+		return coinset.NewCoinSet(best), nil
+	}
+	if s.Fallback != nil {
+		return s.Fallback.CoinSelect(target, coins)
+	}
+	return nil, coinset.ErrCoinsNoSelectionAvailable
+}
+
+func (s *BranchAndBoundSelector) search(target btcutil.Amount, coins []coinset.Coin) []coinset.Coin {
+	maxTries := s.MaxTries
+	if maxTries <= 0 {
+		maxTries = defaultBranchAndBoundTries
+	}
+	sorted := sortedCoins(coins, true)
+	// remaining[i] is the total of every coin from i on
+	remaining := make([]btcutil.Amount, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + sorted[i].Value()
+	}
+
+	var (
+		best      []coinset.Coin
+		bestTotal btcutil.Amount
+		selected  []coinset.Coin
+		tries     int
+		search    func(i int, total btcutil.Amount)
+	)
+	search = func(i int, total btcutil.Amount) {
+		if tries >= maxTries || (best != nil && bestTotal == target) {
+			return
+		}
+		tries++
+		if total > target+s.CostOfChange {
+			return
+		}
+		if total >= target {
+			if best == nil || total < bestTotal || (total == bestTotal && len(selected) < len(best)) {
+				best = append([]coinset.Coin(nil), selected...)
+				bestTotal = total
+			}
+			return
+		}
+		if i == len(sorted) || total+remaining[i] < target || (s.MaxInputs > 0 && len(selected) >= s.MaxInputs) {
+			return
+		}
+		selected = append(selected, sorted[i])
+		search(i+1, total+sorted[i].Value())
+		selected = selected[:len(selected)-1]
+		search(i+1, total)
+	}
+	search(0, 0)
+	return best
+}
+
+// knapsackIterations is how many random subsets are tried when approximating the target
+const knapsackIterations = 1000
+
+// KnapsackSelector picks the subset of the coins smaller than the target which comes closest to it
+// without going under, or the smallest coin larger than the target when that is closer. Change
+// smaller than MinChange is avoided where possible.
+type KnapsackSelector struct {
+	MaxInputs int
+	MinChange btcutil.Amount
+
+	// Rand drives the subset search. A time seeded source is used if it is nil.
+	Rand *rand.Rand
+}
+
+// CoinSelect satisfies the coinset.CoinSelector interface
+func (s *KnapsackSelector) CoinSelect(target btcutil.Amount, coins []coinset.Coin) (coinset.Coins, error) {
+	rnd := s.Rand
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	var (
+		smaller      []coinset.Coin
+		lowestLarger coinset.Coin
+	)
+	for _, c := range coins {
+		switch {
+		case c.Value() == target:
+			return coinset.NewCoinSet([]coinset.Coin{c}), nil
+		case c.Value() < target+s.MinChange:
+			smaller = append(smaller, c)
+		case lowestLarger == nil || c.Value() < lowestLarger.Value():
+			lowestLarger = c
+		}
+	}
+
+	smallerTotal := sumCoins(smaller)
+	if smallerTotal == target && s.withinMaxInputs(len(smaller)) {
+		return coinset.NewCoinSet(smaller), nil
+	}
+	if smallerTotal < target {
+		if lowestLarger == nil {
+			return nil, coinset.ErrCoinsNoSelectionAvailable
+		}
+		return coinset.NewCoinSet([]coinset.Coin{lowestLarger}), nil
+	}
+
+	smaller = sortedCoins(smaller, true)
+	best, bestTotal := s.approximateBestSubset(rnd, smaller, smallerTotal, target)
+	if bestTotal != target && s.MinChange > 0 && smallerTotal >= target+s.MinChange {
+		best, bestTotal = s.approximateBestSubset(rnd, smaller, smallerTotal, target+s.MinChange)
+	}
+	if lowestLarger != nil && (best == nil || (bestTotal != target && bestTotal < target+s.MinChange) || lowestLarger.Value() <= bestTotal) {
+		return coinset.NewCoinSet([]coinset.Coin{lowestLarger}), nil
+	}
+	if best == nil {
+		return nil, coinset.ErrCoinsNoSelectionAvailable
+	}
+	return coinset.NewCoinSet(best), nil
+}
+
+func (s *KnapsackSelector) withinMaxInputs(n int) bool {
+	return s.MaxInputs <= 0 || n <= s.MaxInputs
+}
+
+// approximateBestSubset randomly includes coins, sorted largest first, looking for the subset whose
+// total is closest to target from above
+func (s *KnapsackSelector) approximateBestSubset(rnd *rand.Rand, coins []coinset.Coin, total, target btcutil.Amount) ([]coinset.Coin, btcutil.Amount) {
+	var (
+		best      []bool
+		bestTotal = total
+		included  = make([]bool, len(coins))
+	)
+	if s.withinMaxInputs(len(coins)) {
+		best = make([]bool, len(coins))
+		for i := range best {
+			best[i] = true
+		}
+	}
+	for rep := 0; rep < knapsackIterations && bestTotal != target; rep++ {
+		for i := range included {
+			included[i] = false
+		}
+		var (
+			subtotal btcutil.Amount
+			n        int
+			reached  bool
+		)
+		for pass := 0; pass < 2 && !reached; pass++ {
+			for i, c := range coins {
+				// The first pass includes coins at random and the second fills in the rest
+				if included[i] || (pass == 0 && rnd.Intn(2) == 0) {
+					continue
+				}
+				subtotal += c.Value()
+				included[i] = true
+				n++
+				if subtotal >= target {
+					reached = true
+					if (subtotal < bestTotal || best == nil) && s.withinMaxInputs(n) {
+						bestTotal = subtotal
+						best = append(best[:0:0], included...)
+					}
+					subtotal -= c.Value()
+					included[i] = false
+					n--
+				}
+			}
+		}
+	}
+	if best == nil {
+		return nil, 0
+	}
+	var selected []coinset.Coin
+	for i, ok := range best {
+		if ok {
+			selected = append(selected, coins[i])
+		}
+	}
+	return selected, bestTotal
+}
+
+// SmallestFirstSelector spends the smallest coins first. When MaxInputs would be exceeded the
+// smallest selected coin makes way for the next larger one.
+type SmallestFirstSelector struct {
+	MaxInputs int
+}
+
+// CoinSelect satisfies the coinset.CoinSelector interface
+func (s *SmallestFirstSelector) CoinSelect(target btcutil.Amount, coins []coinset.Coin) (coinset.Coins, error) {
+	var (
+		selected []coinset.Coin
+		total    btcutil.Amount
+	)
+	for _, c := range sortedCoins(coins, false) {
+		if s.MaxInputs > 0 && len(selected) == s.MaxInputs {
+			total -= selected[0].Value()
+			selected = selected[1:]
+		}
+		selected = append(selected, c)
+		total += c.Value()
+		if total >= target {
+			return coinset.NewCoinSet(selected), nil
+		}
+	}
+	return nil, coinset.ErrCoinsNoSelectionAvailable
+}
+
+// PrivacySelector groups coins by the script they pay and spends whole groups so that as few of
+// our addresses as possible appear together in a transaction. The group which covers the target
+// with the least left over is preferred, otherwise the largest groups are combined.
+type PrivacySelector struct {
+	MaxInputs int
+}
+
+// CoinSelect satisfies the coinset.CoinSelector interface
+func (s *PrivacySelector) CoinSelect(target btcutil.Amount, coins []coinset.Coin) (coinset.Coins, error) {
+	type group struct {
+		coins []coinset.Coin
+		total btcutil.Amount
+	}
+	var groups []*group
+	for _, c := range coins {
+		var g *group
+		for _, existing := range groups {
+			if bytes.Equal(existing.coins[0].PkScript(), c.PkScript()) {
+				g = existing
+				break
+			}
+		}
+		if g == nil {
+			g = &group{}
+			groups = append(groups, g)
+		}
+		g.coins = append(g.coins, c)
+		g.total += c.Value()
+	}
+	fits := func(n int) bool { return s.MaxInputs <= 0 || n <= s.MaxInputs }
+
+	var best *group
+	for _, g := range groups {
+		if g.total < target || !fits(len(g.coins)) {
+			continue
+		}
+		if best == nil || g.total < best.total || (g.total == best.total && len(g.coins) < len(best.coins)) {
+			best = g
+		}
+	}
+	if best != nil {
+		return coinset.NewCoinSet(best.coins), nil
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].total > groups[j].total })
+	var (
+		selected []coinset.Coin
+		total    btcutil.Amount
+	)
+	for _, g := range groups {
+		if !fits(len(selected) + len(g.coins)) {
+			continue
+		}
+		selected = append(selected, g.coins...)
+		total += g.total
+		if total >= target {
+			return coinset.NewCoinSet(selected), nil
+		}
+	}
+	return nil, coinset.ErrCoinsNoSelectionAvailable
+}
+
+// AllCoinsSelector spends every coin it is given. It is used when the user has chosen the inputs
+// of a transaction themselves.
+type AllCoinsSelector struct{}
+
+// CoinSelect satisfies the coinset.CoinSelector interface
+func (s AllCoinsSelector) CoinSelect(target btcutil.Amount, coins []coinset.Coin) (coinset.Coins, error) {
+	if len(coins) == 0 || sumCoins(coins) < target {
+		return nil, coinset.ErrCoinsNoSelectionAvailable
+	}
+	return coinset.NewCoinSet(coins), nil
+}
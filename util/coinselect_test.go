@@ -0,0 +1,195 @@
+package util
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+)
+
+const (
+	fixtureFeePerByte = 10
+	fixtureDust       = 546
+)
+
+// fixtureCoins returns coins of the given values, each paying its own script unless scripts
+// are given
+func fixtureCoins(t *testing.T, values []int64, scripts ...string) []coinset.Coin {
+	var coins []coinset.Coin
+	for i, v := range values {
+		script := fmt.Sprintf("script-%d", i)
+		if i < len(scripts) {
+			script = scripts[i]
+		}
+		c, err := NewCoin(chainhash.DoubleHashH([]byte(fmt.Sprintf("coin-%d", i))), 0, btcutil.Amount(v), 6, []byte(script))
+		if err != nil {
+			t.Fatal(err)
+		}
+		coins = append(coins, c)
+	}
+	return coins
+}
+
+// fixtureFee returns the fee of a P2PKH transaction paying target from the selected coins, with
+// change if what is left over is not dust
+func fixtureFee(selected *coinset.CoinSet, target btcutil.Amount) (fee int64, change bool) {
+	outputs := 1
+	if selected.TotalValue()-target >= fixtureDust {
+		outputs, change = 2, true
+	}
+	size := 10 + 148*selected.Num() + 34*outputs
+	return int64(size * fixtureFeePerByte), change
+}
+
+func mustSelect(t *testing.T, selector coinset.CoinSelector, target btcutil.Amount, coins []coinset.Coin) *coinset.CoinSet {
+	result, err := selector.CoinSelect(target, coins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selected := coinset.NewCoinSet(result.Coins())
+	if selected.TotalValue() < target {
+		t.Fatalf("selected %d which does not cover the target of %d", selected.TotalValue(), target)
+	}
+	return selected
+}
+
+func defaultSelector(t *testing.T) coinset.CoinSelector {
+	selector, err := NewCoinSelector("", fixtureDust)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return selector
+}
+
+func TestParseCoinSelectionStrategy(t *testing.T) {
+	if s, err := ParseCoinSelectionStrategy(""); err != nil || s != MaxValueAge {
+		t.Errorf("expected the default strategy, got %q %v", s, err)
+	}
+	for _, s := range []CoinSelectionStrategy{MaxValueAge, BranchAndBound, Knapsack, SmallestFirst, Privacy} {
+		if _, err := NewCoinSelector(s, fixtureDust); err != nil {
+			t.Errorf("%s: %v", s, err)
+		}
+	}
+	if _, err := ParseCoinSelectionStrategy("largest-first"); err == nil {
+		t.Error("expected an unknown strategy to be refused")
+	}
+}
+
+func TestBranchAndBoundAvoidsChange(t *testing.T) {
+	coins := fixtureCoins(t, []int64{50000, 42100, 30000, 7000})
+	bnb := mustSelect(t, &BranchAndBoundSelector{CostOfChange: fixtureDust}, 42000, coins)
+	bnbFee, bnbChange := fixtureFee(bnb, 42000)
+	if bnbChange {
+		t.Errorf("expected a changeless selection, got %d", bnb.TotalValue())
+	}
+
+	mva := mustSelect(t, defaultSelector(t), 42000, coins)
+	mvaFee, mvaChange := fixtureFee(mva, 42000)
+	if !mvaChange {
+		t.Fatal("expected the default selection to need change")
+	}
+	if bnbFee >= mvaFee {
+		t.Errorf("expected branch and bound to pay less than %d, paid %d", mvaFee, bnbFee)
+	}
+}
+
+func TestBranchAndBoundFindsExactCombination(t *testing.T) {
+	coins := fixtureCoins(t, []int64{50000, 30000, 20000, 12000, 7000, 3000})
+	selected := mustSelect(t, &BranchAndBoundSelector{CostOfChange: fixtureDust}, 42000, coins)
+	if selected.TotalValue() != 42000 || selected.Num() != 2 {
+		t.Errorf("expected 30000 and 12000, got %d from %d coins", selected.TotalValue(), selected.Num())
+	}
+}
+
+func TestBranchAndBoundFallsBack(t *testing.T) {
+	coins := fixtureCoins(t, []int64{50000, 30000})
+	bnb := &BranchAndBoundSelector{CostOfChange: fixtureDust}
+	if _, err := bnb.CoinSelect(42000, coins); err != coinset.ErrCoinsNoSelectionAvailable {
+		t.Errorf("expected no changeless selection, got %v", err)
+	}
+	bnb.Fallback = &KnapsackSelector{MinChange: fixtureDust, Rand: rand.New(rand.NewSource(1))}
+	selected := mustSelect(t, bnb, 42000, coins)
+	if selected.TotalValue() != 50000 {
+		t.Errorf("expected the fallback to pick the smallest coin covering the target, got %d", selected.TotalValue())
+	}
+}
+
+func TestKnapsackApproximatesTarget(t *testing.T) {
+	coins := fixtureCoins(t, []int64{1000, 2000, 5000, 9000, 100000})
+	knapsack := &KnapsackSelector{MinChange: fixtureDust, Rand: rand.New(rand.NewSource(1))}
+	selected := mustSelect(t, knapsack, 11000, coins)
+	if selected.TotalValue() != 11000 {
+		t.Errorf("expected a subset paying exactly 11000, got %d", selected.TotalValue())
+	}
+	if _, change := fixtureFee(selected, 11000); change {
+		t.Error("expected no change")
+	}
+	if _, change := fixtureFee(mustSelect(t, defaultSelector(t), 11000, coins), 11000); !change {
+		t.Error("expected the default selection to need change")
+	}
+
+	// Nothing smaller adds up to the target so the smallest larger coin is used
+	selected = mustSelect(t, knapsack, 20000, coins)
+	if selected.Num() != 1 || selected.TotalValue() != 100000 {
+		t.Errorf("expected the 100000 coin, got %d from %d coins", selected.TotalValue(), selected.Num())
+	}
+}
+
+func TestSmallestFirstConsolidates(t *testing.T) {
+	coins := fixtureCoins(t, []int64{600, 800, 1200, 1500, 90000})
+	selected := mustSelect(t, &SmallestFirstSelector{}, 3000, coins)
+	if selected.Num() != 4 {
+		t.Errorf("expected the four smallest coins, got %d", selected.Num())
+	}
+	mva := mustSelect(t, defaultSelector(t), 3000, coins)
+	if selected.Num() <= mva.Num() {
+		t.Errorf("expected more inputs than the default %d, got %d", mva.Num(), selected.Num())
+	}
+
+	// Larger coins replace the smallest once MaxInputs is reached
+	selected = mustSelect(t, &SmallestFirstSelector{MaxInputs: 2}, 3000, coins)
+	if selected.Num() != 2 || selected.TotalValue() != 91500 {
+		t.Errorf("expected the 1500 and 90000 coins, got %d from %d coins", selected.TotalValue(), selected.Num())
+	}
+}
+
+func TestPrivacySpendsWholeAddresses(t *testing.T) {
+	scripts := []string{"a", "a", "a", "b", "b"}
+	coins := fixtureCoins(t, []int64{20000, 15000, 10000, 30000, 1000}, scripts...)
+	distinct := func(selected *coinset.CoinSet) int {
+		seen := make(map[string]bool)
+		for _, c := range selected.Coins() {
+			seen[string(c.PkScript())] = true
+		}
+		return len(seen)
+	}
+
+	selected := mustSelect(t, &PrivacySelector{}, 40000, coins)
+	if distinct(selected) != 1 || selected.Num() != 3 {
+		t.Errorf("expected every coin of one address, got %d coins from %d addresses", selected.Num(), distinct(selected))
+	}
+	mva := mustSelect(t, defaultSelector(t), 40000, coins)
+	if distinct(mva) < 2 {
+		t.Errorf("expected the default selection to link addresses, got %d", distinct(mva))
+	}
+
+	// No one address covers the target so whole addresses are combined
+	selected = mustSelect(t, &PrivacySelector{}, 60000, coins)
+	if selected.Num() != len(coins) {
+		t.Errorf("expected every coin, got %d", selected.Num())
+	}
+}
+
+func TestAllCoinsSpendsEverything(t *testing.T) {
+	coins := fixtureCoins(t, []int64{1000, 2000, 50000})
+	selected := mustSelect(t, AllCoinsSelector{}, 1500, coins)
+	if selected.Num() != len(coins) || selected.TotalValue() != 53000 {
+		t.Errorf("expected every coin, got %d from %d coins", selected.TotalValue(), selected.Num())
+	}
+	if _, err := (AllCoinsSelector{}).CoinSelect(60000, coins); err != coinset.ErrCoinsNoSelectionAvailable {
+		t.Errorf("expected coins short of the target to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package util
+
+import (
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+// CurrencyCode returns the currency code of coinType, including the registered coins which
+// wallet-interface does not know
+func CurrencyCode(coinType wallet.CoinType) string {
+	if c, ok := coins.Lookup(coinType); ok {
+		if coinType == c.TestnetCoinType {
+			return "T" + c.CurrencyCode
+		}
+		return c.CurrencyCode
+	}
+	return coinType.CurrencyCode()
+}
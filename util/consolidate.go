@@ -0,0 +1,116 @@
+package util
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+const (
+	// DefaultConsolidationMaxUtxoValue is the value in satoshi below which a utxo is small enough
+	// to consolidate
+	DefaultConsolidationMaxUtxoValue = 1000000
+
+	// DefaultConsolidationMinUtxos is the fewest small utxos worth consolidating
+	DefaultConsolidationMinUtxos = 20
+
+	// DefaultConsolidationMaxUtxos bounds the inputs of a consolidation so it stays well within the
+	// standard transaction size
+	DefaultConsolidationMaxUtxos = 200
+)
+
+// ErrNothingToConsolidate is returned when too few small utxos are held to be worth consolidating
+var ErrNothingToConsolidate = errors.New("not enough small utxos to consolidate")
+
+// ConsolidationPolicy decides which utxos are merged and when. Zero values use the defaults.
+type ConsolidationPolicy struct {
+	// Consolidations run automatically only while the fee rate they would pay is at or below
+	// MaxFeePerByte. Zero disables automatic consolidation.
+	MaxFeePerByte int64
+
+	// Utxos worth less than MaxUtxoValue are merged, at least MinUtxos and at most MaxUtxos at a time
+	MaxUtxoValue int64
+	MinUtxos     int
+	MaxUtxos     int
+}
+
+// ConsolidationPlan describes a consolidation along with its estimated savings. Savings is what
+// spending each utxo later at FutureFeePerByte would cost less the fee of the consolidation and
+// of later spending its one output, so it is negative if consolidating now would cost more.
+type ConsolidationPlan struct {
+	Utxos            []wallet.Utxo
+	Value            int64
+	Size             int
+	FeePerByte       int64
+	Fee              int64
+	FutureFeePerByte int64
+	Savings          int64
+
+	// Whether the fee rate allows the consolidation to run automatically
+	WithinFeeLimit bool
+
+	// The id of the consolidation once it has been broadcast
+	Txid string
+}
+
+// Worthwhile reports whether the consolidation should run automatically
+func (p *ConsolidationPlan) Worthwhile() bool {
+	return p.WithinFeeLimit && p.Savings > 0
+}
+
+// PlanConsolidation picks the smallest confirmed utxos to merge into one output, paying
+// feePerByte now rather than futureFeePerByte when each would otherwise be spent. Utxos which
+// would cost more than they are worth to spend are left alone. inputSize is the size of a signed
+// input and estimateSize returns the size of a consolidation with numInputs inputs.
+func PlanConsolidation(utxos []wallet.Utxo, policy ConsolidationPolicy, feePerByte, futureFeePerByte int64, inputSize int, estimateSize func(numInputs int) int) (*ConsolidationPlan, error) {
+	maxValue, minUtxos, maxUtxos := policy.MaxUtxoValue, policy.MinUtxos, policy.MaxUtxos
+	if maxValue <= 0 {
+		maxValue = DefaultConsolidationMaxUtxoValue
+	}
+	if minUtxos <= 0 {
+		minUtxos = DefaultConsolidationMinUtxos
+	}
+	if maxUtxos <= 0 {
+		maxUtxos = DefaultConsolidationMaxUtxos
+	}
+
+	type candidate struct {
+		utxo  wallet.Utxo
+		value int64
+	}
+	var candidates []candidate
+	for _, u := range utxos {
+		if u.WatchOnly || u.AtHeight <= 0 {
+			continue
+		}
+		val, err := strconv.ParseInt(u.Value, 10, 64)
+		if err != nil || val >= maxValue || val <= int64(inputSize)*feePerByte {
+			continue
+		}
+		candidates = append(candidates, candidate{u, val})
+	}
+	if len(candidates) < minUtxos {
+		return nil, ErrNothingToConsolidate
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].value < candidates[j].value })
+	if len(candidates) > maxUtxos {
+		candidates = candidates[:maxUtxos]
+	}
+
+	plan := &ConsolidationPlan{
+		FeePerByte:       feePerByte,
+		FutureFeePerByte: futureFeePerByte,
+		WithinFeeLimit:   policy.MaxFeePerByte > 0 && feePerByte <= policy.MaxFeePerByte,
+	}
+	for _, c := range candidates {
+		plan.Utxos = append(plan.Utxos, c.utxo)
+		plan.Value += c.value
+	}
+	plan.Size = estimateSize(len(plan.Utxos))
+	plan.Fee = int64(plan.Size) * feePerByte
+	separately := int64(len(plan.Utxos)*inputSize) * futureFeePerByte
+	plan.Savings = separately - plan.Fee - int64(inputSize)*futureFeePerByte
+	return plan, nil
+}
@@ -0,0 +1,89 @@
+package util
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const fixtureInputSize = 148
+
+func fixtureConsolidationSize(numInputs int) int {
+	return 10 + fixtureInputSize*numInputs + 34
+}
+
+func fixtureUtxos(values ...int64) []wallet.Utxo {
+	var utxos []wallet.Utxo
+	for i, v := range values {
+		hash := chainhash.DoubleHashH([]byte(strconv.Itoa(i)))
+		utxos = append(utxos, wallet.Utxo{
+			Op:       *wire.NewOutPoint(&hash, 0),
+			AtHeight: 1000,
+			Value:    strconv.FormatInt(v, 10),
+		})
+	}
+	return utxos
+}
+
+func TestPlanConsolidation(t *testing.T) {
+	utxos := fixtureUtxos(5000, 2000, 3000, 4000, 900000, 250)
+	policy := ConsolidationPolicy{MaxFeePerByte: 2, MaxUtxoValue: 100000, MinUtxos: 3}
+	plan, err := PlanConsolidation(utxos, policy, 2, 20, fixtureInputSize, fixtureConsolidationSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The large utxo is not small and the 250 satoshi one costs more than it is worth to spend
+	if len(plan.Utxos) != 4 || plan.Value != 14000 {
+		t.Fatalf("expected the four small utxos worth 14000, got %d worth %d", len(plan.Utxos), plan.Value)
+	}
+	if plan.Utxos[0].Value != "2000" {
+		t.Errorf("expected the smallest utxo first, got %s", plan.Utxos[0].Value)
+	}
+	if plan.Fee != 2*int64(fixtureConsolidationSize(4)) {
+		t.Errorf("expected the fee to pay the current rate, got %d", plan.Fee)
+	}
+	// Four inputs later at 20 sat/byte against the consolidation now and one input later
+	if plan.Savings != 4*148*20-plan.Fee-148*20 || !plan.Worthwhile() {
+		t.Errorf("expected the consolidation to be worthwhile, saved %d", plan.Savings)
+	}
+
+	plan, err = PlanConsolidation(utxos, policy, 3, 20, fixtureInputSize, fixtureConsolidationSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.WithinFeeLimit || plan.Worthwhile() {
+		t.Error("expected a fee rate above the limit to stop automatic consolidation")
+	}
+
+	plan, err = PlanConsolidation(utxos, policy, 2, 2, fixtureInputSize, fixtureConsolidationSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Savings >= 0 || plan.Worthwhile() {
+		t.Errorf("expected no savings when fees will not rise, got %d", plan.Savings)
+	}
+}
+
+func TestPlanConsolidationLimits(t *testing.T) {
+	utxos := fixtureUtxos(5000, 6000, 7000, 8000)
+	utxos[3].AtHeight = 0
+	policy := ConsolidationPolicy{MaxUtxoValue: 100000, MinUtxos: 4}
+	if _, err := PlanConsolidation(utxos, policy, 1, 20, fixtureInputSize, fixtureConsolidationSize); err != ErrNothingToConsolidate {
+		t.Errorf("expected too few confirmed utxos to be refused, got %v", err)
+	}
+
+	policy.MinUtxos, policy.MaxUtxos = 2, 2
+	plan, err := PlanConsolidation(utxos, policy, 1, 20, fixtureInputSize, fixtureConsolidationSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Utxos) != 2 || plan.Value != 11000 {
+		t.Errorf("expected the two smallest utxos, got %d worth %d", len(plan.Utxos), plan.Value)
+	}
+	if plan.WithinFeeLimit {
+		t.Error("expected automatic consolidation to be disabled without a fee limit")
+	}
+}
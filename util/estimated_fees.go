@@ -0,0 +1,146 @@
+package util
+
+import (
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+// FeeEstimator estimates the fee in satoshis per kilobyte for a transaction to confirm within
+// nBlocks blocks
+type FeeEstimator interface {
+	EstimateFee(nBlocks int) (int, error)
+}
+
+// FeeLevelTargets are how many blocks a transaction paying each fee level should confirm within
+var FeeLevelTargets = map[wallet.FeeLevel]int{
+	wallet.PRIOIRTY:       1,
+	wallet.NORMAL:         3,
+	wallet.ECONOMIC:       6,
+	wallet.SUPER_ECONOMIC: 24,
+}
+
+// DefaultFeeEstimateExpiry is how long a fee estimate is used before the backend is asked again
+const DefaultFeeEstimateExpiry = time.Minute * 10
+
+// DefaultFeeEstimateMaxAge is how long an estimate which cannot be refreshed is still used before
+// the configured fees are paid instead
+const DefaultFeeEstimateMaxAge = time.Hour
+
+// A failed estimate is retried after minFeeEstimateBackoff, doubling with each failure in a row up
+// to maxFeeEstimateBackoff
+const (
+	minFeeEstimateBackoff = time.Second * 30
+	maxFeeEstimateBackoff = time.Minute * 10
+)
+
+type feeEstimate struct {
+	feePerByte uint64
+	fetched    time.Time
+
+	// failures counts the refreshes which failed in a row. None is retried before retryAt.
+	failures int
+	retryAt  time.Time
+}
+
+// EstimatingFeeProvider pays the fee the backend estimates for each fee level's confirmation
+// target. When the backend has no estimate the fee is derived from the USD targets of
+// FeeProvider instead. Estimates are fetched in the background so GetFeePerByte never waits on
+// the backend.
+type EstimatingFeeProvider struct {
+	estimator FeeEstimator
+	fallback  *FeeProvider
+
+	// Expiry and MaxAge default to DefaultFeeEstimateExpiry and DefaultFeeEstimateMaxAge
+	Expiry time.Duration
+	MaxAge time.Duration
+
+	mtx        sync.Mutex
+	estimates  map[int]feeEstimate
+	refreshing map[int]bool
+}
+
+func NewEstimatingFeeProvider(maxFee, priorityFee, normalFee, economicFee, superEconomicFee uint64, exchangeRates wallet.ExchangeRates, estimator FeeEstimator) *EstimatingFeeProvider {
+	return &EstimatingFeeProvider{
+		estimator:  estimator,
+		fallback:   NewFeeProvider(maxFee, priorityFee, normalFee, economicFee, superEconomicFee, exchangeRates),
+		Expiry:     DefaultFeeEstimateExpiry,
+		MaxAge:     DefaultFeeEstimateMaxAge,
+		estimates:  make(map[int]feeEstimate),
+		refreshing: make(map[int]bool),
+	}
+}
+
+func (fp *EstimatingFeeProvider) GetFeePerByte(feeLevel wallet.FeeLevel) uint64 {
+	level, multiplier := feeLevel, uint64(1)
+	if feeLevel == wallet.FEE_BUMP {
+		level, multiplier = wallet.PRIOIRTY, 2
+	}
+	target, ok := FeeLevelTargets[level]
+	if !ok {
+		target = FeeLevelTargets[wallet.NORMAL]
+	}
+	feePerByte := fp.estimate(target) * multiplier
+	if feePerByte == 0 {
+		return fp.fallback.GetFeePerByte(feeLevel)
+	}
+	if feePerByte > fp.fallback.maxFee {
+		return fp.fallback.maxFee
+	}
+	return feePerByte
+}
+
+// estimate returns the fee per byte to confirm within target blocks, or zero if the backend has
+// no estimate newer than MaxAge. A missing or expired estimate is refreshed in the background
+// unless the last refresh failed less than its backoff ago, and an expired one is still used
+// until it is refreshed or reaches MaxAge.
+func (fp *EstimatingFeeProvider) estimate(target int) uint64 {
+	fp.mtx.Lock()
+	defer fp.mtx.Unlock()
+	e := fp.estimates[target]
+	now := time.Now()
+	if (e.feePerByte == 0 || now.Sub(e.fetched) >= fp.Expiry) && !now.Before(e.retryAt) && !fp.refreshing[target] {
+		fp.refreshing[target] = true
+		go fp.refresh(target)
+	}
+	if now.Sub(e.fetched) >= fp.MaxAge {
+		return 0
+	}
+	return e.feePerByte
+}
+
+// refresh asks the backend for the estimate to confirm within target blocks. After a failure the
+// previous estimate is kept and the next refresh waits for a backoff doubling with each failure.
+func (fp *EstimatingFeeProvider) refresh(target int) {
+	feePerKB, err := fp.estimator.EstimateFee(target)
+
+	fp.mtx.Lock()
+	defer fp.mtx.Unlock()
+	delete(fp.refreshing, target)
+	if err != nil || feePerKB <= 0 {
+		e := fp.estimates[target]
+		e.failures++
+		e.retryAt = time.Now().Add(feeEstimateBackoff(e.failures))
+		fp.estimates[target] = e
+		return
+	}
+	feePerByte := uint64(feePerKB) / 1000
+	if feePerByte == 0 {
+		feePerByte = 1
+	}
+	fp.estimates[target] = feeEstimate{feePerByte: feePerByte, fetched: time.Now()}
+}
+
+// feeEstimateBackoff returns how long to wait before retrying an estimate after failures failed
+// refreshes in a row
+func feeEstimateBackoff(failures int) time.Duration {
+	backoff := minFeeEstimateBackoff
+	for i := 1; i < failures && backoff < maxFeeEstimateBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxFeeEstimateBackoff {
+		return maxFeeEstimateBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,200 @@
+package util
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+// mockFeeEstimator answers with the fee per kilobyte of each target and counts the requests
+type mockFeeEstimator struct {
+	sync.Mutex
+	fees     map[int]int
+	err      error
+	requests int
+}
+
+func (m *mockFeeEstimator) EstimateFee(nBlocks int) (int, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.requests++
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.fees[nBlocks], nil
+}
+
+func (m *mockFeeEstimator) set(fees map[int]int, err error) {
+	m.Lock()
+	defer m.Unlock()
+	m.fees, m.err = fees, err
+}
+
+func (m *mockFeeEstimator) requestCount() int {
+	m.Lock()
+	defer m.Unlock()
+	return m.requests
+}
+
+// waitForRefresh waits until no estimate of fp is being fetched
+func waitForRefresh(t *testing.T, fp *EstimatingFeeProvider) {
+	for i := 0; i < 100; i++ {
+		fp.mtx.Lock()
+		n := len(fp.refreshing)
+		fp.mtx.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Fatal("Timed out waiting for the fee estimates to refresh")
+}
+
+func TestEstimatingFeeProvider_GetFeePerByte(t *testing.T) {
+	est := &mockFeeEstimator{fees: map[int]int{1: 50000, 3: 20000, 6: 5000, 24: 400}}
+	fp := NewEstimatingFeeProvider(60, 40, 30, 20, 10, nil, est)
+
+	// The first call does not wait for the backend and pays the configured fee
+	if fee := fp.GetFeePerByte(wallet.NORMAL); fee != 30 {
+		t.Errorf("Expected the configured normal fee of 30 before the estimate arrives, got %d", fee)
+	}
+	for _, level := range []wallet.FeeLevel{wallet.PRIOIRTY, wallet.ECONOMIC, wallet.SUPER_ECONOMIC} {
+		fp.GetFeePerByte(level)
+	}
+	waitForRefresh(t, fp)
+
+	for _, test := range []struct {
+		level    wallet.FeeLevel
+		expected uint64
+	}{
+		{wallet.PRIOIRTY, 50},
+		{wallet.NORMAL, 20},
+		{wallet.ECONOMIC, 5},
+		// Estimates below a satoshi per byte are rounded up
+		{wallet.SUPER_ECONOMIC, 1},
+		// Twice the priority fee, clamped to the max fee
+		{wallet.FEE_BUMP, 60},
+	} {
+		if fee := fp.GetFeePerByte(test.level); fee != test.expected {
+			t.Errorf("Expected fee per byte of %d at level %d, got %d", test.expected, test.level, fee)
+		}
+	}
+
+	// The estimates are cached until they expire
+	requests := est.requestCount()
+	est.set(map[int]int{3: 25000}, nil)
+	if fee := fp.GetFeePerByte(wallet.NORMAL); fee != 20 {
+		t.Errorf("Expected the cached fee per byte of 20, got %d", fee)
+	}
+	waitForRefresh(t, fp)
+	if n := est.requestCount(); n != requests {
+		t.Errorf("Expected no request for a cached estimate, got %d", n-requests)
+	}
+
+	// An expired estimate is used until its refresh completes
+	fp.mtx.Lock()
+	fp.estimates[3] = feeEstimate{feePerByte: 20, fetched: time.Now().Add(-2 * DefaultFeeEstimateExpiry)}
+	fp.mtx.Unlock()
+	if fee := fp.GetFeePerByte(wallet.NORMAL); fee != 20 {
+		t.Errorf("Expected the expired fee per byte of 20 while refreshing, got %d", fee)
+	}
+	waitForRefresh(t, fp)
+	if fee := fp.GetFeePerByte(wallet.NORMAL); fee != 25 {
+		t.Errorf("Expected the new fee per byte of 25 once the estimate expired, got %d", fee)
+	}
+}
+
+// allowRetry lets the failed estimate of target be retried without waiting for its backoff
+func allowRetry(fp *EstimatingFeeProvider, target int) {
+	fp.mtx.Lock()
+	defer fp.mtx.Unlock()
+	e := fp.estimates[target]
+	e.retryAt = time.Now()
+	fp.estimates[target] = e
+}
+
+func TestEstimatingFeeProvider_Fallback(t *testing.T) {
+	est := &mockFeeEstimator{err: errors.New("backend unavailable")}
+	fp := NewEstimatingFeeProvider(2000, 40, 30, 20, 10, nil, est)
+
+	if fee := fp.GetFeePerByte(wallet.NORMAL); fee != 30 {
+		t.Errorf("Expected the configured normal fee of 30 without an estimate, got %d", fee)
+	}
+	waitForRefresh(t, fp)
+	if fee := fp.GetFeePerByte(wallet.NORMAL); fee != 30 {
+		t.Errorf("Expected the configured normal fee of 30 after a failed estimate, got %d", fee)
+	}
+	waitForRefresh(t, fp)
+	if n := est.requestCount(); n != 1 {
+		t.Errorf("Expected the failed estimate not to be retried before its backoff, got %d requests", n)
+	}
+
+	// Once the backoff has passed the estimate is retried, and the next backoff is doubled
+	allowRetry(fp, 3)
+	fp.GetFeePerByte(wallet.NORMAL)
+	waitForRefresh(t, fp)
+	if n := est.requestCount(); n != 2 {
+		t.Errorf("Expected the failed estimate to be retried after its backoff, got %d requests", n)
+	}
+	fp.mtx.Lock()
+	backoff := time.Until(fp.estimates[3].retryAt)
+	fp.mtx.Unlock()
+	if backoff <= minFeeEstimateBackoff || backoff > 2*minFeeEstimateBackoff {
+		t.Errorf("Expected a backoff of %s after two failures, got %s", 2*minFeeEstimateBackoff, backoff)
+	}
+
+	// Once the backend recovers its estimate is used
+	est.set(map[int]int{3: 50000}, nil)
+	allowRetry(fp, 3)
+	fp.GetFeePerByte(wallet.NORMAL)
+	waitForRefresh(t, fp)
+	if fee := fp.GetFeePerByte(wallet.NORMAL); fee != 50 {
+		t.Errorf("Expected the estimated fee of 50 once the backend recovered, got %d", fee)
+	}
+
+	// An expired estimate which cannot be refreshed is still used until it reaches the max age
+	est.set(nil, errors.New("backend unavailable"))
+	fp.mtx.Lock()
+	fp.estimates[3] = feeEstimate{feePerByte: 50, fetched: time.Now().Add(-2 * DefaultFeeEstimateExpiry)}
+	fp.mtx.Unlock()
+	fp.GetFeePerByte(wallet.NORMAL)
+	waitForRefresh(t, fp)
+	if fee := fp.GetFeePerByte(wallet.NORMAL); fee != 50 {
+		t.Errorf("Expected the expired fee of 50 after a failed refresh, got %d", fee)
+	}
+	fp.mtx.Lock()
+	e := fp.estimates[3]
+	e.fetched = time.Now().Add(-DefaultFeeEstimateMaxAge)
+	fp.estimates[3] = e
+	fp.mtx.Unlock()
+	if fee := fp.GetFeePerByte(wallet.NORMAL); fee != 30 {
+		t.Errorf("Expected the configured normal fee of 30 after the estimate reached its max age, got %d", fee)
+	}
+
+	// Servers without enough data to estimate answer with a negative fee
+	est.set(map[int]int{1: -100000000}, nil)
+	fp.GetFeePerByte(wallet.PRIOIRTY)
+	waitForRefresh(t, fp)
+	if fee := fp.GetFeePerByte(wallet.PRIOIRTY); fee != 40 {
+		t.Errorf("Expected the configured priority fee of 40 without an estimate, got %d", fee)
+	}
+}
+
+func TestFeeEstimateBackoff(t *testing.T) {
+	for _, test := range []struct {
+		failures int
+		expected time.Duration
+	}{
+		{1, minFeeEstimateBackoff},
+		{2, 2 * minFeeEstimateBackoff},
+		{3, 4 * minFeeEstimateBackoff},
+		{100, maxFeeEstimateBackoff},
+	} {
+		if backoff := feeEstimateBackoff(test.failures); backoff != test.expected {
+			t.Errorf("Expected a backoff of %s after %d failures, got %s", test.expected, test.failures, backoff)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package util
 
-import "github.com/btcsuite/btcd/wire"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
 
 func OutPointsEqual(a, b wire.OutPoint) bool {
 	if !a.Hash.IsEqual(&b.Hash) {
@@ -8,3 +15,23 @@ func OutPointsEqual(a, b wire.OutPoint) bool {
 	}
 	return a.Index == b.Index
 }
+
+// ParseOutPoint parses an outpoint in the txid:index form produced by wire.OutPoint.String
+func ParseOutPoint(s string) (wire.OutPoint, error) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return wire.OutPoint{}, fmt.Errorf("outpoint %q is not of the form txid:index", s)
+	}
+	if i != chainhash.MaxHashStringSize {
+		return wire.OutPoint{}, fmt.Errorf("outpoint %q has an invalid txid", s)
+	}
+	hash, err := chainhash.NewHashFromStr(s[:i])
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+	index, err := strconv.ParseUint(s[i+1:], 10, 32)
+	if err != nil {
+		return wire.OutPoint{}, fmt.Errorf("outpoint %q has an invalid index", s)
+	}
+	return *wire.NewOutPoint(hash, uint32(index)), nil
+}
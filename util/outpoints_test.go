@@ -24,3 +24,23 @@ func TestOutPointsEqual(t *testing.T) {
 		t.Error("Incorrectly returned equal outpoints")
 	}
 }
+
+func TestParseOutPoint(t *testing.T) {
+	h, err := chainhash.NewHashFromStr("6f7a58ad92702601fcbaac0e039943a384f5274a205c16bb8bbab54f9ea2fbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := wire.NewOutPoint(h, 3)
+	parsed, err := ParseOutPoint(op.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !OutPointsEqual(parsed, *op) {
+		t.Errorf("expected %s, got %s", op.String(), parsed.String())
+	}
+	for _, s := range []string{h.String(), h.String() + ":x", "abc:0"} {
+		if _, err := ParseOutPoint(s); err == nil {
+			t.Errorf("expected %q to be refused", s)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil/coinset"
+)
+
+// IncrementalRelayFee is the fee per byte a replacement must pay on top of the fee of the
+// transaction it replaces to pay for its own relay (BIP125 rule 4)
+const IncrementalRelayFee = 1
+
+// maxReplaceableSequence is the highest input sequence number which signals BIP125 replaceability
+const maxReplaceableSequence = wire.MaxTxInSequenceNum - 2
+
+// ErrNotReplaceable is returned when replacing a transaction which does not signal BIP125
+// replaceability
+var ErrNotReplaceable = errors.New("transaction does not signal replaceability")
+
+// SignalsReplacement reports whether any input of tx opts in to BIP125 replacement
+func SignalsReplacement(tx *wire.MsgTx) bool {
+	for _, in := range tx.TxIn {
+		if in.Sequence <= maxReplaceableSequence {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplacementFee returns the fee a replacement of size bytes must pay to replace a transaction
+// which paid originalFee. It pays at least feePerByte and at least the original fee plus the
+// incremental relay fee for its own size (BIP125 rules 3 and 4).
+func ReplacementFee(originalFee int64, size int, feePerByte int64) int64 {
+	fee := int64(size) * feePerByte
+	if min := originalFee + int64(size)*IncrementalRelayFee; fee < min {
+		fee = min
+	}
+	return fee
+}
+
+// ReplacementSpec describes a transaction to replace with one paying a higher fee
+type ReplacementSpec struct {
+	// The transaction to replace and the outputs each of its inputs spend
+	Original *wire.MsgTx
+	PrevOuts map[wire.OutPoint]*wire.TxOut
+
+	// IsChange reports whether an output script of the original pays our change. The first
+	// change output pays the extra fee while every other output is kept as is.
+	IsChange func(pkScript []byte) bool
+
+	// Coins which may be added as inputs if the change cannot cover the extra fee. They must be
+	// confirmed since a replacement may not spend new unconfirmed outputs (BIP125 rule 2).
+	Coins []coinset.Coin
+
+	// The script to pay change to if the original had no change output
+	ChangeScript []byte
+
+	// The fee rate the replacement should pay
+	FeePerByte int64
+
+	// EstimateSize returns the signed size of a transaction with numInputs inputs, the given
+	// outputs and optionally a change output. IsDust reports whether an output of amount paying
+	// to a script of scriptSize bytes is too small to relay.
+	EstimateSize func(numInputs int, outputs []*wire.TxOut, addChange bool) int
+	IsDust       func(amount int64, scriptSize int) bool
+}
+
+// BuildReplacement returns an unsigned transaction spending the same inputs as the original and
+// paying the same recipients at a higher fee, along with any coins which were added as inputs.
+// The extra fee comes out of the change, with the largest coins added first if the change is
+// too small.
+func BuildReplacement(spec ReplacementSpec) (*wire.MsgTx, []coinset.Coin, error) {
+	original := spec.Original
+	if !SignalsReplacement(original) {
+		return nil, nil, ErrNotReplaceable
+	}
+
+	var totalIn, totalOut, recipientsOut int64
+	tx := wire.NewMsgTx(original.Version)
+	tx.LockTime = original.LockTime
+	for _, in := range original.TxIn {
+		prevOut, ok := spec.PrevOuts[in.PreviousOutPoint]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown previous output %s", in.PreviousOutPoint.String())
+		}
+		totalIn += prevOut.Value
+		replacementIn := wire.NewTxIn(&in.PreviousOutPoint, nil, nil)
+		replacementIn.Sequence = in.Sequence
+		tx.AddTxIn(replacementIn)
+	}
+	var (
+		recipients   []*wire.TxOut
+		changeScript = spec.ChangeScript
+		foundChange  bool
+	)
+	for _, out := range original.TxOut {
+		totalOut += out.Value
+		if !foundChange && spec.IsChange(out.PkScript) {
+			changeScript, foundChange = out.PkScript, true
+			continue
+		}
+		recipients = append(recipients, wire.NewTxOut(out.Value, out.PkScript))
+		recipientsOut += out.Value
+	}
+	originalFee := totalIn - totalOut
+
+	coins := make([]coinset.Coin, len(spec.Coins))
+	copy(coins, spec.Coins)
+	sort.Slice(coins, func(i, j int) bool { return coins[i].Value() > coins[j].Value() })
+
+	var added []coinset.Coin
+	for {
+		size := spec.EstimateSize(len(tx.TxIn), recipients, true)
+		change := totalIn - recipientsOut - ReplacementFee(originalFee, size, spec.FeePerByte)
+		if change >= 0 {
+			tx.TxOut = recipients
+			if change > 0 && !spec.IsDust(change, len(changeScript)) {
+				tx.AddTxOut(wire.NewTxOut(change, changeScript))
+			}
+			return tx, added, nil
+		}
+		if len(coins) == 0 {
+			return nil, nil, wallet.ErrInsufficientFunds
+		}
+		coin := coins[0]
+		coins = coins[1:]
+		in := wire.NewTxIn(wire.NewOutPoint(coin.Hash(), coin.Index()), nil, nil)
+		in.Sequence = 0 // Keep the replacement replaceable
+		tx.AddTxIn(in)
+		totalIn += int64(coin.Value())
+		added = append(added, coin)
+	}
+}
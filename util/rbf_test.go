@@ -0,0 +1,130 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+)
+
+var (
+	rbfRecipientScript = []byte{0x76, 0xa9, 0x01}
+	rbfChangeScript    = []byte{0x76, 0xa9, 0x02}
+	rbfNewChangeScript = []byte{0x76, 0xa9, 0x03}
+)
+
+// rbfSpec returns a spec replacing a transaction which spends one 100000 satoshi output to pay
+// 60000 to a recipient and change to us, paying a fee of 1000
+func rbfSpec(change int64, sequence uint32) ReplacementSpec {
+	prevHash := chainhash.DoubleHashH([]byte("prev"))
+	prevOut := wire.NewOutPoint(&prevHash, 0)
+	original := wire.NewMsgTx(1)
+	in := wire.NewTxIn(prevOut, nil, nil)
+	in.Sequence = sequence
+	original.AddTxIn(in)
+	original.AddTxOut(wire.NewTxOut(60000, rbfRecipientScript))
+	if change > 0 {
+		original.AddTxOut(wire.NewTxOut(change, rbfChangeScript))
+	}
+	return ReplacementSpec{
+		Original:     original,
+		PrevOuts:     map[wire.OutPoint]*wire.TxOut{*prevOut: wire.NewTxOut(100000, nil)},
+		IsChange:     func(script []byte) bool { return bytes.Equal(script, rbfChangeScript) },
+		ChangeScript: rbfNewChangeScript,
+		FeePerByte:   10,
+		EstimateSize: func(numInputs int, outputs []*wire.TxOut, addChange bool) int {
+			size := 10 + 150*numInputs + 34*len(outputs)
+			if addChange {
+				size += 34
+			}
+			return size
+		},
+		IsDust: func(amount int64, scriptSize int) bool { return amount < 546 },
+	}
+}
+
+func rbfCoin(t *testing.T, seed string, value int64) coinset.Coin {
+	c, err := NewCoin(chainhash.DoubleHashH([]byte(seed)), 1, btcutil.Amount(value), 6, rbfNewChangeScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestReplacementFee(t *testing.T) {
+	if fee := ReplacementFee(1000, 200, 20); fee != 4000 {
+		t.Errorf("expected the fee rate to set the fee, got %d", fee)
+	}
+	if fee := ReplacementFee(5000, 200, 20); fee != 5200 {
+		t.Errorf("expected the original fee plus relay of the replacement, got %d", fee)
+	}
+}
+
+func TestBuildReplacementReducesChange(t *testing.T) {
+	tx, added, err := BuildReplacement(rbfSpec(39000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 0 || len(tx.TxIn) != 1 {
+		t.Errorf("expected only the original input, got %d inputs", len(tx.TxIn))
+	}
+	if tx.TxIn[0].Sequence != 0 {
+		t.Error("expected the replacement to stay replaceable")
+	}
+	if len(tx.TxOut) != 2 || tx.TxOut[0].Value != 60000 || !bytes.Equal(tx.TxOut[0].PkScript, rbfRecipientScript) {
+		t.Fatalf("expected the recipient to be paid unchanged, got %+v", tx.TxOut)
+	}
+	// 228 bytes at 10 sat/byte
+	if change := tx.TxOut[1]; change.Value != 100000-60000-2280 || !bytes.Equal(change.PkScript, rbfChangeScript) {
+		t.Errorf("expected the change to pay the new fee, got %d", change.Value)
+	}
+}
+
+func TestBuildReplacementAddsInputs(t *testing.T) {
+	spec := rbfSpec(39000, 0)
+	spec.FeePerByte = 200
+	spec.Coins = []coinset.Coin{rbfCoin(t, "small", 20000), rbfCoin(t, "large", 90000)}
+	tx, added, err := BuildReplacement(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0].Value() != 90000 {
+		t.Fatalf("expected the largest coin to be added, got %d coins", len(added))
+	}
+	var out int64
+	for _, o := range tx.TxOut {
+		out += o.Value
+	}
+	// 378 bytes at 200 sat/byte
+	if fee := 190000 - out; fee != 75600 {
+		t.Errorf("expected a fee of 75600, got %d", fee)
+	}
+
+	spec.Coins = nil
+	if _, _, err := BuildReplacement(spec); err != wallet.ErrInsufficientFunds {
+		t.Errorf("expected insufficient funds without coins to add, got %v", err)
+	}
+}
+
+func TestBuildReplacementDropsDustChange(t *testing.T) {
+	// The change covers the fee but what remains is dust
+	spec := rbfSpec(39000, 0)
+	spec.FeePerByte = 174
+	tx, _, err := BuildReplacement(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxOut) != 1 {
+		t.Errorf("expected the dust change to be dropped, got %d outputs", len(tx.TxOut))
+	}
+}
+
+func TestBuildReplacementRequiresSignal(t *testing.T) {
+	if _, _, err := BuildReplacement(rbfSpec(39000, wire.MaxTxInSequenceNum)); err != ErrNotReplaceable {
+		t.Errorf("expected ErrNotReplaceable, got %v", err)
+	}
+}
@@ -1,8 +1,15 @@
 package util
 
-import "github.com/OpenBazaar/wallet-interface"
+import (
+	"github.com/OpenBazaar/multiwallet/coins"
+	"github.com/OpenBazaar/wallet-interface"
+)
 
-// All implemented coins currently have 100m satoshis per coin
+// SatoshisPerCoin returns the number of base units in one coin. Registered coins declare their
+// divisibility and every other implemented coin has 100m satoshis per coin.
 func SatoshisPerCoin(coinType wallet.CoinType) float64 {
+	if c, ok := coins.Lookup(coinType); ok {
+		return float64(c.UnitsPerCoin())
+	}
 	return 100000000
 }
@@ -0,0 +1,110 @@
+package utxo
+
+import (
+	wi "github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+)
+
+// Coin is everything which sets one UTXO coin apart from the others: how its addresses are
+// encoded, how its inputs are signed and how its transactions are serialized. Wallet does the
+// rest the same way for every coin.
+type Coin interface {
+	// Features returns the fixed properties of the coin
+	Features() Features
+
+	// DecodeAddress parses an address of the coin on the network of params
+	DecodeAddress(addr string, params *chaincfg.Params) (btcutil.Address, error)
+
+	// PayToAddrScript returns the output script paying addr
+	PayToAddrScript(addr btcutil.Address) ([]byte, error)
+
+	// ExtractAddress returns the address paid by an output script
+	ExtractAddress(script []byte, params *chaincfg.Params) (btcutil.Address, error)
+
+	// ScriptHashAddress returns the address of a multisig redeem script. Segwit coins pay to the
+	// witness script hash.
+	ScriptHashAddress(redeemScript []byte, params *chaincfg.Params) (btcutil.Address, error)
+
+	// SignP2PKH returns the signature script of input idx of tx, which spends a P2PKH output of
+	// key. prevOuts holds the output spent by each input of tx.
+	SignP2PKH(tx *wire.MsgTx, idx int, prevOuts []*wire.TxOut, key *btcec.PrivateKey) ([]byte, error)
+
+	// SignScript returns the SigHashAll signature of input idx of tx, which spends a script hash
+	// output of redeemScript, with the sighash type appended
+	SignScript(tx *wire.MsgTx, idx int, redeemScript []byte, prevOuts []*wire.TxOut, key *btcec.PrivateKey) ([]byte, error)
+
+	// Serialize returns tx in the coin's wire format along with its transaction ID
+	Serialize(tx *wire.MsgTx) ([]byte, chainhash.Hash, error)
+
+	// ToWire converts a raw transaction of the coin to the bitcoin wire format read by
+	// wire.MsgTx. Coins which already use that format return raw as is.
+	ToWire(raw []byte) []byte
+}
+
+// Features are the fixed properties of a coin
+type Features struct {
+	// CoinType is the coin's wallet-interface type
+	CoinType wi.CoinType
+
+	// Currency is the definition balances are reported in
+	Currency wi.CurrencyDefinition
+
+	// P2PKHInput is the size class of the inputs spending the wallet's own outputs
+	P2PKHInput InputType
+
+	// RelayFeePerKb is the relay fee outputs are checked against for dust
+	RelayFeePerKb btcutil.Amount
+
+	// DustLimit, when set, is a flat value below which any output is dust whatever its size,
+	// replacing the check against RelayFeePerKb
+	DustLimit btcutil.Amount
+
+	// MinFeePerByte, when set, is the least fee per byte paid at any fee level
+	MinFeePerByte uint64
+
+	// SafeConfirmations is the number of confirmations from which a transaction is reported
+	// as confirmed rather than pending
+	SafeConfirmations int32
+
+	// FeeEstimateAddress is a mainnet address paid when estimating the fee of a spend. A long one
+	// is used so the fee is not under estimated.
+	FeeEstimateAddress string
+
+	// Segwit is set when multisig outputs pay a witness script hash and are spent with witnesses
+	// rather than signature scripts
+	Segwit bool
+
+	// Timelocks is set when multisig redeem scripts may carry a CHECKSEQUENCEVERIFY timeout
+	Timelocks bool
+
+	// Replacement is set when the network relays BIP125 replacements, so fee bumps may replace
+	// a transaction rather than only spend its change
+	Replacement bool
+}
+
+// IsDust reports whether an output of amount paying to a script of scriptSize bytes is too small
+// to relay
+func (f Features) IsDust(amount btcutil.Amount, scriptSize int) bool {
+	if f.DustLimit > 0 {
+		return amount < f.DustLimit
+	}
+	return txrules.IsDustAmount(amount, scriptSize, f.RelayFeePerKb)
+}
+
+// DustThreshold returns the smallest P2PKH output which is not dust
+func (f Features) DustThreshold() btcutil.Amount {
+	if f.DustLimit > 0 {
+		return f.DustLimit
+	}
+	return txrules.GetDustThreshold(P2PKHOutputSize, f.RelayFeePerKb)
+}
+
+// FeeProvider returns the fee per byte to pay at a fee level
+type FeeProvider interface {
+	GetFeePerByte(feeLevel wi.FeeLevel) uint64
+}
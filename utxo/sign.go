@@ -0,0 +1,925 @@
+package utxo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/OpenBazaar/spvwallet"
+	wi "github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/btcsuite/btcutil/txsort"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
+	"github.com/OpenBazaar/multiwallet/util"
+)
+
+// BuildTx builds and signs a transaction paying amount to addr, plus optionalOutput if it is not
+// nil, from coins chosen by the configured coin selection
+func (w *Wallet) BuildTx(amount int64, addr btcutil.Address, feeLevel wi.FeeLevel, optionalOutput *wire.TxOut) (*wire.MsgTx, error) {
+	// Check for dust
+	script, err := w.AddressToScript(addr)
+	if err != nil {
+		return nil, err
+	}
+	if w.isDust(amount, len(script)) {
+		return nil, wi.ErrorDustAmount
+	}
+	outputs := []*wire.TxOut{wire.NewTxOut(amount, script)}
+	if optionalOutput != nil {
+		outputs = append(outputs, optionalOutput)
+	}
+	return w.buildTxWithOutputs(outputs, feeLevel, w.CoinSelection)
+}
+
+// BuildSpendManyTx builds a transaction paying every output in outs. An empty strategy uses the
+// wallet's configured coin selection.
+func (w *Wallet) BuildSpendManyTx(outs []wi.TransactionOutput, feeLevel wi.FeeLevel, strategy util.CoinSelectionStrategy) (*wire.MsgTx, error) {
+	outputs, err := w.txOutputs(outs)
+	if err != nil {
+		return nil, err
+	}
+	if strategy == "" {
+		strategy = w.CoinSelection
+	}
+	return w.buildTxWithOutputs(outputs, feeLevel, strategy)
+}
+
+// txOutputs converts outs to transaction outputs. Each output is checked for dust on its own since
+// one dust output would get the whole transaction rejected.
+func (w *Wallet) txOutputs(outs []wi.TransactionOutput) ([]*wire.TxOut, error) {
+	if len(outs) == 0 {
+		return nil, errors.New("no outputs to pay")
+	}
+	outputs := make([]*wire.TxOut, 0, len(outs))
+	for i, out := range outs {
+		script, err := w.AddressToScript(out.Address)
+		if err != nil {
+			return nil, clientErr.Classify(clientErr.KindInvalidAddress, fmt.Errorf("output %d: %s", i, err.Error()))
+		}
+		if !out.Value.IsInt64() || w.isDust(out.Value.Int64(), len(script)) {
+			return nil, clientErr.Classify(clientErr.KindDust, fmt.Errorf("output %d paying %s: %s", i, out.Address.String(), wi.ErrorDustAmount.Error()))
+		}
+		outputs = append(outputs, wire.NewTxOut(out.Value.Int64(), script))
+	}
+	return outputs, nil
+}
+
+// buildTxWithOutputs funds, sorts and signs a transaction paying outputs plus change. The coins
+// spent are chosen by the given strategy from those which are not frozen.
+func (w *Wallet) buildTxWithOutputs(outputs []*wire.TxOut, feeLevel wi.FeeLevel, strategy util.CoinSelectionStrategy) (*wire.MsgTx, error) {
+	utxos, err := w.WS.SpendableUtxos()
+	if err != nil {
+		return nil, err
+	}
+	// Change below the dust threshold is left to the miners
+	coinSelector, err := util.NewCoinSelector(strategy, w.Coin.Features().DustThreshold())
+	if err != nil {
+		return nil, err
+	}
+	return w.buildTxFromUtxos(outputs, feeLevel, utxos, coinSelector)
+}
+
+// BuildTxFromOutpoints builds a transaction paying outs which spends exactly the given outputs of
+// ours, with whatever is left after the fee returned as change
+func (w *Wallet) BuildTxFromOutpoints(outpoints []wire.OutPoint, outs []wi.TransactionOutput, feeLevel wi.FeeLevel) (*wire.MsgTx, error) {
+	outputs, err := w.txOutputs(outs)
+	if err != nil {
+		return nil, err
+	}
+	utxos, err := w.WS.SelectUtxos(outpoints)
+	if err != nil {
+		return nil, err
+	}
+	return w.buildTxFromUtxos(outputs, feeLevel, utxos, util.AllCoinsSelector{})
+}
+
+// buildTxFromUtxos funds, sorts and signs a transaction paying outputs plus change from the utxos
+// chosen by coinSelector
+func (w *Wallet) buildTxFromUtxos(outputs []*wire.TxOut, feeLevel wi.FeeLevel, utxos []wi.Utxo, coinSelector coinset.CoinSelector) (*wire.MsgTx, error) {
+	var (
+		prevOuts map[wire.OutPoint]*wire.TxOut
+		keys     map[wire.OutPoint]*btcec.PrivateKey
+	)
+
+	// Create input source
+	height, _ := w.WS.ChainTip()
+	coinMap := util.GatherCoins(height, utxos, w.ScriptToAddress, w.KM.GetKeyForScript)
+
+	coins := make([]coinset.Coin, 0, len(coinMap))
+	for k := range coinMap {
+		coins = append(coins, k)
+	}
+	inputSource := func(target btcutil.Amount) (total btcutil.Amount, inputs []*wire.TxIn, inputValues []btcutil.Amount, scripts [][]byte, err error) {
+		coins, err := coinSelector.CoinSelect(target, coins)
+		if err != nil {
+			return total, inputs, inputValues, scripts, wi.ErrInsufficientFunds
+		}
+		prevOuts = make(map[wire.OutPoint]*wire.TxOut)
+		keys = make(map[wire.OutPoint]*btcec.PrivateKey)
+		for _, c := range coins.Coins() {
+			privKey, err := coinMap[c].ECPrivKey()
+			if err != nil {
+				return total, inputs, inputValues, scripts, err
+			}
+			total += c.Value()
+			outpoint := wire.NewOutPoint(c.Hash(), c.Index())
+			in := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
+			in.Sequence = 0 // Opt-in RBF so we can bump fees
+			inputs = append(inputs, in)
+			prevOuts[*outpoint] = wire.NewTxOut(int64(c.Value()), c.PkScript())
+			keys[*outpoint] = privKey
+		}
+		return total, inputs, inputValues, scripts, nil
+	}
+
+	// Get the fee per kilobyte
+	f := w.GetFeePerByte(feeLevel)
+	feePerKB := f.Int64() * 1000
+
+	// Create change source
+	changeSource := func() ([]byte, error) {
+		return w.AddressToScript(w.CurrentAddress(wi.INTERNAL))
+	}
+
+	authoredTx, err := NewUnsignedTransaction(w.Coin.Features(), outputs, btcutil.Amount(feePerKB), inputSource, changeSource)
+	if err != nil {
+		return nil, err
+	}
+
+	// BIP 69 sorting
+	txsort.InPlaceSort(authoredTx.Tx)
+
+	if err := w.signP2PKHInputs(authoredTx.Tx, prevOuts, keys); err != nil {
+		return nil, err
+	}
+	return authoredTx.Tx, nil
+}
+
+// BuildSpendAllTx builds a transaction spending every coin which is not frozen to addr
+func (w *Wallet) BuildSpendAllTx(addr btcutil.Address, feeLevel wi.FeeLevel) (*wire.MsgTx, error) {
+	utxos, err := w.WS.SpendableUtxos()
+	if err != nil {
+		return nil, err
+	}
+	return w.BuildSweepTx(utxos, addr, feeLevel)
+}
+
+// consolidationPlan plans merging the wallet's small utxos at the economic fee rate, estimating
+// the savings against spending them later at the normal rate. Utxos spent by a pending
+// consolidation are left out. The caller must hold w.consolidateLock.
+func (w *Wallet) consolidationPlan() (*util.ConsolidationPlan, error) {
+	spendable, err := w.WS.SpendableUtxos()
+	if err != nil {
+		return nil, err
+	}
+	pending := w.pendingOutpoints()
+	var utxos []wi.Utxo
+	for _, u := range spendable {
+		if !pending[u.Op] {
+			utxos = append(utxos, u)
+		}
+	}
+	inputType := w.Coin.Features().P2PKHInput
+	feePerByte, futureFeePerByte := w.GetFeePerByte(wi.ECONOMIC), w.GetFeePerByte(wi.NORMAL)
+	estimateSize := func(numInputs int) int {
+		return EstimateSerializeSize(numInputs, []*wire.TxOut{wire.NewTxOut(0, make([]byte, P2PKHPkScriptSize))}, false, inputType)
+	}
+	return util.PlanConsolidation(utxos, w.Consolidation, feePerByte.Int64(), futureFeePerByte.Int64(), InputSize(inputType), estimateSize)
+}
+
+// BuildSweepTx builds a transaction spending every one of utxos to addr
+func (w *Wallet) BuildSweepTx(utxos []wi.Utxo, addr btcutil.Address, feeLevel wi.FeeLevel) (*wire.MsgTx, error) {
+	tx := wire.NewMsgTx(1)
+
+	height, _ := w.WS.ChainTip()
+	coinMap := util.GatherCoins(height, utxos, w.ScriptToAddress, w.KM.GetKeyForScript)
+
+	var totalIn int64
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	keys := make(map[wire.OutPoint]*btcec.PrivateKey)
+	for c, key := range coinMap {
+		privKey, err := key.ECPrivKey()
+		if err != nil {
+			return nil, err
+		}
+		outpoint := wire.NewOutPoint(c.Hash(), c.Index())
+		tx.TxIn = append(tx.TxIn, wire.NewTxIn(outpoint, nil, nil))
+		totalIn += int64(c.Value())
+		prevOuts[*outpoint] = wire.NewTxOut(int64(c.Value()), c.PkScript())
+		keys[*outpoint] = privKey
+	}
+
+	// outputs
+	script, err := w.AddressToScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the fee
+	fee0 := w.GetFeePerByte(feeLevel)
+	feePerByte := fee0.Int64()
+	estimatedSize := EstimateSerializeSize(len(tx.TxIn), []*wire.TxOut{wire.NewTxOut(0, script)}, false, w.Coin.Features().P2PKHInput)
+	fee := int64(estimatedSize) * feePerByte
+
+	// Check for dust output
+	if w.isDust(totalIn-fee, len(script)) {
+		return nil, wi.ErrorDustAmount
+	}
+
+	// Build the output
+	out := wire.NewTxOut(totalIn-fee, script)
+	tx.TxOut = append(tx.TxOut, out)
+
+	// BIP 69 sorting
+	txsort.InPlaceSort(tx)
+
+	if err := w.signP2PKHInputs(tx, prevOuts, keys); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// signP2PKHInputs signs every input of tx, each of which spends the P2PKH output in prevOuts of the
+// key in keys at its outpoint
+func (w *Wallet) signP2PKHInputs(tx *wire.MsgTx, prevOuts map[wire.OutPoint]*wire.TxOut, keys map[wire.OutPoint]*btcec.PrivateKey) error {
+	spent := spentOutputs(tx, prevOuts)
+	for i, txIn := range tx.TxIn {
+		key, ok := keys[txIn.PreviousOutPoint]
+		if !ok {
+			return errors.New("key not found")
+		}
+		script, err := w.Coin.SignP2PKH(tx, i, spent, key)
+		if err != nil {
+			return fmt.Errorf("failed to sign transaction: %s", err.Error())
+		}
+		txIn.SignatureScript = script
+	}
+	return nil
+}
+
+// spentOutputs returns the output spent by each input of tx
+func spentOutputs(tx *wire.MsgTx, prevOuts map[wire.OutPoint]*wire.TxOut) []*wire.TxOut {
+	spent := make([]*wire.TxOut, 0, len(tx.TxIn))
+	for _, in := range tx.TxIn {
+		prevOut, ok := prevOuts[in.PreviousOutPoint]
+		if !ok {
+			prevOut = wire.NewTxOut(0, nil)
+		}
+		spent = append(spent, prevOut)
+	}
+	return spent
+}
+
+// NewUnsignedTransaction funds outputs from fetchInputs at feePerKb, adding change from fetchChange
+// unless it would be dust. The signed size is estimated from the P2PKH input type of features.
+func NewUnsignedTransaction(features Features, outputs []*wire.TxOut, feePerKb btcutil.Amount, fetchInputs txauthor.InputSource, fetchChange txauthor.ChangeSource) (*txauthor.AuthoredTx, error) {
+
+	var targetAmount btcutil.Amount
+	for _, txOut := range outputs {
+		targetAmount += btcutil.Amount(txOut.Value)
+	}
+
+	estimatedSize := EstimateSerializeSize(1, outputs, true, features.P2PKHInput)
+	targetFee := txrules.FeeForSerializeSize(feePerKb, estimatedSize)
+
+	for {
+		inputAmount, inputs, _, scripts, err := fetchInputs(targetAmount + targetFee)
+		if err != nil {
+			return nil, err
+		}
+		if inputAmount < targetAmount+targetFee {
+			return nil, errors.New("insufficient funds available to construct transaction")
+		}
+
+		maxSignedSize := EstimateSerializeSize(len(inputs), outputs, true, features.P2PKHInput)
+		maxRequiredFee := txrules.FeeForSerializeSize(feePerKb, maxSignedSize)
+		remainingAmount := inputAmount - targetAmount
+		if remainingAmount < maxRequiredFee {
+			targetFee = maxRequiredFee
+			continue
+		}
+
+		unsignedTransaction := &wire.MsgTx{
+			Version:  wire.TxVersion,
+			TxIn:     inputs,
+			TxOut:    outputs,
+			LockTime: 0,
+		}
+		changeIndex := -1
+		changeAmount := inputAmount - targetAmount - maxRequiredFee
+		if changeAmount != 0 && !features.IsDust(changeAmount, P2PKHOutputSize) {
+			changeScript, err := fetchChange()
+			if err != nil {
+				return nil, err
+			}
+			if len(changeScript) > P2PKHPkScriptSize {
+				return nil, errors.New("fee estimation requires change " +
+					"scripts no larger than P2PKH output scripts")
+			}
+			change := wire.NewTxOut(int64(changeAmount), changeScript)
+			l := len(outputs)
+			unsignedTransaction.TxOut = append(outputs[:l:l], change)
+			changeIndex = l
+		}
+
+		return &txauthor.AuthoredTx{
+			Tx:          unsignedTransaction,
+			PrevScripts: scripts,
+			TotalInput:  inputAmount,
+			ChangeIndex: changeIndex,
+		}, nil
+	}
+}
+
+// BumpFee raises the fee of an unconfirmed transaction. On coins which relay BIP125 replacements,
+// a transaction which signals replaceability and spends only our coins is replaced by one paying
+// the same recipients at a higher fee. Otherwise an unconfirmed output of ours is swept so the
+// child pays for its parent. If neither is possible the error of the replacement is returned.
+func (w *Wallet) BumpFee(txid chainhash.Hash) (*chainhash.Hash, error) {
+	txn, err := w.DB.Txns().Get(txid)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Height > 0 {
+		return nil, spvwallet.BumpFeeAlreadyConfirmedError
+	}
+	if txn.Height < 0 {
+		return nil, spvwallet.BumpFeeTransactionDeadError
+	}
+	var replaceErr error
+	if w.Coin.Features().Replacement {
+		replacement, err := w.buildReplacementTx(txn)
+		if err == nil {
+			return w.Broadcast(replacement)
+		}
+		w.Log.Warningf("replacing %s: %s, bumping its fee with a child instead", txid, err.Error())
+		replaceErr = err
+	}
+	// Check utxos for CPFP
+	utxos, err := w.WS.SpendableUtxos()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range utxos {
+		if u.Op.Hash.IsEqual(&txid) && u.AtHeight == 0 {
+			addr, err := w.ScriptToAddress(u.ScriptPubkey)
+			if err != nil {
+				return nil, err
+			}
+			key, err := w.KM.GetKeyForScript(addr.ScriptAddress())
+			if err != nil {
+				return nil, err
+			}
+			h, err := hex.DecodeString(u.Op.Hash.String())
+			if err != nil {
+				return nil, err
+			}
+			n := new(big.Int)
+			n, _ = n.SetString(u.Value, 10)
+			in := wi.TransactionInput{
+				LinkedAddress: addr,
+				OutpointIndex: u.Op.Index,
+				OutpointHash:  h,
+				Value:         *n,
+			}
+			return w.SweepAddress([]wi.TransactionInput{in}, nil, key, nil, wi.FEE_BUMP)
+		}
+	}
+	// Without an output to spend the reason replacement failed is more useful than not finding one
+	if replaceErr != nil {
+		return nil, replaceErr
+	}
+	return nil, spvwallet.BumpFeeNotFoundError
+}
+
+// buildReplacementTx builds and signs a BIP125 replacement of txn paying the fee bump rate
+func (w *Wallet) buildReplacementTx(txn wi.Txn) (*wire.MsgTx, error) {
+	original := wire.NewMsgTx(wire.TxVersion)
+	if err := original.Deserialize(bytes.NewReader(w.Coin.ToWire(txn.Bytes))); err != nil {
+		return nil, err
+	}
+	if !util.SignalsReplacement(original) {
+		return nil, util.ErrNotReplaceable
+	}
+	if w.hasUnconfirmedChildren(original.TxHash()) {
+		// Replacing the transaction would also evict its children
+		return nil, errors.New("transaction has unconfirmed children")
+	}
+
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	keys := make(map[wire.OutPoint]*btcec.PrivateKey)
+	for _, in := range original.TxIn {
+		prevOut, err := w.previousOutput(in.PreviousOutPoint)
+		if err != nil {
+			return nil, err
+		}
+		key, err := w.signingKey(prevOut.PkScript)
+		if err != nil {
+			return nil, err
+		}
+		prevOuts[in.PreviousOutPoint] = prevOut
+		keys[in.PreviousOutPoint] = key
+	}
+
+	// Only confirmed coins which are not frozen may be added to a replacement
+	height, _ := w.WS.ChainTip()
+	utxos, err := w.WS.SpendableUtxos()
+	if err != nil {
+		return nil, err
+	}
+	var confirmed []wi.Utxo
+	for _, u := range utxos {
+		if u.AtHeight > 0 {
+			confirmed = append(confirmed, u)
+		}
+	}
+	coinMap := util.GatherCoins(height, confirmed, w.ScriptToAddress, w.KM.GetKeyForScript)
+	coins := make([]coinset.Coin, 0, len(coinMap))
+	for c := range coinMap {
+		coins = append(coins, c)
+	}
+
+	changeScript, err := w.AddressToScript(w.CurrentAddress(wi.INTERNAL))
+	if err != nil {
+		return nil, err
+	}
+	inputType := w.Coin.Features().P2PKHInput
+	feePerByte := w.GetFeePerByte(wi.FEE_BUMP)
+	tx, added, err := util.BuildReplacement(util.ReplacementSpec{
+		Original:     original,
+		PrevOuts:     prevOuts,
+		IsChange:     w.isChangeScript,
+		Coins:        coins,
+		ChangeScript: changeScript,
+		FeePerByte:   feePerByte.Int64(),
+		EstimateSize: func(numInputs int, outputs []*wire.TxOut, addChange bool) int {
+			return EstimateSerializeSize(numInputs, outputs, addChange, inputType)
+		},
+		IsDust: w.isDust,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range added {
+		key, err := w.signingKey(c.PkScript())
+		if err != nil {
+			return nil, err
+		}
+		outpoint := wire.NewOutPoint(c.Hash(), c.Index())
+		prevOuts[*outpoint] = wire.NewTxOut(int64(c.Value()), c.PkScript())
+		keys[*outpoint] = key
+	}
+
+	// BIP 69 sorting
+	txsort.InPlaceSort(tx)
+
+	if err := w.signP2PKHInputs(tx, prevOuts, keys); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// previousOutput returns the output spent by op from our stored transactions or, failing that,
+// from the server
+func (w *Wallet) previousOutput(op wire.OutPoint) (*wire.TxOut, error) {
+	var raw []byte
+	if txn, err := w.DB.Txns().Get(op.Hash); err == nil {
+		raw = txn.Bytes
+	} else if raw, err = w.Client.GetRawTransaction(op.Hash.String()); err != nil {
+		return nil, err
+	}
+	prev := wire.NewMsgTx(wire.TxVersion)
+	if err := prev.Deserialize(bytes.NewReader(w.Coin.ToWire(raw))); err != nil {
+		return nil, err
+	}
+	if int(op.Index) >= len(prev.TxOut) {
+		return nil, fmt.Errorf("output %s not found", op.String())
+	}
+	return prev.TxOut[op.Index], nil
+}
+
+// signingKey returns the key of ours paid by an output script. It fails if the script does not
+// pay one of our keys.
+func (w *Wallet) signingKey(script []byte) (*btcec.PrivateKey, error) {
+	addr, err := w.ScriptToAddress(script)
+	if err != nil {
+		return nil, err
+	}
+	key, err := w.KM.GetKeyForScript(addr.ScriptAddress())
+	if err != nil {
+		return nil, fmt.Errorf("input paying %s is not ours", addr.EncodeAddress())
+	}
+	return key.ECPrivKey()
+}
+
+// isChangeScript reports whether the output script pays one of our internal keys
+func (w *Wallet) isChangeScript(script []byte) bool {
+	addr, err := w.ScriptToAddress(script)
+	if err != nil {
+		return false
+	}
+	path, err := w.DB.Keys().GetPathForKey(addr.ScriptAddress())
+	return err == nil && path.Purpose == wi.INTERNAL
+}
+
+// hasUnconfirmedChildren reports whether any of our unconfirmed transactions spends an output of txid
+func (w *Wallet) hasUnconfirmedChildren(txid chainhash.Hash) bool {
+	txns, err := w.DB.Txns().GetAll(true)
+	if err != nil {
+		return false
+	}
+	for _, txn := range txns {
+		if txn.Height != 0 {
+			continue
+		}
+		tx := wire.NewMsgTx(wire.TxVersion)
+		if err := tx.Deserialize(bytes.NewReader(w.Coin.ToWire(txn.Bytes))); err != nil {
+			continue
+		}
+		for _, in := range tx.TxIn {
+			if in.PreviousOutPoint.Hash.IsEqual(&txid) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SweepAddress spends every one of ins, which pay key or the multisig redeemScript, to address or
+// to an internal address if it is nil
+func (w *Wallet) SweepAddress(ins []wi.TransactionInput, address *btcutil.Address, key *hd.ExtendedKey, redeemScript *[]byte, feeLevel wi.FeeLevel) (*chainhash.Hash, error) {
+	var internalAddr btcutil.Address
+	if address != nil {
+		internalAddr = *address
+	} else {
+		internalAddr = w.CurrentAddress(wi.INTERNAL)
+	}
+	script, err := w.AddressToScript(internalAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	var val int64
+	var inputs []*wire.TxIn
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	for _, in := range ins {
+		val += in.Value.Int64()
+		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
+		if err != nil {
+			return nil, err
+		}
+		script, err := w.AddressToScript(in.LinkedAddress)
+		if err != nil {
+			return nil, err
+		}
+		outpoint := wire.NewOutPoint(ch, in.OutpointIndex)
+		input := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
+		inputs = append(inputs, input)
+		prevOuts[*outpoint] = wire.NewTxOut(in.Value.Int64(), script)
+	}
+	out := wire.NewTxOut(val, script)
+
+	txType := w.Coin.Features().P2PKHInput
+	if redeemScript != nil {
+		txType = P2SH_1of2_Multisig
+		_, err := spvwallet.LockTimeFromRedeemScript(*redeemScript)
+		if err == nil {
+			txType = P2SH_Multisig_Timelock_1Sig
+		}
+	}
+	estimatedSize := EstimateSerializeSize(len(ins), []*wire.TxOut{out}, false, txType)
+
+	// Calculate the fee
+	f := w.GetFeePerByte(feeLevel)
+	feePerByte := int(f.Int64())
+	fee := estimatedSize * feePerByte
+
+	outVal := val - int64(fee)
+	if outVal < 0 {
+		outVal = 0
+	}
+	out.Value = outVal
+
+	tx := &wire.MsgTx{
+		Version:  wire.TxVersion,
+		TxIn:     inputs,
+		TxOut:    []*wire.TxOut{out},
+		LockTime: 0,
+	}
+
+	// BIP 69 sorting
+	txsort.InPlaceSort(tx)
+
+	// Sign tx
+	privKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving private key: %s", err.Error())
+	}
+
+	// Check if time locked
+	var timeLocked bool
+	if redeemScript != nil {
+		rs := *redeemScript
+		if rs[0] == txscript.OP_IF {
+			timeLocked = true
+			tx.Version = 2
+			for _, txIn := range tx.TxIn {
+				locktime, err := spvwallet.LockTimeFromRedeemScript(*redeemScript)
+				if err != nil {
+					return nil, err
+				}
+				txIn.Sequence = locktime
+			}
+		}
+	}
+
+	spent := spentOutputs(tx, prevOuts)
+	for i, txIn := range tx.TxIn {
+		if redeemScript == nil {
+			script, err := w.Coin.SignP2PKH(tx, i, spent, privKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign transaction: %s", err.Error())
+			}
+			txIn.SignatureScript = script
+			continue
+		}
+		sig, err := w.Coin.SignScript(tx, i, *redeemScript, spent, privKey)
+		if err != nil {
+			return nil, err
+		}
+		// The timeout key takes the OP_ELSE branch of a timelocked redeem script. Otherwise the
+		// leading empty element is the one OP_CHECKMULTISIG consumes.
+		stack := [][]byte{{}, sig}
+		if timeLocked {
+			stack = [][]byte{sig, {}}
+		}
+		if err := w.setRedeemStack(txIn, stack, *redeemScript); err != nil {
+			return nil, err
+		}
+	}
+
+	// broadcast
+	return w.Broadcast(tx)
+}
+
+// setRedeemStack satisfies the script hash output spent by txIn with stack followed by
+// redeemScript, as a witness on segwit coins and as the signature script on the others
+func (w *Wallet) setRedeemStack(txIn *wire.TxIn, stack [][]byte, redeemScript []byte) error {
+	if w.Coin.Features().Segwit {
+		txIn.Witness = append(wire.TxWitness(stack), redeemScript)
+		return nil
+	}
+	builder := txscript.NewScriptBuilder()
+	for _, data := range stack {
+		builder.AddData(data)
+	}
+	builder.AddData(redeemScript)
+	scriptSig, err := builder.Script()
+	if err != nil {
+		return err
+	}
+	txIn.SignatureScript = scriptSig
+	return nil
+}
+
+// buildMultisigTx builds the unsigned transaction spending ins from the multisig address of
+// redeemScript to outs, with the fee at feePerByte split evenly between the outputs. It also
+// returns the output spent by each input.
+func (w *Wallet) buildMultisigTx(ins []wi.TransactionInput, outs []wi.TransactionOutput, redeemScript []byte, feePerByte uint64) (*wire.MsgTx, map[wire.OutPoint]*wire.TxOut, error) {
+	// Every input spends the multisig address so they share one scriptPubKey
+	scriptAddr, err := w.Coin.ScriptHashAddress(redeemScript, w.ChainParams)
+	if err != nil {
+		return nil, nil, err
+	}
+	prevOutScript, err := w.AddressToScript(scriptAddr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx := wire.NewMsgTx(1)
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	for _, in := range ins {
+		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
+		if err != nil {
+			return nil, nil, err
+		}
+		outpoint := wire.NewOutPoint(ch, in.OutpointIndex)
+		prevOuts[*outpoint] = wire.NewTxOut(in.Value.Int64(), prevOutScript)
+		input := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
+		tx.TxIn = append(tx.TxIn, input)
+	}
+	for _, out := range outs {
+		scriptPubKey, err := w.AddressToScript(out.Address)
+		if err != nil {
+			return nil, nil, err
+		}
+		output := wire.NewTxOut(out.Value.Int64(), scriptPubKey)
+		tx.TxOut = append(tx.TxOut, output)
+	}
+
+	// Subtract fee
+	txType := P2SH_2of3_Multisig
+	_, err = spvwallet.LockTimeFromRedeemScript(redeemScript)
+	if err == nil {
+		txType = P2SH_Multisig_Timelock_2Sigs
+	}
+	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, txType)
+	fee := estimatedSize * int(feePerByte)
+	if len(tx.TxOut) > 0 {
+		feePerOutput := fee / len(tx.TxOut)
+		for _, output := range tx.TxOut {
+			output.Value -= int64(feePerOutput)
+		}
+	}
+
+	// BIP 69 sorting
+	txsort.InPlaceSort(tx)
+	return tx, prevOuts, nil
+}
+
+func (w *Wallet) CreateMultisigSignature(ins []wi.TransactionInput, outs []wi.TransactionOutput, key *hd.ExtendedKey, redeemScript []byte, feePerByte big.Int) ([]wi.Signature, error) {
+	var sigs []wi.Signature
+	tx, prevOuts, err := w.buildMultisigTx(ins, outs, redeemScript, feePerByte.Uint64())
+	if err != nil {
+		return sigs, err
+	}
+
+	signingKey, err := key.ECPrivKey()
+	if err != nil {
+		return sigs, err
+	}
+
+	spent := spentOutputs(tx, prevOuts)
+	for i := range tx.TxIn {
+		sig, err := w.Coin.SignScript(tx, i, redeemScript, spent, signingKey)
+		if err != nil {
+			continue
+		}
+		bs := wi.Signature{InputIndex: uint32(i), Signature: sig}
+		sigs = append(sigs, bs)
+	}
+	return sigs, nil
+}
+
+func (w *Wallet) Multisign(ins []wi.TransactionInput, outs []wi.TransactionOutput, sigs1 []wi.Signature, sigs2 []wi.Signature, redeemScript []byte, feePerByte big.Int, broadcast bool) ([]byte, error) {
+	tx, _, err := w.buildMultisigTx(ins, outs, redeemScript, feePerByte.Uint64())
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if time locked
+	var timeLocked bool
+	if redeemScript[0] == txscript.OP_IF {
+		timeLocked = true
+	}
+
+	for i, input := range tx.TxIn {
+		var sig1 []byte
+		var sig2 []byte
+		for _, sig := range sigs1 {
+			if int(sig.InputIndex) == i {
+				sig1 = sig.Signature
+				break
+			}
+		}
+		for _, sig := range sigs2 {
+			if int(sig.InputIndex) == i {
+				sig2 = sig.Signature
+				break
+			}
+		}
+
+		stack := [][]byte{{}, sig1, sig2}
+		if timeLocked {
+			stack = append(stack, []byte{0x01})
+		}
+		if err := w.setRedeemStack(input, stack, redeemScript); err != nil {
+			return nil, err
+		}
+	}
+	// broadcast
+	if broadcast {
+		if _, err := w.Broadcast(tx); err != nil {
+			return nil, err
+		}
+	}
+	txBytes, _, err := w.Coin.Serialize(tx)
+	return txBytes, err
+}
+
+// GenerateMultisigScript returns a threshold of len(keys) multisig redeem script and its address.
+// On coins with timelocks a non-zero timeout adds a branch letting timeoutKey spend alone once
+// the timeout has passed.
+func (w *Wallet) GenerateMultisigScript(keys []hd.ExtendedKey, threshold int, timeout time.Duration, timeoutKey *hd.ExtendedKey) (addr btcutil.Address, redeemScript []byte, err error) {
+	if uint32(timeout.Hours()) > 0 && timeoutKey == nil {
+		return nil, nil, errors.New("Timeout key must be non nil when using an escrow timeout")
+	}
+
+	if len(keys) < threshold {
+		return nil, nil, fmt.Errorf("unable to generate multisig script with "+
+			"%d required signatures when there are only %d public "+
+			"keys available", threshold, len(keys))
+	}
+
+	var ecKeys []*btcec.PublicKey
+	for _, key := range keys {
+		ecKey, err := key.ECPubKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		ecKeys = append(ecKeys, ecKey)
+	}
+
+	builder := txscript.NewScriptBuilder()
+	if uint32(timeout.Hours()) == 0 || !w.Coin.Features().Timelocks {
+
+		builder.AddInt64(int64(threshold))
+		for _, key := range ecKeys {
+			builder.AddData(key.SerializeCompressed())
+		}
+		builder.AddInt64(int64(len(ecKeys)))
+		builder.AddOp(txscript.OP_CHECKMULTISIG)
+
+	} else {
+		ecKey, err := timeoutKey.ECPubKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		sequenceLock := blockchain.LockTimeToSequence(false, uint32(timeout.Hours()*6))
+		builder.AddOp(txscript.OP_IF)
+		builder.AddInt64(int64(threshold))
+		for _, key := range ecKeys {
+			builder.AddData(key.SerializeCompressed())
+		}
+		builder.AddInt64(int64(len(ecKeys)))
+		builder.AddOp(txscript.OP_CHECKMULTISIG)
+		builder.AddOp(txscript.OP_ELSE).
+			AddInt64(int64(sequenceLock)).
+			AddOp(txscript.OP_CHECKSEQUENCEVERIFY).
+			AddOp(txscript.OP_DROP).
+			AddData(ecKey.SerializeCompressed()).
+			AddOp(txscript.OP_CHECKSIG).
+			AddOp(txscript.OP_ENDIF)
+	}
+	redeemScript, err = builder.Script()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	addr, err = w.Coin.ScriptHashAddress(redeemScript, w.ChainParams)
+	if err != nil {
+		return nil, nil, err
+	}
+	return addr, redeemScript, nil
+}
+
+// EstimateSpendFee returns the fee of a transaction paying amount at feeLevel from the coins the
+// configured coin selection would choose
+func (w *Wallet) EstimateSpendFee(amount big.Int, feeLevel wi.FeeLevel) (big.Int, error) {
+	// Since this is an estimate we can use a dummy output address. Let's use a long one so we don't under estimate.
+	addr, err := w.Coin.DecodeAddress(w.Coin.Features().FeeEstimateAddress, &chaincfg.MainNetParams)
+	if err != nil {
+		return big.Int{}, err
+	}
+	tx, err := w.BuildTx(amount.Int64(), addr, feeLevel, nil)
+	if err != nil {
+		return big.Int{}, err
+	}
+	var outval int64
+	for _, output := range tx.TxOut {
+		outval += output.Value
+	}
+	var inval int64
+	utxos, err := w.DB.Utxos().GetAll()
+	if err != nil {
+		return big.Int{}, err
+	}
+	for _, input := range tx.TxIn {
+		for _, utxo := range utxos {
+			if util.OutPointsEqual(utxo.Op, input.PreviousOutPoint) {
+				val, _ := strconv.ParseInt(utxo.Value, 10, 64)
+				inval += val
+				break
+			}
+		}
+	}
+	if inval < outval {
+		return big.Int{}, errors.New("Error building transaction: inputs less than outputs")
+	}
+	return *big.NewInt(inval - outval), nil
+}
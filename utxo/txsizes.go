@@ -1,4 +1,4 @@
-package bitcoin
+package utxo
 
 // Copyright (c) 2016 The btcsuite developers
 // Use of this source code is governed by an ISC
@@ -22,6 +22,16 @@ const (
 	//   - 33 bytes serialized compressed pubkey
 	RedeemP2PKHSigScriptSize = 1 + 73 + 1 + 33
 
+	// RedeemP2PKHSchnorrSigScriptSize is the serialize size of a transaction
+	// input script that redeems a compressed P2PKH output with a Schnorr signature.
+	// It is calculated as:
+	//
+	//   - OP_DATA_65
+	//   - 64 bytes Schnorr signature + 1 byte sighash
+	//   - OP_DATA_33
+	//   - 33 bytes serialized compressed pubkey
+	RedeemP2PKHSchnorrSigScriptSize = 1 + 65 + 1 + 33
+
 	// RedeemP2SHMultisigSigScriptSize is the worst case (largest) serialize size
 	// of a transaction input script that redeems a 2 of 3 P2SH multisig output with compressed keys.
 	// It is calculated as:
@@ -143,6 +153,17 @@ const (
 	//   - 4 bytes sequence
 	RedeemP2PKHInputSize = 32 + 4 + 1 + RedeemP2PKHSigScriptSize + 4
 
+	// RedeemP2PKHSchnorrInputSize is the serialize size of a transaction input
+	// redeeming a compressed P2PKH output with a Schnorr signature.  It is
+	// calculated as:
+	//
+	//   - 32 bytes previous tx
+	//   - 4 bytes output index
+	//   - 1 byte script len
+	//   - signature script
+	//   - 4 bytes sequence
+	RedeemP2PKHSchnorrInputSize = 32 + 4 + 1 + RedeemP2PKHSchnorrSigScriptSize + 4
+
 	// RedeemP2SH2of3MultisigInputSize is the worst case (largest) serialize size of a
 	// transaction input redeeming a compressed P2SH 2 of 3 multisig output.  It is
 	// calculated as:
@@ -204,6 +225,7 @@ const (
 	P2SH_2of3_Multisig
 	P2SH_Multisig_Timelock_1Sig
 	P2SH_Multisig_Timelock_2Sigs
+	P2PKH_Schnorr
 )
 
 // EstimateSerializeSize returns a worst case serialize size estimate for a
@@ -218,26 +240,30 @@ func EstimateSerializeSize(inputCount int, txOuts []*wire.TxOut, addChangeOutput
 		outputCount++
 	}
 
-	var redeemScriptSize int
+	// 10 additional bytes are for version, locktime, and segwit flags
+	return 10 + wire.VarIntSerializeSize(uint64(inputCount)) +
+		wire.VarIntSerializeSize(uint64(outputCount)) +
+		inputCount*InputSize(inputType) +
+		SumOutputSerializeSizes(txOuts) +
+		changeSize
+}
+
+// InputSize returns the worst case serialize size of an input of inputType
+func InputSize(inputType InputType) int {
 	switch inputType {
-	case P2PKH:
-		redeemScriptSize = RedeemP2PKHInputSize
 	case P2SH_1of2_Multisig:
-		redeemScriptSize = RedeemP2SH1of2MultisigInputSize
+		return RedeemP2SH1of2MultisigInputSize
 	case P2SH_2of3_Multisig:
-		redeemScriptSize = RedeemP2SH2of3MultisigInputSize
+		return RedeemP2SH2of3MultisigInputSize
 	case P2SH_Multisig_Timelock_1Sig:
-		redeemScriptSize = RedeemP2SHMultisigTimelock1InputSize
+		return RedeemP2SHMultisigTimelock1InputSize
 	case P2SH_Multisig_Timelock_2Sigs:
-		redeemScriptSize = RedeemP2SHMultisigTimelock2InputSize
+		return RedeemP2SHMultisigTimelock2InputSize
+	case P2PKH_Schnorr:
+		return RedeemP2PKHSchnorrInputSize
+	default:
+		return RedeemP2PKHInputSize
 	}
-
-	// 10 additional bytes are for version, locktime, and segwit flags
-	return 10 + wire.VarIntSerializeSize(uint64(inputCount)) +
-		wire.VarIntSerializeSize(uint64(outputCount)) +
-		inputCount*redeemScriptSize +
-		SumOutputSerializeSizes(txOuts) +
-		changeSize
 }
 
 // SumOutputSerializeSizes sums up the serialized size of the supplied outputs.
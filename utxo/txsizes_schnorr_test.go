@@ -0,0 +1,48 @@
+package utxo
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestEstimateSerializeSizeSchnorr(t *testing.T) {
+	tests := []struct {
+		InputCount           int
+		OutputScriptLengths  []int
+		AddChangeOutput      bool
+		ExpectedSizeEstimate int
+	}{
+		0: {1, []int{}, false, 153},
+		1: {1, []int{p2pkhScriptSize}, false, 187},
+		2: {1, []int{}, true, 187},
+		3: {1, []int{p2pkhScriptSize}, true, 221},
+		4: {1, []int{p2shScriptSize}, false, 185},
+		5: {1, []int{p2shScriptSize}, true, 219},
+
+		6:  {2, []int{}, false, 294},
+		7:  {2, []int{p2pkhScriptSize}, false, 328},
+		8:  {2, []int{}, true, 328},
+		9:  {2, []int{p2pkhScriptSize}, true, 362},
+		10: {2, []int{p2shScriptSize}, false, 326},
+		11: {2, []int{p2shScriptSize}, true, 360},
+
+		// 0xfd is discriminant for 16-bit compact ints, compact int
+		// total size increases from 1 byte to 3.
+		12: {1, makeInts(p2pkhScriptSize, 0xfc), false, 8721},
+		13: {1, makeInts(p2pkhScriptSize, 0xfd), false, 8721 + P2PKHOutputSize + 2},
+		14: {1, makeInts(p2pkhScriptSize, 0xfc), true, 8721 + P2PKHOutputSize + 2},
+		15: {0xfc, []int{}, false, 35544},
+		16: {0xfd, []int{}, false, 35544 + RedeemP2PKHSchnorrInputSize + 2},
+	}
+	for i, test := range tests {
+		outputs := make([]*wire.TxOut, 0, len(test.OutputScriptLengths))
+		for _, l := range test.OutputScriptLengths {
+			outputs = append(outputs, &wire.TxOut{PkScript: make([]byte, l)})
+		}
+		actualEstimate := EstimateSerializeSize(test.InputCount, outputs, test.AddChangeOutput, P2PKH_Schnorr)
+		if actualEstimate != test.ExpectedSizeEstimate {
+			t.Errorf("Test %d: Got %v: Expected %v", i, actualEstimate, test.ExpectedSizeEstimate)
+		}
+	}
+}
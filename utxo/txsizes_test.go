@@ -1,4 +1,4 @@
-package zcash
+package utxo
 
 // Copyright (c) 2016 The btcsuite developers
 // Use of this source code is governed by an ISC
@@ -0,0 +1,689 @@
+package utxo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/multiwallet/cache"
+	"github.com/OpenBazaar/multiwallet/client"
+	"github.com/OpenBazaar/multiwallet/client/blockbook"
+	clientErr "github.com/OpenBazaar/multiwallet/client/errors"
+	"github.com/OpenBazaar/multiwallet/config"
+	"github.com/OpenBazaar/multiwallet/keys"
+	"github.com/OpenBazaar/multiwallet/model"
+	"github.com/OpenBazaar/multiwallet/service"
+	"github.com/OpenBazaar/multiwallet/util"
+	wi "github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/op/go-logging"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/net/proxy"
+)
+
+// Base is the state every UTXO coin wallet is built from
+type Base struct {
+	DB     wi.Datastore
+	KM     *keys.KeyManager
+	Client *client.ClientPool
+	WS     *service.WalletService
+
+	MPrivKey *hd.ExtendedKey
+	MPubKey  *hd.ExtendedKey
+
+	CoinSelection util.CoinSelectionStrategy
+	Consolidation util.ConsolidationPolicy
+}
+
+// NewBase derives the wallet's keys from mnemonic and connects the key manager, API clients and
+// wallet service of the coin described by cfg
+func NewBase(cfg config.CoinConfig, mnemonic string, params *chaincfg.Params, proxy proxy.Dialer, cache cache.Cacher, coinType wi.CoinType, getAddr keys.AddrFunc) (*Base, error) {
+	seed := bip39.NewSeed(mnemonic, "")
+	coinSelection, err := util.ParseCoinSelectionStrategy(cfg.CoinSelection)
+	if err != nil {
+		return nil, err
+	}
+
+	mPrivKey, err := hd.NewMaster(seed, params)
+	if err != nil {
+		return nil, err
+	}
+	mPubKey, err := mPrivKey.Neuter()
+	if err != nil {
+		return nil, err
+	}
+	km, err := keys.NewKeyManager(cfg.DB.Keys(), params, mPrivKey, coinType, getAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.NewClientPool(cfg.ClientAPIs, proxy)
+	if err != nil {
+		return nil, err
+	}
+	c.SetRequestLimits(blockbook.RequestLimits{
+		MaxConcurrentRequests: cfg.MaxConcurrentRequests,
+		RequestsPerSecond:     cfg.RequestsPerSecond,
+		RequestBurst:          cfg.RequestBurst,
+	})
+	c.SetBroadcastFanout(cfg.BroadcastToAllEndpoints)
+
+	wm, err := service.NewWalletService(cfg.DB, km, c, params, coinType, cache)
+	if err != nil {
+		return nil, err
+	}
+	wm.SetAbandonAfter(cfg.AbandonAfterBlocks)
+
+	return &Base{
+		DB:            cfg.DB,
+		KM:            km,
+		Client:        c,
+		WS:            wm,
+		MPrivKey:      mPrivKey,
+		MPubKey:       mPubKey,
+		CoinSelection: coinSelection,
+		Consolidation: util.ConsolidationPolicy{
+			MaxFeePerByte: int64(cfg.ConsolidateBelowFee),
+			MaxUtxoValue:  int64(cfg.ConsolidateMaxUtxoValue),
+			MinUtxos:      cfg.ConsolidateMinUtxos,
+			MaxUtxos:      cfg.ConsolidateMaxUtxos,
+		},
+	}, nil
+}
+
+// Wallet implements the wallet-interface methods which work the same way for every UTXO coin,
+// leaving address encoding, signing and serialization to Coin. Coin wallets embed it so its
+// methods are their own.
+type Wallet struct {
+	Coin        Coin
+	ChainParams *chaincfg.Params
+	DB          wi.Datastore
+	KM          *keys.KeyManager
+	Client      model.APIClient
+	WS          *service.WalletService
+	Fees        FeeProvider
+	Rates       wi.ExchangeRates
+	Log         *logging.Logger
+
+	MPrivKey *hd.ExtendedKey
+	MPubKey  *hd.ExtendedKey
+
+	CoinSelection util.CoinSelectionStrategy
+
+	// Consolidations are serialized by consolidateLock so two cannot spend the same utxos.
+	// pendingConsolidations holds the outpoints spent by each consolidation which has not
+	// confirmed yet. Until the servers see a consolidation its utxos may still be listed as
+	// spendable, so they are left out of new plans.
+	Consolidation         util.ConsolidationPolicy
+	consolidateLock       sync.Mutex
+	pendingConsolidations map[chainhash.Hash][]wire.OutPoint
+}
+
+// NewWallet returns the wallet of coin built from b, paying the fees of fees. Exchange rates may
+// be nil. Small utxos are consolidated as blocks arrive if b's consolidation policy allows it.
+func NewWallet(b *Base, coin Coin, params *chaincfg.Params, fees FeeProvider, exchangeRates wi.ExchangeRates, log *logging.Logger) *Wallet {
+	w := &Wallet{
+		Coin:          coin,
+		ChainParams:   params,
+		DB:            b.DB,
+		KM:            b.KM,
+		Client:        b.Client,
+		WS:            b.WS,
+		Fees:          fees,
+		Rates:         exchangeRates,
+		Log:           log,
+		MPrivKey:      b.MPrivKey,
+		MPubKey:       b.MPubKey,
+		CoinSelection: b.CoinSelection,
+		Consolidation: b.Consolidation,
+	}
+	if b.Consolidation.MaxFeePerByte > 0 {
+		b.WS.AddBlockListener(w.AutoConsolidate)
+	}
+	return w
+}
+
+func (w *Wallet) Start() {
+	w.Client.Start()
+	w.WS.Start()
+}
+
+func (w *Wallet) Close() {
+	w.WS.Stop()
+	w.Client.Close()
+}
+
+func (w *Wallet) Params() *chaincfg.Params {
+	return w.ChainParams
+}
+
+func (w *Wallet) MasterPrivateKey() *hd.ExtendedKey {
+	return w.MPrivKey
+}
+
+func (w *Wallet) MasterPublicKey() *hd.ExtendedKey {
+	return w.MPubKey
+}
+
+func (w *Wallet) ChildKey(keyBytes []byte, chaincode []byte, isPrivateKey bool) (*hd.ExtendedKey, error) {
+	parentFP := []byte{0x00, 0x00, 0x00, 0x00}
+	var id []byte
+	if isPrivateKey {
+		id = w.ChainParams.HDPrivateKeyID[:]
+	} else {
+		id = w.ChainParams.HDPublicKeyID[:]
+	}
+	hdKey := hd.NewExtendedKey(
+		id,
+		keyBytes,
+		chaincode,
+		parentFP,
+		0,
+		0,
+		isPrivateKey)
+	return hdKey.Child(0)
+}
+
+func (w *Wallet) CurrentAddress(purpose wi.KeyPurpose) btcutil.Address {
+	key, err := w.KM.GetCurrentKey(purpose)
+	if err != nil {
+		w.Log.Errorf("Error generating current key: %s", err)
+	}
+	addr, err := w.KM.KeyToAddress(key)
+	if err != nil {
+		w.Log.Errorf("Error converting key to address: %s", err)
+	}
+	return addr
+}
+
+func (w *Wallet) NewAddress(purpose wi.KeyPurpose) btcutil.Address {
+	key, err := w.KM.GetNextUnused(purpose)
+	if err != nil {
+		w.Log.Errorf("Error generating next unused key: %s", err)
+	}
+	addr, err := w.KM.KeyToAddress(key)
+	if err != nil {
+		w.Log.Errorf("Error converting key to address: %s", err)
+	}
+	if err := w.DB.Keys().MarkKeyAsUsed(addr.ScriptAddress()); err != nil {
+		w.Log.Errorf("Error marking key as used: %s", err)
+	}
+	return addr
+}
+
+func (w *Wallet) DecodeAddress(addr string) (btcutil.Address, error) {
+	decoded, err := w.Coin.DecodeAddress(addr, w.ChainParams)
+	if err != nil {
+		return nil, clientErr.Classify(clientErr.KindInvalidAddress, err)
+	}
+	return decoded, nil
+}
+
+func (w *Wallet) ScriptToAddress(script []byte) (btcutil.Address, error) {
+	return w.Coin.ExtractAddress(script, w.ChainParams)
+}
+
+func (w *Wallet) AddressToScript(addr btcutil.Address) ([]byte, error) {
+	return w.Coin.PayToAddrScript(addr)
+}
+
+func (w *Wallet) HasKey(addr btcutil.Address) bool {
+	_, err := w.KM.GetKeyForScript(addr.ScriptAddress())
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// IsDust reports whether an output of amount would be too small to relay
+func (w *Wallet) IsDust(amount big.Int) bool {
+	if !amount.IsInt64() || amount.Cmp(big.NewInt(0)) <= 0 {
+		return false
+	}
+	return w.isDust(amount.Int64(), 25)
+}
+
+// isDust reports whether an output of amount paying to a script of scriptSize bytes is too small
+// to relay
+func (w *Wallet) isDust(amount int64, scriptSize int) bool {
+	return w.Coin.Features().IsDust(btcutil.Amount(amount), scriptSize)
+}
+
+func (w *Wallet) Balance() (wi.CurrencyValue, wi.CurrencyValue) {
+	utxos, _ := w.DB.Utxos().GetAll()
+	txns, _ := w.DB.Txns().GetAll(false)
+	for i, tx := range txns {
+		txns[i].Bytes = w.Coin.ToWire(tx.Bytes)
+	}
+	c, u := util.CalcBalance(utxos, txns)
+	currency := w.Coin.Features().Currency
+	return wi.CurrencyValue{Value: *big.NewInt(c), Currency: currency},
+		wi.CurrencyValue{Value: *big.NewInt(u), Currency: currency}
+}
+
+func (w *Wallet) Transactions() ([]wi.Txn, error) {
+	height, _ := w.ChainTip()
+	safeConfirmations := w.Coin.Features().SafeConfirmations
+	txns, err := w.DB.Txns().GetAll(false)
+	if err != nil {
+		return txns, err
+	}
+	for i, tx := range txns {
+		var confirmations int32
+		var status wi.StatusCode
+		confs := int32(height) - tx.Height + 1
+		if tx.Height <= 0 {
+			confs = tx.Height
+		}
+		switch {
+		case confs < 0:
+			status = wi.StatusDead
+		case confs == 0 && time.Since(tx.Timestamp) <= time.Hour*6:
+			status = wi.StatusUnconfirmed
+		case confs == 0 && time.Since(tx.Timestamp) > time.Hour*6:
+			status = wi.StatusDead
+		case confs > 0 && confs < safeConfirmations:
+			status = wi.StatusPending
+			confirmations = confs
+		case confs >= safeConfirmations:
+			status = wi.StatusConfirmed
+			confirmations = confs
+		}
+		tx.Confirmations = int64(confirmations)
+		tx.Status = status
+		txns[i] = tx
+	}
+	return txns, nil
+}
+
+func (w *Wallet) GetTransaction(txid chainhash.Hash) (wi.Txn, error) {
+	txn, err := w.DB.Txns().Get(txid)
+	if err == nil {
+		tx := wire.NewMsgTx(1)
+		rbuf := bytes.NewReader(w.Coin.ToWire(txn.Bytes))
+		err := tx.BtcDecode(rbuf, wire.ProtocolVersion, wire.WitnessEncoding)
+		if err != nil {
+			return txn, err
+		}
+		outs := []wi.TransactionOutput{}
+		for i, out := range tx.TxOut {
+			addr, err := w.ScriptToAddress(out.PkScript)
+			if err != nil {
+				w.Log.Errorf("error extracting address from txn pkscript: %v\n", err)
+			}
+			tout := wi.TransactionOutput{
+				Address: addr,
+				Value:   *big.NewInt(out.Value),
+				Index:   uint32(i),
+			}
+			outs = append(outs, tout)
+		}
+		txn.Outputs = outs
+	}
+	return txn, err
+}
+
+func (w *Wallet) ChainTip() (uint32, chainhash.Hash) {
+	return w.WS.ChainTip()
+}
+
+// GetFeePerByte returns the fee per byte to pay at feeLevel, never less than the coin's minimum
+func (w *Wallet) GetFeePerByte(feeLevel wi.FeeLevel) big.Int {
+	fee := w.Fees.GetFeePerByte(feeLevel)
+	if floor := w.Coin.Features().MinFeePerByte; fee < floor {
+		fee = floor
+	}
+	return *big.NewInt(int64(fee))
+}
+
+func (w *Wallet) Spend(amount big.Int, addr btcutil.Address, feeLevel wi.FeeLevel, referenceID string, spendAll bool) (*chainhash.Hash, error) {
+	var (
+		tx  *wire.MsgTx
+		err error
+	)
+	if spendAll {
+		tx, err = w.BuildSpendAllTx(addr, feeLevel)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		tx, err = w.BuildTx(amount.Int64(), addr, feeLevel, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return w.Broadcast(tx)
+}
+
+// SpendMany pays every output in a single transaction. The coins spent are chosen by strategy, or
+// by the configured coin selection if it is empty.
+func (w *Wallet) SpendMany(outputs []wi.TransactionOutput, feeLevel wi.FeeLevel, referenceID string, strategy util.CoinSelectionStrategy) (*chainhash.Hash, error) {
+	tx, err := w.BuildSpendManyTx(outputs, feeLevel, strategy)
+	if err != nil {
+		return nil, err
+	}
+	return w.Broadcast(tx)
+}
+
+// SpendOutpoints pays the outputs by spending exactly the given outpoints, bypassing coin
+// selection. Anything left over after the fee is returned as change.
+func (w *Wallet) SpendOutpoints(outpoints []wire.OutPoint, outputs []wi.TransactionOutput, feeLevel wi.FeeLevel, referenceID string) (*chainhash.Hash, error) {
+	tx, err := w.BuildTxFromOutpoints(outpoints, outputs, feeLevel)
+	if err != nil {
+		return nil, err
+	}
+	return w.Broadcast(tx)
+}
+
+// FreezeUtxo stops op from being spent until it is unfrozen
+func (w *Wallet) FreezeUtxo(op wire.OutPoint) error {
+	return w.WS.FreezeUtxo(op)
+}
+
+// UnfreezeUtxo makes a frozen output spendable again
+func (w *Wallet) UnfreezeUtxo(op wire.OutPoint) error {
+	return w.WS.UnfreezeUtxo(op)
+}
+
+// ListUtxos returns every unspent output of the wallet and whether it is frozen
+func (w *Wallet) ListUtxos() ([]service.UtxoStatus, error) {
+	return w.WS.UtxoStatuses()
+}
+
+// ValueAtReceipt returns what txn was worth in each currency when the wallet first saw it, or nil
+// if no exchange rates were recorded then
+func (w *Wallet) ValueAtReceipt(txn wi.Txn) (map[string]float64, error) {
+	return w.WS.ValueAtReceipt(txn)
+}
+
+// Consolidate merges the wallet's small utxos into one output paying an internal address. With
+// dryRun the plan and its estimated savings are returned without anything being broadcast.
+func (w *Wallet) Consolidate(dryRun bool) (*util.ConsolidationPlan, error) {
+	w.consolidateLock.Lock()
+	defer w.consolidateLock.Unlock()
+	plan, err := w.consolidationPlan()
+	if err != nil || dryRun {
+		return plan, err
+	}
+	return plan, w.consolidate(plan)
+}
+
+// AutoConsolidate consolidates small utxos when fees are low enough for it to save money later.
+// It is registered as a block listener of the wallet service and waits for the last
+// consolidation to confirm before starting another.
+func (w *Wallet) AutoConsolidate(height uint32) {
+	w.consolidateLock.Lock()
+	defer w.consolidateLock.Unlock()
+	if len(w.pendingOutpoints()) > 0 {
+		return
+	}
+	plan, err := w.consolidationPlan()
+	if err == util.ErrNothingToConsolidate {
+		return
+	}
+	if err != nil {
+		w.Log.Errorf("planning consolidation at height %d: %s", height, err.Error())
+		return
+	}
+	if !plan.Worthwhile() {
+		return
+	}
+	if err := w.consolidate(plan); err != nil {
+		w.Log.Errorf("consolidating %d utxos: %s", len(plan.Utxos), err.Error())
+		return
+	}
+	w.Log.Noticef("consolidated %d utxos in %s at %d per byte saving an estimated %d", len(plan.Utxos), plan.Txid, plan.FeePerByte, plan.Savings)
+}
+
+// consolidate signs and broadcasts the consolidation described by plan. The caller must hold
+// w.consolidateLock.
+func (w *Wallet) consolidate(plan *util.ConsolidationPlan) error {
+	tx, err := w.BuildSweepTx(plan.Utxos, w.CurrentAddress(wi.INTERNAL), wi.ECONOMIC)
+	if err != nil {
+		return err
+	}
+	txid, err := w.Broadcast(tx)
+	if err != nil {
+		return err
+	}
+	if w.pendingConsolidations == nil {
+		w.pendingConsolidations = make(map[chainhash.Hash][]wire.OutPoint)
+	}
+	for _, in := range tx.TxIn {
+		w.pendingConsolidations[*txid] = append(w.pendingConsolidations[*txid], in.PreviousOutPoint)
+	}
+	plan.Txid = txid.String()
+	return nil
+}
+
+// pendingOutpoints returns the outpoints spent by consolidations which have not confirmed,
+// forgetting those which have confirmed or died. The caller must hold w.consolidateLock.
+func (w *Wallet) pendingOutpoints() map[wire.OutPoint]bool {
+	pending := make(map[wire.OutPoint]bool)
+	for txid, outpoints := range w.pendingConsolidations {
+		txn, err := w.DB.Txns().Get(txid)
+		if err != nil || txn.Height != 0 {
+			delete(w.pendingConsolidations, txid)
+			continue
+		}
+		for _, op := range outpoints {
+			pending[op] = true
+		}
+	}
+	return pending
+}
+
+func (w *Wallet) EstimateFee(ins []wi.TransactionInput, outs []wi.TransactionOutput, feePerByte big.Int) big.Int {
+	tx := new(wire.MsgTx)
+	for _, out := range outs {
+		scriptPubKey, _ := w.AddressToScript(out.Address)
+		output := wire.NewTxOut(out.Value.Int64(), scriptPubKey)
+		tx.TxOut = append(tx.TxOut, output)
+	}
+	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, w.Coin.Features().P2PKHInput)
+	fee := estimatedSize * int(feePerByte.Int64())
+	return *big.NewInt(int64(fee))
+}
+
+func (w *Wallet) AddWatchedAddresses(addrs ...btcutil.Address) error {
+	var watchedScripts [][]byte
+	for _, addr := range addrs {
+		if !w.HasKey(addr) {
+			script, err := w.AddressToScript(addr)
+			if err != nil {
+				return err
+			}
+			watchedScripts = append(watchedScripts, script)
+		}
+	}
+
+	err := w.DB.WatchedScripts().PutAll(watchedScripts)
+	if err != nil {
+		return err
+	}
+
+	w.Client.ListenAddresses(addrs...)
+	return nil
+}
+
+func (w *Wallet) AddWatchedScript(script []byte) error {
+	err := w.DB.WatchedScripts().Put(script)
+	if err != nil {
+		return err
+	}
+	addr, err := w.ScriptToAddress(script)
+	if err != nil {
+		return err
+	}
+	w.Client.ListenAddresses(addr)
+	return nil
+}
+
+func (w *Wallet) AddTransactionListener(callback func(wi.TransactionCallback)) {
+	w.WS.AddTransactionListener(callback)
+}
+
+func (w *Wallet) ReSyncBlockchain(fromTime time.Time) {
+	go w.WS.UpdateState()
+}
+
+func (w *Wallet) GetConfirmations(txid chainhash.Hash) (uint32, uint32, error) {
+	txn, err := w.DB.Txns().Get(txid)
+	if err != nil {
+		return 0, 0, err
+	}
+	if txn.Height == 0 {
+		return 0, 0, nil
+	}
+	chainTip, _ := w.ChainTip()
+	return chainTip - uint32(txn.Height) + 1, uint32(txn.Height), nil
+}
+
+func (w *Wallet) ExchangeRates() wi.ExchangeRates {
+	return w.Rates
+}
+
+// AbandonTransaction gives up on an unconfirmed transaction which is not in any server's mempool
+// so the coins it spent can be spent again
+func (w *Wallet) AbandonTransaction(txid chainhash.Hash) error {
+	return w.WS.AbandonTransaction(txid.String())
+}
+
+// AssociateTransactionWithOrder used for ORDER_PAYMENT message
+func (w *Wallet) AssociateTransactionWithOrder(cb wi.TransactionCallback) {
+	w.WS.InvokeTransactionListeners(cb)
+}
+
+// EndpointHealth reports the observed health of each of the wallet's API endpoints
+func (w *Wallet) EndpointHealth() []client.EndpointHealth {
+	if pool, ok := w.Client.(*client.ClientPool); ok {
+		return pool.EndpointHealth()
+	}
+	return nil
+}
+
+func (w *Wallet) DumpTables(wr io.Writer) {
+	fmt.Fprintln(wr, "Transactions-----")
+	txns, _ := w.DB.Txns().GetAll(true)
+	for _, tx := range txns {
+		fmt.Fprintf(wr, "Hash: %s, Height: %d, Value: %s, WatchOnly: %t\n", tx.Txid, int(tx.Height), tx.Value, tx.WatchOnly)
+	}
+	fmt.Fprintln(wr, "\nUtxos-----")
+	utxos, _ := w.DB.Utxos().GetAll()
+	for _, u := range utxos {
+		fmt.Fprintf(wr, "Hash: %s, Index: %d, Height: %d, Value: %s, WatchOnly: %t\n", u.Op.Hash.String(), int(u.Op.Index), int(u.AtHeight), u.Value, u.WatchOnly)
+	}
+	fmt.Fprintln(wr, "\nKeys-----")
+	keys, _ := w.DB.Keys().GetAll()
+	unusedInternal, _ := w.DB.Keys().GetUnused(wi.INTERNAL)
+	unusedExternal, _ := w.DB.Keys().GetUnused(wi.EXTERNAL)
+	internalMap := make(map[int]bool)
+	externalMap := make(map[int]bool)
+	for _, k := range unusedInternal {
+		internalMap[k] = true
+	}
+	for _, k := range unusedExternal {
+		externalMap[k] = true
+	}
+
+	for _, k := range keys {
+		var used bool
+		if k.Purpose == wi.INTERNAL {
+			used = internalMap[k.Index]
+		} else {
+			used = externalMap[k.Index]
+		}
+		fmt.Fprintf(wr, "KeyIndex: %d, Purpose: %d, Used: %t\n", k.Index, k.Purpose, used)
+	}
+}
+
+// Broadcast ingests tx into the wallet service as a client.Transaction and broadcasts it,
+// returning its transaction ID
+func (w *Wallet) Broadcast(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	txBytes, txid, err := w.Coin.Serialize(tx)
+	if err != nil {
+		return nil, err
+	}
+	coinType := w.Coin.Features().CoinType
+	cTxn := model.Transaction{
+		Txid:          txid.String(),
+		Locktime:      int(tx.LockTime),
+		Version:       int(tx.Version),
+		Confirmations: 0,
+		Time:          time.Now().Unix(),
+		RawBytes:      txBytes,
+	}
+	utxos, err := w.DB.Utxos().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for n, in := range tx.TxIn {
+		var (
+			u     wi.Utxo
+			found bool
+		)
+		for _, ut := range utxos {
+			if util.OutPointsEqual(ut.Op, in.PreviousOutPoint) {
+				u, found = ut, true
+				break
+			}
+		}
+		if !found {
+			// The inputs of a replacement were already spent by the transaction it replaces
+			prevOut, err := w.previousOutput(in.PreviousOutPoint)
+			if err != nil {
+				return nil, err
+			}
+			u.ScriptPubkey, u.Value = prevOut.PkScript, strconv.FormatInt(prevOut.Value, 10)
+		}
+		addr, err := w.ScriptToAddress(u.ScriptPubkey)
+		if err != nil {
+			return nil, err
+		}
+		val, _ := strconv.ParseInt(u.Value, 10, 64)
+		input := model.Input{
+			Txid: in.PreviousOutPoint.Hash.String(),
+			Vout: int(in.PreviousOutPoint.Index),
+			ScriptSig: model.Script{
+				Hex: hex.EncodeToString(in.SignatureScript),
+			},
+			Sequence: uint32(in.Sequence),
+			N:        n,
+			Addr:     addr.String(),
+			Satoshis: val,
+			Value:    float64(val) / util.SatoshisPerCoin(coinType),
+		}
+		cTxn.Inputs = append(cTxn.Inputs, input)
+	}
+	for n, out := range tx.TxOut {
+		addr, err := w.ScriptToAddress(out.PkScript)
+		if err != nil {
+			return nil, err
+		}
+		output := model.Output{
+			N: n,
+			ScriptPubKey: model.OutScript{
+				Script: model.Script{
+					Hex: hex.EncodeToString(out.PkScript),
+				},
+				Addresses: []string{addr.String()},
+			},
+			Value: float64(float64(out.Value) / util.SatoshisPerCoin(coinType)),
+		}
+		cTxn.Outputs = append(cTxn.Outputs, output)
+	}
+	if err := w.WS.BroadcastTransaction(cTxn.Txid, txBytes); err != nil {
+		return nil, err
+	}
+	w.WS.ProcessIncomingTransaction(cTxn)
+	return &txid, nil
+}
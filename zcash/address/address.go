@@ -106,6 +106,10 @@ func DecodeAddress(addr string, defaultNet *chaincfg.Params) (btcutil.Address, e
 		return nil, errors.New("unknown network parameters")
 	}
 
+	if isUnifiedAddress(addr, defaultNet) {
+		return decodeUnifiedAddress(addr, defaultNet)
+	}
+
 	// Switch on decoded length to determine the type.
 	decoded, netID, err := CheckDecode(addr)
 	if err != nil {
@@ -297,6 +301,15 @@ func PayToAddrScript(addr btcutil.Address) ([]byte, error) {
 			return nil, errors.New(nilAddrErrStr)
 		}
 		return payToScriptHashScript(addr.ScriptAddress())
+
+	case *UnifiedAddress:
+		if addr == nil {
+			return nil, errors.New(nilAddrErrStr)
+		}
+		if addr.transparent == nil {
+			return nil, ErrNoTransparentReceiver
+		}
+		return PayToAddrScript(addr.transparent)
 	}
 	return nil, fmt.Errorf("unable to generate payment script for unsupported "+
 		"address type %T", addr)
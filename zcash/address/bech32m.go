@@ -0,0 +1,90 @@
+package address
+
+import (
+	"errors"
+	"strings"
+)
+
+// Unified addresses are encoded with Bech32m (BIP 350) without the 90 character limit of Bech32
+// since they may hold several receivers.
+
+const (
+	bech32mCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32mConst   = 0x2bc830a3
+)
+
+// ErrInvalidBech32m describes a string which is not valid Bech32m
+var ErrInvalidBech32m = errors.New("invalid bech32m encoding")
+
+func bech32mPolymod(values []byte) uint32 {
+	gen := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32mHRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// encodeBech32m encodes 5 bit groups of data under hrp
+func encodeBech32m(hrp string, data []byte) string {
+	values := append(bech32mHRPExpand(hrp), data...)
+	polymod := bech32mPolymod(append(values, 0, 0, 0, 0, 0, 0)) ^ bech32mConst
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32mCharset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32mCharset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// decodeBech32m returns the human readable part and the 5 bit groups of data of s
+func decodeBech32m(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, ErrInvalidBech32m
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, ErrInvalidBech32m
+	}
+	hrp := s[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, ErrInvalidBech32m
+		}
+	}
+	data := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		d := strings.IndexByte(bech32mCharset, s[i])
+		if d < 0 {
+			return "", nil, ErrInvalidBech32m
+		}
+		data = append(data, byte(d))
+	}
+	if bech32mPolymod(append(bech32mHRPExpand(hrp), data...)) != bech32mConst {
+		return "", nil, ErrChecksumMismatch
+	}
+	return hrp, data[:len(data)-6], nil
+}
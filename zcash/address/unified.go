@@ -0,0 +1,278 @@
+package address
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/bech32"
+	"github.com/minio/blake2b-simd"
+)
+
+// ReceiverType is the typecode of a receiver within a unified address (ZIP-316)
+type ReceiverType uint64
+
+const (
+	P2PKHReceiver   ReceiverType = 0x00
+	P2SHReceiver    ReceiverType = 0x01
+	SaplingReceiver ReceiverType = 0x02
+	OrchardReceiver ReceiverType = 0x03
+)
+
+const (
+	shieldedReceiverSize = 43
+	unifiedPaddingSize   = 16
+
+	f4JumbleMinLength = 48
+	f4JumbleMaxLength = 4194368
+)
+
+var (
+	// ErrNoTransparentReceiver describes a unified address which can only be paid with a shielded
+	// output, which this wallet cannot create
+	ErrNoTransparentReceiver = errors.New("unified address has no transparent receiver")
+
+	// ErrInvalidUnifiedAddress describes a unified address which does not follow ZIP-316
+	ErrInvalidUnifiedAddress = errors.New("invalid unified address")
+
+	unifiedHRPs = map[string]string{
+		chaincfg.MainNetParams.Name:       "u",
+		chaincfg.TestNet3Params.Name:      "utest",
+		chaincfg.RegressionNetParams.Name: "uregtest",
+	}
+)
+
+// Receiver is one of the ways a unified address can be paid. Data is the raw encoding of the
+// receiver, which is the hash for transparent receivers.
+type Receiver struct {
+	Type ReceiverType
+	Data []byte
+}
+
+// UnifiedAddress is a ZIP-316 unified address bundling receivers for several pools. It is paid
+// through its transparent receiver since this wallet only creates transparent outputs.
+type UnifiedAddress struct {
+	hrp         string
+	receivers   []Receiver
+	transparent btcutil.Address
+}
+
+// NewUnifiedAddress returns a new UnifiedAddress. It must hold a shielded receiver, at most one
+// receiver of each type and not both a P2PKH and a P2SH receiver.
+func NewUnifiedAddress(receivers []Receiver, net *chaincfg.Params) (*UnifiedAddress, error) {
+	hrp, ok := unifiedHRPs[net.Name]
+	if !ok {
+		return nil, errors.New("unknown network parameters")
+	}
+	addr := &UnifiedAddress{hrp: hrp}
+	var shielded bool
+	seen := make(map[ReceiverType]bool)
+	for _, r := range receivers {
+		if seen[r.Type] {
+			return nil, fmt.Errorf("%v: more than one receiver of type %d", ErrInvalidUnifiedAddress, r.Type)
+		}
+		seen[r.Type] = true
+		var err error
+		switch r.Type {
+		case P2PKHReceiver:
+			addr.transparent, err = newAddressPubKeyHash(r.Data, net)
+		case P2SHReceiver:
+			addr.transparent, err = newAddressScriptHashFromHash(r.Data, net)
+		case SaplingReceiver, OrchardReceiver:
+			if len(r.Data) != shieldedReceiverSize {
+				err = fmt.Errorf("%v: receiver of type %d must be %d bytes", ErrInvalidUnifiedAddress, r.Type, shieldedReceiverSize)
+			}
+			shielded = true
+		default:
+			// Receivers of pools added after this was written are kept so the address round trips
+			shielded = true
+		}
+		if err != nil {
+			return nil, err
+		}
+		addr.receivers = append(addr.receivers, Receiver{r.Type, append([]byte(nil), r.Data...)})
+	}
+	if seen[P2PKHReceiver] && seen[P2SHReceiver] {
+		return nil, fmt.Errorf("%v: both P2PKH and P2SH receivers", ErrInvalidUnifiedAddress)
+	}
+	if !shielded {
+		return nil, fmt.Errorf("%v: no shielded receiver", ErrInvalidUnifiedAddress)
+	}
+	sort.Slice(addr.receivers, func(i, j int) bool { return addr.receivers[i].Type < addr.receivers[j].Type })
+	return addr, nil
+}
+
+// decodeUnifiedAddress decodes the bech32m encoding of a unified address for net
+func decodeUnifiedAddress(addr string, net *chaincfg.Params) (*UnifiedAddress, error) {
+	hrp, data, err := decodeBech32m(addr)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != unifiedHRPs[net.Name] {
+		return nil, ErrUnknownAddressType
+	}
+	jumbled, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := f4JumbleInv(jumbled)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(raw[len(raw)-unifiedPaddingSize:], unifiedPadding(hrp)) {
+		return nil, fmt.Errorf("%v: wrong padding", ErrInvalidUnifiedAddress)
+	}
+
+	r := bytes.NewReader(raw[:len(raw)-unifiedPaddingSize])
+	var receivers []Receiver
+	for r.Len() > 0 {
+		typecode, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(receivers) > 0 && ReceiverType(typecode) <= receivers[len(receivers)-1].Type {
+			return nil, fmt.Errorf("%v: receivers out of order", ErrInvalidUnifiedAddress)
+		}
+		data, err := wire.ReadVarBytes(r, 0, uint32(r.Len()), "receiver")
+		if err != nil {
+			return nil, err
+		}
+		receivers = append(receivers, Receiver{ReceiverType(typecode), data})
+	}
+	return NewUnifiedAddress(receivers, net)
+}
+
+// unifiedPadding is the human readable part padded with zeros to 16 bytes
+func unifiedPadding(hrp string) []byte {
+	padding := make([]byte, unifiedPaddingSize)
+	copy(padding, hrp)
+	return padding
+}
+
+// EncodeAddress returns the bech32m string encoding of the unified address.
+// Part of the Address interface.
+func (a *UnifiedAddress) EncodeAddress() string {
+	var raw bytes.Buffer
+	for _, r := range a.receivers {
+		wire.WriteVarInt(&raw, 0, uint64(r.Type))
+		wire.WriteVarBytes(&raw, 0, r.Data)
+	}
+	raw.Write(unifiedPadding(a.hrp))
+	jumbled, err := f4Jumble(raw.Bytes())
+	if err != nil {
+		return ""
+	}
+	data, err := bech32.ConvertBits(jumbled, 8, 5, true)
+	if err != nil {
+		return ""
+	}
+	return encodeBech32m(a.hrp, data)
+}
+
+// ScriptAddress returns the hash of the transparent receiver, or nil if the
+// address has none. Part of the Address interface.
+func (a *UnifiedAddress) ScriptAddress() []byte {
+	if a.transparent == nil {
+		return nil
+	}
+	return a.transparent.ScriptAddress()
+}
+
+// IsForNet returns whether or not the unified address is associated with the
+// passed zcash network.
+func (a *UnifiedAddress) IsForNet(net *chaincfg.Params) bool {
+	return unifiedHRPs[net.Name] == a.hrp
+}
+
+// String returns a human-readable string for the unified address.
+// This is equivalent to calling EncodeAddress, but is provided so the type can
+// be used as a fmt.Stringer.
+func (a *UnifiedAddress) String() string {
+	return a.EncodeAddress()
+}
+
+// Receivers returns the receivers of the address in typecode order
+func (a *UnifiedAddress) Receivers() []Receiver {
+	return a.receivers
+}
+
+// TransparentAddress returns the P2PKH or P2SH receiver of the address, or nil
+// if it only has shielded receivers.
+func (a *UnifiedAddress) TransparentAddress() btcutil.Address {
+	return a.transparent
+}
+
+// f4Jumble is the unkeyed four round Feistel permutation ZIP-316 applies to a
+// unified address so that changing any byte changes the whole encoding.
+func f4Jumble(m []byte) ([]byte, error) {
+	if len(m) < f4JumbleMinLength || len(m) > f4JumbleMaxLength {
+		return nil, fmt.Errorf("cannot jumble %d bytes", len(m))
+	}
+	left := f4JumbleLeftLength(len(m))
+	a, b := m[:left], m[left:]
+	x := xorBytes(b, f4JumbleG(0, a, len(b)))
+	y := xorBytes(a, f4JumbleH(0, x, left))
+	d := xorBytes(x, f4JumbleG(1, y, len(b)))
+	c := xorBytes(y, f4JumbleH(1, d, left))
+	return append(c, d...), nil
+}
+
+// f4JumbleInv is the inverse of f4Jumble
+func f4JumbleInv(m []byte) ([]byte, error) {
+	if len(m) < f4JumbleMinLength || len(m) > f4JumbleMaxLength {
+		return nil, fmt.Errorf("cannot unjumble %d bytes", len(m))
+	}
+	left := f4JumbleLeftLength(len(m))
+	c, d := m[:left], m[left:]
+	y := xorBytes(c, f4JumbleH(1, d, left))
+	x := xorBytes(d, f4JumbleG(1, y, len(d)))
+	a := xorBytes(y, f4JumbleH(0, x, left))
+	b := xorBytes(x, f4JumbleG(0, a, len(d)))
+	return append(a, b...), nil
+}
+
+func f4JumbleLeftLength(n int) int {
+	if n/2 < blake2b.Size {
+		return n / 2
+	}
+	return blake2b.Size
+}
+
+func f4JumbleH(i byte, u []byte, size int) []byte {
+	person := append([]byte("UA_F4Jumble_H"), i, 0, 0)
+	h, _ := blake2b.New(&blake2b.Config{Size: uint8(size), Person: person})
+	h.Write(u)
+	return h.Sum(nil)
+}
+
+func f4JumbleG(i byte, u []byte, size int) []byte {
+	out := make([]byte, 0, size+blake2b.Size)
+	for j := 0; len(out) < size; j++ {
+		person := append([]byte("UA_F4Jumble_G"), i, 0, 0)
+		binary.LittleEndian.PutUint16(person[14:], uint16(j))
+		h, _ := blake2b.New(&blake2b.Config{Size: blake2b.Size, Person: person})
+		h.Write(u)
+		out = h.Sum(out)
+	}
+	return out[:size]
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// isUnifiedAddress reports whether addr has the human readable part of unified addresses on net
+func isUnifiedAddress(addr string, net *chaincfg.Params) bool {
+	hrp, ok := unifiedHRPs[net.Name]
+	return ok && strings.HasPrefix(strings.ToLower(addr), hrp+"1")
+}
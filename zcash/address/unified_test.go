@@ -0,0 +1,145 @@
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// The expected encodings below were computed with an independent implementation of ZIP-316
+var (
+	saplingReceiver = func() []byte {
+		b := make([]byte, 43)
+		for i := range b {
+			b[i] = byte(i)
+		}
+		return b
+	}()
+	orchardReceiver = func() []byte {
+		b := make([]byte, 43)
+		for i := range b {
+			b[i] = byte(100 + i)
+		}
+		return b
+	}()
+)
+
+const unifiedAddress = "u1jddy5pzn4m29gk2wfmgzeea58asfjlrgrh860meaql4tnlt2enfy9rhqvyecc3nurajnklfr20jnnrx64ktn5ul09x8c9tgp9yjp6uyedcdtuaxs8et32me5y6ugem03mfargm6m8v32dhkzx8u0yqdj8sqs0wcn0e64gwwk77pqfwfgvygdrd7d3j68z74t9n85wlhjfpkqxephytl"
+
+func TestUnifiedAddress_EncodeAddress(t *testing.T) {
+	// Receivers are encoded in typecode order whatever order they are given in
+	addr, err := NewUnifiedAddress([]Receiver{
+		{OrchardReceiver, orchardReceiver},
+		{P2PKHReceiver, dataElement},
+		{SaplingReceiver, saplingReceiver},
+	}, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.String() != unifiedAddress {
+		t.Errorf("Address encoding error: %s", addr.String())
+	}
+
+	addr, err = NewUnifiedAddress([]Receiver{{SaplingReceiver, saplingReceiver}}, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.String() != "utest1ra864p6wk82ce53gq4wmrqe8yyggvs6dg3vr2uhdprk9qm7ye9dswgj7ksn48d3tgnhvr6pkaqm9es96fez0ma6hsq3jdggm7vxscrf5" {
+		t.Errorf("Address encoding error: %s", addr.String())
+	}
+}
+
+func TestDecodeUnifiedAddress(t *testing.T) {
+	addr, err := DecodeAddress(unifiedAddress, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ua, ok := addr.(*UnifiedAddress)
+	if !ok {
+		t.Fatalf("Expected a unified address, got %T", addr)
+	}
+	receivers := ua.Receivers()
+	if len(receivers) != 3 || !bytes.Equal(receivers[1].Data, saplingReceiver) || !bytes.Equal(receivers[2].Data, orchardReceiver) {
+		t.Error("Failed to decode receivers")
+	}
+	if ua.TransparentAddress().String() != "t1cQTWs2rPYM5R3zJiLA8MR3nZsXd1p2U6Q" {
+		t.Errorf("Wrong transparent receiver %s", ua.TransparentAddress())
+	}
+	if !ua.IsForNet(&chaincfg.MainNetParams) || ua.IsForNet(&chaincfg.TestNet3Params) {
+		t.Error("Wrong network")
+	}
+
+	// Unified addresses are paid through their transparent receiver
+	script, err := PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paid, err := ExtractPkScriptAddrs(script, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paid.String() != ua.TransparentAddress().String() {
+		t.Error("Failed to pay the transparent receiver")
+	}
+
+	if _, err := DecodeAddress(unifiedAddress, &chaincfg.TestNet3Params); err == nil {
+		t.Error("Expected a mainnet address to be refused on testnet")
+	}
+	corrupted := []byte(unifiedAddress)
+	corrupted[20] = 'q'
+	if _, err := DecodeAddress(string(corrupted), &chaincfg.MainNetParams); err != ErrChecksumMismatch {
+		t.Errorf("Expected a checksum error, got %v", err)
+	}
+}
+
+func TestUnifiedAddressShieldedOnly(t *testing.T) {
+	addr, err := NewUnifiedAddress([]Receiver{{SaplingReceiver, saplingReceiver}}, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeAddress(addr.String(), &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PayToAddrScript(decoded); err != ErrNoTransparentReceiver {
+		t.Errorf("Expected an address without a transparent receiver to be refused, got %v", err)
+	}
+}
+
+func TestNewUnifiedAddressInvalid(t *testing.T) {
+	tests := [][]Receiver{
+		{{P2PKHReceiver, dataElement}},
+		{{P2PKHReceiver, dataElement}, {P2SHReceiver, dataElement2}, {SaplingReceiver, saplingReceiver}},
+		{{SaplingReceiver, saplingReceiver}, {SaplingReceiver, saplingReceiver}},
+		{{OrchardReceiver, saplingReceiver[:42]}},
+	}
+	for i, receivers := range tests {
+		if _, err := NewUnifiedAddress(receivers, &chaincfg.MainNetParams); err == nil {
+			t.Errorf("Expected receivers %d to be refused", i)
+		}
+	}
+}
+
+func TestF4Jumble(t *testing.T) {
+	m := make([]byte, 200)
+	for i := range m {
+		m[i] = byte(i)
+	}
+	jumbled, err := f4Jumble(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "b23b9554c2ac6e0222c9546061472c0d67a83a782d4cbe7c7564cd5068adf74056036dabf15136f739a0703313c9888c34b51550424912a93fac0b0c87dbba19cbe304296511b8b26e4db1033c24e207e78de0834e17f41bd303cbfe6c70a306ed5a8e5fa88450779776cd0a5c651abfffcb49cf25db47a80ac1c6b80ecf963f2a33038fd0add7240185a86c6ddb422493fe3e3d1a3f7a5e0e10886d638ff606a477aaaa01ea0ab6fa1baac3787d525a596f820c4f46b99e71ae3d7d117e5849aedd9ffcc16bbc55"
+	if hex.EncodeToString(jumbled) != expected {
+		t.Errorf("Jumble error: %x", jumbled)
+	}
+	unjumbled, err := f4JumbleInv(jumbled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(unjumbled, m) {
+		t.Error("Failed to invert jumble")
+	}
+}
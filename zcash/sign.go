@@ -3,30 +3,22 @@ package zcash
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/hex"
-	"errors"
 	"fmt"
-	"math/big"
-	"strconv"
+	"sync"
 	"time"
 
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/minio/blake2b-simd"
 
-	"github.com/OpenBazaar/spvwallet"
 	wi "github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	btc "github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/coinset"
-	hd "github.com/btcsuite/btcutil/hdkeychain"
-	"github.com/btcsuite/btcutil/txsort"
-	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
 
-	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/multiwallet/utxo"
 	zaddr "github.com/OpenBazaar/multiwallet/zcash/address"
 )
 
@@ -40,608 +32,143 @@ var (
 	sigHashPersonalization      = []byte("ZcashSigHash")
 )
 
-const (
-	sigHashMask = 0x1f
-	branchID    = 0xf5b9230b
-)
-
-func (w *ZCashWallet) buildTx(amount int64, addr btc.Address, feeLevel wi.FeeLevel, optionalOutput *wire.TxOut) (*wire.MsgTx, error) {
-	// Check for dust
-	script, err := zaddr.PayToAddrScript(addr)
-	if err != nil {
-		return nil, err
-	}
-	if txrules.IsDustAmount(btc.Amount(amount), len(script), txrules.DefaultRelayFeePerKb) {
-		return nil, wi.ErrorDustAmount
-	}
-
-	var (
-		additionalPrevScripts   map[wire.OutPoint][]byte
-		additionalKeysByAddress map[string]*btc.WIF
-		inVals                  map[wire.OutPoint]btc.Amount
-	)
-
-	// Create input source
-	height, _ := w.ws.ChainTip()
-	utxos, err := w.db.Utxos().GetAll()
-	if err != nil {
-		return nil, err
-	}
-	coinMap := util.GatherCoins(height, utxos, w.ScriptToAddress, w.km.GetKeyForScript)
-
-	coins := make([]coinset.Coin, 0, len(coinMap))
-	for k := range coinMap {
-		coins = append(coins, k)
-	}
-	inputSource := func(target btc.Amount) (total btc.Amount, inputs []*wire.TxIn, inputValues []btc.Amount, scripts [][]byte, err error) {
-		coinSelector := coinset.MaxValueAgeCoinSelector{MaxInputs: 10000, MinChangeAmount: btc.Amount(0)}
-		coins, err := coinSelector.CoinSelect(target, coins)
-		if err != nil {
-			return total, inputs, inputValues, scripts, wi.ErrInsufficientFunds
-		}
-		additionalPrevScripts = make(map[wire.OutPoint][]byte)
-		additionalKeysByAddress = make(map[string]*btc.WIF)
-		inVals = make(map[wire.OutPoint]btc.Amount)
-		for _, c := range coins.Coins() {
-			total += c.Value()
-			outpoint := wire.NewOutPoint(c.Hash(), c.Index())
-			in := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
-			in.Sequence = 0 // Opt-in RBF so we can bump fees
-			inputs = append(inputs, in)
-			additionalPrevScripts[*outpoint] = c.PkScript()
-			key := coinMap[c]
-			addr, err := key.Address(w.params)
-			if err != nil {
-				continue
-			}
-			privKey, err := key.ECPrivKey()
-			if err != nil {
-				continue
-			}
-			wif, _ := btc.NewWIF(privKey, w.params, true)
-			additionalKeysByAddress[addr.EncodeAddress()] = wif
-			inVals[*outpoint] = c.Value()
-		}
-		return total, inputs, inputValues, scripts, nil
-	}
-
-	// Get the fee per kilobyte
-	f := w.GetFeePerByte(feeLevel)
-	feePerKB := f.Int64() * 1000
-
-	// outputs
-	out := wire.NewTxOut(amount, script)
-
-	// Create change source
-	changeSource := func() ([]byte, error) {
-		addr := w.CurrentAddress(wi.INTERNAL)
-		script, err := zaddr.PayToAddrScript(addr)
-		if err != nil {
-			return []byte{}, err
-		}
-		return script, nil
-	}
-
-	outputs := []*wire.TxOut{out}
-	if optionalOutput != nil {
-		outputs = append(outputs, optionalOutput)
-	}
-	authoredTx, err := newUnsignedTransaction(outputs, btc.Amount(feePerKB), inputSource, changeSource)
-	if err != nil {
-		return nil, err
-	}
+const sigHashMask = 0x1f
 
-	// BIP 69 sorting
-	txsort.InPlaceSort(authoredTx.Tx)
+// pinnedUpgradeLifetime is how long the upgrade a transaction was first signed or serialized for
+// is remembered
+const pinnedUpgradeLifetime = time.Hour
 
-	// Sign tx
-	getKey := txscript.KeyClosure(func(addr btc.Address) (*btcec.PrivateKey, bool, error) {
-		addrStr := addr.EncodeAddress()
-		wif := additionalKeysByAddress[addrStr]
-		return wif.PrivKey, wif.CompressPubKey, nil
-	})
-	for i, txIn := range authoredTx.Tx.TxIn {
-		prevOutScript := additionalPrevScripts[txIn.PreviousOutPoint]
-		_, addrs, _, err := txscript.ExtractPkScriptAddrs(prevOutScript, w.params)
-		if err != nil {
-			return nil, err
-		}
-		key, _, err := getKey(addrs[0])
-		if err != nil {
-			return nil, err
-		}
-		val := int64(inVals[txIn.PreviousOutPoint].ToUnit(btc.AmountSatoshi))
-		sig, err := rawTxInSignature(authoredTx.Tx, i, prevOutScript, txscript.SigHashAll, key, val)
-		if err != nil {
-			return nil, errors.New("failed to sign transaction")
-		}
-		builder := txscript.NewScriptBuilder()
-		builder.AddData(sig)
-		builder.AddData(key.PubKey().SerializeCompressed())
-		script, err := builder.Script()
-		if err != nil {
-			return nil, err
-		}
-		txIn.SignatureScript = script
-	}
-	return authoredTx.Tx, nil
+// zcashCoin signs and serializes transactions in the format of the network upgrade they will be
+// mined in, which upgrade returns when asked. The upgrade is looked up once per transaction and
+// pinned, so its signatures and serialization agree even if the chain tip crosses an upgrade
+// while it is built. Only transparent P2PKH and P2SH outputs are supported.
+type zcashCoin struct {
+	upgrade func() NetworkUpgrade
+	pinned  *pinnedUpgrades
 }
 
-func (w *ZCashWallet) buildSpendAllTx(addr btc.Address, feeLevel wi.FeeLevel) (*wire.MsgTx, error) {
-	tx := wire.NewMsgTx(1)
-
-	height, _ := w.ws.ChainTip()
-	utxos, err := w.db.Utxos().GetAll()
-	if err != nil {
-		return nil, err
-	}
-	coinMap := util.GatherCoins(height, utxos, w.ScriptToAddress, w.km.GetKeyForScript)
-
-	totalIn, inVals, additionalPrevScripts, additionalKeysByAddress := util.LoadAllInputs(tx, coinMap, w.params)
-
-	// outputs
-	script, err := zaddr.PayToAddrScript(addr)
-	if err != nil {
-		return nil, err
-	}
-
-	// Get the fee
-	fee0 := w.GetFeePerByte(feeLevel)
-	feePerByte := fee0.Int64()
-	estimatedSize := EstimateSerializeSize(1, []*wire.TxOut{wire.NewTxOut(0, script)}, false, P2PKH)
-	fee := int64(estimatedSize) * feePerByte
-
-	// Check for dust output
-	if txrules.IsDustAmount(btc.Amount(totalIn-fee), len(script), txrules.DefaultRelayFeePerKb) {
-		return nil, wi.ErrorDustAmount
-	}
-
-	// Build the output
-	out := wire.NewTxOut(totalIn-fee, script)
-	tx.TxOut = append(tx.TxOut, out)
-
-	// BIP 69 sorting
-	txsort.InPlaceSort(tx)
-
-	// Sign
-	getKey := txscript.KeyClosure(func(addr btc.Address) (*btcec.PrivateKey, bool, error) {
-		addrStr := addr.EncodeAddress()
-		wif, ok := additionalKeysByAddress[addrStr]
-		if !ok {
-			return nil, false, errors.New("key not found")
-		}
-		return wif.PrivKey, wif.CompressPubKey, nil
-	})
-	for i, txIn := range tx.TxIn {
-		prevOutScript := additionalPrevScripts[txIn.PreviousOutPoint]
-		_, addrs, _, err := txscript.ExtractPkScriptAddrs(prevOutScript, w.params)
-		if err != nil {
-			return nil, err
-		}
-		key, _, err := getKey(addrs[0])
-		if err != nil {
-			return nil, err
-		}
-		sig, err := rawTxInSignature(tx, i, prevOutScript, txscript.SigHashAll, key, inVals[txIn.PreviousOutPoint])
-		if err != nil {
-			return nil, errors.New("failed to sign transaction")
-		}
-		builder := txscript.NewScriptBuilder()
-		builder.AddData(sig)
-		builder.AddData(key.PubKey().SerializeCompressed())
-		script, err := builder.Script()
-		if err != nil {
-			return nil, err
-		}
-		txIn.SignatureScript = script
-	}
-	return tx, nil
+type pinnedUpgrades struct {
+	mtx      sync.Mutex
+	upgrades map[*wire.MsgTx]pinnedUpgrade
 }
 
-func newUnsignedTransaction(outputs []*wire.TxOut, feePerKb btc.Amount, fetchInputs txauthor.InputSource, fetchChange txauthor.ChangeSource) (*txauthor.AuthoredTx, error) {
-
-	var targetAmount btc.Amount
-	for _, txOut := range outputs {
-		targetAmount += btc.Amount(txOut.Value)
-	}
-
-	estimatedSize := EstimateSerializeSize(1, outputs, true, P2PKH)
-	targetFee := txrules.FeeForSerializeSize(feePerKb, estimatedSize)
-	for {
-		inputAmount, inputs, _, scripts, err := fetchInputs(targetAmount + targetFee)
-		if err != nil {
-			return nil, err
-		}
-		if inputAmount < targetAmount+targetFee {
-			return nil, errors.New("insufficient funds available to construct transaction")
-		}
-
-		maxSignedSize := EstimateSerializeSize(len(inputs), outputs, true, P2PKH)
-		maxRequiredFee := txrules.FeeForSerializeSize(feePerKb, maxSignedSize)
-		remainingAmount := inputAmount - targetAmount
-		if remainingAmount < maxRequiredFee {
-			targetFee = maxRequiredFee
-			continue
-		}
-
-		unsignedTransaction := &wire.MsgTx{
-			Version:  wire.TxVersion,
-			TxIn:     inputs,
-			TxOut:    outputs,
-			LockTime: 0,
-		}
-		changeIndex := -1
-		changeAmount := inputAmount - targetAmount - maxRequiredFee
-		if changeAmount != 0 && !txrules.IsDustAmount(changeAmount,
-			P2PKHOutputSize, txrules.DefaultRelayFeePerKb) {
-			changeScript, err := fetchChange()
-			if err != nil {
-				return nil, err
-			}
-			if len(changeScript) > P2PKHPkScriptSize {
-				return nil, errors.New("fee estimation requires change " +
-					"scripts no larger than P2PKH output scripts")
-			}
-			change := wire.NewTxOut(int64(changeAmount), changeScript)
-			l := len(outputs)
-			unsignedTransaction.TxOut = append(outputs[:l:l], change)
-			changeIndex = l
-		}
-
-		return &txauthor.AuthoredTx{
-			Tx:          unsignedTransaction,
-			PrevScripts: scripts,
-			TotalInput:  inputAmount,
-			ChangeIndex: changeIndex,
-		}, nil
-	}
+type pinnedUpgrade struct {
+	upgrade NetworkUpgrade
+	pinned  time.Time
 }
 
-func (w *ZCashWallet) bumpFee(txid chainhash.Hash) (*chainhash.Hash, error) {
-	txn, err := w.db.Txns().Get(txid)
-	if err != nil {
-		return nil, err
-	}
-	if txn.Height > 0 {
-		return nil, spvwallet.BumpFeeAlreadyConfirmedError
-	}
-	if txn.Height < 0 {
-		return nil, spvwallet.BumpFeeTransactionDeadError
+func newZcashCoin(upgrade func() NetworkUpgrade) zcashCoin {
+	return zcashCoin{
+		upgrade: upgrade,
+		pinned:  &pinnedUpgrades{upgrades: make(map[*wire.MsgTx]pinnedUpgrade)},
 	}
-	// Check utxos for CPFP
-	utxos, _ := w.db.Utxos().GetAll()
-	for _, u := range utxos {
-		if u.Op.Hash.IsEqual(&txid) && u.AtHeight == 0 {
-			addr, err := w.ScriptToAddress(u.ScriptPubkey)
-			if err != nil {
-				return nil, err
-			}
-			key, err := w.km.GetKeyForScript(addr.ScriptAddress())
-			if err != nil {
-				return nil, err
-			}
-			h, err := hex.DecodeString(u.Op.Hash.String())
-			if err != nil {
-				return nil, err
-			}
-			n := new(big.Int)
-			n, _ = n.SetString(u.Value, 10)
-			in := wi.TransactionInput{
-				LinkedAddress: addr,
-				OutpointIndex: u.Op.Index,
-				OutpointHash:  h,
-				Value:         *n,
-			}
-			transactionID, err := w.sweepAddress([]wi.TransactionInput{in}, nil, key, nil, wi.FEE_BUMP)
-			if err != nil {
-				return nil, err
-			}
-			return transactionID, nil
-		}
-	}
-	return nil, spvwallet.BumpFeeNotFoundError
 }
 
-func (w *ZCashWallet) sweepAddress(ins []wi.TransactionInput, address *btc.Address, key *hd.ExtendedKey, redeemScript *[]byte, feeLevel wi.FeeLevel) (*chainhash.Hash, error) {
-	var internalAddr btc.Address
-	if address != nil {
-		internalAddr = *address
-	} else {
-		internalAddr = w.CurrentAddress(wi.INTERNAL)
-	}
-	script, err := zaddr.PayToAddrScript(internalAddr)
-	if err != nil {
-		return nil, err
-	}
-
-	var val int64
-	var inputs []*wire.TxIn
-	additionalPrevScripts := make(map[wire.OutPoint][]byte)
-	var values []int64
-	for _, in := range ins {
-		val += in.Value.Int64()
-		values = append(values, in.Value.Int64())
-		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
-		if err != nil {
-			return nil, err
-		}
-		script, err := zaddr.PayToAddrScript(in.LinkedAddress)
-		if err != nil {
-			return nil, err
-		}
-		outpoint := wire.NewOutPoint(ch, in.OutpointIndex)
-		input := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
-		inputs = append(inputs, input)
-		additionalPrevScripts[*outpoint] = script
+// upgradeFor returns the upgrade tx is built for, looking it up the first time tx is seen.
+// Transactions pinned longer than pinnedUpgradeLifetime ago are forgotten.
+func (c zcashCoin) upgradeFor(tx *wire.MsgTx) NetworkUpgrade {
+	c.pinned.mtx.Lock()
+	defer c.pinned.mtx.Unlock()
+	if p, ok := c.pinned.upgrades[tx]; ok {
+		return p.upgrade
 	}
-	out := wire.NewTxOut(val, script)
-
-	txType := P2PKH
-	if redeemScript != nil {
-		txType = P2SH_1of2_Multisig
-		_, err := spvwallet.LockTimeFromRedeemScript(*redeemScript)
-		if err == nil {
-			txType = P2SH_Multisig_Timelock_1Sig
+	for pinnedTx, p := range c.pinned.upgrades {
+		if time.Since(p.pinned) > pinnedUpgradeLifetime {
+			delete(c.pinned.upgrades, pinnedTx)
 		}
-	} else {
-		redeemScript = &[]byte{}
-	}
-	estimatedSize := EstimateSerializeSize(len(ins), []*wire.TxOut{out}, false, txType)
-
-	// Calculate the fee
-	f := w.GetFeePerByte(feeLevel)
-	feePerByte := int(f.Int64())
-	fee := estimatedSize * feePerByte
-
-	outVal := val - int64(fee)
-	if outVal < 0 {
-		outVal = 0
 	}
-	out.Value = outVal
-
-	tx := &wire.MsgTx{
-		Version:  wire.TxVersion,
-		TxIn:     inputs,
-		TxOut:    []*wire.TxOut{out},
-		LockTime: 0,
-	}
-
-	// BIP 69 sorting
-	txsort.InPlaceSort(tx)
+	upgrade := c.upgrade()
+	c.pinned.upgrades[tx] = pinnedUpgrade{upgrade: upgrade, pinned: time.Now()}
+	return upgrade
+}
 
-	// Sign tx
-	privKey, err := key.ECPrivKey()
-	if err != nil {
-		return nil, err
-	}
+var zcashFeatures = utxo.Features{
+	CoinType:           wi.Zcash,
+	Currency:           ZcashCurrencyDefinition,
+	P2PKHInput:         utxo.P2PKH,
+	RelayFeePerKb:      txrules.DefaultRelayFeePerKb,
+	SafeConfirmations:  24,
+	FeeEstimateAddress: "t1hASvMj8e6TXWryuB3L5TKXJB7XfNioZP3",
+}
 
-	for i, txIn := range tx.TxIn {
-		sig, err := rawTxInSignature(tx, i, *redeemScript, txscript.SigHashAll, privKey, values[i])
-		if err != nil {
-			return nil, errors.New("failed to sign transaction")
-		}
-		builder := txscript.NewScriptBuilder()
-		builder.AddOp(txscript.OP_0)
-		builder.AddData(sig)
-		if redeemScript != nil {
-			builder.AddData(*redeemScript)
-		}
-		script, err := builder.Script()
-		if err != nil {
-			return nil, err
-		}
-		txIn.SignatureScript = script
-	}
+func (zcashCoin) Features() utxo.Features {
+	return zcashFeatures
+}
 
-	// broadcast
-	txid, err := w.Broadcast(tx)
-	if err != nil {
-		return nil, err
-	}
-	return chainhash.NewHashFromStr(txid)
+func (zcashCoin) DecodeAddress(addr string, params *chaincfg.Params) (btc.Address, error) {
+	return zaddr.DecodeAddress(addr, params)
 }
 
-func (w *ZCashWallet) createMultisigSignature(ins []wi.TransactionInput, outs []wi.TransactionOutput, key *hd.ExtendedKey, redeemScript []byte, feePerByte uint64) ([]wi.Signature, error) {
-	var sigs []wi.Signature
-	tx := wire.NewMsgTx(1)
-	var values []int64
-	for _, in := range ins {
-		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
-		if err != nil {
-			return sigs, err
-		}
-		values = append(values, in.Value.Int64())
-		outpoint := wire.NewOutPoint(ch, in.OutpointIndex)
-		input := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
-		tx.TxIn = append(tx.TxIn, input)
-	}
-	for _, out := range outs {
-		scriptPubkey, err := zaddr.PayToAddrScript(out.Address)
-		if err != nil {
-			return sigs, err
-		}
-		output := wire.NewTxOut(out.Value.Int64(), scriptPubkey)
-		tx.TxOut = append(tx.TxOut, output)
-	}
+func (zcashCoin) PayToAddrScript(addr btc.Address) ([]byte, error) {
+	return zaddr.PayToAddrScript(addr)
+}
 
-	// Subtract fee
-	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, P2SH_2of3_Multisig)
-	fee := estimatedSize * int(feePerByte)
-	if len(tx.TxOut) > 0 {
-		feePerOutput := fee / len(tx.TxOut)
-		for _, output := range tx.TxOut {
-			output.Value -= int64(feePerOutput)
-		}
-	}
+func (zcashCoin) ExtractAddress(script []byte, params *chaincfg.Params) (btc.Address, error) {
+	return zaddr.ExtractPkScriptAddrs(script, params)
+}
 
-	// BIP 69 sorting
-	txsort.InPlaceSort(tx)
+func (zcashCoin) ScriptHashAddress(redeemScript []byte, params *chaincfg.Params) (btc.Address, error) {
+	return zaddr.NewAddressScriptHash(redeemScript, params)
+}
 
-	signingKey, err := key.ECPrivKey()
+func (c zcashCoin) SignP2PKH(tx *wire.MsgTx, idx int, prevOuts []*wire.TxOut, key *btcec.PrivateKey) ([]byte, error) {
+	sig, err := rawTxInSignature(tx, idx, prevOuts[idx].PkScript, txscript.SigHashAll, key, prevOuts, c.upgradeFor(tx))
 	if err != nil {
-		return sigs, err
-	}
-
-	for i := range tx.TxIn {
-		sig, err := rawTxInSignature(tx, i, redeemScript, txscript.SigHashAll, signingKey, values[i])
-		if err != nil {
-			continue
-		}
-		bs := wi.Signature{InputIndex: uint32(i), Signature: sig}
-		sigs = append(sigs, bs)
+		return nil, err
 	}
-	return sigs, nil
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(sig)
+	builder.AddData(key.PubKey().SerializeCompressed())
+	return builder.Script()
 }
 
-func (w *ZCashWallet) multisign(ins []wi.TransactionInput, outs []wi.TransactionOutput, sigs1 []wi.Signature, sigs2 []wi.Signature, redeemScript []byte, feePerByte uint64, broadcast bool) ([]byte, error) {
-	tx := wire.NewMsgTx(1)
-	for _, in := range ins {
-		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
-		if err != nil {
-			return nil, err
-		}
-		outpoint := wire.NewOutPoint(ch, in.OutpointIndex)
-		input := wire.NewTxIn(outpoint, []byte{}, [][]byte{})
-		tx.TxIn = append(tx.TxIn, input)
-	}
-	for _, out := range outs {
-		scriptPubkey, err := zaddr.PayToAddrScript(out.Address)
-		if err != nil {
-			return nil, err
-		}
-		output := wire.NewTxOut(out.Value.Int64(), scriptPubkey)
-		tx.TxOut = append(tx.TxOut, output)
-	}
-
-	// Subtract fee
-	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, P2SH_2of3_Multisig)
-	fee := estimatedSize * int(feePerByte)
-	if len(tx.TxOut) > 0 {
-		feePerOutput := fee / len(tx.TxOut)
-		for _, output := range tx.TxOut {
-			output.Value -= int64(feePerOutput)
-		}
-	}
-
-	// BIP 69 sorting
-	txsort.InPlaceSort(tx)
-
-	for i, input := range tx.TxIn {
-		var sig1 []byte
-		var sig2 []byte
-		for _, sig := range sigs1 {
-			if int(sig.InputIndex) == i {
-				sig1 = sig.Signature
-				break
-			}
-		}
-		for _, sig := range sigs2 {
-			if int(sig.InputIndex) == i {
-				sig2 = sig.Signature
-				break
-			}
-		}
-
-		builder := txscript.NewScriptBuilder()
-		builder.AddOp(txscript.OP_0)
-		builder.AddData(sig1)
-		builder.AddData(sig2)
-		builder.AddData(redeemScript)
-		scriptSig, err := builder.Script()
-		if err != nil {
-			return nil, err
-		}
-		input.SignatureScript = scriptSig
-	}
-	// broadcast
-	if broadcast {
-		if _, err := w.Broadcast(tx); err != nil {
-			return nil, err
-		}
-	}
-	return serializeVersion4Transaction(tx, 0)
+func (c zcashCoin) SignScript(tx *wire.MsgTx, idx int, redeemScript []byte, prevOuts []*wire.TxOut, key *btcec.PrivateKey) ([]byte, error) {
+	return rawTxInSignature(tx, idx, redeemScript, txscript.SigHashAll, key, prevOuts, c.upgradeFor(tx))
 }
 
-func (w *ZCashWallet) generateMultisigScript(keys []hd.ExtendedKey, threshold int, timeout time.Duration, timeoutKey *hd.ExtendedKey) (addr btc.Address, redeemScript []byte, err error) {
-	if uint32(timeout.Hours()) > 0 && timeoutKey == nil {
-		return nil, nil, errors.New("Timeout key must be non nil when using an escrow timeout")
-	}
-
-	if len(keys) < threshold {
-		return nil, nil, fmt.Errorf("unable to generate multisig script with "+
-			"%d required signatures when there are only %d public "+
-			"keys available", threshold, len(keys))
-	}
-
-	var ecKeys []*btcec.PublicKey
-	for _, key := range keys {
-		ecKey, err := key.ECPubKey()
-		if err != nil {
-			return nil, nil, err
-		}
-		ecKeys = append(ecKeys, ecKey)
-	}
-
-	builder := txscript.NewScriptBuilder()
-	builder.AddInt64(int64(threshold))
-	for _, key := range ecKeys {
-		builder.AddData(key.SerializeCompressed())
-	}
-	builder.AddInt64(int64(len(ecKeys)))
-	builder.AddOp(txscript.OP_CHECKMULTISIG)
-
-	redeemScript, err = builder.Script()
+func (c zcashCoin) Serialize(tx *wire.MsgTx) ([]byte, chainhash.Hash, error) {
+	upgrade := c.upgradeFor(tx)
+	txBytes, err := serializeTransaction(tx, upgrade, 0)
 	if err != nil {
-		return nil, nil, err
+		return nil, chainhash.Hash{}, err
 	}
+	return txBytes, transactionID(tx, upgrade, 0, txBytes), nil
+}
 
-	addr, err = zaddr.NewAddressScriptHash(redeemScript, w.params)
-	if err != nil {
-		return nil, nil, err
-	}
-	return addr, redeemScript, nil
+func (zcashCoin) ToWire(raw []byte) []byte {
+	return trimTxForDeserialization(raw)
 }
 
-func (w *ZCashWallet) estimateSpendFee(amount int64, feeLevel wi.FeeLevel) (uint64, error) {
-	// Since this is an estimate we can use a dummy output address. Let's use a long one so we don't under estimate.
-	addr, err := zaddr.DecodeAddress("t1hASvMj8e6TXWryuB3L5TKXJB7XfNioZP3", &chaincfg.MainNetParams)
-	if err != nil {
-		return 0, err
-	}
-	tx, err := w.buildTx(amount, addr, feeLevel, nil)
-	if err != nil {
-		return 0, err
-	}
-	var outval int64
-	for _, output := range tx.TxOut {
-		outval += output.Value
-	}
-	var inval int64
-	utxos, err := w.db.Utxos().GetAll()
-	if err != nil {
-		return 0, err
+// txUpgrade returns the network upgrade of the next block, which new transactions are built and
+// signed for. Until the chain tip is known the latest upgrade is assumed.
+func (w *ZCashWallet) txUpgrade() NetworkUpgrade {
+	height, _ := w.WS.ChainTip()
+	if height == 0 {
+		upgrades := NetworkUpgrades(w.ChainParams)
+		return upgrades[len(upgrades)-1]
 	}
-	for _, input := range tx.TxIn {
-		for _, utxo := range utxos {
-			val, _ := strconv.ParseInt(utxo.Value, 10, 64)
-			if utxo.Op.Hash.IsEqual(&input.PreviousOutPoint.Hash) && utxo.Op.Index == input.PreviousOutPoint.Index {
-				inval += val
-				break
-			}
-		}
-	}
-	if inval < outval {
-		return 0, errors.New("Error building transaction: inputs less than outputs")
-	}
-	return uint64(inval - outval), err
+	return UpgradeAt(w.ChainParams, height+1)
 }
 
 // rawTxInSignature returns the serialized ECDSA signature for the input idx of
-// the given transaction, with hashType appended to it.
-func rawTxInSignature(tx *wire.MsgTx, idx int, prevScriptBytes []byte,
-	hashType txscript.SigHashType, key *btcec.PrivateKey, amt int64) ([]byte, error) {
+// the given transaction, with hashType appended to it. scriptCode is the script
+// being satisfied and prevOuts holds the output spent by each input. The signature
+// hash is that of the transaction version of upgrade.
+func rawTxInSignature(tx *wire.MsgTx, idx int, scriptCode []byte,
+	hashType txscript.SigHashType, key *btcec.PrivateKey, prevOuts []*wire.TxOut, upgrade NetworkUpgrade) ([]byte, error) {
 
-	hash, err := calcSignatureHash(prevScriptBytes, hashType, tx, idx, amt, 0)
+	var (
+		hash []byte
+		err  error
+	)
+	switch upgrade.TxVersion {
+	case 4:
+		hash, err = calcSignatureHash(scriptCode, hashType, tx, idx, prevOuts[idx].Value, upgrade.BranchID, 0)
+	case 5:
+		hash, err = calcSignatureHashV5(tx, idx, hashType, prevOuts, upgrade.BranchID, 0)
+	default:
+		err = errUnsupportedTxVersion
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -653,7 +180,8 @@ func rawTxInSignature(tx *wire.MsgTx, idx int, prevScriptBytes []byte,
 	return append(signature.Serialize(), byte(hashType)), nil
 }
 
-func calcSignatureHash(prevScriptBytes []byte, hashType txscript.SigHashType, tx *wire.MsgTx, idx int, amt int64, expiry uint32) ([]byte, error) {
+// calcSignatureHash returns the ZIP-243 signature hash of a version four transaction
+func calcSignatureHash(prevScriptBytes []byte, hashType txscript.SigHashType, tx *wire.MsgTx, idx int, amt int64, branchID, expiry uint32) ([]byte, error) {
 
 	// As a sanity check, ensure the passed input index for the transaction
 	// is valid.
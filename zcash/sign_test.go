@@ -3,6 +3,7 @@ package zcash
 import (
 	"bytes"
 	"encoding/hex"
+	"math/big"
 	"os"
 	"testing"
 	"time"
@@ -13,14 +14,17 @@ import (
 	"github.com/OpenBazaar/multiwallet/model/mock"
 	"github.com/OpenBazaar/multiwallet/service"
 	"github.com/OpenBazaar/multiwallet/util"
+	"github.com/OpenBazaar/multiwallet/utxo"
 	zaddr "github.com/OpenBazaar/multiwallet/zcash/address"
 	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/op/go-logging"
 )
 
 type FeeResponse struct {
@@ -53,19 +57,21 @@ func newMockWallet() (*ZCashWallet, error) {
 
 	fp := util.NewFeeProvider(2000, 300, 200, 100, nil)
 
-	bw := &ZCashWallet{
-		params: params,
-		km:     km,
-		db:     db,
-		fp:     fp,
-	}
+	bw := &ZCashWallet{&utxo.Wallet{
+		ChainParams: params,
+		KM:          km,
+		DB:          db,
+		Fees:        fp,
+		Log:         logging.MustGetLogger("zcash-wallet"),
+	}}
+	bw.Coin = newZcashCoin(bw.txUpgrade)
 	cli := mock.NewMockApiClient(bw.AddressToScript)
 	ws, err := service.NewWalletService(db, km, cli, params, wallet.Zcash, cache.NewMockCacher())
 	if err != nil {
 		return nil, err
 	}
-	bw.client = cli
-	bw.ws = ws
+	bw.Client = cli
+	bw.WS = ws
 	return bw, nil
 }
 
@@ -75,9 +81,9 @@ func TestWalletService_VerifyWatchScriptFilter(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	keys := w.km.GetKeys()
+	keys := w.KM.GetKeys()
 
-	addr, err := w.km.KeyToAddress(keys[0])
+	addr, err := w.KM.KeyToAddress(keys[0])
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -86,7 +92,7 @@ func TestWalletService_VerifyWatchScriptFilter(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	watchScripts, err := w.db.WatchedScripts().GetAll()
+	watchScripts, err := w.DB.WatchedScripts().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -113,7 +119,7 @@ func TestWalletService_VerifyWatchScriptPut(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	watchScripts, err := w.db.WatchedScripts().GetAll()
+	watchScripts, err := w.DB.WatchedScripts().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -146,7 +152,7 @@ func waitForTxnSync(t *testing.T, txnStore wallet.Txns) {
 
 func TestZCashWallet_buildTx(t *testing.T) {
 	w, err := newMockWallet()
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 	if err != nil {
 		t.Error(err)
@@ -156,7 +162,7 @@ func TestZCashWallet_buildTx(t *testing.T) {
 		t.Error(err)
 	}
 	// Test build normal tx
-	tx, err := w.buildTx(1500000, addr, wallet.NORMAL, nil)
+	tx, err := w.BuildTx(1500000, addr, wallet.NORMAL, nil)
 	if err != nil {
 		w.DumpTables(os.Stdout)
 		t.Error(err)
@@ -165,21 +171,21 @@ func TestZCashWallet_buildTx(t *testing.T) {
 	if !containsOutput(tx, addr) {
 		t.Error("Built tx does not contain the requested output")
 	}
-	if !validInputs(tx, w.db) {
+	if !validInputs(tx, w.DB) {
 		t.Error("Built tx does not contain valid inputs")
 	}
-	if !validChangeAddress(tx, w.db, w.params) {
+	if !validChangeAddress(tx, w.DB, w.ChainParams) {
 		t.Error("Built tx does not contain a valid change output")
 	}
 
 	// Insuffient funds
-	_, err = w.buildTx(1000000000, addr, wallet.NORMAL, nil)
+	_, err = w.BuildTx(1000000000, addr, wallet.NORMAL, nil)
 	if err != wallet.ErrorInsuffientFunds {
 		t.Error("Failed to throw insuffient funds error")
 	}
 
 	// Dust
-	_, err = w.buildTx(1, addr, wallet.NORMAL, nil)
+	_, err = w.BuildTx(1, addr, wallet.NORMAL, nil)
 	if err != wallet.ErrorDustAmount {
 		t.Error("Failed to throw dust error")
 	}
@@ -190,21 +196,21 @@ func TestZCashWallet_buildSpendAllTx(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 
-	waitForTxnSync(t, w.db.Txns())
+	waitForTxnSync(t, w.DB.Txns())
 	addr, err := w.DecodeAddress("t1hASvMj8e6TXWryuB3L5TKXJB7XfNioZP3")
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test build spendAll tx
-	tx, err := w.buildSpendAllTx(addr, wallet.NORMAL)
+	tx, err := w.BuildSpendAllTx(addr, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -220,7 +226,7 @@ func TestZCashWallet_buildSpendAllTx(t *testing.T) {
 	if !containsOutput(tx, addr) {
 		t.Error("Built tx does not contain the requested output")
 	}
-	if !validInputs(tx, w.db) {
+	if !validInputs(tx, w.DB) {
 		t.Error("Built tx does not contain valid inputs")
 	}
 	if len(tx.TxOut) != 1 {
@@ -274,7 +280,7 @@ func TestZCashWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -282,7 +288,7 @@ func TestZCashWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -290,7 +296,7 @@ func TestZCashWallet_GenerateMultisigScript(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	key3, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key3, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -301,7 +307,7 @@ func TestZCashWallet_GenerateMultisigScript(t *testing.T) {
 	keys := []hdkeychain.ExtendedKey{*key1, *key2, *key3}
 
 	// test without timeout
-	addr, redeemScript, err := w.generateMultisigScript(keys, 2, 0, nil)
+	addr, redeemScript, err := w.GenerateMultisigScript(keys, 2, 0, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -326,12 +332,12 @@ func TestZCashWallet_GenerateMultisigScript(t *testing.T) {
 
 func TestZCashWallet_newUnsignedTransaction(t *testing.T) {
 	w, err := newMockWallet()
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
@@ -365,7 +371,7 @@ func TestZCashWallet_newUnsignedTransaction(t *testing.T) {
 	}
 
 	// Regular transaction
-	authoredTx, err := newUnsignedTransaction(outputs, btcutil.Amount(1000), inputSource, changeSource)
+	authoredTx, err := utxo.NewUnsignedTransaction(zcashFeatures, outputs, btcutil.Amount(1000), inputSource, changeSource)
 	if err != nil {
 		t.Error(err)
 	}
@@ -378,7 +384,7 @@ func TestZCashWallet_newUnsignedTransaction(t *testing.T) {
 
 	// Insufficient funds
 	outputs[0].Value = 1000000000
-	_, err = newUnsignedTransaction(outputs, btcutil.Amount(1000), inputSource, changeSource)
+	_, err = utxo.NewUnsignedTransaction(zcashFeatures, outputs, btcutil.Amount(1000), inputSource, changeSource)
 	if err == nil {
 		t.Error("Failed to return insuffient funds error")
 	}
@@ -394,7 +400,7 @@ func TestZCashWallet_CreateMultisigSignature(t *testing.T) {
 		t.Error(err)
 	}
 
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -457,12 +463,12 @@ func TestZCashWallet_Multisign(t *testing.T) {
 		t.Error(err)
 	}
 
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
 
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -493,9 +499,9 @@ func TestZCashWallet_bumpFee(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
-	txns, err := w.db.Txns().GetAll(false)
+	txns, err := w.DB.Txns().GetAll(false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -504,34 +510,34 @@ func TestZCashWallet_bumpFee(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Fatal(err)
 	}
 	for _, u := range utxos {
 		if u.Op.Hash.IsEqual(ch) {
 			u.AtHeight = 0
-			if err := w.db.Utxos().Put(u); err != nil {
+			if err := w.DB.Utxos().Put(u); err != nil {
 				t.Fatal(err)
 			}
 		}
 	}
 
-	w.db.Txns().UpdateHeight(*ch, 0, time.Now())
+	w.DB.Txns().UpdateHeight(*ch, 0, time.Now())
 
 	// Test unconfirmed
-	_, err = w.bumpFee(*ch)
+	_, err = w.BumpFee(*ch)
 	if err != nil {
 		t.Error(err)
 	}
 
-	err = w.db.Txns().UpdateHeight(*ch, 1289597, time.Now())
+	err = w.DB.Txns().UpdateHeight(*ch, 1289597, time.Now())
 	if err != nil {
 		t.Error(err)
 	}
 
 	// Test confirmed
-	_, err = w.bumpFee(*ch)
+	_, err = w.BumpFee(*ch)
 	if err == nil {
 		t.Error("Should not be able to bump fee of confirmed txs")
 	}
@@ -539,12 +545,12 @@ func TestZCashWallet_bumpFee(t *testing.T) {
 
 func TestZCashWallet_sweepAddress(t *testing.T) {
 	w, err := newMockWallet()
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 	if err != nil {
 		t.Error(err)
 	}
-	utxos, err := w.db.Utxos().GetAll()
+	utxos, err := w.DB.Utxos().GetAll()
 	if err != nil {
 		t.Error(err)
 	}
@@ -556,7 +562,7 @@ func TestZCashWallet_sweepAddress(t *testing.T) {
 			if err != nil {
 				t.Error(err)
 			}
-			key, err = w.km.GetKeyForScript(addr.ScriptAddress())
+			key, err = w.KM.GetKeyForScript(addr.ScriptAddress())
 			if err != nil {
 				t.Error(err)
 			}
@@ -573,7 +579,7 @@ func TestZCashWallet_sweepAddress(t *testing.T) {
 		}
 	}
 	// P2PKH addr
-	_, err = w.sweepAddress([]wallet.TransactionInput{in}, nil, key, nil, wallet.NORMAL)
+	_, err = w.SweepAddress([]wallet.TransactionInput{in}, nil, key, nil, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 		return
@@ -598,12 +604,12 @@ func TestZCashWallet_sweepAddress(t *testing.T) {
 			}
 		}
 	}
-	key1, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key1, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
 
-	key2, err := w.km.GetFreshKey(wallet.INTERNAL)
+	key2, err := w.KM.GetFreshKey(wallet.INTERNAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -611,7 +617,7 @@ func TestZCashWallet_sweepAddress(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	_, err = w.sweepAddress([]wallet.TransactionInput{in}, nil, key1, &redeemScript, wallet.NORMAL)
+	_, err = w.SweepAddress([]wallet.TransactionInput{in}, nil, key1, &redeemScript, wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
@@ -619,16 +625,16 @@ func TestZCashWallet_sweepAddress(t *testing.T) {
 
 func TestZCashWallet_estimateSpendFee(t *testing.T) {
 	w, err := newMockWallet()
-	w.ws.Start()
+	w.WS.Start()
 	time.Sleep(time.Second / 2)
 	if err != nil {
 		t.Error(err)
 	}
-	fee, err := w.estimateSpendFee(1000, wallet.NORMAL)
+	fee, err := w.EstimateSpendFee(*big.NewInt(1000), wallet.NORMAL)
 	if err != nil {
 		t.Error(err)
 	}
-	if fee <= 0 {
+	if fee.Sign() <= 0 {
 		t.Error("Returned incorrect fee")
 	}
 }
@@ -703,7 +709,7 @@ func TestCalcSignatureHash(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	sigHash, err := calcSignatureHash(prevScript, txscript.SigHashAll, tx, 0, 50000000, 307272)
+	sigHash, err := calcSignatureHash(prevScript, txscript.SigHashAll, tx, 0, 50000000, 0x2bb40e60, 307272)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -715,3 +721,45 @@ func TestCalcSignatureHash(t *testing.T) {
 		t.Fatal("Failed to calculate correct sig hash")
 	}
 }
+
+func TestZcashCoin_PinsUpgrade(t *testing.T) {
+	// The chain tip crosses into NU5 after the first lookup
+	canopy := UpgradeAt(&chaincfg.MainNetParams, 1687103)
+	nu5 := UpgradeAt(&chaincfg.MainNetParams, 1687104)
+	next := canopy
+	c := newZcashCoin(func() NetworkUpgrade {
+		upgrade := next
+		next = nu5
+		return upgrade
+	})
+
+	tx, _, err := buildTestTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SignP2PKH(tx, 0, testPrevOuts(t), key); err != nil {
+		t.Fatal(err)
+	}
+	txBytes, _, err := c.Serialize(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txBytes[0] != 4 {
+		t.Errorf("Expected the transaction to be serialized for the upgrade it was signed for, got version %d", txBytes[0])
+	}
+
+	other, _, err := buildTestTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txBytes, _, err = c.Serialize(other); err != nil {
+		t.Fatal(err)
+	}
+	if txBytes[0] != 5 {
+		t.Errorf("Expected a new transaction to be built for the current upgrade, got version %d", txBytes[0])
+	}
+}
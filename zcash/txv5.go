@@ -0,0 +1,306 @@
+package zcash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/minio/blake2b-simd"
+)
+
+// Version five transactions were introduced by NU5 (ZIP-225). Their ids and signature hashes are
+// computed from a tree of digests over each part of the transaction (ZIP-244).
+var (
+	txV5HeaderBytes          = []byte{0x05, 0x00, 0x00, 0x80}
+	txV5NVersionGroupIDBytes = []byte{0x0a, 0x27, 0xa7, 0x26}
+
+	txHashPersonalization         = []byte("ZcashTxHash_")
+	headersDigestPersonalization  = []byte("ZTxIdHeadersHash")
+	transparentPersonalization    = []byte("ZTxIdTranspaHash")
+	prevoutsDigestPersonalization = []byte("ZTxIdPrevoutHash")
+	sequenceDigestPersonalization = []byte("ZTxIdSequencHash")
+	outputsDigestPersonalization  = []byte("ZTxIdOutputsHash")
+	amountsDigestPersonalization  = []byte("ZTxTrAmountsHash")
+	scriptsDigestPersonalization  = []byte("ZTxTrScriptsHash")
+	txInDigestPersonalization     = []byte("Zcash___TxInHash")
+	saplingDigestPersonalization  = []byte("ZTxIdSaplingHash")
+	orchardDigestPersonalization  = []byte("ZTxIdOrchardHash")
+)
+
+var errUnsupportedTxVersion = errors.New("unsupported zcash transaction version")
+
+func blake2bHash(personalization []byte, data ...[]byte) []byte {
+	bl, _ := blake2b.New(&blake2b.Config{
+		Size:   32,
+		Person: personalization,
+	})
+	for _, d := range data {
+		bl.Write(d)
+	}
+	return bl.Sum(nil)
+}
+
+func uint32Bytes(n uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, n)
+	return b
+}
+
+// serializeTransaction serializes tx in the format of the network upgrade it will be mined in
+func serializeTransaction(tx *wire.MsgTx, upgrade NetworkUpgrade, expiryHeight uint32) ([]byte, error) {
+	switch upgrade.TxVersion {
+	case 4:
+		return serializeVersion4Transaction(tx, expiryHeight)
+	case 5:
+		return serializeVersion5Transaction(tx, upgrade.BranchID, expiryHeight)
+	default:
+		return nil, errUnsupportedTxVersion
+	}
+}
+
+// transactionID returns the id of tx serialized as txBytes. Version five ids are the ZIP-244
+// digest rather than a hash of the serialization.
+func transactionID(tx *wire.MsgTx, upgrade NetworkUpgrade, expiryHeight uint32, txBytes []byte) chainhash.Hash {
+	if upgrade.TxVersion == 5 {
+		var h chainhash.Hash
+		copy(h[:], txIDVersion5(tx, upgrade.BranchID, expiryHeight))
+		return h
+	}
+	return chainhash.DoubleHashH(txBytes)
+}
+
+// serializeVersion5Transaction serializes a wire.MsgTx into the zcash version five wire
+// transaction format with no shielded components
+func serializeVersion5Transaction(tx *wire.MsgTx, branchID, expiryHeight uint32) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(txV5HeaderBytes)
+	buf.Write(txV5NVersionGroupIDBytes)
+	buf.Write(uint32Bytes(branchID))
+	buf.Write(uint32Bytes(tx.LockTime))
+	buf.Write(uint32Bytes(expiryHeight))
+	if err := writeTransparentBundle(&buf, tx); err != nil {
+		return nil, err
+	}
+	// No sapling spends, sapling outputs or orchard actions
+	buf.Write([]byte{0x00, 0x00, 0x00})
+	return buf.Bytes(), nil
+}
+
+func writeTransparentBundle(w io.Writer, tx *wire.MsgTx) error {
+	if err := wire.WriteVarInt(w, wire.ProtocolVersion, uint64(len(tx.TxIn))); err != nil {
+		return err
+	}
+	for _, in := range tx.TxIn {
+		if err := writeOutPoint(w, in.PreviousOutPoint); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, wire.ProtocolVersion, in.SignatureScript); err != nil {
+			return err
+		}
+		if _, err := w.Write(uint32Bytes(in.Sequence)); err != nil {
+			return err
+		}
+	}
+	if err := wire.WriteVarInt(w, wire.ProtocolVersion, uint64(len(tx.TxOut))); err != nil {
+		return err
+	}
+	for _, out := range tx.TxOut {
+		if err := wire.WriteTxOut(w, 0, 0, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeOutPoint(w io.Writer, op wire.OutPoint) error {
+	if _, err := w.Write(op.Hash[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(uint32Bytes(op.Index))
+	return err
+}
+
+// parseTransparentTx reads the transparent inputs and outputs and the lock time of a version
+// four or five zcash transaction. Shielded components are not read.
+func parseTransparentTx(txBytes []byte) (*wire.MsgTx, error) {
+	r := bytes.NewReader(txBytes)
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	version := binary.LittleEndian.Uint32(header[:4]) &^ (1 << 31)
+	tx := wire.NewMsgTx(int32(version))
+	var lockTime [4]byte
+	switch version {
+	case 4:
+	case 5:
+		// Branch ID, lock time and expiry height come before the transparent bundle
+		var fields [12]byte
+		if _, err := io.ReadFull(r, fields[:]); err != nil {
+			return nil, err
+		}
+		copy(lockTime[:], fields[4:8])
+	default:
+		return nil, errUnsupportedTxVersion
+	}
+	n, err := wire.ReadVarInt(r, wire.ProtocolVersion)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < n; i++ {
+		in := new(wire.TxIn)
+		if _, err := io.ReadFull(r, in.PreviousOutPoint.Hash[:]); err != nil {
+			return nil, err
+		}
+		var b [4]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		in.PreviousOutPoint.Index = binary.LittleEndian.Uint32(b[:])
+		if in.SignatureScript, err = wire.ReadVarBytes(r, wire.ProtocolVersion, txscript.MaxScriptSize, "sigScript"); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		in.Sequence = binary.LittleEndian.Uint32(b[:])
+		tx.AddTxIn(in)
+	}
+	if n, err = wire.ReadVarInt(r, wire.ProtocolVersion); err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < n; i++ {
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		script, err := wire.ReadVarBytes(r, wire.ProtocolVersion, txscript.MaxScriptSize, "pkScript")
+		if err != nil {
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(int64(binary.LittleEndian.Uint64(b[:])), script))
+	}
+	if version == 4 {
+		if _, err := io.ReadFull(r, lockTime[:]); err != nil {
+			return nil, err
+		}
+	}
+	tx.LockTime = binary.LittleEndian.Uint32(lockTime[:])
+	return tx, nil
+}
+
+func headerDigest(tx *wire.MsgTx, branchID, expiryHeight uint32) []byte {
+	return blake2bHash(headersDigestPersonalization,
+		txV5HeaderBytes, txV5NVersionGroupIDBytes, uint32Bytes(branchID),
+		uint32Bytes(tx.LockTime), uint32Bytes(expiryHeight))
+}
+
+func prevoutsDigest(tx *wire.MsgTx) []byte {
+	var b bytes.Buffer
+	for _, in := range tx.TxIn {
+		writeOutPoint(&b, in.PreviousOutPoint)
+	}
+	return blake2bHash(prevoutsDigestPersonalization, b.Bytes())
+}
+
+func sequenceDigest(tx *wire.MsgTx) []byte {
+	var b bytes.Buffer
+	for _, in := range tx.TxIn {
+		b.Write(uint32Bytes(in.Sequence))
+	}
+	return blake2bHash(sequenceDigestPersonalization, b.Bytes())
+}
+
+func outputsDigest(outs []*wire.TxOut) []byte {
+	var b bytes.Buffer
+	for _, out := range outs {
+		wire.WriteTxOut(&b, 0, 0, out)
+	}
+	return blake2bHash(outputsDigestPersonalization, b.Bytes())
+}
+
+func transparentDigest(tx *wire.MsgTx) []byte {
+	if len(tx.TxIn) == 0 && len(tx.TxOut) == 0 {
+		return blake2bHash(transparentPersonalization)
+	}
+	return blake2bHash(transparentPersonalization, prevoutsDigest(tx), sequenceDigest(tx), outputsDigest(tx.TxOut))
+}
+
+// txIDVersion5 returns the ZIP-244 transaction id digest of a transaction with no shielded
+// components
+func txIDVersion5(tx *wire.MsgTx, branchID, expiryHeight uint32) []byte {
+	return blake2bHash(append(txHashPersonalization, uint32Bytes(branchID)...),
+		headerDigest(tx, branchID, expiryHeight),
+		transparentDigest(tx),
+		blake2bHash(saplingDigestPersonalization),
+		blake2bHash(orchardDigestPersonalization))
+}
+
+// calcSignatureHashV5 returns the ZIP-244 signature hash for transparent input idx of a
+// transaction with no shielded components. prevOuts holds the output spent by each input, whose
+// amounts and scripts every signature commits to.
+func calcSignatureHashV5(tx *wire.MsgTx, idx int, hashType txscript.SigHashType, prevOuts []*wire.TxOut, branchID, expiryHeight uint32) ([]byte, error) {
+	if idx > len(tx.TxIn)-1 {
+		return nil, fmt.Errorf("idx %d but %d txins", idx, len(tx.TxIn))
+	}
+	if len(prevOuts) != len(tx.TxIn) {
+		return nil, fmt.Errorf("%d previous outputs for %d txins", len(prevOuts), len(tx.TxIn))
+	}
+	switch hashType &^ txscript.SigHashAnyOneCanPay {
+	case txscript.SigHashAll, txscript.SigHashNone, txscript.SigHashSingle:
+	default:
+		return nil, fmt.Errorf("invalid sighash type %#x", uint32(hashType))
+	}
+	anyoneCanPay := hashType&txscript.SigHashAnyOneCanPay != 0
+
+	var (
+		prevouts  = blake2bHash(prevoutsDigestPersonalization)
+		amounts   = blake2bHash(amountsDigestPersonalization)
+		scripts   = blake2bHash(scriptsDigestPersonalization)
+		sequences = blake2bHash(sequenceDigestPersonalization)
+		outputs   = blake2bHash(outputsDigestPersonalization)
+	)
+	if !anyoneCanPay {
+		var a, s bytes.Buffer
+		for _, prevOut := range prevOuts {
+			var amt [8]byte
+			binary.LittleEndian.PutUint64(amt[:], uint64(prevOut.Value))
+			a.Write(amt[:])
+			wire.WriteVarBytes(&s, 0, prevOut.PkScript)
+		}
+		prevouts = prevoutsDigest(tx)
+		amounts = blake2bHash(amountsDigestPersonalization, a.Bytes())
+		scripts = blake2bHash(scriptsDigestPersonalization, s.Bytes())
+		sequences = sequenceDigest(tx)
+	}
+	switch baseType := hashType & sigHashMask; {
+	case baseType != txscript.SigHashSingle && baseType != txscript.SigHashNone:
+		outputs = outputsDigest(tx.TxOut)
+	case baseType == txscript.SigHashSingle && idx < len(tx.TxOut):
+		outputs = outputsDigest(tx.TxOut[idx : idx+1])
+	}
+
+	var txIn bytes.Buffer
+	in := tx.TxIn[idx]
+	writeOutPoint(&txIn, in.PreviousOutPoint)
+	var amt [8]byte
+	binary.LittleEndian.PutUint64(amt[:], uint64(prevOuts[idx].Value))
+	txIn.Write(amt[:])
+	wire.WriteVarBytes(&txIn, 0, prevOuts[idx].PkScript)
+	txIn.Write(uint32Bytes(in.Sequence))
+
+	transparent := blake2bHash(transparentPersonalization,
+		[]byte{byte(hashType)}, prevouts, amounts, scripts, sequences, outputs,
+		blake2bHash(txInDigestPersonalization, txIn.Bytes()))
+
+	return blake2bHash(append(txHashPersonalization, uint32Bytes(branchID)...),
+		headerDigest(tx, branchID, expiryHeight),
+		transparent,
+		blake2bHash(saplingDigestPersonalization),
+		blake2bHash(orchardDigestPersonalization)), nil
+}
@@ -0,0 +1,109 @@
+package zcash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// The expected digests below are regression cases computed with an independent implementation of
+// ZIP-244. TestZIP244Vectors checks the official vectors.
+const testNU5BranchID = 0xc2d6d0b4
+
+func testPrevOuts(t *testing.T) []*wire.TxOut {
+	prevScript, err := hex.DecodeString("76a914507173527b4c3318a2aecd793bf1cfed705950cf88ac")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*wire.TxOut{wire.NewTxOut(50000000, prevScript)}
+}
+
+func TestSerializeVersion5Transaction(t *testing.T) {
+	tx, _, err := buildTestTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serialized, err := serializeVersion5Transaction(tx, testNU5BranchID, 307272)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := hex.DecodeString(`050000800a27a726b4d0d6c229b0040048b0040001a8c685478265f4c14dada651969c45a65e1aeb8cd6791f2f5bb6a1d9952104d9010000006b483045022100a61e5d557568c2ddc1d9b03a7173c6ce7c996c4daecab007ac8f34bee01e6b9702204d38fdc0bcf2728a69fde78462a10fb45a9baa27873e6a5fc45fb5c76764202a01210365ffea3efa3908918a8b8627724af852fc9b86d7375b103ab0543cf418bcaa7ffeffffff02005a6202000000001976a9148132712c3ff19f3a151234616777420a6d7ef22688ac8b959800000000001976a9145453e4698f02a38abdaa521cd1ff2dee6fac187188ac000000`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(serialized, expected) {
+		t.Fatalf("Failed to serialize transaction correctly: %x", serialized)
+	}
+
+	txid := txIDVersion5(tx, testNU5BranchID, 307272)
+	if hex.EncodeToString(txid) != "35866c6f8fa9568d31cf861ed8e23c89bf106985aefbf6fbe6aecf6117003024" {
+		t.Errorf("Failed to calculate correct txid: %x", txid)
+	}
+}
+
+func TestParseTransparentTx(t *testing.T) {
+	tx, v4, err := buildTestTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v5, err := serializeVersion5Transaction(tx, testNU5BranchID, 307272)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range [][]byte{v4, v5} {
+		parsed, err := parseTransparentTx(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.Version != int32(i+4) {
+			t.Errorf("Expected version %d, got %d", i+4, parsed.Version)
+		}
+		parsed.Version = tx.Version
+		if parsed.TxHash() != tx.TxHash() {
+			t.Errorf("Failed to parse version %d transaction", i+4)
+		}
+	}
+	if _, err := parseTransparentTx([]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}); err != errUnsupportedTxVersion {
+		t.Errorf("Expected version one transactions to be refused, got %v", err)
+	}
+}
+
+func TestCalcSignatureHashV5(t *testing.T) {
+	tx, _, err := buildTestTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		hashType txscript.SigHashType
+		expected string
+	}{
+		{txscript.SigHashAll, "4f84a5da06d5de516df61d9456db59fabd7fe4a4d8af3cae787b7adc8667e06c"},
+		{txscript.SigHashNone, "a9ba55c88a440b0969071efb8e34aebf15ac1fb95cea840e290400d6b4e57be4"},
+		{txscript.SigHashSingle | txscript.SigHashAnyOneCanPay, "8fdb828c06b5f85980a0892566d9a68ad8a4617d76e8774e8c87ea0a0c048bfe"},
+	}
+	for _, test := range tests {
+		sigHash, err := calcSignatureHashV5(tx, 0, test.hashType, testPrevOuts(t), testNU5BranchID, 307272)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(sigHash) != test.expected {
+			t.Errorf("Failed to calculate correct sig hash for type %#x: %x", uint32(test.hashType), sigHash)
+		}
+	}
+
+	// Signatures commit to the branch ID and to the amount spent
+	all, _ := calcSignatureHashV5(tx, 0, txscript.SigHashAll, testPrevOuts(t), testNU5BranchID, 307272)
+	otherBranch, _ := calcSignatureHashV5(tx, 0, txscript.SigHashAll, testPrevOuts(t), 0xc8e71055, 307272)
+	prevOuts := testPrevOuts(t)
+	prevOuts[0].Value++
+	otherAmount, _ := calcSignatureHashV5(tx, 0, txscript.SigHashAll, prevOuts, testNU5BranchID, 307272)
+	if bytes.Equal(all, otherBranch) || bytes.Equal(all, otherAmount) {
+		t.Error("Expected the sig hash to change with the branch ID and amount")
+	}
+	if _, err := calcSignatureHashV5(tx, 0, txscript.SigHashAll, nil, testNU5BranchID, 307272); err == nil {
+		t.Error("Expected missing previous outputs to be refused")
+	}
+}
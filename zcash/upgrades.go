@@ -0,0 +1,72 @@
+package zcash
+
+import (
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// NetworkUpgrade is a Zcash network upgrade along with the consensus branch ID and transaction
+// version in force from its activation height. Signatures commit to the branch ID so a
+// transaction must be signed for the upgrade of the block it will be mined in.
+type NetworkUpgrade struct {
+	Name             string
+	ActivationHeight uint32
+	BranchID         uint32
+	TxVersion        uint32
+}
+
+var (
+	mainnetUpgrades = []NetworkUpgrade{
+		{Name: "Sprout", ActivationHeight: 0, BranchID: 0, TxVersion: 1},
+		{Name: "Overwinter", ActivationHeight: 347500, BranchID: 0x5ba81b19, TxVersion: 3},
+		{Name: "Sapling", ActivationHeight: 419200, BranchID: 0x76b809bb, TxVersion: 4},
+		{Name: "Blossom", ActivationHeight: 653600, BranchID: 0x2bb40e60, TxVersion: 4},
+		{Name: "Heartwood", ActivationHeight: 903000, BranchID: 0xf5b9230b, TxVersion: 4},
+		{Name: "Canopy", ActivationHeight: 1046400, BranchID: 0xe9ff75a6, TxVersion: 4},
+		{Name: "NU5", ActivationHeight: 1687104, BranchID: 0xc2d6d0b4, TxVersion: 5},
+		{Name: "NU6", ActivationHeight: 2726400, BranchID: 0xc8e71055, TxVersion: 5},
+		{Name: "NU6.1", ActivationHeight: 3146400, BranchID: 0x4dec4df0, TxVersion: 5},
+	}
+
+	testnetUpgrades = []NetworkUpgrade{
+		{Name: "Sprout", ActivationHeight: 0, BranchID: 0, TxVersion: 1},
+		{Name: "Overwinter", ActivationHeight: 207500, BranchID: 0x5ba81b19, TxVersion: 3},
+		{Name: "Sapling", ActivationHeight: 280000, BranchID: 0x76b809bb, TxVersion: 4},
+		{Name: "Blossom", ActivationHeight: 584000, BranchID: 0x2bb40e60, TxVersion: 4},
+		{Name: "Heartwood", ActivationHeight: 903800, BranchID: 0xf5b9230b, TxVersion: 4},
+		{Name: "Canopy", ActivationHeight: 1028500, BranchID: 0xe9ff75a6, TxVersion: 4},
+		{Name: "NU5", ActivationHeight: 1842420, BranchID: 0xc2d6d0b4, TxVersion: 5},
+		{Name: "NU6", ActivationHeight: 2976000, BranchID: 0xc8e71055, TxVersion: 5},
+		{Name: "NU6.1", ActivationHeight: 3536500, BranchID: 0x4dec4df0, TxVersion: 5},
+	}
+
+	// Regtest nodes are expected to activate every upgrade from the first block
+	regtestUpgrades = []NetworkUpgrade{
+		{Name: "Sprout", ActivationHeight: 0, BranchID: 0, TxVersion: 1},
+		{Name: "NU6.1", ActivationHeight: 1, BranchID: 0x4dec4df0, TxVersion: 5},
+	}
+)
+
+// NetworkUpgrades returns the upgrades of the network in activation order
+func NetworkUpgrades(params *chaincfg.Params) []NetworkUpgrade {
+	switch params.Name {
+	case chaincfg.MainNetParams.Name:
+		return mainnetUpgrades
+	case chaincfg.TestNet3Params.Name:
+		return testnetUpgrades
+	default:
+		return regtestUpgrades
+	}
+}
+
+// UpgradeAt returns the network upgrade in force at height
+func UpgradeAt(params *chaincfg.Params, height uint32) NetworkUpgrade {
+	upgrades := NetworkUpgrades(params)
+	current := upgrades[0]
+	for _, u := range upgrades[1:] {
+		if height < u.ActivationHeight {
+			break
+		}
+		current = u
+	}
+	return current
+}
@@ -0,0 +1,32 @@
+package zcash
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestUpgradeAt(t *testing.T) {
+	tests := []struct {
+		params   *chaincfg.Params
+		height   uint32
+		name     string
+		branchID uint32
+	}{
+		{&chaincfg.MainNetParams, 0, "Sprout", 0},
+		{&chaincfg.MainNetParams, 419199, "Overwinter", 0x5ba81b19},
+		{&chaincfg.MainNetParams, 419200, "Sapling", 0x76b809bb},
+		{&chaincfg.MainNetParams, 1046399, "Heartwood", 0xf5b9230b},
+		{&chaincfg.MainNetParams, 1687104, "NU5", 0xc2d6d0b4},
+		{&chaincfg.MainNetParams, 4000000, "NU6.1", 0x4dec4df0},
+		{&chaincfg.TestNet3Params, 1842419, "Canopy", 0xe9ff75a6},
+		{&chaincfg.TestNet3Params, 1842420, "NU5", 0xc2d6d0b4},
+		{&chaincfg.RegressionNetParams, 1, "NU6.1", 0x4dec4df0},
+	}
+	for _, test := range tests {
+		u := UpgradeAt(test.params, test.height)
+		if u.Name != test.name || u.BranchID != test.branchID {
+			t.Errorf("%s at %d: expected %s (%x), got %s (%x)", test.params.Name, test.height, test.name, test.branchID, u.Name, u.BranchID)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package zcash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	zaddr "github.com/OpenBazaar/multiwallet/zcash/address"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Official test vectors are read from testdata in the JSON format of
+// https://github.com/zcash/zcash-test-vectors: a row naming their source, a row of comma separated
+// field names, then one row per vector. A missing file fails the test.
+func loadTestVectors(t *testing.T, name string) []map[string]interface{} {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if os.IsNotExist(err) {
+		t.Fatalf("%s not found, copy it into testdata from zcash-test-vectors/test-vectors/json", name)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows [][]interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	// Amounts do not fit in a float64
+	d.UseNumber()
+	if err := d.Decode(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) < 2 || len(rows[1]) != 1 {
+		t.Fatalf("%s does not name its fields", name)
+	}
+	header, _ := rows[1][0].(string)
+	fields := strings.Split(header, ",")
+	var vectors []map[string]interface{}
+	for i, row := range rows[2:] {
+		if len(row) != len(fields) {
+			t.Fatalf("Vector %d of %s has %d fields, expected %d", i, name, len(row), len(fields))
+		}
+		v := make(map[string]interface{})
+		for j, field := range fields {
+			v[strings.TrimSpace(field)] = row[j]
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors
+}
+
+// vectorBytes decodes a hex field of a test vector, returning nil if it is null
+func vectorBytes(t *testing.T, v map[string]interface{}, field string) []byte {
+	s, ok := v[field].(string)
+	if !ok {
+		return nil
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Field %s: %s", field, err)
+	}
+	return b
+}
+
+func vectorInt(t *testing.T, n interface{}) int64 {
+	num, ok := n.(json.Number)
+	if !ok {
+		t.Fatalf("Expected a number, got %v", n)
+	}
+	i, err := num.Int64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+func TestZIP244Vectors(t *testing.T) {
+	hashTypes := map[string]txscript.SigHashType{
+		"sighash_all":           txscript.SigHashAll,
+		"sighash_none":          txscript.SigHashNone,
+		"sighash_single":        txscript.SigHashSingle,
+		"sighash_all_anyone":    txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+		"sighash_none_anyone":   txscript.SigHashNone | txscript.SigHashAnyOneCanPay,
+		"sighash_single_anyone": txscript.SigHashSingle | txscript.SigHashAnyOneCanPay,
+	}
+	var checked int
+	for i, v := range loadTestVectors(t, "zip_0244.json") {
+		txBytes := vectorBytes(t, v, "tx")
+		tx, err := parseTransparentTx(txBytes)
+		if err != nil {
+			t.Fatalf("Vector %d: %s", i, err)
+		}
+		branchID := binary.LittleEndian.Uint32(txBytes[8:12])
+		expiryHeight := binary.LittleEndian.Uint32(txBytes[16:20])
+		serialized, err := serializeVersion5Transaction(tx, branchID, expiryHeight)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Only transactions without shielded components can be hashed. The transparent bundle of
+		// the others is still checked.
+		if !bytes.Equal(serialized, txBytes) {
+			if !bytes.HasPrefix(txBytes, serialized[:len(serialized)-3]) {
+				t.Errorf("Vector %d: failed to parse the transparent bundle", i)
+			}
+			continue
+		}
+		checked++
+
+		if txid := txIDVersion5(tx, branchID, expiryHeight); !bytes.Equal(txid, vectorBytes(t, v, "txid")) {
+			t.Errorf("Vector %d: wrong txid %x", i, txid)
+		}
+		if v["transparent_input"] == nil {
+			continue
+		}
+		idx := int(vectorInt(t, v["transparent_input"]))
+		amounts, _ := v["amounts"].([]interface{})
+		scripts, _ := v["script_pubkeys"].([]interface{})
+		if len(amounts) != len(tx.TxIn) || len(scripts) != len(tx.TxIn) {
+			t.Fatalf("Vector %d: expected an amount and script for each of %d inputs", i, len(tx.TxIn))
+		}
+		prevOuts := make([]*wire.TxOut, len(tx.TxIn))
+		for j := range prevOuts {
+			script, err := hex.DecodeString(scripts[j].(string))
+			if err != nil {
+				t.Fatal(err)
+			}
+			prevOuts[j] = wire.NewTxOut(vectorInt(t, amounts[j]), script)
+		}
+		for field, hashType := range hashTypes {
+			expected := vectorBytes(t, v, field)
+			if expected == nil {
+				continue
+			}
+			sigHash, err := calcSignatureHashV5(tx, idx, hashType, prevOuts, branchID, expiryHeight)
+			if err != nil {
+				t.Fatalf("Vector %d: %s", i, err)
+			}
+			if !bytes.Equal(sigHash, expected) {
+				t.Errorf("Vector %d: wrong %s %x", i, field, sigHash)
+			}
+		}
+	}
+	if checked == 0 {
+		t.Fatal("No vector without shielded components was checked")
+	}
+	t.Logf("Checked %d vectors without shielded components", checked)
+}
+
+func TestZIP316Vectors(t *testing.T) {
+	for i, v := range loadTestVectors(t, "unified_address.json") {
+		var receivers []zaddr.Receiver
+		for field, typecode := range map[string]zaddr.ReceiverType{
+			"p2pkh_bytes":      zaddr.P2PKHReceiver,
+			"p2sh_bytes":       zaddr.P2SHReceiver,
+			"sapling_raw_addr": zaddr.SaplingReceiver,
+			"orchard_raw_addr": zaddr.OrchardReceiver,
+		} {
+			if data := vectorBytes(t, v, field); data != nil {
+				receivers = append(receivers, zaddr.Receiver{Type: typecode, Data: data})
+			}
+		}
+		if v["unknown_typecode"] != nil {
+			receivers = append(receivers, zaddr.Receiver{
+				Type: zaddr.ReceiverType(vectorInt(t, v["unknown_typecode"])),
+				Data: vectorBytes(t, v, "unknown_bytes"),
+			})
+		}
+		// The address is given as the hex of its encoding
+		expected := string(vectorBytes(t, v, "unified_addr"))
+
+		addr, err := zaddr.NewUnifiedAddress(receivers, &chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatalf("Vector %d: %s", i, err)
+		}
+		if addr.String() != expected {
+			t.Errorf("Vector %d: address encoding error: %s", i, addr.String())
+		}
+		decoded, err := zaddr.DecodeAddress(expected, &chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatalf("Vector %d: %s", i, err)
+		}
+		ua, ok := decoded.(*zaddr.UnifiedAddress)
+		if !ok {
+			t.Fatalf("Vector %d: expected a unified address, got %T", i, decoded)
+		}
+		if decodedReceivers := ua.Receivers(); len(decodedReceivers) != len(receivers) {
+			t.Errorf("Vector %d: decoded %d receivers, expected %d", i, len(decodedReceivers), len(receivers))
+		}
+		for _, r := range ua.Receivers() {
+			for _, expected := range receivers {
+				if r.Type == expected.Type && !bytes.Equal(r.Data, expected.Data) {
+					t.Errorf("Vector %d: failed to decode the receiver of type %d", i, r.Type)
+				}
+			}
+		}
+	}
+}
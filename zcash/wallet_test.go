@@ -2,6 +2,7 @@ package zcash
 
 import (
 	"github.com/OpenBazaar/multiwallet/datastore"
+	"github.com/OpenBazaar/multiwallet/utxo"
 	"github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -16,9 +17,9 @@ func TestZCashWallet_Balance(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	w := ZCashWallet{
-		db: db,
-	}
+	w := ZCashWallet{&utxo.Wallet{
+		DB: db,
+	}}
 
 	ch1, err := chainhash.NewHashFromStr("ccfd8d91b38e065a4d0f655fffabbdbf61666d1fdf1b54b7432c5d0ad453b76d")
 	if err != nil {